@@ -6,8 +6,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -26,19 +24,19 @@ import (
 
 type AWSClient struct {
 	Ec2Client            EC2ClientAPI
-	Route53Client        *route53.Client
+	Route53Client        Route53ClientAPI
 	StackFormationClient *cloudformation.Client
-	ElbClient            *elb.Client
-	StsClient            *sts.Client
+	ElbClient            ELBClientAPI
+	StsClient            STSClientAPI
 	Region               string
-	IamClient            *iam.Client
+	IamClient            IAMClientAPI
 	ClientContext        context.Context
 	AccountID            string
 	Arn                  string
-	KmsClient            *kms.Client
-	CloudWatchLogsClient *cloudwatchlogs.Client
+	KmsClient            KMSClientAPI
+	CloudWatchLogsClient CloudWatchLogsClientAPI
 	AWSConfig            *aws.Config
-	RamClient            *ram.Client
+	RamClient            RAMClientAPI
 }
 
 type AccessKeyMod struct {
@@ -47,75 +45,70 @@ type AccessKeyMod struct {
 }
 
 func CreateAWSClient(profileName string, region string, awsSharedCredentialFile ...string) (*AWSClient, error) {
-	var cfg aws.Config
-	var err error
+	return CreateAWSClientWithRetryPolicy(profileName, region, nil, awsSharedCredentialFile...)
+}
 
+// CreateAWSClientWithRetryPolicy creates a client like CreateAWSClient whose SDK clients retry failed
+// calls according to policy. A nil policy keeps the SDK defaults.
+func CreateAWSClientWithRetryPolicy(profileName string, region string, policy *RetryPolicy, awsSharedCredentialFile ...string) (*AWSClient, error) {
+	options := []ClientOption{WithRetryPolicy(policy)}
 	if len(awsSharedCredentialFile) > 0 {
 		file := awsSharedCredentialFile[0]
 		log.LogInfo("Got aws shared credential file path: %s ", file)
-		cfg, err = config.LoadDefaultConfig(context.TODO(),
-			config.WithRegion(region),
-			config.WithSharedCredentialsFiles([]string{file}),
-		)
+		options = append(options, WithSharedCredentialsFiles(file))
 	} else {
 		if envAwsProfile() {
 			file := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
 			log.LogInfo("Got file path: %s from env variable AWS_SHARED_CREDENTIALS_FILE\n", file)
-			cfg, err = config.LoadDefaultConfig(context.TODO(),
-				config.WithRegion(region),
-				config.WithSharedCredentialsFiles([]string{file}),
-			)
+			options = append(options, WithSharedCredentialsFiles(file))
 		} else {
 			if envCredential() {
 				log.LogInfo("Got AWS_ACCESS_KEY_ID env settings, going to build the config with the env")
-				cfg, err = config.LoadDefaultConfig(context.TODO(),
-					config.WithRegion(region),
-					config.WithCredentialsProvider(
-						credentials.NewStaticCredentialsProvider(
-							os.Getenv("AWS_ACCESS_KEY_ID"),
-							os.Getenv("AWS_SECRET_ACCESS_KEY"),
-							"")),
-				)
+				options = append(options, WithStaticCredentials(
+					os.Getenv("AWS_ACCESS_KEY_ID"),
+					os.Getenv("AWS_SECRET_ACCESS_KEY"),
+					""))
 			} else {
 				log.LogInfo("AWS_SHARED_CREDENTIALS_FILE not supplied")
-				cfg, err = config.LoadDefaultConfig(context.TODO(),
-					config.WithRegion(region),
-					config.WithSharedConfigProfile(profileName),
-				)
+				options = append(options, WithProfile(profileName))
 			}
 		}
 	}
+	return NewAWSClient(region, options...)
+}
 
-	if err != nil {
-		return nil, err
-	}
-
-	awsClient := &AWSClient{
+// newAWSClientFromConfig creates the service clients of an AWSClient from cfg.
+func newAWSClientFromConfig(ctx context.Context, cfg aws.Config) *AWSClient {
+	return &AWSClient{
 		Ec2Client:            ec2.NewFromConfig(cfg),
 		Route53Client:        route53.NewFromConfig(cfg),
 		StackFormationClient: cloudformation.NewFromConfig(cfg),
 		ElbClient:            elb.NewFromConfig(cfg),
-		Region:               region,
+		Region:               cfg.Region,
 		StsClient:            sts.NewFromConfig(cfg),
 		IamClient:            iam.NewFromConfig(cfg),
-		ClientContext:        context.TODO(),
+		ClientContext:        ctx,
 		KmsClient:            kms.NewFromConfig(cfg),
 		AWSConfig:            &cfg,
 		RamClient:            ram.NewFromConfig(cfg),
 		CloudWatchLogsClient: cloudwatchlogs.NewFromConfig(cfg),
 	}
-	out, err := awsClient.GetCallerIdentity()
+}
+
+// loadCallerIdentity sets AccountID and Arn from the identity of the client credentials.
+func (client *AWSClient) loadCallerIdentity() error {
+	out, err := client.GetCallerIdentity()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	awsClient.AccountID = *out.Account
-	awsClient.Arn = *out.Arn
-	return awsClient, nil
+	client.AccountID = *out.Account
+	client.Arn = *out.Arn
+	return nil
 }
 
 func (client *AWSClient) GetCallerIdentity() (*sts.GetCallerIdentityOutput, error) {
 	input := &sts.GetCallerIdentityInput{}
-	out, err := client.StsClient.GetCallerIdentity(client.ClientContext, input)
+	out, err := client.StsClient.GetCallerIdentity(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Error happened when calling GetCallerIdentity: %s", err)
 		return nil, err
@@ -126,7 +119,7 @@ func (client *AWSClient) GetCallerIdentity() (*sts.GetCallerIdentityOutput, erro
 func (client *AWSClient) GetAWSPartition() string {
 	defaultPartition := "aws"
 	input := &sts.GetCallerIdentityInput{}
-	out, err := client.StsClient.GetCallerIdentity(client.ClientContext, input)
+	out, err := client.StsClient.GetCallerIdentity(client.requestContext(), input)
 	if err != nil {
 		// Failed to get caller identity, return default partition
 		return defaultPartition
@@ -143,13 +136,13 @@ func (client *AWSClient) EC2() EC2ClientAPI {
 	return client.Ec2Client
 }
 
-func (client *AWSClient) Route53() *route53.Client {
+func (client *AWSClient) Route53() Route53ClientAPI {
 	return client.Route53Client
 }
 func (client *AWSClient) CloudFormation() *cloudformation.Client {
 	return client.StackFormationClient
 }
-func (client *AWSClient) ELB() *elb.Client {
+func (client *AWSClient) ELB() ELBClientAPI {
 	return client.ElbClient
 }
 
@@ -167,7 +160,7 @@ func GrantValidAccessKeys(userName string) (*AccessKeyMod, error) {
 			return nil, err
 		}
 
-		cre, err = client.AWSConfig.Credentials.Retrieve(client.ClientContext)
+		cre, err = client.AWSConfig.Credentials.Retrieve(client.requestContext())
 		if err != nil {
 			return nil, err
 		}
@@ -1,14 +1,12 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/openshift-online/ocm-common/pkg/log"
 )
 
 func (client *AWSClient) DescribeLogGroupsByName(logGroupName string) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
-	output, err := client.CloudWatchLogsClient.DescribeLogGroups(context.TODO(), &cloudwatchlogs.DescribeLogGroupsInput{
+	output, err := client.CloudWatchLogsClient.DescribeLogGroups(client.requestContext(), &cloudwatchlogs.DescribeLogGroupsInput{
 		LogGroupNamePrefix: &logGroupName,
 	})
 	if err != nil {
@@ -18,7 +16,7 @@ func (client *AWSClient) DescribeLogGroupsByName(logGroupName string) (*cloudwat
 }
 
 func (client *AWSClient) DescribeLogStreamByName(logGroupName string) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
-	output, err := client.CloudWatchLogsClient.DescribeLogStreams(context.TODO(), &cloudwatchlogs.DescribeLogStreamsInput{
+	output, err := client.CloudWatchLogsClient.DescribeLogStreams(client.requestContext(), &cloudwatchlogs.DescribeLogStreamsInput{
 		LogGroupName: &logGroupName,
 	})
 	if err != nil {
@@ -28,7 +26,7 @@ func (client *AWSClient) DescribeLogStreamByName(logGroupName string) (*cloudwat
 }
 
 func (client *AWSClient) DeleteLogGroupByName(logGroupName string) (*cloudwatchlogs.DeleteLogGroupOutput, error) {
-	output, err := client.CloudWatchLogsClient.DeleteLogGroup(context.TODO(), &cloudwatchlogs.DeleteLogGroupInput{
+	output, err := client.CloudWatchLogsClient.DeleteLogGroup(client.requestContext(), &cloudwatchlogs.DeleteLogGroupInput{
 		LogGroupName: &logGroupName,
 	})
 	if err != nil {
@@ -0,0 +1,99 @@
+package aws_client
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+// RetryPolicy configures how the AWS SDK clients of an AWSClient retry failed calls.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per API call, including the first one.
+	MaxAttempts int
+	// MaxBackoff caps the exponential backoff between attempts.
+	MaxBackoff time.Duration
+	// ThrottleMaxBackoff caps the backoff after a throttling error. Zero means MaxBackoff.
+	ThrottleMaxBackoff time.Duration
+	// DisableRetryQuota turns off the client-side retry token bucket, so throttling bursts do not
+	// exhaust retries across calls.
+	DisableRetryQuota bool
+}
+
+// DefaultRetryPolicy returns the SDK standard retry settings.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: retry.DefaultMaxAttempts,
+		MaxBackoff:  retry.DefaultMaxBackoff,
+	}
+}
+
+// Retryer builds an SDK retryer from the policy. Throttling errors, as reported by
+// awserrors.IsThrottle, are always retried.
+func (policy *RetryPolicy) Retryer() aws.Retryer {
+	return retry.NewStandard(func(options *retry.StandardOptions) {
+		if policy.MaxAttempts > 0 {
+			options.MaxAttempts = policy.MaxAttempts
+		}
+		maxBackoff := policy.MaxBackoff
+		if maxBackoff <= 0 {
+			maxBackoff = retry.DefaultMaxBackoff
+		}
+		throttleMaxBackoff := policy.ThrottleMaxBackoff
+		if throttleMaxBackoff <= 0 {
+			throttleMaxBackoff = maxBackoff
+		}
+		options.MaxBackoff = maxBackoff
+		backoff := retry.NewExponentialJitterBackoff(maxBackoff)
+		throttleBackoff := retry.NewExponentialJitterBackoff(throttleMaxBackoff)
+		options.Backoff = retry.BackoffDelayerFunc(func(attempt int, err error) (time.Duration, error) {
+			if awserrors.IsThrottle(err) {
+				return throttleBackoff.BackoffDelay(attempt, err)
+			}
+			return backoff.BackoffDelay(attempt, err)
+		})
+		options.Retryables = append([]retry.IsErrorRetryable{
+			retry.IsErrorRetryableFunc(func(err error) aws.Ternary {
+				if awserrors.IsThrottle(err) {
+					return aws.TrueTernary
+				}
+				return aws.UnknownTernary
+			}),
+		}, options.Retryables...)
+		if policy.DisableRetryQuota {
+			options.RateLimiter = ratelimit.None
+		}
+	})
+}
+
+// WithContext returns a shallow copy of the client whose operations all run with ctx, so they can be
+// cancelled or bounded by a deadline. The SDK clients are shared with the original client.
+func (client *AWSClient) WithContext(ctx context.Context) *AWSClient {
+	copied := *client
+	copied.ClientContext = ctx
+	return &copied
+}
+
+// WithTimeout returns a copy of the client whose operations are bounded by timeout, and the function
+// releasing its context.
+func (client *AWSClient) WithTimeout(timeout time.Duration) (*AWSClient, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(client.requestContext(), timeout)
+	return client.WithContext(ctx), cancel
+}
+
+// requestContext returns the context API calls of the client run with.
+func (client *AWSClient) requestContext() context.Context {
+	if client.ClientContext == nil {
+		return context.Background()
+	}
+	return client.ClientContext
+}
+
+// sleep waits for d, returning early with the context error when the client context is done.
+func (client *AWSClient) sleep(d time.Duration) error {
+	return sleepContext(client.requestContext(), d)
+}
@@ -0,0 +1,124 @@
+package aws_client_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	smithy "github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+type contextKey string
+
+var _ = Describe("Client context", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockEC2Client *MockEC2ClientAPI
+		client        *AWSClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockEC2Client = NewMockEC2ClientAPI(mockCtrl)
+		client = &AWSClient{
+			Ec2Client: mockEC2Client,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should pass the WithContext context to API calls", func() {
+		ctx := context.WithValue(context.Background(), contextKey("reconcile"), "r-1")
+		mockEC2Client.EXPECT().
+			DescribeSubnets(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(got context.Context, _ *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+				Expect(got.Value(contextKey("reconcile"))).To(Equal("r-1"))
+				return &ec2.DescribeSubnetsOutput{}, nil
+			})
+
+		_, err := client.WithContext(ctx).ListSubnetDetail("subnet-1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.ClientContext).To(BeNil())
+	})
+
+	It("should use a background context when none is set", func() {
+		mockEC2Client.EXPECT().
+			DescribeSubnets(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(got context.Context, _ *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+				Expect(got).ToNot(BeNil())
+				return &ec2.DescribeSubnetsOutput{}, nil
+			})
+
+		_, err := client.ListSubnetDetail("subnet-1")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should stop polling when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		mockEC2Client.EXPECT().
+			DescribeSubnets(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, *ec2.DescribeSubnetsInput, ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+				cancel()
+				return &ec2.DescribeSubnetsOutput{}, nil
+			})
+
+		start := time.Now()
+		err := client.WithContext(ctx).WaitForSubnetAccessibility([]string{"subnet-1"}, 60)
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(time.Since(start)).To(BeNumerically("<", ClientWaiterDelay))
+	})
+
+	It("should bound operations with WithTimeout", func() {
+		timed, cancel := client.WithTimeout(time.Millisecond)
+		defer cancel()
+		deadline, ok := timed.ClientContext.Deadline()
+		Expect(ok).To(BeTrue())
+		Expect(deadline).To(BeTemporally("~", time.Now(), time.Second))
+	})
+})
+
+var _ = Describe("RetryPolicy", func() {
+	throttle := &smithy.GenericAPIError{Code: awserrors.Throttling, Message: "Rate exceeded"}
+
+	It("should apply the configured attempts", func() {
+		retryer := (&RetryPolicy{MaxAttempts: 7}).Retryer()
+		Expect(retryer.MaxAttempts()).To(Equal(7))
+	})
+
+	It("should fall back to the SDK defaults", func() {
+		policy := DefaultRetryPolicy()
+		Expect(policy.Retryer().MaxAttempts()).To(Equal(policy.MaxAttempts))
+	})
+
+	It("should retry throttling errors", func() {
+		retryer := DefaultRetryPolicy().Retryer()
+		Expect(retryer.IsErrorRetryable(throttle)).To(BeTrue())
+		Expect(retryer.IsErrorRetryable(errors.New("boom"))).To(BeFalse())
+	})
+
+	It("should cap backoff separately for throttling errors", func() {
+		retryer := (&RetryPolicy{
+			MaxAttempts:        10,
+			MaxBackoff:         time.Millisecond,
+			ThrottleMaxBackoff: time.Hour,
+		}).Retryer()
+		other := &smithy.GenericAPIError{Code: "InternalError"}
+		for attempt := 1; attempt < 10; attempt++ {
+			delay, err := retryer.RetryDelay(attempt, other)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(delay).To(BeNumerically("<=", time.Millisecond))
+			delay, err = retryer.RetryDelay(attempt, throttle)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(delay).To(BeNumerically("<=", time.Hour))
+		}
+	})
+})
@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -20,7 +18,7 @@ func (client *AWSClient) AllocateEIPAddress() (*ec2.AllocateAddressOutput, error
 		TagSpecifications:     nil,
 	}
 
-	respEIP, err := client.Ec2Client.AllocateAddress(context.TODO(), inputs)
+	respEIP, err := client.Ec2Client.AllocateAddress(client.requestContext(), inputs)
 	if err != nil {
 		log.LogError("Create EIP failed %s", err.Error())
 		return nil, err
@@ -34,7 +32,7 @@ func (client *AWSClient) DisassociateAddress(associateID string) (*ec2.Disassoci
 		AssociationId: aws.String(associateID),
 	}
 
-	respDisassociate, err := client.EC2().DisassociateAddress(context.TODO(), inputDisassociate)
+	respDisassociate, err := client.EC2().DisassociateAddress(client.requestContext(), inputDisassociate)
 	if err != nil {
 		log.LogError("Disassociate EIP failed %s", err.Error())
 		return nil, err
@@ -51,7 +49,7 @@ func (client *AWSClient) AllocateEIPAndAssociateInstance(instanceID string) (str
 	} else {
 		log.LogInfo("Successfully allocated EIP: %s", *allocRes.PublicIp)
 	}
-	assocRes, err := client.EC2().AssociateAddress(context.TODO(),
+	assocRes, err := client.EC2().AssociateAddress(client.requestContext(),
 		&ec2.AssociateAddressInput{
 			AllocationId: allocRes.AllocationId,
 			InstanceId:   aws.String(instanceID),
@@ -79,7 +77,7 @@ func (client *AWSClient) ReleaseAddressWithAllocationID(allocationID string) err
 		NetworkBorderGroup: nil,
 		PublicIp:           nil,
 	}
-	_, err := client.Ec2Client.ReleaseAddress(context.TODO(), inputRelease)
+	_, err := client.Ec2Client.ReleaseAddress(client.requestContext(), inputRelease)
 	if err != nil {
 		log.LogError("Release EIP %s failed: %s", allocationID, err.Error())
 		return err
@@ -103,7 +101,7 @@ func (client *AWSClient) DescribeAddresses(filters ...map[string][]string) (*ec2
 	inputAdd := &ec2.DescribeAddressesInput{
 		Filters: filterInput,
 	}
-	output, err := client.EC2().DescribeAddresses(context.TODO(), inputAdd)
+	output, err := client.EC2().DescribeAddresses(client.requestContext(), inputAdd)
 	if err != nil {
 		log.LogError("Describe EIP met error: %s", err.Error())
 		return nil, err
@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 
 	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
@@ -13,7 +11,7 @@ func (client *AWSClient) DescribeLoadBalancers(vpcID string) ([]elbtypes.LoadBal
 
 	listenedELB := []elbtypes.LoadBalancer{}
 	input := &elb.DescribeLoadBalancersInput{}
-	resp, err := client.ElbClient.DescribeLoadBalancers(context.TODO(), input)
+	resp, err := client.ElbClient.DescribeLoadBalancers(client.requestContext(), input)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +32,6 @@ func (client *AWSClient) DeleteELB(ELB elbtypes.LoadBalancer) error {
 		// LoadBalancerArn: ELB.LoadBalancerArn,
 		LoadBalancerArn: ELB.LoadBalancerArn,
 	}
-	_, err := client.ElbClient.DeleteLoadBalancer(context.TODO(), deleteELBInput)
+	_, err := client.ElbClient.DeleteLoadBalancer(client.requestContext(), deleteELBInput)
 	return err
 }
@@ -0,0 +1,16 @@
+package aws_client
+
+import (
+	"context"
+
+	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+)
+
+// ELBClientAPI defines the ELB methods used by AWSClient.
+// This interface allows us to mock AWS ELB calls in unit tests.
+//
+//go:generate mockgen -source=elb_client_interface.go -package=aws_client -destination=mock_elb_client.go
+type ELBClientAPI interface {
+	DeleteLoadBalancer(ctx context.Context, params *elb.DeleteLoadBalancerInput, optFns ...func(*elb.Options)) (*elb.DeleteLoadBalancerOutput, error)
+	DescribeLoadBalancers(ctx context.Context, params *elb.DescribeLoadBalancersInput, optFns ...func(*elb.Options)) (*elb.DescribeLoadBalancersOutput, error)
+}
@@ -0,0 +1,9 @@
+package aws_client
+
+// Export internal funcs for testing only.
+// This file is compiled exclusively as part of the test binary.
+
+var (
+	OldestNonDefaultPolicyVersion = oldestNonDefaultPolicyVersion
+	DecodePolicyVersion           = decodePolicyVersion
+)
@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/openshift-online/ocm-common/pkg/aws/consts"
@@ -15,7 +13,7 @@ func (client *AWSClient) CopyImage(sourceImageID string, sourceRegion string, na
 		SourceImageId: &sourceImageID,
 		SourceRegion:  &sourceRegion,
 	}
-	output, err := client.EC2().CopyImage(context.TODO(), copyImageInput)
+	output, err := client.EC2().CopyImage(client.requestContext(), copyImageInput)
 	if err != nil {
 		log.LogError("Error happens when copy image: %s", err)
 		return "", err
@@ -44,7 +42,7 @@ func (client *AWSClient) DescribeImage(imageIDs []string, filters ...map[string]
 	if len(imageIDs) != 0 {
 		describeImageInput.ImageIds = imageIDs
 	}
-	output, err := client.EC2().DescribeImages(context.TODO(), describeImageInput)
+	output, err := client.EC2().DescribeImages(client.requestContext(), describeImageInput)
 	if err != nil {
 		log.LogError("Describe image %s meet error: %s", imageIDs, err)
 		return nil, err
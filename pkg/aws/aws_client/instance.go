@@ -2,6 +2,7 @@ package aws_client
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
 	"github.com/openshift-online/ocm-common/pkg/log"
 )
 
@@ -29,7 +31,7 @@ func (client *AWSClient) LaunchInstance(subnetID string, imageID string, count i
 		input.UserData = &userDate[0]
 	}
 
-	output, err := client.Ec2Client.RunInstances(context.TODO(), input)
+	output, err := client.Ec2Client.RunInstances(client.requestContext(), input)
 	if wait && err == nil {
 		instanceIDs := []string{}
 		for _, instance := range output.Instances {
@@ -37,8 +39,10 @@ func (client *AWSClient) LaunchInstance(subnetID string, imageID string, count i
 		}
 		log.LogInfo("Waiting for below instances ready: %s", strings.Join(instanceIDs, "，"))
 		// Wait 2 seconds for the asynchronous bastion instance to be created
-		time.Sleep(2 * time.Second)
-		_, err = client.WaitForInstancesRunning(context.TODO(), instanceIDs, 10)
+		if err = client.sleep(2 * time.Second); err != nil {
+			return output, err
+		}
+		_, err = client.WaitForInstancesRunning(client.requestContext(), instanceIDs, 10)
 		if err != nil {
 			log.LogError("Error happened for instance running: %s", err)
 		} else {
@@ -68,7 +72,7 @@ func (client *AWSClient) ListInstances(instanceIDs []string, filters ...map[stri
 	if len(instanceIDs) != 0 {
 		getInstanceInput.InstanceIds = instanceIDs
 	}
-	resp, err := client.EC2().DescribeInstances(context.TODO(), getInstanceInput)
+	resp, err := client.EC2().DescribeInstances(client.requestContext(), getInstanceInput)
 	if err != nil {
 		log.LogError("List instances failed with filters %v: %s", filters, err)
 	}
@@ -96,30 +100,70 @@ func (client *AWSClient) CheckInstanceState(ctx context.Context, instanceIDs ...
 	return output, err
 }
 
-// WaitForInstancesRunning uses the EC2 InstanceStatusOk waiter to block until
-// all instances have InstanceState=running and both status checks pass (ok).
-// This is stricter than InstanceRunningWaiter and ensures SSH is reachable.
+// WaitForInstancesRunning blocks until all instances have InstanceState=running and both status
+// checks pass (ok), as the EC2 InstanceStatusOk waiter does. This is stricter than waiting for the
+// running state and ensures SSH is reachable. Instances not visible yet are waited for.
 // timeout is in minutes.
 func (client *AWSClient) WaitForInstancesRunning(ctx context.Context, instanceIDs []string, timeout time.Duration) (bool, error) {
 	log.LogInfo("Waiting for instances to reach running+ok status: %s", strings.Join(instanceIDs, ","))
-	waiter := ec2.NewInstanceStatusOkWaiter(client.Ec2Client)
-	err := waiter.Wait(ctx, &ec2.DescribeInstanceStatusInput{
-		InstanceIds: instanceIDs,
-	}, timeout*time.Minute)
+	err := NewWaiter(timeout*time.Minute).Wait(ctx, "instances status ok: "+strings.Join(instanceIDs, ","),
+		func(ctx context.Context) (bool, string, error) {
+			output, err := client.Ec2Client.DescribeInstanceStatus(ctx, &ec2.DescribeInstanceStatusInput{
+				InstanceIds: instanceIDs,
+			})
+			if awserrors.IsErrorCode(err, awserrors.InvalidInstanceID) {
+				return false, "not found", nil
+			}
+			if err != nil {
+				return false, "", err
+			}
+			states := []string{}
+			ready := len(output.InstanceStatuses) == len(instanceIDs)
+			for _, status := range output.InstanceStatuses {
+				summary := types.SummaryStatus("")
+				if status.InstanceStatus != nil {
+					summary = status.InstanceStatus.Status
+				}
+				ready = ready && summary == types.SummaryStatusOk
+				states = append(states, fmt.Sprintf("%s: %s", aws.ToString(status.InstanceId), summary))
+			}
+			return ready, strings.Join(states, ", "), nil
+		})
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-// WaitForInstancesTerminated uses the EC2 InstanceTerminated waiter to block
-// until all instances reach the terminated state. timeout is in minutes.
+// WaitForInstancesTerminated blocks until all instances reach the terminated state. Instances
+// that are not found any more count as terminated. timeout is in minutes.
 func (client *AWSClient) WaitForInstancesTerminated(ctx context.Context, instanceIDs []string, timeout time.Duration) (bool, error) {
 	log.LogInfo("Waiting for instances to be terminated: %s", strings.Join(instanceIDs, ","))
-	waiter := ec2.NewInstanceTerminatedWaiter(client.Ec2Client)
-	err := waiter.Wait(ctx, &ec2.DescribeInstancesInput{
-		InstanceIds: instanceIDs,
-	}, timeout*time.Minute)
+	err := NewWaiter(timeout*time.Minute).Wait(ctx, "instances terminated: "+strings.Join(instanceIDs, ","),
+		func(ctx context.Context) (bool, string, error) {
+			output, err := client.Ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+				InstanceIds: instanceIDs,
+			})
+			if awserrors.IsErrorCode(err, awserrors.InvalidInstanceID) {
+				return true, "", nil
+			}
+			if err != nil {
+				return false, "", err
+			}
+			states := []string{}
+			terminated := true
+			for _, reservation := range output.Reservations {
+				for _, instance := range reservation.Instances {
+					state := types.InstanceStateName("")
+					if instance.State != nil {
+						state = instance.State.Name
+					}
+					terminated = terminated && state == types.InstanceStateNameTerminated
+					states = append(states, fmt.Sprintf("%s: %s", aws.ToString(instance.InstanceId), state))
+				}
+			}
+			return terminated, strings.Join(states, ", "), nil
+		})
 	if err != nil {
 		return false, err
 	}
@@ -142,7 +186,7 @@ func (client *AWSClient) ListAvaliableInstanceTypesForRegion(region string, avai
 	var instanceTypes []types.InstanceTypeOffering
 	paginator := ec2.NewDescribeInstanceTypeOfferingsPaginator(client.Ec2Client, params)
 	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
+		page, err := paginator.NextPage(client.requestContext())
 		if err != nil {
 			return nil, err
 		}
@@ -159,7 +203,7 @@ func (client *AWSClient) ListAvaliableInstanceTypesForRegion(region string, avai
 // zone type are: local-zone/availability-zone/wavelength-zone
 func (client *AWSClient) ListAvaliableZonesForRegion(region string, zoneType string) ([]string, error) {
 	var zones []string
-	availabilityZones, err := client.Ec2Client.DescribeAvailabilityZones(context.TODO(), &ec2.DescribeAvailabilityZonesInput{
+	availabilityZones, err := client.Ec2Client.DescribeAvailabilityZones(client.requestContext(), &ec2.DescribeAvailabilityZonesInput{
 		Filters: []types.Filter{
 			{
 				Name:   aws.String("region-name"),
@@ -192,7 +236,7 @@ func (client *AWSClient) TerminateInstances(instanceIDs []string, wait bool, tim
 	terminateInput := &ec2.TerminateInstancesInput{
 		InstanceIds: instanceIDs,
 	}
-	_, err := client.EC2().TerminateInstances(context.TODO(), terminateInput)
+	_, err := client.EC2().TerminateInstances(client.requestContext(), terminateInput)
 	if err != nil {
 		log.LogError("Error happens when terminate instances %s : %s", strings.Join(instanceIDs, ","), err)
 		return err
@@ -200,7 +244,7 @@ func (client *AWSClient) TerminateInstances(instanceIDs []string, wait bool, tim
 		log.LogInfo("Terminate instances %s successfully", strings.Join(instanceIDs, ","))
 	}
 	if wait {
-		err = client.WaitForInstanceTerminated(context.TODO(), instanceIDs, timeout)
+		err = client.WaitForInstanceTerminated(client.requestContext(), instanceIDs, timeout)
 		if err != nil {
 			log.LogError("Waiting for  instances %s termination timeout %s ", strings.Join(instanceIDs, ","), err)
 			return err
@@ -220,7 +264,7 @@ func (client *AWSClient) GetTagsOfInstanceProfile(instanceProfileName string) ([
 	input := &iam.ListInstanceProfileTagsInput{
 		InstanceProfileName: &instanceProfileName,
 	}
-	resp, err := client.IamClient.ListInstanceProfileTags(context.TODO(), input)
+	resp, err := client.IamClient.ListInstanceProfileTags(client.requestContext(), input)
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +290,7 @@ func (client *AWSClient) GetInstancesByInfraID(infraID string) ([]types.Instance
 			"owned",
 		},
 	}
-	output, err := client.Ec2Client.DescribeInstances(context.TODO(), &ec2.DescribeInstancesInput{
+	output, err := client.Ec2Client.DescribeInstances(client.requestContext(), &ec2.DescribeInstancesInput{
 		Filters: []types.Filter{
 			filter,
 		},
@@ -276,7 +320,7 @@ func (client *AWSClient) GetInstancesByNodePoolID(nodePoolID string, clusterID s
 			clusterID,
 		},
 	}
-	output, err := client.Ec2Client.DescribeInstances(context.TODO(), &ec2.DescribeInstancesInput{
+	output, err := client.Ec2Client.DescribeInstances(client.requestContext(), &ec2.DescribeInstancesInput{
 		Filters: []types.Filter{
 			filter1,
 			filter2,
@@ -299,7 +343,7 @@ func (client *AWSClient) ListAvaliableRegionsFromAWS() ([]types.Region, error) {
 	optIn := "opted-in"
 	filter := types.Filter{Name: &optInStatus, Values: []string{optInNotRequired, optIn}}
 
-	output, err := client.Ec2Client.DescribeRegions(context.TODO(), &ec2.DescribeRegionsInput{
+	output, err := client.Ec2Client.DescribeRegions(client.requestContext(), &ec2.DescribeRegionsInput{
 		Filters: []types.Filter{
 			filter,
 		},
@@ -34,14 +34,13 @@ var _ = Describe("WaitForInstancesRunning", func() {
 		mockCtrl.Finish()
 	})
 
-	It("returns true when the SDK waiter reports all instances are status-ok", func() {
+	It("returns true when all instances are status-ok", func() {
 		instanceID := "i-0abc123"
 
-		// The InstanceStatusOkWaiter calls DescribeInstanceStatus until
-		// all instances have InstanceStatus.Status == ok.
-		// The SDK waiter injects an extra option function (3rd variadic arg).
+		// The waiter calls DescribeInstanceStatus until all instances
+		// have InstanceStatus.Status == ok.
 		mockEC2Client.EXPECT().
-			DescribeInstanceStatus(gomock.Any(), gomock.Any(), gomock.Any()).
+			DescribeInstanceStatus(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeInstanceStatusOutput{
 				InstanceStatuses: []types.InstanceStatus{
 					{
@@ -59,14 +58,14 @@ var _ = Describe("WaitForInstancesRunning", func() {
 		Expect(allRunning).To(BeTrue())
 	})
 
-	It("propagates errors returned by the SDK waiter", func() {
-		// Use a short deadline so the waiter gives up after the first failed
-		// call instead of retrying for the full timeout duration.
+	It("propagates errors returned by the API", func() {
+		// Use a short deadline so the test cannot hang if the error were
+		// retried instead of returned.
 		shortCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 		defer cancel()
 
 		mockEC2Client.EXPECT().
-			DescribeInstanceStatus(gomock.Any(), gomock.Any(), gomock.Any()).
+			DescribeInstanceStatus(gomock.Any(), gomock.Any()).
 			Return(nil, fmt.Errorf("api error: request throttled")).
 			AnyTimes()
 
@@ -95,14 +94,13 @@ var _ = Describe("WaitForInstancesTerminated", func() {
 		mockCtrl.Finish()
 	})
 
-	It("returns true when the SDK waiter reports all instances are terminated", func() {
+	It("returns true when all instances are terminated", func() {
 		instanceID := "i-dead"
 
-		// The InstanceTerminatedWaiter calls DescribeInstances until
-		// all instances have State.Name == terminated.
-		// The SDK waiter injects an extra option function (3rd variadic arg).
+		// The waiter calls DescribeInstances until all instances have
+		// State.Name == terminated.
 		mockEC2Client.EXPECT().
-			DescribeInstances(gomock.Any(), gomock.Any(), gomock.Any()).
+			DescribeInstances(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeInstancesOutput{
 				Reservations: []types.Reservation{
 					{
@@ -122,13 +120,13 @@ var _ = Describe("WaitForInstancesTerminated", func() {
 		Expect(allTerminated).To(BeTrue())
 	})
 
-	It("propagates errors returned by the SDK waiter", func() {
+	It("propagates errors returned by the API", func() {
 		// Use a short deadline so the waiter gives up quickly.
 		shortCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 		defer cancel()
 
 		mockEC2Client.EXPECT().
-			DescribeInstances(gomock.Any(), gomock.Any(), gomock.Any()).
+			DescribeInstances(gomock.Any(), gomock.Any()).
 			Return(nil, fmt.Errorf("api error: unauthorized")).
 			AnyTimes()
 
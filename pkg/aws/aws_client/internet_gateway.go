@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -14,7 +12,7 @@ func (client *AWSClient) CreateInternetGateway() (*ec2.CreateInternetGatewayOutp
 		DryRun:            nil,
 		TagSpecifications: nil,
 	}
-	respCreateInternetGateway, err := client.Ec2Client.CreateInternetGateway(context.TODO(), inputCreateInternetGateway)
+	respCreateInternetGateway, err := client.Ec2Client.CreateInternetGateway(client.requestContext(), inputCreateInternetGateway)
 	if err != nil {
 		log.LogError("Create igw error %s", err.Error())
 		return nil, err
@@ -30,7 +28,7 @@ func (client *AWSClient) AttachInternetGateway(internetGatewayID string, vpcID s
 		VpcId:             aws.String(vpcID),
 		DryRun:            nil,
 	}
-	resp, err := client.Ec2Client.AttachInternetGateway(context.TODO(), input)
+	resp, err := client.Ec2Client.AttachInternetGateway(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Attach igw error %s", err.Error())
 		return nil, err
@@ -45,7 +43,7 @@ func (client *AWSClient) DetachInternetGateway(internetGatewayID string, vpcID s
 		VpcId:             aws.String(vpcID),
 		DryRun:            nil,
 	}
-	resp, err := client.Ec2Client.DetachInternetGateway(context.TODO(), input)
+	resp, err := client.Ec2Client.DetachInternetGateway(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Detach igw %s error  from vpc %s:"+err.Error(), internetGatewayID, vpcID)
 		return nil, err
@@ -66,7 +64,7 @@ func (client *AWSClient) ListInternetGateWay(vpcID string) ([]types.InternetGate
 	input := &ec2.DescribeInternetGatewaysInput{
 		Filters: filter,
 	}
-	resp, err := client.Ec2Client.DescribeInternetGateways(context.TODO(), input)
+	resp, err := client.Ec2Client.DescribeInternetGateways(client.requestContext(), input)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +75,7 @@ func (client *AWSClient) DeleteInternetGateway(internetGatewayID string) (*ec2.D
 		InternetGatewayId: aws.String(internetGatewayID),
 		DryRun:            nil,
 	}
-	respDeleteInternetGateway, err := client.Ec2Client.DeleteInternetGateway(context.TODO(), inputDeleteInternetGateway)
+	respDeleteInternetGateway, err := client.Ec2Client.DeleteInternetGateway(client.requestContext(), inputDeleteInternetGateway)
 	if err != nil {
 		log.LogError("Delete igw error %s", err.Error())
 		return nil, err
@@ -85,3 +83,48 @@ func (client *AWSClient) DeleteInternetGateway(internetGatewayID string) (*ec2.D
 	log.LogInfo("Delete igw success: %s", internetGatewayID)
 	return respDeleteInternetGateway, err
 }
+
+// CreateEgressOnlyInternetGateway creates an egress-only internet gateway attached to the vpc, the
+// IPv6 counterpart of a NAT gateway.
+func (client *AWSClient) CreateEgressOnlyInternetGateway(vpcID string) (*types.EgressOnlyInternetGateway, error) {
+	input := &ec2.CreateEgressOnlyInternetGatewayInput{
+		VpcId: aws.String(vpcID),
+	}
+	resp, err := client.Ec2Client.CreateEgressOnlyInternetGateway(client.requestContext(), input)
+	if err != nil {
+		log.LogError("Create egress-only igw for vpc %s error %s", vpcID, err.Error())
+		return nil, err
+	}
+	log.LogInfo("Create egress-only igw success: %s", *resp.EgressOnlyInternetGateway.EgressOnlyInternetGatewayId)
+	return resp.EgressOnlyInternetGateway, err
+}
+
+// ListEgressOnlyInternetGateways lists the egress-only internet gateways attached to the vpc.
+func (client *AWSClient) ListEgressOnlyInternetGateways(vpcID string) ([]types.EgressOnlyInternetGateway, error) {
+	input := &ec2.DescribeEgressOnlyInternetGatewaysInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("attachment.vpc-id"),
+				Values: []string{vpcID},
+			},
+		},
+	}
+	resp, err := client.Ec2Client.DescribeEgressOnlyInternetGateways(client.requestContext(), input)
+	if err != nil {
+		return nil, err
+	}
+	return resp.EgressOnlyInternetGateways, err
+}
+
+func (client *AWSClient) DeleteEgressOnlyInternetGateway(gatewayID string) error {
+	input := &ec2.DeleteEgressOnlyInternetGatewayInput{
+		EgressOnlyInternetGatewayId: aws.String(gatewayID),
+	}
+	_, err := client.Ec2Client.DeleteEgressOnlyInternetGateway(client.requestContext(), input)
+	if err != nil {
+		log.LogError("Delete egress-only igw %s error %s", gatewayID, err.Error())
+		return err
+	}
+	log.LogInfo("Delete egress-only igw success: %s", gatewayID)
+	return nil
+}
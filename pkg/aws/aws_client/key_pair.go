@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 )
 
@@ -12,7 +10,7 @@ func (client *AWSClient) CreateKeyPair(keyName string) (*ec2.CreateKeyPairOutput
 		KeyName: &keyName,
 	}
 
-	output, err := client.Ec2Client.CreateKeyPair(context.TODO(), input)
+	output, err := client.Ec2Client.CreateKeyPair(client.requestContext(), input)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +22,7 @@ func (client *AWSClient) DeleteKeyPair(keyName string) (*ec2.DeleteKeyPairOutput
 		KeyName: &keyName,
 	}
 
-	output, err := client.Ec2Client.DeleteKeyPair(context.TODO(), input)
+	output, err := client.Ec2Client.DeleteKeyPair(client.requestContext(), input)
 	if err != nil {
 
 		return nil, err
@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/kms/types"
@@ -12,7 +10,7 @@ import (
 func (client *AWSClient) CreateKMSKeys(tagKey string, tagValue string, description string, policy string, multiRegion bool) (keyID string, keyArn string, err error) {
 	//Create the key
 
-	result, err := client.KmsClient.CreateKey(context.TODO(), &kms.CreateKeyInput{
+	result, err := client.KmsClient.CreateKey(client.requestContext(), &kms.CreateKeyInput{
 		Tags: []types.Tag{
 			{
 				TagKey:   aws.String(tagKey),
@@ -34,7 +32,7 @@ func (client *AWSClient) CreateKMSKeys(tagKey string, tagValue string, descripti
 
 func (client *AWSClient) DescribeKMSKeys(keyID string) (kms.DescribeKeyOutput, error) {
 	// Create the key
-	result, err := client.KmsClient.DescribeKey(context.TODO(), &kms.DescribeKeyInput{
+	result, err := client.KmsClient.DescribeKey(client.requestContext(), &kms.DescribeKeyInput{
 		KeyId: &keyID,
 	})
 	if err != nil {
@@ -43,7 +41,7 @@ func (client *AWSClient) DescribeKMSKeys(keyID string) (kms.DescribeKeyOutput, e
 	return *result, err
 }
 func (client *AWSClient) ScheduleKeyDeletion(kmsKeyId string, pendingWindowInDays int32) (*kms.ScheduleKeyDeletionOutput, error) {
-	result, err := client.KmsClient.ScheduleKeyDeletion(context.TODO(), &kms.ScheduleKeyDeletionInput{
+	result, err := client.KmsClient.ScheduleKeyDeletion(client.requestContext(), &kms.ScheduleKeyDeletionInput{
 		KeyId:               aws.String(kmsKeyId),
 		PendingWindowInDays: &pendingWindowInDays,
 	})
@@ -60,7 +58,7 @@ func (client *AWSClient) GetKMSPolicy(keyID string, policyName string) (kms.GetK
 	if policyName == "" {
 		policyName = "default"
 	}
-	result, err := client.KmsClient.GetKeyPolicy(context.TODO(), &kms.GetKeyPolicyInput{
+	result, err := client.KmsClient.GetKeyPolicy(client.requestContext(), &kms.GetKeyPolicyInput{
 		KeyId:      &keyID,
 		PolicyName: &policyName,
 	})
@@ -74,7 +72,7 @@ func (client *AWSClient) PutKMSPolicy(keyID string, policyName string, policy st
 	if policyName == "" {
 		policyName = "default"
 	}
-	result, err := client.KmsClient.PutKeyPolicy(context.TODO(), &kms.PutKeyPolicyInput{
+	result, err := client.KmsClient.PutKeyPolicy(client.requestContext(), &kms.PutKeyPolicyInput{
 		KeyId:      &keyID,
 		PolicyName: &policyName,
 		Policy:     &policy,
@@ -87,7 +85,7 @@ func (client *AWSClient) PutKMSPolicy(keyID string, policyName string, policy st
 
 func (client *AWSClient) TagKeys(kmsKeyId string, tagKey string, tagValue string) (*kms.TagResourceOutput, error) {
 
-	output, err := client.KmsClient.TagResource(context.TODO(), &kms.TagResourceInput{
+	output, err := client.KmsClient.TagResource(client.requestContext(), &kms.TagResourceInput{
 		KeyId: &kmsKeyId,
 		Tags: []types.Tag{
 			{
@@ -104,7 +102,7 @@ func (client *AWSClient) TagKeys(kmsKeyId string, tagKey string, tagValue string
 
 func (client *AWSClient) ListKMSKeys() (*kms.ListKeysOutput, error) {
 
-	result, err := client.KmsClient.ListKeys(context.TODO(), &kms.ListKeysInput{})
+	result, err := client.KmsClient.ListKeys(client.requestContext(), &kms.ListKeysInput{})
 	if err != nil {
 		log.LogError("Got error list key: %s", err)
 	}
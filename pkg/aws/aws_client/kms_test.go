@@ -0,0 +1,63 @@
+package aws_client_test
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+)
+
+var _ = Describe("KMS keys", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockKMSClient *MockKMSClientAPI
+		client        *AWSClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKMSClient = NewMockKMSClientAPI(mockCtrl)
+		client = &AWSClient{
+			KmsClient: mockKMSClient,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should create a tagged key", func() {
+		mockKMSClient.EXPECT().
+			CreateKey(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, input *kms.CreateKeyInput, _ ...func(*kms.Options)) (*kms.CreateKeyOutput, error) {
+				Expect(input.Tags).To(Equal([]types.Tag{{TagKey: aws.String("owner"), TagValue: aws.String("ci")}}))
+				Expect(aws.ToBool(input.MultiRegion)).To(BeTrue())
+				return &kms.CreateKeyOutput{KeyMetadata: &types.KeyMetadata{
+					KeyId: aws.String("key-1"),
+					Arn:   aws.String("arn:aws:kms:us-east-1:123456789012:key/key-1"),
+				}}, nil
+			})
+
+		keyID, keyArn, err := client.CreateKMSKeys("owner", "ci", "test key", "{}", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keyID).To(Equal("key-1"))
+		Expect(keyArn).To(HaveSuffix("key/key-1"))
+	})
+
+	It("should read the default key policy when no name is given", func() {
+		mockKMSClient.EXPECT().
+			GetKeyPolicy(gomock.Any(), &kms.GetKeyPolicyInput{
+				KeyId:      aws.String("key-1"),
+				PolicyName: aws.String("default"),
+			}).
+			Return(&kms.GetKeyPolicyOutput{Policy: aws.String("{}")}, nil)
+
+		out, err := client.GetKMSPolicy("key-1", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.ToString(out.Policy)).To(Equal("{}"))
+	})
+})
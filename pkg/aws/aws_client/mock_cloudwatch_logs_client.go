@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cloudwatch_logs_client_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cloudwatch_logs_client_interface.go -package=aws_client -destination=mock_cloudwatch_logs_client.go
+//
+
+// Package aws_client is a generated GoMock package.
+package aws_client
+
+import (
+	context "context"
+	reflect "reflect"
+
+	cloudwatchlogs "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCloudWatchLogsClientAPI is a mock of CloudWatchLogsClientAPI interface.
+type MockCloudWatchLogsClientAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloudWatchLogsClientAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockCloudWatchLogsClientAPIMockRecorder is the mock recorder for MockCloudWatchLogsClientAPI.
+type MockCloudWatchLogsClientAPIMockRecorder struct {
+	mock *MockCloudWatchLogsClientAPI
+}
+
+// NewMockCloudWatchLogsClientAPI creates a new mock instance.
+func NewMockCloudWatchLogsClientAPI(ctrl *gomock.Controller) *MockCloudWatchLogsClientAPI {
+	mock := &MockCloudWatchLogsClientAPI{ctrl: ctrl}
+	mock.recorder = &MockCloudWatchLogsClientAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloudWatchLogsClientAPI) EXPECT() *MockCloudWatchLogsClientAPIMockRecorder {
+	return m.recorder
+}
+
+// DeleteLogGroup mocks base method.
+func (m *MockCloudWatchLogsClientAPI) DeleteLogGroup(ctx context.Context, params *cloudwatchlogs.DeleteLogGroupInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DeleteLogGroupOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteLogGroup", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DeleteLogGroupOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLogGroup indicates an expected call of DeleteLogGroup.
+func (mr *MockCloudWatchLogsClientAPIMockRecorder) DeleteLogGroup(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogGroup", reflect.TypeOf((*MockCloudWatchLogsClientAPI)(nil).DeleteLogGroup), varargs...)
+}
+
+// DescribeLogGroups mocks base method.
+func (m *MockCloudWatchLogsClientAPI) DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeLogGroups", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeLogGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLogGroups indicates an expected call of DescribeLogGroups.
+func (mr *MockCloudWatchLogsClientAPIMockRecorder) DescribeLogGroups(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogGroups", reflect.TypeOf((*MockCloudWatchLogsClientAPI)(nil).DescribeLogGroups), varargs...)
+}
+
+// DescribeLogStreams mocks base method.
+func (m *MockCloudWatchLogsClientAPI) DescribeLogStreams(ctx context.Context, params *cloudwatchlogs.DescribeLogStreamsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeLogStreams", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeLogStreamsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLogStreams indicates an expected call of DescribeLogStreams.
+func (mr *MockCloudWatchLogsClientAPIMockRecorder) DescribeLogStreams(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogStreams", reflect.TypeOf((*MockCloudWatchLogsClientAPI)(nil).DescribeLogStreams), varargs...)
+}
+
+// ListTagsForResource mocks base method.
+func (m *MockCloudWatchLogsClientAPI) ListTagsForResource(ctx context.Context, params *cloudwatchlogs.ListTagsForResourceInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.ListTagsForResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTagsForResource", varargs...)
+	ret0, _ := ret[0].(*cloudwatchlogs.ListTagsForResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsForResource indicates an expected call of ListTagsForResource.
+func (mr *MockCloudWatchLogsClientAPIMockRecorder) ListTagsForResource(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForResource", reflect.TypeOf((*MockCloudWatchLogsClientAPI)(nil).ListTagsForResource), varargs...)
+}
@@ -5,6 +5,7 @@
 //
 //	mockgen -source=ec2_client_interface.go -package=aws_client -destination=mock_ec2_client.go
 //
+
 // Package aws_client is a generated GoMock package.
 package aws_client
 
@@ -20,6 +21,7 @@ import (
 type MockEC2ClientAPI struct {
 	ctrl     *gomock.Controller
 	recorder *MockEC2ClientAPIMockRecorder
+	isgomock struct{}
 }
 
 // MockEC2ClientAPIMockRecorder is the mock recorder for MockEC2ClientAPI.
@@ -39,6 +41,26 @@ func (m *MockEC2ClientAPI) EXPECT() *MockEC2ClientAPIMockRecorder {
 	return m.recorder
 }
 
+// AcceptVpcPeeringConnection mocks base method.
+func (m *MockEC2ClientAPI) AcceptVpcPeeringConnection(ctx context.Context, params *ec2.AcceptVpcPeeringConnectionInput, optFns ...func(*ec2.Options)) (*ec2.AcceptVpcPeeringConnectionOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AcceptVpcPeeringConnection", varargs...)
+	ret0, _ := ret[0].(*ec2.AcceptVpcPeeringConnectionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptVpcPeeringConnection indicates an expected call of AcceptVpcPeeringConnection.
+func (mr *MockEC2ClientAPIMockRecorder) AcceptVpcPeeringConnection(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptVpcPeeringConnection", reflect.TypeOf((*MockEC2ClientAPI)(nil).AcceptVpcPeeringConnection), varargs...)
+}
+
 // AllocateAddress mocks base method.
 func (m *MockEC2ClientAPI) AllocateAddress(ctx context.Context, params *ec2.AllocateAddressInput, optFns ...func(*ec2.Options)) (*ec2.AllocateAddressOutput, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +121,46 @@ func (mr *MockEC2ClientAPIMockRecorder) AssociateRouteTable(ctx, params any, opt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateRouteTable", reflect.TypeOf((*MockEC2ClientAPI)(nil).AssociateRouteTable), varargs...)
 }
 
+// AssociateTransitGatewayRouteTable mocks base method.
+func (m *MockEC2ClientAPI) AssociateTransitGatewayRouteTable(ctx context.Context, params *ec2.AssociateTransitGatewayRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.AssociateTransitGatewayRouteTableOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AssociateTransitGatewayRouteTable", varargs...)
+	ret0, _ := ret[0].(*ec2.AssociateTransitGatewayRouteTableOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateTransitGatewayRouteTable indicates an expected call of AssociateTransitGatewayRouteTable.
+func (mr *MockEC2ClientAPIMockRecorder) AssociateTransitGatewayRouteTable(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateTransitGatewayRouteTable", reflect.TypeOf((*MockEC2ClientAPI)(nil).AssociateTransitGatewayRouteTable), varargs...)
+}
+
+// AssociateVpcCidrBlock mocks base method.
+func (m *MockEC2ClientAPI) AssociateVpcCidrBlock(ctx context.Context, params *ec2.AssociateVpcCidrBlockInput, optFns ...func(*ec2.Options)) (*ec2.AssociateVpcCidrBlockOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AssociateVpcCidrBlock", varargs...)
+	ret0, _ := ret[0].(*ec2.AssociateVpcCidrBlockOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateVpcCidrBlock indicates an expected call of AssociateVpcCidrBlock.
+func (mr *MockEC2ClientAPIMockRecorder) AssociateVpcCidrBlock(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateVpcCidrBlock", reflect.TypeOf((*MockEC2ClientAPI)(nil).AssociateVpcCidrBlock), varargs...)
+}
+
 // AttachInternetGateway mocks base method.
 func (m *MockEC2ClientAPI) AttachInternetGateway(ctx context.Context, params *ec2.AttachInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.AttachInternetGatewayOutput, error) {
 	m.ctrl.T.Helper()
@@ -199,6 +261,26 @@ func (mr *MockEC2ClientAPIMockRecorder) CreateCapacityReservation(ctx, params an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCapacityReservation", reflect.TypeOf((*MockEC2ClientAPI)(nil).CreateCapacityReservation), varargs...)
 }
 
+// CreateEgressOnlyInternetGateway mocks base method.
+func (m *MockEC2ClientAPI) CreateEgressOnlyInternetGateway(ctx context.Context, params *ec2.CreateEgressOnlyInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.CreateEgressOnlyInternetGatewayOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateEgressOnlyInternetGateway", varargs...)
+	ret0, _ := ret[0].(*ec2.CreateEgressOnlyInternetGatewayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEgressOnlyInternetGateway indicates an expected call of CreateEgressOnlyInternetGateway.
+func (mr *MockEC2ClientAPIMockRecorder) CreateEgressOnlyInternetGateway(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEgressOnlyInternetGateway", reflect.TypeOf((*MockEC2ClientAPI)(nil).CreateEgressOnlyInternetGateway), varargs...)
+}
+
 // CreateInternetGateway mocks base method.
 func (m *MockEC2ClientAPI) CreateInternetGateway(ctx context.Context, params *ec2.CreateInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.CreateInternetGatewayOutput, error) {
 	m.ctrl.T.Helper()
@@ -379,6 +461,86 @@ func (mr *MockEC2ClientAPIMockRecorder) CreateTags(ctx, params any, optFns ...an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTags", reflect.TypeOf((*MockEC2ClientAPI)(nil).CreateTags), varargs...)
 }
 
+// CreateTransitGateway mocks base method.
+func (m *MockEC2ClientAPI) CreateTransitGateway(ctx context.Context, params *ec2.CreateTransitGatewayInput, optFns ...func(*ec2.Options)) (*ec2.CreateTransitGatewayOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateTransitGateway", varargs...)
+	ret0, _ := ret[0].(*ec2.CreateTransitGatewayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransitGateway indicates an expected call of CreateTransitGateway.
+func (mr *MockEC2ClientAPIMockRecorder) CreateTransitGateway(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransitGateway", reflect.TypeOf((*MockEC2ClientAPI)(nil).CreateTransitGateway), varargs...)
+}
+
+// CreateTransitGatewayRoute mocks base method.
+func (m *MockEC2ClientAPI) CreateTransitGatewayRoute(ctx context.Context, params *ec2.CreateTransitGatewayRouteInput, optFns ...func(*ec2.Options)) (*ec2.CreateTransitGatewayRouteOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateTransitGatewayRoute", varargs...)
+	ret0, _ := ret[0].(*ec2.CreateTransitGatewayRouteOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransitGatewayRoute indicates an expected call of CreateTransitGatewayRoute.
+func (mr *MockEC2ClientAPIMockRecorder) CreateTransitGatewayRoute(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransitGatewayRoute", reflect.TypeOf((*MockEC2ClientAPI)(nil).CreateTransitGatewayRoute), varargs...)
+}
+
+// CreateTransitGatewayRouteTable mocks base method.
+func (m *MockEC2ClientAPI) CreateTransitGatewayRouteTable(ctx context.Context, params *ec2.CreateTransitGatewayRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.CreateTransitGatewayRouteTableOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateTransitGatewayRouteTable", varargs...)
+	ret0, _ := ret[0].(*ec2.CreateTransitGatewayRouteTableOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransitGatewayRouteTable indicates an expected call of CreateTransitGatewayRouteTable.
+func (mr *MockEC2ClientAPIMockRecorder) CreateTransitGatewayRouteTable(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransitGatewayRouteTable", reflect.TypeOf((*MockEC2ClientAPI)(nil).CreateTransitGatewayRouteTable), varargs...)
+}
+
+// CreateTransitGatewayVpcAttachment mocks base method.
+func (m *MockEC2ClientAPI) CreateTransitGatewayVpcAttachment(ctx context.Context, params *ec2.CreateTransitGatewayVpcAttachmentInput, optFns ...func(*ec2.Options)) (*ec2.CreateTransitGatewayVpcAttachmentOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateTransitGatewayVpcAttachment", varargs...)
+	ret0, _ := ret[0].(*ec2.CreateTransitGatewayVpcAttachmentOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransitGatewayVpcAttachment indicates an expected call of CreateTransitGatewayVpcAttachment.
+func (mr *MockEC2ClientAPIMockRecorder) CreateTransitGatewayVpcAttachment(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransitGatewayVpcAttachment", reflect.TypeOf((*MockEC2ClientAPI)(nil).CreateTransitGatewayVpcAttachment), varargs...)
+}
+
 // CreateVolume mocks base method.
 func (m *MockEC2ClientAPI) CreateVolume(ctx context.Context, params *ec2.CreateVolumeInput, optFns ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
 	m.ctrl.T.Helper()
@@ -439,6 +601,46 @@ func (mr *MockEC2ClientAPIMockRecorder) CreateVpcEndpoint(ctx, params any, optFn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVpcEndpoint", reflect.TypeOf((*MockEC2ClientAPI)(nil).CreateVpcEndpoint), varargs...)
 }
 
+// CreateVpcPeeringConnection mocks base method.
+func (m *MockEC2ClientAPI) CreateVpcPeeringConnection(ctx context.Context, params *ec2.CreateVpcPeeringConnectionInput, optFns ...func(*ec2.Options)) (*ec2.CreateVpcPeeringConnectionOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateVpcPeeringConnection", varargs...)
+	ret0, _ := ret[0].(*ec2.CreateVpcPeeringConnectionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVpcPeeringConnection indicates an expected call of CreateVpcPeeringConnection.
+func (mr *MockEC2ClientAPIMockRecorder) CreateVpcPeeringConnection(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVpcPeeringConnection", reflect.TypeOf((*MockEC2ClientAPI)(nil).CreateVpcPeeringConnection), varargs...)
+}
+
+// DeleteEgressOnlyInternetGateway mocks base method.
+func (m *MockEC2ClientAPI) DeleteEgressOnlyInternetGateway(ctx context.Context, params *ec2.DeleteEgressOnlyInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.DeleteEgressOnlyInternetGatewayOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteEgressOnlyInternetGateway", varargs...)
+	ret0, _ := ret[0].(*ec2.DeleteEgressOnlyInternetGatewayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEgressOnlyInternetGateway indicates an expected call of DeleteEgressOnlyInternetGateway.
+func (mr *MockEC2ClientAPIMockRecorder) DeleteEgressOnlyInternetGateway(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEgressOnlyInternetGateway", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteEgressOnlyInternetGateway), varargs...)
+}
+
 // DeleteInternetGateway mocks base method.
 func (m *MockEC2ClientAPI) DeleteInternetGateway(ctx context.Context, params *ec2.DeleteInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.DeleteInternetGatewayOutput, error) {
 	m.ctrl.T.Helper()
@@ -519,44 +721,44 @@ func (mr *MockEC2ClientAPIMockRecorder) DeleteNetworkAclEntry(ctx, params any, o
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNetworkAclEntry", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteNetworkAclEntry), varargs...)
 }
 
-// DetachNetworkInterface mocks base method.
-func (m *MockEC2ClientAPI) DetachNetworkInterface(ctx context.Context, params *ec2.DetachNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DetachNetworkInterfaceOutput, error) {
+// DeleteNetworkInterface mocks base method.
+func (m *MockEC2ClientAPI) DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error) {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx, params}
 	for _, a := range optFns {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "DetachNetworkInterface", varargs...)
-	ret0, _ := ret[0].(*ec2.DetachNetworkInterfaceOutput)
+	ret := m.ctrl.Call(m, "DeleteNetworkInterface", varargs...)
+	ret0, _ := ret[0].(*ec2.DeleteNetworkInterfaceOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DetachNetworkInterface indicates an expected call of DetachNetworkInterface.
-func (mr *MockEC2ClientAPIMockRecorder) DetachNetworkInterface(ctx, params any, optFns ...any) *gomock.Call {
+// DeleteNetworkInterface indicates an expected call of DeleteNetworkInterface.
+func (mr *MockEC2ClientAPIMockRecorder) DeleteNetworkInterface(ctx, params any, optFns ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]any{ctx, params}, optFns...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachNetworkInterface", reflect.TypeOf((*MockEC2ClientAPI)(nil).DetachNetworkInterface), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNetworkInterface", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteNetworkInterface), varargs...)
 }
 
-// DeleteNetworkInterface mocks base method.
-func (m *MockEC2ClientAPI) DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error) {
+// DeleteRoute mocks base method.
+func (m *MockEC2ClientAPI) DeleteRoute(ctx context.Context, params *ec2.DeleteRouteInput, optFns ...func(*ec2.Options)) (*ec2.DeleteRouteOutput, error) {
 	m.ctrl.T.Helper()
 	varargs := []any{ctx, params}
 	for _, a := range optFns {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "DeleteNetworkInterface", varargs...)
-	ret0, _ := ret[0].(*ec2.DeleteNetworkInterfaceOutput)
+	ret := m.ctrl.Call(m, "DeleteRoute", varargs...)
+	ret0, _ := ret[0].(*ec2.DeleteRouteOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DeleteNetworkInterface indicates an expected call of DeleteNetworkInterface.
-func (mr *MockEC2ClientAPIMockRecorder) DeleteNetworkInterface(ctx, params any, optFns ...any) *gomock.Call {
+// DeleteRoute indicates an expected call of DeleteRoute.
+func (mr *MockEC2ClientAPIMockRecorder) DeleteRoute(ctx, params any, optFns ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]any{ctx, params}, optFns...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNetworkInterface", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteNetworkInterface), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoute", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteRoute), varargs...)
 }
 
 // DeleteRouteTable mocks base method.
@@ -639,6 +841,86 @@ func (mr *MockEC2ClientAPIMockRecorder) DeleteTags(ctx, params any, optFns ...an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTags", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteTags), varargs...)
 }
 
+// DeleteTransitGateway mocks base method.
+func (m *MockEC2ClientAPI) DeleteTransitGateway(ctx context.Context, params *ec2.DeleteTransitGatewayInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTransitGatewayOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteTransitGateway", varargs...)
+	ret0, _ := ret[0].(*ec2.DeleteTransitGatewayOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTransitGateway indicates an expected call of DeleteTransitGateway.
+func (mr *MockEC2ClientAPIMockRecorder) DeleteTransitGateway(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransitGateway", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteTransitGateway), varargs...)
+}
+
+// DeleteTransitGatewayRoute mocks base method.
+func (m *MockEC2ClientAPI) DeleteTransitGatewayRoute(ctx context.Context, params *ec2.DeleteTransitGatewayRouteInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTransitGatewayRouteOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteTransitGatewayRoute", varargs...)
+	ret0, _ := ret[0].(*ec2.DeleteTransitGatewayRouteOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTransitGatewayRoute indicates an expected call of DeleteTransitGatewayRoute.
+func (mr *MockEC2ClientAPIMockRecorder) DeleteTransitGatewayRoute(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransitGatewayRoute", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteTransitGatewayRoute), varargs...)
+}
+
+// DeleteTransitGatewayRouteTable mocks base method.
+func (m *MockEC2ClientAPI) DeleteTransitGatewayRouteTable(ctx context.Context, params *ec2.DeleteTransitGatewayRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTransitGatewayRouteTableOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteTransitGatewayRouteTable", varargs...)
+	ret0, _ := ret[0].(*ec2.DeleteTransitGatewayRouteTableOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTransitGatewayRouteTable indicates an expected call of DeleteTransitGatewayRouteTable.
+func (mr *MockEC2ClientAPIMockRecorder) DeleteTransitGatewayRouteTable(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransitGatewayRouteTable", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteTransitGatewayRouteTable), varargs...)
+}
+
+// DeleteTransitGatewayVpcAttachment mocks base method.
+func (m *MockEC2ClientAPI) DeleteTransitGatewayVpcAttachment(ctx context.Context, params *ec2.DeleteTransitGatewayVpcAttachmentInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTransitGatewayVpcAttachmentOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteTransitGatewayVpcAttachment", varargs...)
+	ret0, _ := ret[0].(*ec2.DeleteTransitGatewayVpcAttachmentOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTransitGatewayVpcAttachment indicates an expected call of DeleteTransitGatewayVpcAttachment.
+func (mr *MockEC2ClientAPIMockRecorder) DeleteTransitGatewayVpcAttachment(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransitGatewayVpcAttachment", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteTransitGatewayVpcAttachment), varargs...)
+}
+
 // DeleteVolume mocks base method.
 func (m *MockEC2ClientAPI) DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
 	m.ctrl.T.Helper()
@@ -699,6 +981,26 @@ func (mr *MockEC2ClientAPIMockRecorder) DeleteVpcEndpoints(ctx, params any, optF
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVpcEndpoints", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteVpcEndpoints), varargs...)
 }
 
+// DeleteVpcPeeringConnection mocks base method.
+func (m *MockEC2ClientAPI) DeleteVpcPeeringConnection(ctx context.Context, params *ec2.DeleteVpcPeeringConnectionInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVpcPeeringConnectionOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteVpcPeeringConnection", varargs...)
+	ret0, _ := ret[0].(*ec2.DeleteVpcPeeringConnectionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVpcPeeringConnection indicates an expected call of DeleteVpcPeeringConnection.
+func (mr *MockEC2ClientAPIMockRecorder) DeleteVpcPeeringConnection(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVpcPeeringConnection", reflect.TypeOf((*MockEC2ClientAPI)(nil).DeleteVpcPeeringConnection), varargs...)
+}
+
 // DescribeAddresses mocks base method.
 func (m *MockEC2ClientAPI) DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
 	m.ctrl.T.Helper()
@@ -759,6 +1061,26 @@ func (mr *MockEC2ClientAPIMockRecorder) DescribeCapacityReservations(ctx, params
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCapacityReservations", reflect.TypeOf((*MockEC2ClientAPI)(nil).DescribeCapacityReservations), varargs...)
 }
 
+// DescribeEgressOnlyInternetGateways mocks base method.
+func (m *MockEC2ClientAPI) DescribeEgressOnlyInternetGateways(ctx context.Context, params *ec2.DescribeEgressOnlyInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeEgressOnlyInternetGatewaysOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeEgressOnlyInternetGateways", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeEgressOnlyInternetGatewaysOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeEgressOnlyInternetGateways indicates an expected call of DescribeEgressOnlyInternetGateways.
+func (mr *MockEC2ClientAPIMockRecorder) DescribeEgressOnlyInternetGateways(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeEgressOnlyInternetGateways", reflect.TypeOf((*MockEC2ClientAPI)(nil).DescribeEgressOnlyInternetGateways), varargs...)
+}
+
 // DescribeImages mocks base method.
 func (m *MockEC2ClientAPI) DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
 	m.ctrl.T.Helper()
@@ -1059,6 +1381,66 @@ func (mr *MockEC2ClientAPIMockRecorder) DescribeSubnets(ctx, params any, optFns
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSubnets", reflect.TypeOf((*MockEC2ClientAPI)(nil).DescribeSubnets), varargs...)
 }
 
+// DescribeTransitGatewayRouteTables mocks base method.
+func (m *MockEC2ClientAPI) DescribeTransitGatewayRouteTables(ctx context.Context, params *ec2.DescribeTransitGatewayRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayRouteTablesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeTransitGatewayRouteTables", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeTransitGatewayRouteTablesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTransitGatewayRouteTables indicates an expected call of DescribeTransitGatewayRouteTables.
+func (mr *MockEC2ClientAPIMockRecorder) DescribeTransitGatewayRouteTables(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTransitGatewayRouteTables", reflect.TypeOf((*MockEC2ClientAPI)(nil).DescribeTransitGatewayRouteTables), varargs...)
+}
+
+// DescribeTransitGatewayVpcAttachments mocks base method.
+func (m *MockEC2ClientAPI) DescribeTransitGatewayVpcAttachments(ctx context.Context, params *ec2.DescribeTransitGatewayVpcAttachmentsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayVpcAttachmentsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeTransitGatewayVpcAttachments", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeTransitGatewayVpcAttachmentsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTransitGatewayVpcAttachments indicates an expected call of DescribeTransitGatewayVpcAttachments.
+func (mr *MockEC2ClientAPIMockRecorder) DescribeTransitGatewayVpcAttachments(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTransitGatewayVpcAttachments", reflect.TypeOf((*MockEC2ClientAPI)(nil).DescribeTransitGatewayVpcAttachments), varargs...)
+}
+
+// DescribeTransitGateways mocks base method.
+func (m *MockEC2ClientAPI) DescribeTransitGateways(ctx context.Context, params *ec2.DescribeTransitGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewaysOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeTransitGateways", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeTransitGatewaysOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTransitGateways indicates an expected call of DescribeTransitGateways.
+func (mr *MockEC2ClientAPIMockRecorder) DescribeTransitGateways(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTransitGateways", reflect.TypeOf((*MockEC2ClientAPI)(nil).DescribeTransitGateways), varargs...)
+}
+
 // DescribeVolumes mocks base method.
 func (m *MockEC2ClientAPI) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
 	m.ctrl.T.Helper()
@@ -1119,6 +1501,26 @@ func (mr *MockEC2ClientAPIMockRecorder) DescribeVpcEndpoints(ctx, params any, op
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeVpcEndpoints", reflect.TypeOf((*MockEC2ClientAPI)(nil).DescribeVpcEndpoints), varargs...)
 }
 
+// DescribeVpcPeeringConnections mocks base method.
+func (m *MockEC2ClientAPI) DescribeVpcPeeringConnections(ctx context.Context, params *ec2.DescribeVpcPeeringConnectionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcPeeringConnectionsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeVpcPeeringConnections", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeVpcPeeringConnectionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeVpcPeeringConnections indicates an expected call of DescribeVpcPeeringConnections.
+func (mr *MockEC2ClientAPIMockRecorder) DescribeVpcPeeringConnections(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeVpcPeeringConnections", reflect.TypeOf((*MockEC2ClientAPI)(nil).DescribeVpcPeeringConnections), varargs...)
+}
+
 // DescribeVpcs mocks base method.
 func (m *MockEC2ClientAPI) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
 	m.ctrl.T.Helper()
@@ -1159,6 +1561,46 @@ func (mr *MockEC2ClientAPIMockRecorder) DetachInternetGateway(ctx, params any, o
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachInternetGateway", reflect.TypeOf((*MockEC2ClientAPI)(nil).DetachInternetGateway), varargs...)
 }
 
+// DetachNetworkInterface mocks base method.
+func (m *MockEC2ClientAPI) DetachNetworkInterface(ctx context.Context, params *ec2.DetachNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DetachNetworkInterfaceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DetachNetworkInterface", varargs...)
+	ret0, _ := ret[0].(*ec2.DetachNetworkInterfaceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachNetworkInterface indicates an expected call of DetachNetworkInterface.
+func (mr *MockEC2ClientAPIMockRecorder) DetachNetworkInterface(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachNetworkInterface", reflect.TypeOf((*MockEC2ClientAPI)(nil).DetachNetworkInterface), varargs...)
+}
+
+// DisableTransitGatewayRouteTablePropagation mocks base method.
+func (m *MockEC2ClientAPI) DisableTransitGatewayRouteTablePropagation(ctx context.Context, params *ec2.DisableTransitGatewayRouteTablePropagationInput, optFns ...func(*ec2.Options)) (*ec2.DisableTransitGatewayRouteTablePropagationOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DisableTransitGatewayRouteTablePropagation", varargs...)
+	ret0, _ := ret[0].(*ec2.DisableTransitGatewayRouteTablePropagationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableTransitGatewayRouteTablePropagation indicates an expected call of DisableTransitGatewayRouteTablePropagation.
+func (mr *MockEC2ClientAPIMockRecorder) DisableTransitGatewayRouteTablePropagation(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTransitGatewayRouteTablePropagation", reflect.TypeOf((*MockEC2ClientAPI)(nil).DisableTransitGatewayRouteTablePropagation), varargs...)
+}
+
 // DisassociateAddress mocks base method.
 func (m *MockEC2ClientAPI) DisassociateAddress(ctx context.Context, params *ec2.DisassociateAddressInput, optFns ...func(*ec2.Options)) (*ec2.DisassociateAddressOutput, error) {
 	m.ctrl.T.Helper()
@@ -1199,6 +1641,66 @@ func (mr *MockEC2ClientAPIMockRecorder) DisassociateRouteTable(ctx, params any,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateRouteTable", reflect.TypeOf((*MockEC2ClientAPI)(nil).DisassociateRouteTable), varargs...)
 }
 
+// DisassociateTransitGatewayRouteTable mocks base method.
+func (m *MockEC2ClientAPI) DisassociateTransitGatewayRouteTable(ctx context.Context, params *ec2.DisassociateTransitGatewayRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.DisassociateTransitGatewayRouteTableOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DisassociateTransitGatewayRouteTable", varargs...)
+	ret0, _ := ret[0].(*ec2.DisassociateTransitGatewayRouteTableOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisassociateTransitGatewayRouteTable indicates an expected call of DisassociateTransitGatewayRouteTable.
+func (mr *MockEC2ClientAPIMockRecorder) DisassociateTransitGatewayRouteTable(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateTransitGatewayRouteTable", reflect.TypeOf((*MockEC2ClientAPI)(nil).DisassociateTransitGatewayRouteTable), varargs...)
+}
+
+// EnableTransitGatewayRouteTablePropagation mocks base method.
+func (m *MockEC2ClientAPI) EnableTransitGatewayRouteTablePropagation(ctx context.Context, params *ec2.EnableTransitGatewayRouteTablePropagationInput, optFns ...func(*ec2.Options)) (*ec2.EnableTransitGatewayRouteTablePropagationOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EnableTransitGatewayRouteTablePropagation", varargs...)
+	ret0, _ := ret[0].(*ec2.EnableTransitGatewayRouteTablePropagationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTransitGatewayRouteTablePropagation indicates an expected call of EnableTransitGatewayRouteTablePropagation.
+func (mr *MockEC2ClientAPIMockRecorder) EnableTransitGatewayRouteTablePropagation(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTransitGatewayRouteTablePropagation", reflect.TypeOf((*MockEC2ClientAPI)(nil).EnableTransitGatewayRouteTablePropagation), varargs...)
+}
+
+// GetTransitGatewayRouteTablePropagations mocks base method.
+func (m *MockEC2ClientAPI) GetTransitGatewayRouteTablePropagations(ctx context.Context, params *ec2.GetTransitGatewayRouteTablePropagationsInput, optFns ...func(*ec2.Options)) (*ec2.GetTransitGatewayRouteTablePropagationsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetTransitGatewayRouteTablePropagations", varargs...)
+	ret0, _ := ret[0].(*ec2.GetTransitGatewayRouteTablePropagationsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransitGatewayRouteTablePropagations indicates an expected call of GetTransitGatewayRouteTablePropagations.
+func (mr *MockEC2ClientAPIMockRecorder) GetTransitGatewayRouteTablePropagations(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransitGatewayRouteTablePropagations", reflect.TypeOf((*MockEC2ClientAPI)(nil).GetTransitGatewayRouteTablePropagations), varargs...)
+}
+
 // ModifyVpcAttribute mocks base method.
 func (m *MockEC2ClientAPI) ModifyVpcAttribute(ctx context.Context, params *ec2.ModifyVpcAttributeInput, optFns ...func(*ec2.Options)) (*ec2.ModifyVpcAttributeOutput, error) {
 	m.ctrl.T.Helper()
@@ -1219,6 +1721,26 @@ func (mr *MockEC2ClientAPIMockRecorder) ModifyVpcAttribute(ctx, params any, optF
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyVpcAttribute", reflect.TypeOf((*MockEC2ClientAPI)(nil).ModifyVpcAttribute), varargs...)
 }
 
+// ModifyVpcEndpoint mocks base method.
+func (m *MockEC2ClientAPI) ModifyVpcEndpoint(ctx context.Context, params *ec2.ModifyVpcEndpointInput, optFns ...func(*ec2.Options)) (*ec2.ModifyVpcEndpointOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ModifyVpcEndpoint", varargs...)
+	ret0, _ := ret[0].(*ec2.ModifyVpcEndpointOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModifyVpcEndpoint indicates an expected call of ModifyVpcEndpoint.
+func (mr *MockEC2ClientAPIMockRecorder) ModifyVpcEndpoint(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyVpcEndpoint", reflect.TypeOf((*MockEC2ClientAPI)(nil).ModifyVpcEndpoint), varargs...)
+}
+
 // ReleaseAddress mocks base method.
 func (m *MockEC2ClientAPI) ReleaseAddress(ctx context.Context, params *ec2.ReleaseAddressInput, optFns ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error) {
 	m.ctrl.T.Helper()
@@ -1299,6 +1821,26 @@ func (mr *MockEC2ClientAPIMockRecorder) RunInstances(ctx, params any, optFns ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunInstances", reflect.TypeOf((*MockEC2ClientAPI)(nil).RunInstances), varargs...)
 }
 
+// SearchTransitGatewayRoutes mocks base method.
+func (m *MockEC2ClientAPI) SearchTransitGatewayRoutes(ctx context.Context, params *ec2.SearchTransitGatewayRoutesInput, optFns ...func(*ec2.Options)) (*ec2.SearchTransitGatewayRoutesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SearchTransitGatewayRoutes", varargs...)
+	ret0, _ := ret[0].(*ec2.SearchTransitGatewayRoutesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransitGatewayRoutes indicates an expected call of SearchTransitGatewayRoutes.
+func (mr *MockEC2ClientAPIMockRecorder) SearchTransitGatewayRoutes(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransitGatewayRoutes", reflect.TypeOf((*MockEC2ClientAPI)(nil).SearchTransitGatewayRoutes), varargs...)
+}
+
 // TerminateInstances mocks base method.
 func (m *MockEC2ClientAPI) TerminateInstances(ctx context.Context, params *ec2.TerminateInstancesInput, optFns ...func(*ec2.Options)) (*ec2.TerminateInstancesOutput, error) {
 	m.ctrl.T.Helper()
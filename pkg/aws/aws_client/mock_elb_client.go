@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: elb_client_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=elb_client_interface.go -package=aws_client -destination=mock_elb_client.go
+//
+
+// Package aws_client is a generated GoMock package.
+package aws_client
+
+import (
+	context "context"
+	reflect "reflect"
+
+	elasticloadbalancingv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockELBClientAPI is a mock of ELBClientAPI interface.
+type MockELBClientAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockELBClientAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockELBClientAPIMockRecorder is the mock recorder for MockELBClientAPI.
+type MockELBClientAPIMockRecorder struct {
+	mock *MockELBClientAPI
+}
+
+// NewMockELBClientAPI creates a new mock instance.
+func NewMockELBClientAPI(ctrl *gomock.Controller) *MockELBClientAPI {
+	mock := &MockELBClientAPI{ctrl: ctrl}
+	mock.recorder = &MockELBClientAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockELBClientAPI) EXPECT() *MockELBClientAPIMockRecorder {
+	return m.recorder
+}
+
+// DeleteLoadBalancer mocks base method.
+func (m *MockELBClientAPI) DeleteLoadBalancer(ctx context.Context, params *elasticloadbalancingv2.DeleteLoadBalancerInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DeleteLoadBalancerOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteLoadBalancer", varargs...)
+	ret0, _ := ret[0].(*elasticloadbalancingv2.DeleteLoadBalancerOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLoadBalancer indicates an expected call of DeleteLoadBalancer.
+func (mr *MockELBClientAPIMockRecorder) DeleteLoadBalancer(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoadBalancer", reflect.TypeOf((*MockELBClientAPI)(nil).DeleteLoadBalancer), varargs...)
+}
+
+// DescribeLoadBalancers mocks base method.
+func (m *MockELBClientAPI) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeLoadBalancers", varargs...)
+	ret0, _ := ret[0].(*elasticloadbalancingv2.DescribeLoadBalancersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLoadBalancers indicates an expected call of DescribeLoadBalancers.
+func (mr *MockELBClientAPIMockRecorder) DescribeLoadBalancers(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLoadBalancers", reflect.TypeOf((*MockELBClientAPI)(nil).DescribeLoadBalancers), varargs...)
+}
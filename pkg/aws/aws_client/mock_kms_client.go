@@ -0,0 +1,202 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: kms_client_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=kms_client_interface.go -package=aws_client -destination=mock_kms_client.go
+//
+
+// Package aws_client is a generated GoMock package.
+package aws_client
+
+import (
+	context "context"
+	reflect "reflect"
+
+	kms "github.com/aws/aws-sdk-go-v2/service/kms"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKMSClientAPI is a mock of KMSClientAPI interface.
+type MockKMSClientAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockKMSClientAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockKMSClientAPIMockRecorder is the mock recorder for MockKMSClientAPI.
+type MockKMSClientAPIMockRecorder struct {
+	mock *MockKMSClientAPI
+}
+
+// NewMockKMSClientAPI creates a new mock instance.
+func NewMockKMSClientAPI(ctrl *gomock.Controller) *MockKMSClientAPI {
+	mock := &MockKMSClientAPI{ctrl: ctrl}
+	mock.recorder = &MockKMSClientAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKMSClientAPI) EXPECT() *MockKMSClientAPIMockRecorder {
+	return m.recorder
+}
+
+// CreateKey mocks base method.
+func (m *MockKMSClientAPI) CreateKey(ctx context.Context, params *kms.CreateKeyInput, optFns ...func(*kms.Options)) (*kms.CreateKeyOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateKey", varargs...)
+	ret0, _ := ret[0].(*kms.CreateKeyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKey indicates an expected call of CreateKey.
+func (mr *MockKMSClientAPIMockRecorder) CreateKey(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKey", reflect.TypeOf((*MockKMSClientAPI)(nil).CreateKey), varargs...)
+}
+
+// DescribeKey mocks base method.
+func (m *MockKMSClientAPI) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeKey", varargs...)
+	ret0, _ := ret[0].(*kms.DescribeKeyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeKey indicates an expected call of DescribeKey.
+func (mr *MockKMSClientAPIMockRecorder) DescribeKey(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeKey", reflect.TypeOf((*MockKMSClientAPI)(nil).DescribeKey), varargs...)
+}
+
+// GetKeyPolicy mocks base method.
+func (m *MockKMSClientAPI) GetKeyPolicy(ctx context.Context, params *kms.GetKeyPolicyInput, optFns ...func(*kms.Options)) (*kms.GetKeyPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetKeyPolicy", varargs...)
+	ret0, _ := ret[0].(*kms.GetKeyPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyPolicy indicates an expected call of GetKeyPolicy.
+func (mr *MockKMSClientAPIMockRecorder) GetKeyPolicy(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyPolicy", reflect.TypeOf((*MockKMSClientAPI)(nil).GetKeyPolicy), varargs...)
+}
+
+// ListKeys mocks base method.
+func (m *MockKMSClientAPI) ListKeys(ctx context.Context, params *kms.ListKeysInput, optFns ...func(*kms.Options)) (*kms.ListKeysOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListKeys", varargs...)
+	ret0, _ := ret[0].(*kms.ListKeysOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKeys indicates an expected call of ListKeys.
+func (mr *MockKMSClientAPIMockRecorder) ListKeys(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKeys", reflect.TypeOf((*MockKMSClientAPI)(nil).ListKeys), varargs...)
+}
+
+// ListResourceTags mocks base method.
+func (m *MockKMSClientAPI) ListResourceTags(ctx context.Context, params *kms.ListResourceTagsInput, optFns ...func(*kms.Options)) (*kms.ListResourceTagsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListResourceTags", varargs...)
+	ret0, _ := ret[0].(*kms.ListResourceTagsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourceTags indicates an expected call of ListResourceTags.
+func (mr *MockKMSClientAPIMockRecorder) ListResourceTags(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceTags", reflect.TypeOf((*MockKMSClientAPI)(nil).ListResourceTags), varargs...)
+}
+
+// PutKeyPolicy mocks base method.
+func (m *MockKMSClientAPI) PutKeyPolicy(ctx context.Context, params *kms.PutKeyPolicyInput, optFns ...func(*kms.Options)) (*kms.PutKeyPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutKeyPolicy", varargs...)
+	ret0, _ := ret[0].(*kms.PutKeyPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutKeyPolicy indicates an expected call of PutKeyPolicy.
+func (mr *MockKMSClientAPIMockRecorder) PutKeyPolicy(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutKeyPolicy", reflect.TypeOf((*MockKMSClientAPI)(nil).PutKeyPolicy), varargs...)
+}
+
+// ScheduleKeyDeletion mocks base method.
+func (m *MockKMSClientAPI) ScheduleKeyDeletion(ctx context.Context, params *kms.ScheduleKeyDeletionInput, optFns ...func(*kms.Options)) (*kms.ScheduleKeyDeletionOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ScheduleKeyDeletion", varargs...)
+	ret0, _ := ret[0].(*kms.ScheduleKeyDeletionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleKeyDeletion indicates an expected call of ScheduleKeyDeletion.
+func (mr *MockKMSClientAPIMockRecorder) ScheduleKeyDeletion(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleKeyDeletion", reflect.TypeOf((*MockKMSClientAPI)(nil).ScheduleKeyDeletion), varargs...)
+}
+
+// TagResource mocks base method.
+func (m *MockKMSClientAPI) TagResource(ctx context.Context, params *kms.TagResourceInput, optFns ...func(*kms.Options)) (*kms.TagResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TagResource", varargs...)
+	ret0, _ := ret[0].(*kms.TagResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagResource indicates an expected call of TagResource.
+func (mr *MockKMSClientAPIMockRecorder) TagResource(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResource", reflect.TypeOf((*MockKMSClientAPI)(nil).TagResource), varargs...)
+}
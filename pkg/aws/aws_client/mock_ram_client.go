@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ram_client_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=ram_client_interface.go -package=aws_client -destination=mock_ram_client.go
+//
+
+// Package aws_client is a generated GoMock package.
+package aws_client
+
+import (
+	context "context"
+	reflect "reflect"
+
+	ram "github.com/aws/aws-sdk-go-v2/service/ram"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRAMClientAPI is a mock of RAMClientAPI interface.
+type MockRAMClientAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockRAMClientAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockRAMClientAPIMockRecorder is the mock recorder for MockRAMClientAPI.
+type MockRAMClientAPIMockRecorder struct {
+	mock *MockRAMClientAPI
+}
+
+// NewMockRAMClientAPI creates a new mock instance.
+func NewMockRAMClientAPI(ctrl *gomock.Controller) *MockRAMClientAPI {
+	mock := &MockRAMClientAPI{ctrl: ctrl}
+	mock.recorder = &MockRAMClientAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRAMClientAPI) EXPECT() *MockRAMClientAPIMockRecorder {
+	return m.recorder
+}
+
+// CreateResourceShare mocks base method.
+func (m *MockRAMClientAPI) CreateResourceShare(ctx context.Context, params *ram.CreateResourceShareInput, optFns ...func(*ram.Options)) (*ram.CreateResourceShareOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateResourceShare", varargs...)
+	ret0, _ := ret[0].(*ram.CreateResourceShareOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateResourceShare indicates an expected call of CreateResourceShare.
+func (mr *MockRAMClientAPIMockRecorder) CreateResourceShare(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResourceShare", reflect.TypeOf((*MockRAMClientAPI)(nil).CreateResourceShare), varargs...)
+}
+
+// DeleteResourceShare mocks base method.
+func (m *MockRAMClientAPI) DeleteResourceShare(ctx context.Context, params *ram.DeleteResourceShareInput, optFns ...func(*ram.Options)) (*ram.DeleteResourceShareOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteResourceShare", varargs...)
+	ret0, _ := ret[0].(*ram.DeleteResourceShareOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteResourceShare indicates an expected call of DeleteResourceShare.
+func (mr *MockRAMClientAPIMockRecorder) DeleteResourceShare(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourceShare", reflect.TypeOf((*MockRAMClientAPI)(nil).DeleteResourceShare), varargs...)
+}
+
+// GetResourceShareAssociations mocks base method.
+func (m *MockRAMClientAPI) GetResourceShareAssociations(ctx context.Context, params *ram.GetResourceShareAssociationsInput, optFns ...func(*ram.Options)) (*ram.GetResourceShareAssociationsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetResourceShareAssociations", varargs...)
+	ret0, _ := ret[0].(*ram.GetResourceShareAssociationsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceShareAssociations indicates an expected call of GetResourceShareAssociations.
+func (mr *MockRAMClientAPIMockRecorder) GetResourceShareAssociations(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceShareAssociations", reflect.TypeOf((*MockRAMClientAPI)(nil).GetResourceShareAssociations), varargs...)
+}
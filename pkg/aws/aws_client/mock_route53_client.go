@@ -0,0 +1,202 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: route53_client_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=route53_client_interface.go -package=aws_client -destination=mock_route53_client.go
+//
+
+// Package aws_client is a generated GoMock package.
+package aws_client
+
+import (
+	context "context"
+	reflect "reflect"
+
+	route53 "github.com/aws/aws-sdk-go-v2/service/route53"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoute53ClientAPI is a mock of Route53ClientAPI interface.
+type MockRoute53ClientAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoute53ClientAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockRoute53ClientAPIMockRecorder is the mock recorder for MockRoute53ClientAPI.
+type MockRoute53ClientAPIMockRecorder struct {
+	mock *MockRoute53ClientAPI
+}
+
+// NewMockRoute53ClientAPI creates a new mock instance.
+func NewMockRoute53ClientAPI(ctrl *gomock.Controller) *MockRoute53ClientAPI {
+	mock := &MockRoute53ClientAPI{ctrl: ctrl}
+	mock.recorder = &MockRoute53ClientAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoute53ClientAPI) EXPECT() *MockRoute53ClientAPIMockRecorder {
+	return m.recorder
+}
+
+// ChangeResourceRecordSets mocks base method.
+func (m *MockRoute53ClientAPI) ChangeResourceRecordSets(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ChangeResourceRecordSets", varargs...)
+	ret0, _ := ret[0].(*route53.ChangeResourceRecordSetsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeResourceRecordSets indicates an expected call of ChangeResourceRecordSets.
+func (mr *MockRoute53ClientAPIMockRecorder) ChangeResourceRecordSets(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeResourceRecordSets", reflect.TypeOf((*MockRoute53ClientAPI)(nil).ChangeResourceRecordSets), varargs...)
+}
+
+// CreateHostedZone mocks base method.
+func (m *MockRoute53ClientAPI) CreateHostedZone(ctx context.Context, params *route53.CreateHostedZoneInput, optFns ...func(*route53.Options)) (*route53.CreateHostedZoneOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateHostedZone", varargs...)
+	ret0, _ := ret[0].(*route53.CreateHostedZoneOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHostedZone indicates an expected call of CreateHostedZone.
+func (mr *MockRoute53ClientAPIMockRecorder) CreateHostedZone(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHostedZone", reflect.TypeOf((*MockRoute53ClientAPI)(nil).CreateHostedZone), varargs...)
+}
+
+// DeleteHostedZone mocks base method.
+func (m *MockRoute53ClientAPI) DeleteHostedZone(ctx context.Context, params *route53.DeleteHostedZoneInput, optFns ...func(*route53.Options)) (*route53.DeleteHostedZoneOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteHostedZone", varargs...)
+	ret0, _ := ret[0].(*route53.DeleteHostedZoneOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteHostedZone indicates an expected call of DeleteHostedZone.
+func (mr *MockRoute53ClientAPIMockRecorder) DeleteHostedZone(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHostedZone", reflect.TypeOf((*MockRoute53ClientAPI)(nil).DeleteHostedZone), varargs...)
+}
+
+// GetHostedZone mocks base method.
+func (m *MockRoute53ClientAPI) GetHostedZone(ctx context.Context, params *route53.GetHostedZoneInput, optFns ...func(*route53.Options)) (*route53.GetHostedZoneOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetHostedZone", varargs...)
+	ret0, _ := ret[0].(*route53.GetHostedZoneOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHostedZone indicates an expected call of GetHostedZone.
+func (mr *MockRoute53ClientAPIMockRecorder) GetHostedZone(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHostedZone", reflect.TypeOf((*MockRoute53ClientAPI)(nil).GetHostedZone), varargs...)
+}
+
+// ListHostedZones mocks base method.
+func (m *MockRoute53ClientAPI) ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListHostedZones", varargs...)
+	ret0, _ := ret[0].(*route53.ListHostedZonesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHostedZones indicates an expected call of ListHostedZones.
+func (mr *MockRoute53ClientAPIMockRecorder) ListHostedZones(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHostedZones", reflect.TypeOf((*MockRoute53ClientAPI)(nil).ListHostedZones), varargs...)
+}
+
+// ListHostedZonesByName mocks base method.
+func (m *MockRoute53ClientAPI) ListHostedZonesByName(ctx context.Context, params *route53.ListHostedZonesByNameInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesByNameOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListHostedZonesByName", varargs...)
+	ret0, _ := ret[0].(*route53.ListHostedZonesByNameOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHostedZonesByName indicates an expected call of ListHostedZonesByName.
+func (mr *MockRoute53ClientAPIMockRecorder) ListHostedZonesByName(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHostedZonesByName", reflect.TypeOf((*MockRoute53ClientAPI)(nil).ListHostedZonesByName), varargs...)
+}
+
+// ListResourceRecordSets mocks base method.
+func (m *MockRoute53ClientAPI) ListResourceRecordSets(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListResourceRecordSets", varargs...)
+	ret0, _ := ret[0].(*route53.ListResourceRecordSetsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourceRecordSets indicates an expected call of ListResourceRecordSets.
+func (mr *MockRoute53ClientAPIMockRecorder) ListResourceRecordSets(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceRecordSets", reflect.TypeOf((*MockRoute53ClientAPI)(nil).ListResourceRecordSets), varargs...)
+}
+
+// ListTagsForResource mocks base method.
+func (m *MockRoute53ClientAPI) ListTagsForResource(ctx context.Context, params *route53.ListTagsForResourceInput, optFns ...func(*route53.Options)) (*route53.ListTagsForResourceOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTagsForResource", varargs...)
+	ret0, _ := ret[0].(*route53.ListTagsForResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsForResource indicates an expected call of ListTagsForResource.
+func (mr *MockRoute53ClientAPIMockRecorder) ListTagsForResource(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForResource", reflect.TypeOf((*MockRoute53ClientAPI)(nil).ListTagsForResource), varargs...)
+}
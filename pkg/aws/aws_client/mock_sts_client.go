@@ -0,0 +1,62 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: sts_client_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=sts_client_interface.go -package=aws_client -destination=mock_sts_client.go
+//
+
+// Package aws_client is a generated GoMock package.
+package aws_client
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sts "github.com/aws/aws-sdk-go-v2/service/sts"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSTSClientAPI is a mock of STSClientAPI interface.
+type MockSTSClientAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockSTSClientAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockSTSClientAPIMockRecorder is the mock recorder for MockSTSClientAPI.
+type MockSTSClientAPIMockRecorder struct {
+	mock *MockSTSClientAPI
+}
+
+// NewMockSTSClientAPI creates a new mock instance.
+func NewMockSTSClientAPI(ctrl *gomock.Controller) *MockSTSClientAPI {
+	mock := &MockSTSClientAPI{ctrl: ctrl}
+	mock.recorder = &MockSTSClientAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSTSClientAPI) EXPECT() *MockSTSClientAPIMockRecorder {
+	return m.recorder
+}
+
+// GetCallerIdentity mocks base method.
+func (m *MockSTSClientAPI) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetCallerIdentity", varargs...)
+	ret0, _ := ret[0].(*sts.GetCallerIdentityOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCallerIdentity indicates an expected call of GetCallerIdentity.
+func (mr *MockSTSClientAPIMockRecorder) GetCallerIdentity(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallerIdentity", reflect.TypeOf((*MockSTSClientAPI)(nil).GetCallerIdentity), varargs...)
+}
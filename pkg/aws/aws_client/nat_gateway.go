@@ -2,10 +2,13 @@ package aws_client
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
 	"github.com/openshift-online/ocm-common/pkg/log"
 )
 
@@ -18,7 +21,7 @@ func (client *AWSClient) CreateNatGateway(subnetID string, allocationID string,
 		DryRun:            nil,
 		TagSpecifications: nil,
 	}
-	respCreateNat, err := client.Ec2Client.CreateNatGateway(context.TODO(), inputCreateNat)
+	respCreateNat, err := client.Ec2Client.CreateNatGateway(client.requestContext(), inputCreateNat)
 	if err != nil {
 		log.LogError("Create nat error %s", err.Error())
 		return nil, err
@@ -34,7 +37,7 @@ func (client *AWSClient) DeleteNatGateway(natGatewayID string, timeout ...int) (
 		NatGatewayId: aws.String(natGatewayID),
 		DryRun:       nil,
 	}
-	respDeleteNatGateway, err := client.Ec2Client.DeleteNatGateway(context.TODO(), inputDeleteNatGateway)
+	respDeleteNatGateway, err := client.Ec2Client.DeleteNatGateway(client.requestContext(), inputDeleteNatGateway)
 	if err != nil {
 		log.LogError("Delete Nat Gateway error %s", err.Error())
 		return nil, err
@@ -43,7 +46,24 @@ func (client *AWSClient) DeleteNatGateway(natGatewayID string, timeout ...int) (
 	if len(timeout) != 0 {
 		timeoutTime = timeout[0]
 	}
-	err = client.WaitForResourceDeleted(natGatewayID, timeoutTime)
+	err = client.WaitFor("NAT gateway deleted: "+natGatewayID, time.Duration(timeoutTime)*time.Second,
+		func(ctx context.Context) (bool, string, error) {
+			output, err := client.Ec2Client.DescribeNatGateways(ctx, &ec2.DescribeNatGatewaysInput{
+				NatGatewayIds: []string{natGatewayID},
+			})
+			if awserrors.IsErrorCode(err, awserrors.InvalidNatGatewayID) {
+				return true, "", nil
+			}
+			if err != nil {
+				log.LogError("%s", err.Error())
+				return false, err.Error(), nil
+			}
+			if len(output.NatGateways) == 0 {
+				return true, "", nil
+			}
+			state := output.NatGateways[0].State
+			return state == types.NatGatewayStateDeleted, string(state), nil
+		})
 	if err != nil {
 		return respDeleteNatGateway, err
 	}
@@ -64,7 +84,7 @@ func (client *AWSClient) ListNatGateways(vpcID string) ([]types.NatGateway, erro
 	input := &ec2.DescribeNatGatewaysInput{
 		Filter: filter,
 	}
-	output, err := client.Ec2Client.DescribeNatGateways(context.TODO(), input)
+	output, err := client.Ec2Client.DescribeNatGateways(client.requestContext(), input)
 	if err != nil {
 		return nil, err
 	}
@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -24,7 +22,7 @@ func (client *AWSClient) ListNetWorkAcls(vpcID string) ([]types.NetworkAcl, erro
 	describeACLInput := &ec2.DescribeNetworkAclsInput{
 		Filters: filter,
 	}
-	output, err := client.Ec2Client.DescribeNetworkAcls(context.TODO(), describeACLInput)
+	output, err := client.Ec2Client.DescribeNetworkAcls(client.requestContext(), describeACLInput)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +45,7 @@ func (client *AWSClient) AddNetworkAclEntry(networkAclId string, egress bool, pr
 			To:   aws.Int32(toPort),
 		},
 	}
-	resp, err := client.Ec2Client.CreateNetworkAclEntry(context.TODO(), input)
+	resp, err := client.Ec2Client.CreateNetworkAclEntry(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Create NetworkAcl rule failed %s", err.Error())
 		return nil, err
@@ -62,7 +60,7 @@ func (client *AWSClient) DeleteNetworkAclEntry(networkAclId string, egress bool,
 		NetworkAclId: aws.String(networkAclId),
 		RuleNumber:   aws.Int32(ruleNumber),
 	}
-	resp, err := client.Ec2Client.DeleteNetworkAclEntry(context.TODO(), input)
+	resp, err := client.Ec2Client.DeleteNetworkAclEntry(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Delete NetworkAcl rule failed %s", err.Error())
 		return nil, err
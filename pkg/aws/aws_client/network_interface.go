@@ -1,7 +1,6 @@
 package aws_client
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -23,7 +22,7 @@ func (client *AWSClient) DescribeNetWorkInterface(vpcID string) ([]types.Network
 	input := &ec2.DescribeNetworkInterfacesInput{
 		Filters: filter,
 	}
-	resp, err := client.Ec2Client.DescribeNetworkInterfaces(context.TODO(), input)
+	resp, err := client.Ec2Client.DescribeNetworkInterfaces(client.requestContext(), input)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +42,7 @@ func (client *AWSClient) DescribeNetWorkInterfaceBySG(securityGroupID string) ([
 	input := &ec2.DescribeNetworkInterfacesInput{
 		Filters: filter,
 	}
-	resp, err := client.Ec2Client.DescribeNetworkInterfaces(context.TODO(), input)
+	resp, err := client.Ec2Client.DescribeNetworkInterfaces(client.requestContext(), input)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +59,7 @@ func (client *AWSClient) GetNetworkInterfacesByInstanceID(instanceID string) ([]
 		},
 	}
 
-	output, err := client.Ec2Client.DescribeNetworkInterfaces(context.TODO(), input)
+	output, err := client.Ec2Client.DescribeNetworkInterfaces(client.requestContext(), input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe network interfaces: %w", err)
 	}
@@ -73,7 +72,7 @@ func (client *AWSClient) DetachNetworkInterface(attachmentID string, force bool)
 		AttachmentId: aws.String(attachmentID),
 		Force:        aws.Bool(force),
 	}
-	_, err := client.Ec2Client.DetachNetworkInterface(context.TODO(), input)
+	_, err := client.Ec2Client.DetachNetworkInterface(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Detach network interface attachment %s failed: %s", attachmentID, err)
 		return err
@@ -98,7 +97,7 @@ func (client *AWSClient) DeleteNetworkInterface(networkinterface types.NetworkIn
 	deleteNIInput := &ec2.DeleteNetworkInterfaceInput{
 		NetworkInterfaceId: networkinterface.NetworkInterfaceId,
 	}
-	_, err := client.Ec2Client.DeleteNetworkInterface(context.TODO(), deleteNIInput)
+	_, err := client.Ec2Client.DeleteNetworkInterface(client.requestContext(), deleteNIInput)
 	if err != nil {
 		log.LogError("Delete network interface %s failed： %s", *networkinterface.NetworkInterfaceId, err)
 	} else {
@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 )
 
@@ -10,6 +8,6 @@ func (client *AWSClient) DeleteOIDCProvider(providerArn string) error {
 	input := &iam.DeleteOpenIDConnectProviderInput{
 		OpenIDConnectProviderArn: &providerArn,
 	}
-	_, err := client.IamClient.DeleteOpenIDConnectProvider(context.TODO(), input)
+	_, err := client.IamClient.DeleteOpenIDConnectProvider(client.requestContext(), input)
 	return err
 }
@@ -0,0 +1,247 @@
+package aws_client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+)
+
+// defaultRoleSessionName is the session name used for assumed roles when none is given.
+const defaultRoleSessionName = "ocm-common"
+
+// ClientOption configures a client built by NewAWSClient.
+type ClientOption func(*clientOptions)
+
+// AssumeRole describes a role assumed on top of the credentials resolved so far.
+type AssumeRole struct {
+	// RoleARN is the role to assume.
+	RoleARN string
+	// ExternalID is passed to sts:AssumeRole when the trust policy requires one.
+	ExternalID string
+	// SessionName names the role session. Empty means a generated name.
+	SessionName string
+	// Tags are session tags passed to sts:AssumeRole.
+	Tags map[string]string
+	// TransitiveTagKeys lists the session tags that carry over to later roles in the chain.
+	TransitiveTagKeys []string
+	// Duration is the session duration. Zero keeps the STS default.
+	Duration time.Duration
+}
+
+// webIdentity describes a role assumed with a web identity token.
+type webIdentity struct {
+	roleARN     string
+	tokenFile   string
+	sessionName string
+}
+
+type clientOptions struct {
+	ctx                    context.Context
+	profile                string
+	sharedConfigFiles      []string
+	sharedCredentialsFiles []string
+	credentials            aws.CredentialsProvider
+	webIdentity            *webIdentity
+	assumeRoles            []AssumeRole
+	endpoint               string
+	retryPolicy            *RetryPolicy
+	skipIdentity           bool
+}
+
+// WithClientContext sets the context used to load the configuration and stored as ClientContext.
+func WithClientContext(ctx context.Context) ClientOption {
+	return func(o *clientOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithStaticCredentials uses the given access key instead of resolving credentials.
+func WithStaticCredentials(accessKeyID string, secretAccessKey string, sessionToken string) ClientOption {
+	return func(o *clientOptions) {
+		o.credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, sessionToken)
+	}
+}
+
+// WithCredentialsProvider uses provider to resolve credentials.
+func WithCredentialsProvider(provider aws.CredentialsProvider) ClientOption {
+	return func(o *clientOptions) {
+		o.credentials = provider
+	}
+}
+
+// WithProfile selects a profile of the shared configuration.
+func WithProfile(profileName string) ClientOption {
+	return func(o *clientOptions) {
+		o.profile = profileName
+	}
+}
+
+// WithSharedConfigFiles replaces the default shared config files.
+func WithSharedConfigFiles(files ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.sharedConfigFiles = files
+	}
+}
+
+// WithSharedCredentialsFiles replaces the default shared credentials files.
+func WithSharedCredentialsFiles(files ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.sharedCredentialsFiles = files
+	}
+}
+
+// WithWebIdentity assumes roleARN with the web identity token read from tokenFile, as used by
+// workloads with projected service account tokens. An empty session name means a generated one.
+func WithWebIdentity(roleARN string, tokenFile string, sessionName string) ClientOption {
+	return func(o *clientOptions) {
+		o.webIdentity = &webIdentity{roleARN: roleARN, tokenFile: tokenFile, sessionName: sessionName}
+	}
+}
+
+// WithAssumeRole appends role to the chain of roles assumed after the base credentials are resolved.
+// Roles are assumed in the order the options are given, each with the credentials of the previous one.
+func WithAssumeRole(role AssumeRole) ClientOption {
+	return func(o *clientOptions) {
+		o.assumeRoles = append(o.assumeRoles, role)
+	}
+}
+
+// WithEndpoint sends the requests of every service client to url, for example a LocalStack instance.
+func WithEndpoint(url string) ClientOption {
+	return func(o *clientOptions) {
+		o.endpoint = url
+	}
+}
+
+// WithRetryPolicy sets how failed calls are retried. A nil policy keeps the SDK defaults.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithSkipIdentity skips the sts:GetCallerIdentity call, leaving AccountID and Arn empty.
+func WithSkipIdentity() ClientOption {
+	return func(o *clientOptions) {
+		o.skipIdentity = true
+	}
+}
+
+// NewAWSClient creates a client for region configured by opts. Credentials are resolved from the
+// static credentials, profile or shared files given, falling back to the SDK default chain; then the
+// web identity role and the assume role chain are applied in that order. Unless WithSkipIdentity is
+// given, AccountID and Arn are loaded from the resulting identity.
+func NewAWSClient(region string, opts ...ClientOption) (*AWSClient, error) {
+	o := &clientOptions{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	cfg, err := o.loadConfig(region)
+	if err != nil {
+		return nil, err
+	}
+	client := newAWSClientFromConfig(o.ctx, cfg)
+	if o.skipIdentity {
+		return client, nil
+	}
+	if err := client.loadCallerIdentity(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// AssumeRoleClient returns a new client for the same region and endpoint using the credentials of
+// role, assumed with the credentials of this client. It is meant for cross-account operations such
+// as shared-VPC setups.
+func (client *AWSClient) AssumeRoleClient(role AssumeRole) (*AWSClient, error) {
+	cfg := client.AWSConfig.Copy()
+	cfg.Credentials = assumeRoleCredentials(cfg, role)
+	assumed := newAWSClientFromConfig(client.requestContext(), cfg)
+	if err := assumed.loadCallerIdentity(); err != nil {
+		return nil, fmt.Errorf("failed to assume role %s: %w", role.RoleARN, err)
+	}
+	return assumed, nil
+}
+
+// loadConfig resolves the SDK configuration described by the options.
+func (o *clientOptions) loadConfig(region string) (aws.Config, error) {
+	loadOptions := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if o.profile != "" {
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(o.profile))
+	}
+	if len(o.sharedConfigFiles) > 0 {
+		loadOptions = append(loadOptions, config.WithSharedConfigFiles(o.sharedConfigFiles))
+	}
+	if len(o.sharedCredentialsFiles) > 0 {
+		loadOptions = append(loadOptions, config.WithSharedCredentialsFiles(o.sharedCredentialsFiles))
+	}
+	if o.credentials != nil {
+		loadOptions = append(loadOptions, config.WithCredentialsProvider(o.credentials))
+	}
+	if o.retryPolicy != nil {
+		loadOptions = append(loadOptions, config.WithRetryer(o.retryPolicy.Retryer))
+	}
+	cfg, err := config.LoadDefaultConfig(o.ctx, loadOptions...)
+	if err != nil {
+		return cfg, err
+	}
+	if o.endpoint != "" {
+		cfg.BaseEndpoint = aws.String(o.endpoint)
+	}
+	if o.webIdentity != nil {
+		sessionName := o.webIdentity.sessionName
+		if sessionName == "" {
+			sessionName = roleSessionName()
+		}
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewWebIdentityRoleProvider(
+			sts.NewFromConfig(cfg),
+			o.webIdentity.roleARN,
+			stscreds.IdentityTokenFile(o.webIdentity.tokenFile),
+			func(options *stscreds.WebIdentityRoleOptions) {
+				options.RoleSessionName = sessionName
+			}))
+	}
+	for _, role := range o.assumeRoles {
+		cfg.Credentials = assumeRoleCredentials(cfg, role)
+	}
+	return cfg, nil
+}
+
+// assumeRoleCredentials returns cached credentials for role, assumed with the credentials of cfg.
+func assumeRoleCredentials(cfg aws.Config, role AssumeRole) aws.CredentialsProvider {
+	return aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.RoleARN,
+		func(options *stscreds.AssumeRoleOptions) {
+			options.RoleSessionName = role.SessionName
+			if options.RoleSessionName == "" {
+				options.RoleSessionName = roleSessionName()
+			}
+			if role.ExternalID != "" {
+				options.ExternalID = aws.String(role.ExternalID)
+			}
+			if role.Duration > 0 {
+				options.Duration = role.Duration
+			}
+			keys := make([]string, 0, len(role.Tags))
+			for key := range role.Tags {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				options.Tags = append(options.Tags, ststypes.Tag{Key: aws.String(key), Value: aws.String(role.Tags[key])})
+			}
+			options.TransitiveTagKeys = role.TransitiveTagKeys
+		}))
+}
+
+// roleSessionName generates a role session name.
+func roleSessionName() string {
+	return fmt.Sprintf("%s-%d", defaultRoleSessionName, time.Now().UnixNano())
+}
@@ -0,0 +1,153 @@
+package aws_client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+)
+
+const (
+	stsCallerIdentityResponse = `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>%s</Arn>
+    <UserId>AIDEXAMPLE</UserId>
+    <Account>%s</Account>
+  </GetCallerIdentityResult>
+  <ResponseMetadata><RequestId>req-1</RequestId></ResponseMetadata>
+</GetCallerIdentityResponse>`
+	stsAssumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASSUMEDKEY</AccessKeyId>
+      <SecretAccessKey>assumed-secret</SecretAccessKey>
+      <SessionToken>assumed-token</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::222222222222:assumed-role/shared-vpc/session</Arn>
+      <AssumedRoleId>AROAEXAMPLE:session</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+  <ResponseMetadata><RequestId>req-2</RequestId></ResponseMetadata>
+</AssumeRoleResponse>`
+)
+
+// fakeSTS answers GetCallerIdentity and AssumeRole, recording the requests it received.
+type fakeSTS struct {
+	mutex    sync.Mutex
+	requests []url.Values
+	keys     []string
+}
+
+func (f *fakeSTS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Expect(r.ParseForm()).To(Succeed())
+	f.mutex.Lock()
+	f.requests = append(f.requests, r.PostForm)
+	f.keys = append(f.keys, r.Header.Get("Authorization"))
+	f.mutex.Unlock()
+	w.Header().Set("Content-Type", "text/xml")
+	switch r.PostForm.Get("Action") {
+	case "AssumeRole":
+		fmt.Fprint(w, stsAssumeRoleResponse)
+	case "GetCallerIdentity":
+		if strings.Contains(r.Header.Get("Authorization"), "ASSUMEDKEY") {
+			fmt.Fprintf(w, stsCallerIdentityResponse, "arn:aws:sts::222222222222:assumed-role/shared-vpc/session", "222222222222")
+		} else {
+			fmt.Fprintf(w, stsCallerIdentityResponse, "arn:aws:iam::111111111111:user/tester", "111111111111")
+		}
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+var _ = Describe("NewAWSClient", func() {
+	var (
+		sts    *fakeSTS
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		sts = &fakeSTS{}
+		server = httptest.NewServer(sts)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should build a client without calling STS when identity is skipped", func() {
+		client, err := NewAWSClient("us-east-1",
+			WithStaticCredentials("AKIDEXAMPLE", "secret", ""),
+			WithEndpoint(server.URL),
+			WithSkipIdentity(),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.Region).To(Equal("us-east-1"))
+		Expect(client.AccountID).To(BeEmpty())
+		Expect(*client.AWSConfig.BaseEndpoint).To(Equal(server.URL))
+		creds, err := client.AWSConfig.Credentials.Retrieve(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(creds.AccessKeyID).To(Equal("AKIDEXAMPLE"))
+		Expect(sts.requests).To(BeEmpty())
+	})
+
+	It("should load the caller identity through the custom endpoint", func() {
+		client, err := NewAWSClient("us-east-1",
+			WithStaticCredentials("AKIDEXAMPLE", "secret", ""),
+			WithEndpoint(server.URL),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.AccountID).To(Equal("111111111111"))
+		Expect(client.Arn).To(Equal("arn:aws:iam::111111111111:user/tester"))
+	})
+
+	It("should assume a role with external ID and session tags", func() {
+		client, err := NewAWSClient("us-east-1",
+			WithStaticCredentials("AKIDEXAMPLE", "secret", ""),
+			WithEndpoint(server.URL),
+			WithAssumeRole(AssumeRole{
+				RoleARN:     "arn:aws:iam::222222222222:role/shared-vpc",
+				ExternalID:  "ext-123",
+				SessionName: "session",
+				Tags:        map[string]string{"team": "hcm", "env": "ci"},
+			}),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.AccountID).To(Equal("222222222222"))
+
+		Expect(sts.requests).To(HaveLen(2))
+		assume := sts.requests[0]
+		Expect(assume.Get("Action")).To(Equal("AssumeRole"))
+		Expect(assume.Get("RoleArn")).To(Equal("arn:aws:iam::222222222222:role/shared-vpc"))
+		Expect(assume.Get("ExternalId")).To(Equal("ext-123"))
+		Expect(assume.Get("RoleSessionName")).To(Equal("session"))
+		Expect(assume.Get("Tags.member.1.Key")).To(Equal("env"))
+		Expect(assume.Get("Tags.member.2.Key")).To(Equal("team"))
+		Expect(sts.keys[0]).To(ContainSubstring("AKIDEXAMPLE"))
+		Expect(sts.keys[1]).To(ContainSubstring("ASSUMEDKEY"))
+	})
+
+	It("should derive a cross-account client from an existing one", func() {
+		client, err := NewAWSClient("us-east-1",
+			WithStaticCredentials("AKIDEXAMPLE", "secret", ""),
+			WithEndpoint(server.URL),
+			WithSkipIdentity(),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		shared, err := client.AssumeRoleClient(AssumeRole{RoleARN: "arn:aws:iam::222222222222:role/shared-vpc"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shared.AccountID).To(Equal("222222222222"))
+		Expect(shared.Region).To(Equal("us-east-1"))
+		Expect(sts.requests[0].Get("RoleSessionName")).To(HavePrefix("ocm-common-"))
+	})
+})
@@ -1,16 +1,19 @@
 package aws_client
 
 import (
-	"context"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
 	"github.com/openshift-online/ocm-common/pkg/log"
 )
 
 func (client *AWSClient) CreateIAMPolicy(policyName string, policyDocument string, tags map[string]string) (*types.Policy, error) {
+	if err := iampolicy.CheckPolicySize(policyDocument, iampolicy.ManagedPolicySizeLimit); err != nil {
+		return nil, err
+	}
 	var policyTags []types.Tag
 	for tagKey, tagValue := range tags {
 		policyTags = append(policyTags, types.Tag{
@@ -25,7 +28,7 @@ func (client *AWSClient) CreateIAMPolicy(policyName string, policyDocument strin
 		Tags:           policyTags,
 		Description:    &description,
 	}
-	output, err := client.IamClient.CreatePolicy(context.TODO(), input)
+	output, err := client.IamClient.CreatePolicy(client.requestContext(), input)
 	if err != nil {
 		return nil, err
 	}
@@ -37,8 +40,11 @@ func (client *AWSClient) GetIAMPolicy(policyArn string) (*types.Policy, error) {
 	input := &iam.GetPolicyInput{
 		PolicyArn: &policyArn,
 	}
-	out, err := client.IamClient.GetPolicy(context.TODO(), input)
-	return out.Policy, err
+	out, err := client.IamClient.GetPolicy(client.requestContext(), input)
+	if err != nil {
+		return nil, err
+	}
+	return out.Policy, nil
 }
 
 func (client *AWSClient) DeleteIAMPolicy(arn string) error {
@@ -49,7 +55,7 @@ func (client *AWSClient) DeleteIAMPolicy(arn string) error {
 	if err != nil {
 		return err
 	}
-	_, err = client.IamClient.DeletePolicy(context.TODO(), input)
+	_, err = client.IamClient.DeletePolicy(client.requestContext(), input)
 	return err
 }
 
@@ -58,7 +64,7 @@ func (client *AWSClient) AttachIAMPolicy(roleName string, policyArn string) erro
 		PolicyArn: &policyArn,
 		RoleName:  &roleName,
 	}
-	_, err := client.IamClient.AttachRolePolicy(context.TODO(), input)
+	_, err := client.IamClient.AttachRolePolicy(client.requestContext(), input)
 	return err
 
 }
@@ -67,7 +73,7 @@ func (client *AWSClient) DetachIAMPolicy(roleAName string, policyArn string) err
 		RoleName:  &roleAName,
 		PolicyArn: &policyArn,
 	}
-	_, err := client.IamClient.DetachRolePolicy(context.TODO(), input)
+	_, err := client.IamClient.DetachRolePolicy(client.requestContext(), input)
 	return err
 }
 func (client *AWSClient) GetCustomerIAMPolicies() ([]types.Policy, error) {
@@ -77,7 +83,7 @@ func (client *AWSClient) GetCustomerIAMPolicies() ([]types.Policy, error) {
 		Scope:    "Local",
 		MaxItems: &maxItem,
 	}
-	out, err := client.IamClient.ListPolicies(context.TODO(), input)
+	out, err := client.IamClient.ListPolicies(client.requestContext(), input)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +124,7 @@ func (client *AWSClient) DeletePolicy(arn string) error {
 	if err != nil {
 		return err
 	}
-	_, err = client.IamClient.DeletePolicy(context.TODO(), input)
+	_, err = client.IamClient.DeletePolicy(client.requestContext(), input)
 	return err
 }
 
@@ -126,7 +132,7 @@ func (client *AWSClient) DeletePolicyVersions(policyArn string) error {
 	input := &iam.ListPolicyVersionsInput{
 		PolicyArn: &policyArn,
 	}
-	out, err := client.IamClient.ListPolicyVersions(context.TODO(), input)
+	out, err := client.IamClient.ListPolicyVersions(client.requestContext(), input)
 	if err != nil {
 		return err
 	}
@@ -138,7 +144,7 @@ func (client *AWSClient) DeletePolicyVersions(policyArn string) error {
 			PolicyArn: &policyArn,
 			VersionId: version.VersionId,
 		}
-		_, err = client.IamClient.DeletePolicyVersion(context.TODO(), input)
+		_, err = client.IamClient.DeletePolicyVersion(client.requestContext(), input)
 		if err != nil {
 			return err
 		}
@@ -174,7 +180,7 @@ func (client *AWSClient) TagPolicy(policyArn string, tags map[string]string) err
 		PolicyArn: &policyArn,
 		Tags:      policyTags,
 	}
-	_, err := client.IamClient.TagPolicy(context.TODO(), input)
+	_, err := client.IamClient.TagPolicy(client.requestContext(), input)
 	return err
 }
 
@@ -183,6 +189,6 @@ func (client *AWSClient) UntagPolicy(policyArn string, tagKeys []string) error {
 		PolicyArn: &policyArn,
 		TagKeys:   tagKeys,
 	}
-	_, err := client.IamClient.UntagPolicy(context.TODO(), input)
+	_, err := client.IamClient.UntagPolicy(client.requestContext(), input)
 	return err
 }
\ No newline at end of file
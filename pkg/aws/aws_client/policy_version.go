@@ -0,0 +1,159 @@
+package aws_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+	"github.com/openshift-online/ocm-common/pkg/log"
+)
+
+// MaxManagedPolicyVersions is the number of versions IAM keeps for a managed policy.
+const MaxManagedPolicyVersions = 5
+
+// PolicyVersion is a managed policy version with its decoded document.
+type PolicyVersion struct {
+	VersionID  string
+	IsDefault  bool
+	CreateDate time.Time
+	// RawDocument is the percent-decoded policy JSON.
+	RawDocument string
+	Document    *iampolicy.Document
+}
+
+// CreatePolicyVersion adds a version to a managed policy. When the policy already has
+// MaxManagedPolicyVersions versions, the oldest non-default version is deleted first.
+func (client *AWSClient) CreatePolicyVersion(policyArn string, policyDocument string, setAsDefault bool) (*types.PolicyVersion, error) {
+	if err := iampolicy.CheckPolicySize(policyDocument, iampolicy.ManagedPolicySizeLimit); err != nil {
+		return nil, err
+	}
+	out, err := client.IamClient.ListPolicyVersions(client.requestContext(), &iam.ListPolicyVersionsInput{
+		PolicyArn: &policyArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Versions) >= MaxManagedPolicyVersions {
+		oldest := oldestNonDefaultPolicyVersion(out.Versions)
+		if oldest == nil {
+			return nil, fmt.Errorf("policy %s has %d versions and none can be pruned", policyArn, len(out.Versions))
+		}
+		log.LogInfo("Pruning version %s of policy %s to stay within the version limit", aws.ToString(oldest.VersionId), policyArn)
+		_, err = client.IamClient.DeletePolicyVersion(client.requestContext(), &iam.DeletePolicyVersionInput{
+			PolicyArn: &policyArn,
+			VersionId: oldest.VersionId,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	created, err := client.IamClient.CreatePolicyVersion(client.requestContext(), &iam.CreatePolicyVersionInput{
+		PolicyArn:      &policyArn,
+		PolicyDocument: &policyDocument,
+		SetAsDefault:   setAsDefault,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created.PolicyVersion, nil
+}
+
+// SetDefaultPolicyVersion makes versionID the default version of a managed policy.
+func (client *AWSClient) SetDefaultPolicyVersion(policyArn string, versionID string) error {
+	_, err := client.IamClient.SetDefaultPolicyVersion(client.requestContext(), &iam.SetDefaultPolicyVersionInput{
+		PolicyArn: &policyArn,
+		VersionId: &versionID,
+	})
+	return err
+}
+
+// GetPolicyVersion returns one version of a managed policy with its document decoded.
+func (client *AWSClient) GetPolicyVersion(policyArn string, versionID string) (*PolicyVersion, error) {
+	out, err := client.IamClient.GetPolicyVersion(client.requestContext(), &iam.GetPolicyVersionInput{
+		PolicyArn: &policyArn,
+		VersionId: &versionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decodePolicyVersion(out.PolicyVersion)
+}
+
+// ListPolicyVersionsWithDocuments returns every version of a managed policy, newest first, with documents decoded.
+func (client *AWSClient) ListPolicyVersionsWithDocuments(policyArn string) ([]*PolicyVersion, error) {
+	out, err := client.IamClient.ListPolicyVersions(client.requestContext(), &iam.ListPolicyVersionsInput{
+		PolicyArn: &policyArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*PolicyVersion, 0, len(out.Versions))
+	for _, version := range out.Versions {
+		decoded, err := client.GetPolicyVersion(policyArn, aws.ToString(version.VersionId))
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, decoded)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].CreateDate.After(versions[j].CreateDate)
+	})
+	return versions, nil
+}
+
+// DiffPolicyVersions compares two versions of a managed policy.
+func (client *AWSClient) DiffPolicyVersions(policyArn string, fromVersionID string, toVersionID string) (*iampolicy.DocumentDiff, error) {
+	from, err := client.GetPolicyVersion(policyArn, fromVersionID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := client.GetPolicyVersion(policyArn, toVersionID)
+	if err != nil {
+		return nil, err
+	}
+	return iampolicy.DiffDocuments(from.Document, to.Document)
+}
+
+// oldestNonDefaultPolicyVersion returns the oldest version that is not the default, or nil.
+func oldestNonDefaultPolicyVersion(versions []types.PolicyVersion) *types.PolicyVersion {
+	var oldest *types.PolicyVersion
+	for i := range versions {
+		version := &versions[i]
+		if version.IsDefaultVersion {
+			continue
+		}
+		if oldest == nil || aws.ToTime(version.CreateDate).Before(aws.ToTime(oldest.CreateDate)) {
+			oldest = version
+		}
+	}
+	return oldest
+}
+
+// decodePolicyVersion percent-decodes and parses the document of version.
+func decodePolicyVersion(version *types.PolicyVersion) (*PolicyVersion, error) {
+	if version == nil {
+		return nil, fmt.Errorf("policy version not returned")
+	}
+	raw, err := url.PathUnescape(aws.ToString(version.Document))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode policy version %s: %w", aws.ToString(version.VersionId), err)
+	}
+	document := &iampolicy.Document{}
+	if err := json.Unmarshal([]byte(raw), document); err != nil {
+		return nil, fmt.Errorf("failed to parse policy version %s: %w", aws.ToString(version.VersionId), err)
+	}
+	return &PolicyVersion{
+		VersionID:   aws.ToString(version.VersionId),
+		IsDefault:   version.IsDefaultVersion,
+		CreateDate:  aws.ToTime(version.CreateDate),
+		RawDocument: raw,
+		Document:    document,
+	}, nil
+}
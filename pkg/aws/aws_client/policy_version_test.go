@@ -0,0 +1,140 @@
+package aws_client_test
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+)
+
+var _ = Describe("Policy versions", func() {
+	const policyArn = "arn:aws:iam::123456789012:policy/test"
+
+	var (
+		mockCtrl      *gomock.Controller
+		mockIAMClient *MockIAMClientAPI
+		client        *AWSClient
+	)
+
+	version := func(id string, age time.Duration, isDefault bool) types.PolicyVersion {
+		return types.PolicyVersion{
+			VersionId:        aws.String(id),
+			CreateDate:       aws.Time(time.Now().Add(-age)),
+			IsDefaultVersion: isDefault,
+		}
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockIAMClient = NewMockIAMClientAPI(mockCtrl)
+		client = &AWSClient{
+			IamClient: mockIAMClient,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should select the oldest non-default version to prune", func() {
+		oldest := OldestNonDefaultPolicyVersion([]types.PolicyVersion{
+			version("v3", 3*time.Hour, false),
+			version("v1", 5*time.Hour, true),
+			version("v5", time.Hour, false),
+			version("v2", 4*time.Hour, false),
+			version("v4", 2*time.Hour, false),
+		})
+		Expect(aws.ToString(oldest.VersionId)).To(Equal("v2"))
+	})
+
+	It("should never prune the default version", func() {
+		Expect(OldestNonDefaultPolicyVersion([]types.PolicyVersion{version("v1", time.Hour, true)})).To(BeNil())
+		Expect(OldestNonDefaultPolicyVersion(nil)).To(BeNil())
+	})
+
+	It("should decode the document of a version", func() {
+		document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"ec2:Describe*","Resource":"*"}]}`
+		created := time.Now()
+		decoded, err := DecodePolicyVersion(&types.PolicyVersion{
+			VersionId:        aws.String("v2"),
+			IsDefaultVersion: true,
+			CreateDate:       aws.Time(created),
+			Document:         aws.String(url.PathEscape(document)),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decoded.VersionID).To(Equal("v2"))
+		Expect(decoded.IsDefault).To(BeTrue())
+		Expect(decoded.CreateDate).To(Equal(created))
+		Expect(decoded.RawDocument).To(Equal(document))
+		Expect(decoded.Document.Statement).To(HaveLen(1))
+	})
+
+	It("should prune the oldest non-default version when the limit is reached", func() {
+		mockIAMClient.EXPECT().
+			ListPolicyVersions(gomock.Any(), gomock.Any()).
+			Return(&iam.ListPolicyVersionsOutput{
+				Versions: []types.PolicyVersion{
+					version("v1", 5*time.Hour, true),
+					version("v2", 4*time.Hour, false),
+					version("v3", 3*time.Hour, false),
+					version("v4", 2*time.Hour, false),
+					version("v5", time.Hour, false),
+				},
+			}, nil)
+		mockIAMClient.EXPECT().
+			DeletePolicyVersion(gomock.Any(), &iam.DeletePolicyVersionInput{
+				PolicyArn: aws.String(policyArn),
+				VersionId: aws.String("v2"),
+			}).
+			Return(&iam.DeletePolicyVersionOutput{}, nil)
+		mockIAMClient.EXPECT().
+			CreatePolicyVersion(gomock.Any(), gomock.Any()).
+			Return(&iam.CreatePolicyVersionOutput{PolicyVersion: &types.PolicyVersion{VersionId: aws.String("v6")}}, nil)
+
+		created, err := client.CreatePolicyVersion(policyArn, `{"Version":"2012-10-17","Statement":[]}`, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.ToString(created.VersionId)).To(Equal("v6"))
+	})
+
+	It("should not prune below the limit", func() {
+		mockIAMClient.EXPECT().
+			ListPolicyVersions(gomock.Any(), gomock.Any()).
+			Return(&iam.ListPolicyVersionsOutput{
+				Versions: []types.PolicyVersion{version("v1", time.Hour, true)},
+			}, nil)
+		mockIAMClient.EXPECT().
+			CreatePolicyVersion(gomock.Any(), gomock.Any()).
+			Return(&iam.CreatePolicyVersionOutput{PolicyVersion: &types.PolicyVersion{VersionId: aws.String("v2")}}, nil)
+
+		_, err := client.CreatePolicyVersion(policyArn, `{"Version":"2012-10-17","Statement":[]}`, false)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should diff two versions", func() {
+		documents := map[string]string{
+			"v1": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"ec2:Describe*","Resource":"*"}]}`,
+			"v2": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["ec2:Describe*","s3:GetObject"],"Resource":"*"}]}`,
+		}
+		mockIAMClient.EXPECT().
+			GetPolicyVersion(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, input *iam.GetPolicyVersionInput, _ ...func(*iam.Options)) (*iam.GetPolicyVersionOutput, error) {
+				return &iam.GetPolicyVersionOutput{PolicyVersion: &types.PolicyVersion{
+					VersionId: input.VersionId,
+					Document:  aws.String(url.PathEscape(documents[*input.VersionId])),
+				}}, nil
+			}).
+			Times(2)
+
+		diff, err := client.DiffPolicyVersions(policyArn, "v1", "v2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.AddedActions).To(Equal([]string{"s3:GetObject"}))
+		Expect(diff.RemovedActions).To(BeEmpty())
+	})
+})
@@ -1,7 +1,6 @@
 package aws_client
 
 import (
-	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/service/ram"
 	"github.com/aws/aws-sdk-go-v2/service/ram/types"
@@ -16,7 +15,7 @@ func (awsClient AWSClient) CreateResourceShare(resourceShareName string, resourc
 		Principals:   principles,
 	}
 
-	resp, err := awsClient.RamClient.CreateResourceShare(context.TODO(), input)
+	resp, err := awsClient.RamClient.CreateResourceShare(awsClient.requestContext(), input)
 	if err != nil {
 		log.LogError("Create resource share failed with name %s: %s", resourceShareName, err.Error())
 	} else {
@@ -32,7 +31,7 @@ func (awsClient AWSClient) GetResourceShareAssociations(resourceShareArn string,
 		AssociationType:   associationType,
 	}
 
-	resp, err := awsClient.RamClient.GetResourceShareAssociations(context.TODO(), input)
+	resp, err := awsClient.RamClient.GetResourceShareAssociations(awsClient.requestContext(), input)
 	if err != nil {
 		log.LogError("Get resource share association failed with name %s: %s", resourceShareArn, err.Error())
 	} else {
@@ -46,7 +45,7 @@ func (awsClient AWSClient) DeleteResourceShare(resourceShareArn string) error {
 		ResourceShareArn: &resourceShareArn,
 	}
 
-	_, err := awsClient.RamClient.DeleteResourceShare(context.TODO(), input)
+	_, err := awsClient.RamClient.DeleteResourceShare(awsClient.requestContext(), input)
 	return err
 }
 
@@ -88,7 +87,9 @@ func (awsClient AWSClient) CheckSubnetResourceShareAssociationsStatus(resourceSh
 			return nil
 		}
 
-		time.Sleep(10 * time.Second)
+		if err := awsClient.sleep(10 * time.Second); err != nil {
+			return err
+		}
 	}
 
 	return fmt.Errorf("Subnets resource shares did not become associated within %v", timeout)
@@ -0,0 +1,17 @@
+package aws_client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ram"
+)
+
+// RAMClientAPI defines the RAM methods used by AWSClient.
+// This interface allows us to mock AWS RAM calls in unit tests.
+//
+//go:generate mockgen -source=ram_client_interface.go -package=aws_client -destination=mock_ram_client.go
+type RAMClientAPI interface {
+	CreateResourceShare(ctx context.Context, params *ram.CreateResourceShareInput, optFns ...func(*ram.Options)) (*ram.CreateResourceShareOutput, error)
+	DeleteResourceShare(ctx context.Context, params *ram.DeleteResourceShareInput, optFns ...func(*ram.Options)) (*ram.DeleteResourceShareOutput, error)
+	GetResourceShareAssociations(ctx context.Context, params *ram.GetResourceShareAssociationsInput, optFns ...func(*ram.Options)) (*ram.GetResourceShareAssociationsOutput, error)
+}
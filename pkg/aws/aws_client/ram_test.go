@@ -0,0 +1,64 @@
+package aws_client_test
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ram"
+	"github.com/aws/aws-sdk-go-v2/service/ram/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+)
+
+var _ = Describe("Resource shares", func() {
+	const shareArn = "arn:aws:ram:us-east-1:123456789012:resource-share/share-1"
+
+	var (
+		mockCtrl      *gomock.Controller
+		mockRAMClient *MockRAMClientAPI
+		client        *AWSClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRAMClient = NewMockRAMClientAPI(mockCtrl)
+		client = &AWSClient{
+			RamClient: mockRAMClient,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should share the resources with the account and return the share ARN", func() {
+		mockRAMClient.EXPECT().
+			CreateResourceShare(gomock.Any(), &ram.CreateResourceShareInput{
+				Name:         aws.String("share"),
+				ResourceArns: []string{"arn:subnet"},
+				Principals:   []string{"222222222222"},
+			}).
+			Return(&ram.CreateResourceShareOutput{ResourceShare: &types.ResourceShare{ResourceShareArn: aws.String(shareArn)}}, nil)
+
+		arn, err := client.PrepareResourceShare("share", []string{"arn:subnet"}, "222222222222")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(arn).To(Equal(shareArn))
+	})
+
+	It("should return once every subnet is associated", func() {
+		mockRAMClient.EXPECT().
+			GetResourceShareAssociations(gomock.Any(), gomock.Any()).
+			Return(&ram.GetResourceShareAssociationsOutput{
+				ResourceShareAssociations: []types.ResourceShareAssociation{
+					{AssociatedEntity: aws.String("arn:subnet-1"), Status: types.ResourceShareAssociationStatusAssociated},
+					{AssociatedEntity: aws.String("arn:subnet-2"), Status: types.ResourceShareAssociationStatusAssociated},
+				},
+			}, nil)
+
+		err := client.CheckSubnetResourceShareAssociationsStatus(shareArn, []string{"arn:subnet-1", "arn:subnet-2"}, time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -4,341 +4,491 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
 	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
 	"github.com/openshift-online/ocm-common/pkg/log"
 )
 
-func (client *AWSClient) ResourceExisting(resourceID string) bool {
-	splitedResource := strings.SplitN(resourceID, "-", 2) //Just split the first -
-	resourceType := splitedResource[0]
-	switch resourceType {
-	case "sg":
-		input := &ec2.DescribeSecurityGroupsInput{
-			GroupIds: []string{
-				resourceID,
-			},
-		}
-		output, err := client.Ec2Client.DescribeSecurityGroups(context.TODO(), input)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return false
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(output.SecurityGroups) != 0 {
-			return true
-		}
-	case "subnet":
-		subnetInput := &ec2.DescribeSubnetsInput{
-			SubnetIds: []string{resourceID},
-		}
-		subnetOutput, err := client.Ec2Client.DescribeSubnets(context.TODO(), subnetInput)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return false
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(subnetOutput.Subnets) != 0 {
-			return true
-		}
+// ResourceState is the lifecycle state of a resource as seen by ResourceExisting and
+// ResourceDeleted.
+type ResourceState int
 
-		vpcInput := &ec2.DescribeVpcsInput{
-			VpcIds: []string{resourceID},
-		}
-		vpcOutput, err := client.Ec2Client.DescribeVpcs(context.TODO(), vpcInput)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return false
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(vpcOutput.Vpcs) != 0 {
-			return true
-		}
-	case "rtb":
-		rbtInput := &ec2.DescribeRouteTablesInput{
-			RouteTableIds: []string{
-				resourceID,
-			},
-		}
-		rbtOutput, err := client.Ec2Client.DescribeRouteTables(context.TODO(), rbtInput)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return false
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(rbtOutput.RouteTables) != 0 {
-			return true
-		}
-	case "vpc":
-		vpcInput := &ec2.DescribeVpcsInput{
-			VpcIds: []string{
-				resourceID,
-			},
-		}
-		vpcOutput, err := client.Ec2Client.DescribeVpcs(context.TODO(), vpcInput)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return false
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(vpcOutput.Vpcs) != 0 {
-			return true
-		}
-	case "eipalloc":
-		input := &ec2.DescribeAddressesInput{
-			AllocationIds: []string{
-				resourceID,
-			},
-		}
-		eipOutput, err := client.Ec2Client.DescribeAddresses(context.TODO(), input)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return false
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(eipOutput.Addresses) != 0 {
-			return true
-		}
-	case "igw":
-		input := &ec2.DescribeInternetGatewaysInput{
-			InternetGatewayIds: []string{
-				resourceID,
-			},
-		}
-		output, err := client.Ec2Client.DescribeInternetGateways(context.TODO(), input)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return false
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(output.InternetGateways) != 0 {
-			return true
-		}
-	case "nat":
-		input := &ec2.DescribeNatGatewaysInput{
-			NatGatewayIds: []string{
-				resourceID,
-			},
-		}
-		output, err := client.Ec2Client.DescribeNatGateways(context.TODO(), input)
-		if err != nil {
-			if awserrors.IsErrorCode(err, awserrors.InvalidNatGatewayID) {
-				return false
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(output.NatGateways) != 0 {
-			status := output.NatGateways[0].State
-			log.LogInfo("Current NAT gateway '%s' status: %s", resourceID, status)
-			if status == types.NatGatewayStateAvailable {
-				return true
-			}
-			if status == types.NatGatewayStateFailed {
-				log.LogError("NAT gateway %s entered failed state", resourceID)
-				return false
-			}
+const (
+	// ResourceNotFound means the resource does not exist, or only exists in a terminal deleted
+	// state such as a terminated instance.
+	ResourceNotFound ResourceState = iota
+	// ResourcePending means the resource exists but is not usable yet, or is being deleted.
+	ResourcePending
+	// ResourceAvailable means the resource exists and is usable.
+	ResourceAvailable
+)
 
+// ResourceDescriber returns the state of the resource with the given ID. Errors telling that the
+// resource does not exist are translated with resourceNotFound; any other error is returned to the
+// caller of ResourceExisting or ResourceDeleted.
+type ResourceDescriber func(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error)
+
+var (
+	resourceDescribersMutex sync.RWMutex
+	resourceDescribers      = map[string]ResourceDescriber{
+		"vpc":        describeVpcState,
+		"subnet":     describeSubnetState,
+		"sg":         describeSecurityGroupState,
+		"rtb":        describeRouteTableState,
+		"igw":        describeInternetGatewayState,
+		"eigw":       describeEgressOnlyInternetGatewayState,
+		"nat":        describeNatGatewayState,
+		"eipalloc":   describeAddressState,
+		"eni":        describeNetworkInterfaceState,
+		"vpce":       describeVpcEndpointState,
+		"pcx":        describeVpcPeeringConnectionState,
+		"tgw":        describeTransitGatewayState,
+		"tgw-attach": describeTransitGatewayVpcAttachmentState,
+		"tgw-rtb":    describeTransitGatewayRouteTableState,
+		"acl":        describeNetworkAclState,
+		"i":          describeInstanceState,
+		"vol":        describeVolumeState,
+		"ami":        describeImageState,
+		"key":        describeKeyPairState,
+		// role should use "role-<rolename>" to pass
+		"role": describeRoleState,
+		// policy should use "policy-<policy arn>" as parameter
+		"policy": describePolicyState,
+	}
+)
+
+// RegisterResourceDescriber makes ResourceExisting and ResourceDeleted support the IDs starting
+// with prefix followed by a dash, replacing the describer registered for prefix before. When
+// several prefixes match an ID the longest one wins, so "tgw-attach" can be told apart from "tgw".
+func RegisterResourceDescriber(prefix string, describer ResourceDescriber) {
+	resourceDescribersMutex.Lock()
+	defer resourceDescribersMutex.Unlock()
+	resourceDescribers[prefix] = describer
+}
+
+// UnregisterResourceDescriber removes the describer registered for prefix, so that IDs with the
+// prefix are no longer supported. Tests registering describers use it to clean up.
+func UnregisterResourceDescriber(prefix string) {
+	resourceDescribersMutex.Lock()
+	defer resourceDescribersMutex.Unlock()
+	delete(resourceDescribers, prefix)
+}
+
+// resourceDescriber returns the describer registered for the longest prefix of resourceID.
+func resourceDescriber(resourceID string) (ResourceDescriber, bool) {
+	resourceDescribersMutex.RLock()
+	defer resourceDescribersMutex.RUnlock()
+	var (
+		match     string
+		describer ResourceDescriber
+	)
+	for prefix, candidate := range resourceDescribers {
+		if strings.HasPrefix(resourceID, prefix+"-") && len(prefix) > len(match) {
+			match, describer = prefix, candidate
 		}
-	// role should use "role-<rolename>" to pass
-	case "role":
-		role, _ := client.GetRole(splitedResource[1])
-		return role != nil
-	// policy should use "policy-<policy arn>" as parameter
-	case "policy":
-		policy, _ := client.GetIAMPolicy(splitedResource[1])
-		return policy != nil
-	default:
-		log.LogError("Unknow resource type: %s of resource %s .Please define it in the method ResourceExisting.", resourceType, resourceID)
-	}
-	return false
-}
-
-func (client *AWSClient) ResourceDeleted(resourceID string) bool {
-	var deleted bool = true
-	splitedResource := strings.Split(resourceID, "-")
-	resourceType := splitedResource[0]
-	switch resourceType {
-	case "subnet":
-		subnetInput := &ec2.DescribeSubnetsInput{
-			SubnetIds: []string{resourceID},
-		}
-		subnetOutput, err := client.Ec2Client.DescribeSubnets(context.TODO(), subnetInput)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return true
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(subnetOutput.Subnets) != 0 {
-			deleted = false
-		}
-	case "rtb":
-		rbtInput := &ec2.DescribeRouteTablesInput{
-			RouteTableIds: []string{
-				resourceID,
-			},
-		}
-		rbtOutput, err := client.Ec2Client.DescribeRouteTables(context.TODO(), rbtInput)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return true
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(rbtOutput.RouteTables) != 0 {
-			deleted = false
-		}
-	case "vpc":
-		vpcInput := &ec2.DescribeVpcsInput{
-			VpcIds: []string{
-				resourceID,
-			},
-		}
-		vpcOutput, err := client.Ec2Client.DescribeVpcs(context.TODO(), vpcInput)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return true
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(vpcOutput.Vpcs) != 0 {
-			deleted = false
-		}
-	case "eipalloc":
-		input := &ec2.DescribeAddressesInput{
-			AllocationIds: []string{
-				resourceID,
-			},
-		}
-		eipOutput, err := client.Ec2Client.DescribeAddresses(context.TODO(), input)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return true
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(eipOutput.Addresses) != 0 {
-			deleted = false
-		}
-	case "igw":
-		input := &ec2.DescribeInternetGatewaysInput{
-			InternetGatewayIds: []string{
-				resourceID,
-			},
-		}
-		output, err := client.Ec2Client.DescribeInternetGateways(context.TODO(), input)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return true
-			} else {
-				log.LogError("%s", err.Error())
-				return false
-			}
-		}
-		if len(output.InternetGateways) != 0 {
-			deleted = false
-		}
-	case "sg":
-		input := &ec2.DescribeSecurityGroupsInput{
-			GroupIds: []string{
-				resourceID,
-			},
+	}
+	return describer, describer != nil
+}
+
+// resourceNotFound reports ResourceNotFound when err carries one of the given error codes, and
+// returns err otherwise.
+func resourceNotFound(err error, codes ...string) (ResourceState, error) {
+	for _, code := range codes {
+		if awserrors.IsErrorCode(err, code) {
+			return ResourceNotFound, nil
 		}
-		output, err := client.Ec2Client.DescribeSecurityGroups(context.TODO(), input)
-		if err != nil {
-			if strings.Contains(err.Error(), "NotFound") {
-				return true
-			} else {
-				log.LogError("%s", err.Error())
-				return false
+	}
+	return ResourceNotFound, err
+}
+
+// transientError reports whether err is a throttling or transient failure, such as Throttling,
+// RequestLimitExceeded or a dropped connection, that the waiters retry rather than give up on.
+func transientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return awserrors.IsThrottle(err) || retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
+
+// foundState returns ResourceAvailable when a describe call returned at least one resource.
+func foundState(count int) ResourceState {
+	if count == 0 {
+		return ResourceNotFound
+	}
+	return ResourceAvailable
+}
+
+// DescribeResourceState returns the state of the resource with the given ID, looked up with the
+// describer registered for the prefix of the ID. Unknown prefixes are an error.
+func (client *AWSClient) DescribeResourceState(resourceID string) (ResourceState, error) {
+	describer, ok := resourceDescriber(resourceID)
+	if !ok {
+		return ResourceNotFound, fmt.Errorf("unknown resource type of resource %s, register it with RegisterResourceDescriber", resourceID)
+	}
+	return describer(client.requestContext(), client, resourceID)
+}
+
+// ResourceExisting tells whether the resource with the given ID exists and is usable. Errors other
+// than the resource not being found, for example missing permissions, are returned.
+func (client *AWSClient) ResourceExisting(resourceID string) (bool, error) {
+	state, err := client.DescribeResourceState(resourceID)
+	if err != nil {
+		return false, err
+	}
+	return state == ResourceAvailable, nil
+}
+
+// ResourceDeleted tells whether the resource with the given ID is gone. Errors other than the
+// resource not being found, for example missing permissions, are returned.
+func (client *AWSClient) ResourceDeleted(resourceID string) (bool, error) {
+	state, err := client.DescribeResourceState(resourceID)
+	if err != nil {
+		return false, err
+	}
+	return state == ResourceNotFound, nil
+}
+
+func describeVpcState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		VpcIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidVpcID)
+	}
+	return foundState(len(output.Vpcs)), nil
+}
+
+func describeSubnetState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.SubnetNotFound, awserrors.InvalidSubnetID)
+	}
+	return foundState(len(output.Subnets)), nil
+}
+
+func describeSecurityGroupState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		GroupIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidGroup)
+	}
+	return foundState(len(output.SecurityGroups)), nil
+}
+
+func describeRouteTableState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
+		RouteTableIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidRouteTableID)
+	}
+	return foundState(len(output.RouteTables)), nil
+}
+
+func describeInternetGatewayState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{
+		InternetGatewayIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidInternetGatewayID)
+	}
+	return foundState(len(output.InternetGateways)), nil
+}
+
+func describeEgressOnlyInternetGatewayState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeEgressOnlyInternetGateways(ctx, &ec2.DescribeEgressOnlyInternetGatewaysInput{
+		EgressOnlyInternetGatewayIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidGatewayID)
+	}
+	return foundState(len(output.EgressOnlyInternetGateways)), nil
+}
+
+func describeNatGatewayState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeNatGateways(ctx, &ec2.DescribeNatGatewaysInput{
+		NatGatewayIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidNatGatewayID, awserrors.NatGatewayNotFound)
+	}
+	if len(output.NatGateways) == 0 {
+		return ResourceNotFound, nil
+	}
+	status := output.NatGateways[0].State
+	log.LogInfo("Current NAT gateway '%s' status: %s", resourceID, status)
+	switch status {
+	case types.NatGatewayStateAvailable:
+		return ResourceAvailable, nil
+	case types.NatGatewayStateDeleted:
+		return ResourceNotFound, nil
+	case types.NatGatewayStateFailed:
+		log.LogError("NAT gateway %s entered failed state", resourceID)
+	}
+	return ResourcePending, nil
+}
+
+func describeAddressState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{
+		AllocationIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidAllocationID)
+	}
+	return foundState(len(output.Addresses)), nil
+}
+
+func describeNetworkInterfaceState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
+		NetworkInterfaceIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidNetworkInterfaceID)
+	}
+	return foundState(len(output.NetworkInterfaces)), nil
+}
+
+func describeVpcEndpointState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeVpcEndpoints(ctx, &ec2.DescribeVpcEndpointsInput{
+		VpcEndpointIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidVpcEndpointID)
+	}
+	if len(output.VpcEndpoints) == 0 {
+		return ResourceNotFound, nil
+	}
+	switch output.VpcEndpoints[0].State {
+	case types.StateAvailable:
+		return ResourceAvailable, nil
+	case types.StateDeleted:
+		return ResourceNotFound, nil
+	}
+	return ResourcePending, nil
+}
+
+func describeVpcPeeringConnectionState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeVpcPeeringConnections(ctx, &ec2.DescribeVpcPeeringConnectionsInput{
+		VpcPeeringConnectionIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidVpcPeeringConnectionID)
+	}
+	if len(output.VpcPeeringConnections) == 0 || output.VpcPeeringConnections[0].Status == nil {
+		return ResourceNotFound, nil
+	}
+	switch output.VpcPeeringConnections[0].Status.Code {
+	case types.VpcPeeringConnectionStateReasonCodeActive:
+		return ResourceAvailable, nil
+	case types.VpcPeeringConnectionStateReasonCodeDeleted, types.VpcPeeringConnectionStateReasonCodeRejected,
+		types.VpcPeeringConnectionStateReasonCodeExpired, types.VpcPeeringConnectionStateReasonCodeFailed:
+		return ResourceNotFound, nil
+	}
+	return ResourcePending, nil
+}
+
+func describeTransitGatewayVpcAttachmentState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeTransitGatewayVpcAttachments(ctx, &ec2.DescribeTransitGatewayVpcAttachmentsInput{
+		TransitGatewayAttachmentIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidTransitGatewayAttachmentID)
+	}
+	if len(output.TransitGatewayVpcAttachments) == 0 {
+		return ResourceNotFound, nil
+	}
+	switch output.TransitGatewayVpcAttachments[0].State {
+	case types.TransitGatewayAttachmentStateAvailable:
+		return ResourceAvailable, nil
+	case types.TransitGatewayAttachmentStateDeleted, types.TransitGatewayAttachmentStateRejected,
+		types.TransitGatewayAttachmentStateFailed:
+		return ResourceNotFound, nil
+	}
+	return ResourcePending, nil
+}
+
+func describeTransitGatewayState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeTransitGateways(ctx, &ec2.DescribeTransitGatewaysInput{
+		TransitGatewayIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidTransitGatewayID)
+	}
+	if len(output.TransitGateways) == 0 {
+		return ResourceNotFound, nil
+	}
+	switch output.TransitGateways[0].State {
+	case types.TransitGatewayStateAvailable:
+		return ResourceAvailable, nil
+	case types.TransitGatewayStateDeleted:
+		return ResourceNotFound, nil
+	}
+	return ResourcePending, nil
+}
+
+func describeTransitGatewayRouteTableState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeTransitGatewayRouteTables(ctx, &ec2.DescribeTransitGatewayRouteTablesInput{
+		TransitGatewayRouteTableIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidRouteTableID)
+	}
+	if len(output.TransitGatewayRouteTables) == 0 {
+		return ResourceNotFound, nil
+	}
+	switch output.TransitGatewayRouteTables[0].State {
+	case types.TransitGatewayRouteTableStateAvailable:
+		return ResourceAvailable, nil
+	case types.TransitGatewayRouteTableStateDeleted:
+		return ResourceNotFound, nil
+	}
+	return ResourcePending, nil
+}
+
+func describeNetworkAclState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeNetworkAcls(ctx, &ec2.DescribeNetworkAclsInput{
+		NetworkAclIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidNetworkAclID)
+	}
+	return foundState(len(output.NetworkAcls)), nil
+}
+
+func describeInstanceState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidInstanceID)
+	}
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.State == nil {
+				return ResourcePending, nil
 			}
-		}
-		if len(output.SecurityGroups) != 0 {
-			deleted = false
-		}
-	case "nat":
-		input := &ec2.DescribeNatGatewaysInput{
-			NatGatewayIds: []string{
-				resourceID,
-			},
-		}
-		output, err := client.Ec2Client.DescribeNatGateways(context.TODO(), input)
-		if err != nil {
-			if awserrors.IsErrorCode(err, awserrors.InvalidNatGatewayID) {
-				return true
-			} else {
-				log.LogError("%s", err.Error())
-				return false
+			switch instance.State.Name {
+			case types.InstanceStateNameRunning:
+				return ResourceAvailable, nil
+			case types.InstanceStateNameTerminated:
+				return ResourceNotFound, nil
 			}
+			return ResourcePending, nil
 		}
-		if len(output.NatGateways) != 0 {
-			status := output.NatGateways[0].State
-			log.LogInfo("Current NAT gateway '%s' status: %s", resourceID, status)
-			if status != types.NatGatewayStateDeleted {
-				deleted = false
-			}
+	}
+	return ResourceNotFound, nil
+}
 
-		}
-	default:
-		log.LogError("Unknow resource type: %s of resource %s .Please define it in the method ResourceExisting.", resourceType, resourceID)
+func describeVolumeState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidVolume)
+	}
+	if len(output.Volumes) == 0 {
+		return ResourceNotFound, nil
 	}
-	return deleted
+	switch output.Volumes[0].State {
+	case types.VolumeStateAvailable, types.VolumeStateInUse:
+		return ResourceAvailable, nil
+	case types.VolumeStateDeleted:
+		return ResourceNotFound, nil
+	}
+	return ResourcePending, nil
 }
 
-// WaitForResourceExisting will wait for the resource created in <timeout> seconds
-func (client AWSClient) WaitForResourceExisting(resourceID string, timeout int) error {
-	now := time.Now()
-	for now.Add(time.Duration(timeout) * time.Second).After(time.Now()) {
-		if client.ResourceExisting(resourceID) {
-			return nil
-		}
-		time.Sleep(2 * time.Second)
+func describeImageState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		ImageIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidAMIID)
+	}
+	if len(output.Images) == 0 {
+		return ResourceNotFound, nil
 	}
-	return fmt.Errorf("timeout after %d seconds for waiting resource created: %s", timeout, resourceID)
+	switch output.Images[0].State {
+	case types.ImageStateAvailable:
+		return ResourceAvailable, nil
+	case types.ImageStateDeregistered:
+		return ResourceNotFound, nil
+	}
+	return ResourcePending, nil
+}
+
+func describeKeyPairState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.Ec2Client.DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{
+		KeyPairIds: []string{resourceID},
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.InvalidKeyPair)
+	}
+	return foundState(len(output.KeyPairs)), nil
+}
+
+func describeRoleState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.IamClient.GetRole(ctx, &iam.GetRoleInput{
+		RoleName: aws.String(strings.TrimPrefix(resourceID, "role-")),
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.NoSuchEntity)
+	}
+	if output.Role == nil {
+		return ResourceNotFound, nil
+	}
+	return ResourceAvailable, nil
+}
+
+func describePolicyState(ctx context.Context, client *AWSClient, resourceID string) (ResourceState, error) {
+	output, err := client.IamClient.GetPolicy(ctx, &iam.GetPolicyInput{
+		PolicyArn: aws.String(strings.TrimPrefix(resourceID, "policy-")),
+	})
+	if err != nil {
+		return resourceNotFound(err, awserrors.NoSuchEntity)
+	}
+	if output.Policy == nil {
+		return ResourceNotFound, nil
+	}
+	return ResourceAvailable, nil
 }
 
 // WaitForResourceExisting will wait for the resource created in <timeout> seconds
+func (client AWSClient) WaitForResourceExisting(resourceID string, timeout int) error {
+	return client.WaitFor("resource created: "+resourceID, time.Duration(timeout)*time.Second,
+		func(context.Context) (bool, string, error) {
+			existing, err := client.ResourceExisting(resourceID)
+			if transientError(err) {
+				return false, err.Error(), nil
+			}
+			if err != nil || existing {
+				return existing, "", err
+			}
+			return false, "not existing", nil
+		})
+}
+
+// WaitForResourceDeleted will wait for the resource deleted in <timeout> seconds
 func (client AWSClient) WaitForResourceDeleted(resourceID string, timeout int) error {
-	now := time.Now()
-	for now.Add(time.Duration(timeout) * time.Second).After(time.Now()) {
-		if client.ResourceDeleted(resourceID) {
-			return nil
-		}
-		time.Sleep(2 * time.Second)
-	}
-	return fmt.Errorf("Timeout after %d seconds for waiting resource deleted: %s", timeout, resourceID)
+	return client.WaitFor("resource deleted: "+resourceID, time.Duration(timeout)*time.Second,
+		func(context.Context) (bool, string, error) {
+			deleted, err := client.ResourceDeleted(resourceID)
+			if transientError(err) {
+				return false, err.Error(), nil
+			}
+			if err != nil || deleted {
+				return deleted, "", err
+			}
+			return false, "still existing", nil
+		})
 }
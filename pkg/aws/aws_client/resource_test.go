@@ -1,11 +1,14 @@
 package aws_client_test
 
 import (
+	"context"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	smithy "github.com/aws/smithy-go"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -47,7 +50,8 @@ var _ = Describe("ResourceExisting", func() {
 					},
 				}, nil)
 
-			exists := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			exists, err := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			Expect(err).ToNot(HaveOccurred())
 			Expect(exists).To(BeTrue())
 		})
 
@@ -63,7 +67,8 @@ var _ = Describe("ResourceExisting", func() {
 					},
 				}, nil)
 
-			exists := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			exists, err := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			Expect(err).ToNot(HaveOccurred())
 			Expect(exists).To(BeFalse())
 		})
 
@@ -79,7 +84,8 @@ var _ = Describe("ResourceExisting", func() {
 					},
 				}, nil)
 
-			exists := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			exists, err := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			Expect(err).ToNot(HaveOccurred())
 			Expect(exists).To(BeFalse())
 		})
 
@@ -90,7 +96,8 @@ var _ = Describe("ResourceExisting", func() {
 					NatGateways: []types.NatGateway{},
 				}, nil)
 
-			exists := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			exists, err := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			Expect(err).ToNot(HaveOccurred())
 			Expect(exists).To(BeFalse())
 		})
 
@@ -102,7 +109,8 @@ var _ = Describe("ResourceExisting", func() {
 					Message: "The NAT gateway 'nat-0cdb4695c3b5fa603' does not exist",
 				})
 
-			exists := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			exists, err := client.ResourceExisting("nat-0cdb4695c3b5fa603")
+			Expect(err).ToNot(HaveOccurred())
 			Expect(exists).To(BeFalse())
 		})
 	})
@@ -140,7 +148,8 @@ var _ = Describe("ResourceDeleted", func() {
 					},
 				}, nil)
 
-			deleted := client.ResourceDeleted("nat-0cdb4695c3b5fa603")
+			deleted, err := client.ResourceDeleted("nat-0cdb4695c3b5fa603")
+			Expect(err).ToNot(HaveOccurred())
 			Expect(deleted).To(BeTrue())
 		})
 
@@ -156,7 +165,8 @@ var _ = Describe("ResourceDeleted", func() {
 					},
 				}, nil)
 
-			deleted := client.ResourceDeleted("nat-0cdb4695c3b5fa603")
+			deleted, err := client.ResourceDeleted("nat-0cdb4695c3b5fa603")
+			Expect(err).ToNot(HaveOccurred())
 			Expect(deleted).To(BeFalse())
 		})
 
@@ -168,12 +178,147 @@ var _ = Describe("ResourceDeleted", func() {
 					Message: "The NAT gateway 'nat-0cdb4695c3b5fa603' does not exist",
 				})
 
-			deleted := client.ResourceDeleted("nat-0cdb4695c3b5fa603")
+			deleted, err := client.ResourceDeleted("nat-0cdb4695c3b5fa603")
+			Expect(err).ToNot(HaveOccurred())
 			Expect(deleted).To(BeTrue())
 		})
 	})
 })
 
+var _ = Describe("Resource describers", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockEC2Client *MockEC2ClientAPI
+		mockIAMClient *MockIAMClientAPI
+		client        *AWSClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockEC2Client = NewMockEC2ClientAPI(mockCtrl)
+		mockIAMClient = NewMockIAMClientAPI(mockCtrl)
+		client = &AWSClient{
+			Ec2Client: mockEC2Client,
+			IamClient: mockIAMClient,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should fail for unknown resource types", func() {
+		_, err := client.ResourceExisting("unknown-0abc")
+		Expect(err).To(MatchError(ContainSubstring("unknown resource type of resource unknown-0abc")))
+		_, err = client.ResourceDeleted("unknown-0abc")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return errors other than NotFound", func() {
+		mockEC2Client.EXPECT().
+			DescribeVpcs(gomock.Any(), gomock.Any()).
+			Return(nil, &smithy.GenericAPIError{Code: awserrors.AccessDenied, Message: "not authorized"}).
+			Times(2)
+
+		existing, err := client.ResourceExisting("vpc-0abc")
+		Expect(err).To(HaveOccurred())
+		Expect(awserrors.IsAccessDeniedException(err)).To(BeTrue())
+		Expect(existing).To(BeFalse())
+		deleted, err := client.ResourceDeleted("vpc-0abc")
+		Expect(err).To(HaveOccurred())
+		Expect(deleted).To(BeFalse())
+	})
+
+	It("should describe network interfaces, endpoints and ACLs", func() {
+		mockEC2Client.EXPECT().
+			DescribeNetworkInterfaces(gomock.Any(), &ec2.DescribeNetworkInterfacesInput{NetworkInterfaceIds: []string{"eni-0abc"}}).
+			Return(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: []types.NetworkInterface{{}}}, nil)
+		mockEC2Client.EXPECT().
+			DescribeVpcEndpoints(gomock.Any(), gomock.Any()).
+			Return(&ec2.DescribeVpcEndpointsOutput{VpcEndpoints: []types.VpcEndpoint{{State: types.StateDeleted}}}, nil)
+		mockEC2Client.EXPECT().
+			DescribeNetworkAcls(gomock.Any(), gomock.Any()).
+			Return(nil, &smithy.GenericAPIError{Code: awserrors.InvalidNetworkAclID})
+
+		existing, err := client.ResourceExisting("eni-0abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(existing).To(BeTrue())
+		deleted, err := client.ResourceDeleted("vpce-0abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+		deleted, err = client.ResourceDeleted("acl-0abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+	})
+
+	It("should treat terminated instances as deleted", func() {
+		mockEC2Client.EXPECT().
+			DescribeInstances(gomock.Any(), gomock.Any()).
+			Return(&ec2.DescribeInstancesOutput{
+				Reservations: []types.Reservation{{
+					Instances: []types.Instance{{
+						State: &types.InstanceState{Name: types.InstanceStateNameShuttingDown},
+					}},
+				}},
+			}, nil)
+		mockEC2Client.EXPECT().
+			DescribeInstances(gomock.Any(), gomock.Any()).
+			Return(&ec2.DescribeInstancesOutput{
+				Reservations: []types.Reservation{{
+					Instances: []types.Instance{{
+						State: &types.InstanceState{Name: types.InstanceStateNameTerminated},
+					}},
+				}},
+			}, nil)
+
+		deleted, err := client.ResourceDeleted("i-0abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeFalse())
+		deleted, err = client.ResourceDeleted("i-0abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+	})
+
+	It("should look up roles and policies in IAM", func() {
+		mockIAMClient.EXPECT().
+			GetRole(gomock.Any(), &iam.GetRoleInput{RoleName: aws.String("my-role")}).
+			Return(&iam.GetRoleOutput{Role: &iamtypes.Role{RoleName: aws.String("my-role")}}, nil)
+		mockIAMClient.EXPECT().
+			GetPolicy(gomock.Any(), gomock.Any()).
+			Return(nil, &iamtypes.NoSuchEntityException{Message: aws.String("not found")})
+
+		existing, err := client.ResourceExisting("role-my-role")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(existing).To(BeTrue())
+		existing, err = client.ResourceExisting("policy-arn:aws:iam::123456789012:policy/my-policy")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(existing).To(BeFalse())
+	})
+
+	It("should prefer the longest registered prefix", func() {
+		RegisterResourceDescriber("test-attach", func(context.Context, *AWSClient, string) (ResourceState, error) {
+			return ResourceAvailable, nil
+		})
+		DeferCleanup(UnregisterResourceDescriber, "test-attach")
+		RegisterResourceDescriber("test", func(context.Context, *AWSClient, string) (ResourceState, error) {
+			return ResourcePending, nil
+		})
+		DeferCleanup(UnregisterResourceDescriber, "test")
+
+		state, err := client.DescribeResourceState("test-attach-0abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).To(Equal(ResourceAvailable))
+		state, err = client.DescribeResourceState("test-0abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).To(Equal(ResourcePending))
+
+		UnregisterResourceDescriber("test-attach")
+		state, err = client.DescribeResourceState("test-attach-0abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).To(Equal(ResourcePending))
+	})
+})
+
 var _ = Describe("WaitForResourceExisting", func() {
 	var (
 		mockCtrl      *gomock.Controller
@@ -282,4 +427,62 @@ var _ = Describe("WaitForResourceExisting", func() {
 			Expect(duration).To(BeNumerically(">=", 60*time.Second))
 		})
 	})
+
+	It("should keep waiting through throttling", func() {
+		gomock.InOrder(
+			mockEC2Client.EXPECT().
+				DescribeVpcs(gomock.Any(), gomock.Any()).
+				Return(nil, &smithy.GenericAPIError{Code: "RequestLimitExceeded", Message: "slow down"}),
+			mockEC2Client.EXPECT().
+				DescribeVpcs(gomock.Any(), gomock.Any()).
+				Return(nil, &smithy.GenericAPIError{Code: awserrors.Throttling, Message: "rate exceeded"}),
+			mockEC2Client.EXPECT().
+				DescribeVpcs(gomock.Any(), gomock.Any()).
+				Return(&ec2.DescribeVpcsOutput{Vpcs: []types.Vpc{{VpcId: aws.String("vpc-1")}}}, nil),
+		)
+
+		Expect(client.WaitForResourceExisting("vpc-1", 20)).To(Succeed())
+	})
+
+	It("should stop waiting on other errors", func() {
+		mockEC2Client.EXPECT().
+			DescribeVpcs(gomock.Any(), gomock.Any()).
+			Return(nil, &smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "denied"})
+
+		err := client.WaitForResourceExisting("vpc-1", 20)
+		Expect(awserrors.IsErrorCode(err, "UnauthorizedOperation")).To(BeTrue())
+	})
+})
+
+var _ = Describe("WaitForResourceDeleted", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockEC2Client *MockEC2ClientAPI
+		client        *AWSClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockEC2Client = NewMockEC2ClientAPI(mockCtrl)
+		client = &AWSClient{
+			Ec2Client: mockEC2Client,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should keep waiting through throttling", func() {
+		gomock.InOrder(
+			mockEC2Client.EXPECT().
+				DescribeSubnets(gomock.Any(), gomock.Any()).
+				Return(nil, &smithy.GenericAPIError{Code: awserrors.Throttling, Message: "rate exceeded"}),
+			mockEC2Client.EXPECT().
+				DescribeSubnets(gomock.Any(), gomock.Any()).
+				Return(nil, &smithy.GenericAPIError{Code: "InvalidSubnetID.NotFound", Message: "gone"}),
+		)
+
+		Expect(client.WaitForResourceDeleted("subnet-1", 20)).To(Succeed())
+	})
 })
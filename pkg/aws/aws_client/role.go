@@ -1,11 +1,11 @@
 package aws_client
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
 	"github.com/openshift-online/ocm-common/pkg/log"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -47,7 +47,7 @@ func (client *AWSClient) CreateRole(roleName string,
 		input.Tags = roleTags
 	}
 	var resp *iam.CreateRoleOutput
-	resp, err = client.IamClient.CreateRole(context.TODO(), input)
+	resp, err = client.IamClient.CreateRole(client.requestContext(), input)
 	if err == nil && resp != nil {
 		role = *resp.Role
 		err = client.WaitForResourceExisting("role-"+*resp.Role.RoleName, 10) // add a prefix to meet the resourceExisting split rule
@@ -73,15 +73,18 @@ func (client *AWSClient) GetRole(roleName string) (*types.Role, error) {
 	input := &iam.GetRoleInput{
 		RoleName: &roleName,
 	}
-	out, err := client.IamClient.GetRole(context.TODO(), input)
-	return out.Role, err
+	out, err := client.IamClient.GetRole(client.requestContext(), input)
+	if err != nil {
+		return nil, err
+	}
+	return out.Role, nil
 }
 func (client *AWSClient) DeleteRole(roleName string) error {
 
 	input := &iam.DeleteRoleInput{
 		RoleName: &roleName,
 	}
-	_, err := client.IamClient.DeleteRole(context.TODO(), input)
+	_, err := client.IamClient.DeleteRole(client.requestContext(), input)
 	return err
 }
 
@@ -89,7 +92,7 @@ func (client *AWSClient) DeleteRoleAndPolicy(roleName string, managedPolicy bool
 	input := &iam.ListAttachedRolePoliciesInput{
 		RoleName: &roleName,
 	}
-	output, err := client.IamClient.ListAttachedRolePolicies(client.ClientContext, input)
+	output, err := client.IamClient.ListAttachedRolePolicies(client.requestContext(), input)
 	if err != nil {
 		return err
 	}
@@ -118,7 +121,7 @@ func (client *AWSClient) DeleteRoleAndPolicy(roleName string, managedPolicy bool
 
 func (client *AWSClient) ListRoles() ([]types.Role, error) {
 	input := &iam.ListRolesInput{}
-	out, err := client.IamClient.ListRoles(context.TODO(), input)
+	out, err := client.IamClient.ListRoles(client.requestContext(), input)
 	return out.Roles, err
 }
 
@@ -140,7 +143,7 @@ func (client *AWSClient) ListAttachedRolePolicies(roleName string) ([]types.Atta
 	policyLister := iam.ListAttachedRolePoliciesInput{
 		RoleName: &roleName,
 	}
-	policyOut, err := client.IamClient.ListAttachedRolePolicies(context.TODO(), &policyLister)
+	policyOut, err := client.IamClient.ListAttachedRolePolicies(client.requestContext(), &policyLister)
 	if err != nil {
 		return policies, err
 	}
@@ -157,7 +160,28 @@ func (client *AWSClient) DetachRolePolicies(roleName string) error {
 			PolicyArn: policy.PolicyArn,
 			RoleName:  &roleName,
 		}
-		_, err := client.IamClient.DetachRolePolicy(context.TODO(), &policyDetacher)
+		_, err := client.IamClient.DetachRolePolicy(client.requestContext(), &policyDetacher)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteRoleInlinePolicies deletes the inline policies of a role, which IAM requires before the
+// role can be deleted.
+func (client *AWSClient) DeleteRoleInlinePolicies(roleName string) error {
+	out, err := client.IamClient.ListRolePolicies(client.requestContext(), &iam.ListRolePoliciesInput{
+		RoleName: &roleName,
+	})
+	if err != nil {
+		return err
+	}
+	for _, policyName := range out.PolicyNames {
+		_, err = client.IamClient.DeleteRolePolicy(client.requestContext(), &iam.DeleteRolePolicyInput{
+			PolicyName: aws.String(policyName),
+			RoleName:   &roleName,
+		})
 		if err != nil {
 			return err
 		}
@@ -169,7 +193,7 @@ func (client *AWSClient) DeleteRoleInstanceProfiles(roleName string) error {
 	inProfileLister := iam.ListInstanceProfilesForRoleInput{
 		RoleName: &roleName,
 	}
-	out, err := client.IamClient.ListInstanceProfilesForRole(context.TODO(), &inProfileLister)
+	out, err := client.IamClient.ListInstanceProfilesForRole(client.requestContext(), &inProfileLister)
 	if err != nil {
 		return err
 	}
@@ -178,7 +202,7 @@ func (client *AWSClient) DeleteRoleInstanceProfiles(roleName string) error {
 			InstanceProfileName: inProfile.InstanceProfileName,
 			RoleName:            &roleName,
 		}
-		_, err = client.IamClient.RemoveRoleFromInstanceProfile(context.TODO(), &profileDeleter)
+		_, err = client.IamClient.RemoveRoleFromInstanceProfile(client.requestContext(), &profileDeleter)
 		if err != nil {
 			return err
 		}
@@ -189,25 +213,13 @@ func (client *AWSClient) DeleteRoleInstanceProfiles(roleName string) error {
 
 func (client *AWSClient) CreateIAMRole(roleName string, ProdENVTrustedRole string, StageENVTrustedRole string, StageIssuerTrustedRole string, policyArn string,
 	externalID ...string) (types.Role, error) {
-	statement := map[string]interface{}{
-		"Effect": "Allow",
-		"Principal": map[string]interface{}{
-			"Service": "ec2.amazonaws.com",
-			"AWS": []string{
-				ProdENVTrustedRole,
-				StageENVTrustedRole,
-				StageIssuerTrustedRole,
-			},
-		},
-		"Action": "sts:AssumeRole",
-	}
+	statement := iampolicy.NewStatement().
+		AddServicePrincipal("ec2.amazonaws.com").
+		AddAWSPrincipal(ProdENVTrustedRole, StageENVTrustedRole, StageIssuerTrustedRole).
+		AddAction("sts:AssumeRole")
 
 	if len(externalID) == 1 {
-		statement["Condition"] = map[string]map[string]string{
-			"StringEquals": {
-				"sts:ExternalId": "aaaa",
-			},
-		}
+		statement.AddCondition("StringEquals", "sts:ExternalId", "aaaa")
 	}
 
 	assumeRolePolicyDocument, err := completeRolePolicyDocument(statement)
@@ -221,13 +233,9 @@ func (client *AWSClient) CreateIAMRole(roleName string, ProdENVTrustedRole strin
 
 func (client *AWSClient) CreateRegularRole(roleName string, policyArn string) (types.Role, error) {
 
-	statement := map[string]interface{}{
-		"Effect": "Allow",
-		"Principal": map[string]interface{}{
-			"Service": "ec2.amazonaws.com",
-		},
-		"Action": "sts:AssumeRole",
-	}
+	statement := iampolicy.NewStatement().
+		AddServicePrincipal("ec2.amazonaws.com").
+		AddAction("sts:AssumeRole")
 
 	assumeRolePolicyDocument, err := completeRolePolicyDocument(statement)
 	if err != nil {
@@ -238,18 +246,11 @@ func (client *AWSClient) CreateRegularRole(roleName string, policyArn string) (t
 }
 
 func (client *AWSClient) CreateRoleForAuditLogForward(roleName, awsAccountID string, oidcEndpointURL string, policyArn string) (types.Role, error) {
-	statement := map[string]interface{}{
-		"Effect": "Allow",
-		"Principal": map[string]interface{}{
-			"Federated": fmt.Sprintf("arn:aws:iam::%s:oidc-provider/%s", awsAccountID, oidcEndpointURL),
-		},
-		"Action": "sts:AssumeRoleWithWebIdentity",
-		"Condition": map[string]interface{}{
-			"StringEquals": map[string]interface{}{
-				fmt.Sprintf("%s:sub", oidcEndpointURL): "system:serviceaccount:openshift-config-managed:cloudwatch-audit-exporter",
-			},
-		},
-	}
+	statement := iampolicy.NewStatement().
+		AddFederatedPrincipal(fmt.Sprintf("arn:aws:iam::%s:oidc-provider/%s", awsAccountID, oidcEndpointURL)).
+		AddAction("sts:AssumeRoleWithWebIdentity").
+		AddCondition("StringEquals", fmt.Sprintf("%s:sub", oidcEndpointURL),
+			"system:serviceaccount:openshift-config-managed:cloudwatch-audit-exporter")
 
 	assumeRolePolicyDocument, err := completeRolePolicyDocument(statement)
 	if err != nil {
@@ -260,30 +261,48 @@ func (client *AWSClient) CreateRoleForAuditLogForward(roleName, awsAccountID str
 	return client.CreateRoleAndAttachPolicy(roleName, string(assumeRolePolicyDocument), "", make(map[string]string), "/", policyArn)
 }
 
+// CreatePolicy creates a managed policy from untyped statements, sent as they are. Prefer
+// CreatePolicyFromDocument, which validates the document first.
 func (client *AWSClient) CreatePolicy(policyName string, statements ...map[string]interface{}) (string, error) {
-	timeCreation := time.Now().Local().String()
-	description := fmt.Sprintf("Created by OCM QE at %s", timeCreation)
 	document := map[string]interface{}{
-		"Version":   "2012-10-17",
-		"Statement": []map[string]interface{}{},
+		"Version":   iampolicy.Version20121017,
+		"Statement": statements,
 	}
-	if len(statements) != 0 {
-		for _, statement := range statements {
-			document["Statement"] = append(document["Statement"].([]map[string]interface{}), statement)
-		}
+	if statements == nil {
+		document["Statement"] = []map[string]interface{}{}
 	}
 	documentBytes, err := json.Marshal(document)
 	if err != nil {
-		err = fmt.Errorf("error to unmarshal the statement to string: %v", err)
+		return "", fmt.Errorf("error to marshal the statement to string: %v", err)
+	}
+	return client.createPolicy(policyName, string(documentBytes))
+}
+
+// CreatePolicyFromDocument validates document and creates a managed policy from it.
+func (client *AWSClient) CreatePolicyFromDocument(policyName string, document *iampolicy.Document) (string, error) {
+	if err := document.Validate(); err != nil {
+		return "", err
+	}
+	if err := document.ValidateSize(iampolicy.ManagedPolicySizeLimit); err != nil {
+		return "", err
+	}
+	documentStr, err := document.JSON()
+	if err != nil {
 		return "", err
 	}
-	documentStr := string(documentBytes)
+	return client.createPolicy(policyName, documentStr)
+}
+
+// createPolicy creates a managed policy from documentStr.
+func (client *AWSClient) createPolicy(policyName string, documentStr string) (string, error) {
+	timeCreation := time.Now().Local().String()
+	description := fmt.Sprintf("Created by OCM QE at %s", timeCreation)
 	policyCreator := iam.CreatePolicyInput{
 		PolicyDocument: &documentStr,
 		PolicyName:     &policyName,
 		Description:    &description,
 	}
-	outRes, err := client.IamClient.CreatePolicy(context.TODO(), &policyCreator)
+	outRes, err := client.IamClient.CreatePolicy(client.requestContext(), &policyCreator)
 	if err != nil {
 		return "", err
 	}
@@ -293,29 +312,21 @@ func (client *AWSClient) CreatePolicy(policyName string, statements ...map[strin
 
 func (client *AWSClient) CreatePolicyForAuditLogForward(policyName string) (string, error) {
 
-	statement := map[string]interface{}{
-		"Effect":   "Allow",
-		"Resource": "arn:aws:logs:*:*:*",
-		"Action": []string{
+	statement := iampolicy.NewStatement().
+		AddResource("arn:aws:logs:*:*:*").
+		AddAction(
 			"logs:PutLogEvents",
 			"logs:CreateLogGroup",
 			"logs:PutRetentionPolicy",
 			"logs:CreateLogStream",
 			"logs:DescribeLogGroups",
 			"logs:DescribeLogStreams",
-		},
-	}
-	return client.CreatePolicy(policyName, statement)
+		)
+	return client.CreatePolicyFromDocument(policyName, iampolicy.NewDocument().AddStatement(statement))
 }
 
-func completeRolePolicyDocument(statement map[string]interface{}) (string, error) {
-	rolePolicyDocument := map[string]interface{}{
-		"Version":   "2012-10-17",
-		"Statement": statement,
-	}
-
-	assumeRolePolicyDocument, err := json.Marshal(rolePolicyDocument)
-	return string(assumeRolePolicyDocument), err
+func completeRolePolicyDocument(statement *iampolicy.Statement) (string, error) {
+	return iampolicy.NewDocument().AddStatement(statement).JSON()
 }
 
 func (client *AWSClient) AttachPolicy(roleName string, policyArn string, retries int, retryIntervalInSeconds time.Duration) error {
@@ -323,7 +334,7 @@ func (client *AWSClient) AttachPolicy(roleName string, policyArn string, retries
 		PolicyArn: &policyArn,
 		RoleName:  &roleName,
 	}
-	_, err := client.IamClient.AttachRolePolicy(context.TODO(), &policyAttach)
+	_, err := client.IamClient.AttachRolePolicy(client.requestContext(), &policyAttach)
 	if err != nil {
 		return err
 	}
@@ -347,7 +358,9 @@ func (client *AWSClient) AttachPolicy(roleName string, policyArn string, retries
 		if attached {
 			return nil
 		}
-		time.Sleep(retryIntervalInSeconds)
+		if err := client.sleep(retryIntervalInSeconds); err != nil {
+			return err
+		}
 		start++
 	}
 	return fmt.Errorf("failed to attach policy to role but no errors were thrown, please investigate")
@@ -371,7 +384,7 @@ func (client *AWSClient) ListRoleAttachedPolicies(roleName string) ([]types.Atta
 	policyLister := iam.ListAttachedRolePoliciesInput{
 		RoleName: &roleName,
 	}
-	policyOut, err := client.IamClient.ListAttachedRolePolicies(context.TODO(), &policyLister)
+	policyOut, err := client.IamClient.ListAttachedRolePolicies(client.requestContext(), &policyLister)
 	if err != nil {
 		return policies, err
 	}
@@ -390,7 +403,7 @@ func (client *AWSClient) TagRole(roleName string, tags map[string]string) error
 		RoleName: &roleName,
 		Tags:     roleTags,
 	}
-	_, err := client.IamClient.TagRole(context.TODO(), input)
+	_, err := client.IamClient.TagRole(client.requestContext(), input)
 	return err
 }
 
@@ -399,19 +412,15 @@ func (client *AWSClient) UntagRole(roleName string, tagKeys []string) error {
 		RoleName: &roleName,
 		TagKeys:  tagKeys,
 	}
-	_, err := client.IamClient.UntagRole(context.TODO(), input)
+	_, err := client.IamClient.UntagRole(client.requestContext(), input)
 	return err
 }
 
 func (client *AWSClient) CreateRoleForSharedVPC(roleName, installerRoleArn string, ingressOperatorRoleArn string) (types.Role, error) {
-	statement := map[string]interface{}{
-		"Sid":    "Statement1",
-		"Effect": "Allow",
-		"Principal": map[string]interface{}{
-			"AWS": []string{installerRoleArn, ingressOperatorRoleArn},
-		},
-		"Action": "sts:AssumeRole",
-	}
+	statement := iampolicy.NewStatement().
+		SetSid("Statement1").
+		AddAWSPrincipal(installerRoleArn, ingressOperatorRoleArn).
+		AddAction("sts:AssumeRole")
 
 	assumeRolePolicyDocument, err := completeRolePolicyDocument(statement)
 	if err != nil {
@@ -426,14 +435,10 @@ func (client *AWSClient) CreateRoleForSharedVPC(roleName, installerRoleArn strin
 // shared-vpc cluster only. This function can be used for both classic and hosted-cp shared-vpc cluster. Keep CreateRoleForSharedVPC
 // for the compatibility of the eale reference
 func (client *AWSClient) CreateRoleForSharedVPCHCP(roleName string, assumeRolesArns []string) (types.Role, error) {
-	statement := map[string]interface{}{
-		"Sid":    "Statement1",
-		"Effect": "Allow",
-		"Principal": map[string]interface{}{
-			"AWS": assumeRolesArns,
-		},
-		"Action": "sts:AssumeRole",
-	}
+	statement := iampolicy.NewStatement().
+		SetSid("Statement1").
+		AddAWSPrincipal(assumeRolesArns...).
+		AddAction("sts:AssumeRole")
 
 	assumeRolePolicyDocument, err := completeRolePolicyDocument(statement)
 	if err != nil {
@@ -445,10 +450,9 @@ func (client *AWSClient) CreateRoleForSharedVPCHCP(roleName string, assumeRolesA
 }
 
 func (client *AWSClient) CreatePolicyForSharedVPC(policyName string) (string, error) {
-	statement := map[string]interface{}{
-		"Sid":    "Statement1",
-		"Effect": "Allow",
-		"Action": []string{
+	statement := iampolicy.NewStatement().
+		SetSid("Statement1").
+		AddAction(
 			"route53:GetChange",
 			"route53:GetHostedZone",
 			"route53:ChangeResourceRecordSets",
@@ -461,17 +465,15 @@ func (client *AWSClient) CreatePolicyForSharedVPC(policyName string) (string, er
 			"route53:UpdateHostedZoneComment",
 			"tag:GetResources",
 			"tag:UntagResources",
-		},
-		"Resource": "*",
-	}
-	return client.CreatePolicy(policyName, statement)
+		).
+		AddResource("*")
+	return client.CreatePolicyFromDocument(policyName, iampolicy.NewDocument().AddStatement(statement))
 }
 
 func (client *AWSClient) CreatePolicyForSharedVPCEndpoint(policyName string) (string, error) {
-	statement := map[string]interface{}{
-		"Sid":    "Statement1",
-		"Effect": "Allow",
-		"Action": []string{
+	statement := iampolicy.NewStatement().
+		SetSid("Statement1").
+		AddAction(
 			"ec2:CreateVpcEndpoint",
 			"ec2:DescribeVpcEndpoints",
 			"ec2:ModifyVpcEndpoint",
@@ -488,21 +490,16 @@ func (client *AWSClient) CreatePolicyForSharedVPCEndpoint(policyName string) (st
 			"route53:ListHostedZones",
 			"route53:ChangeResourceRecordSets",
 			"route53:ListResourceRecordSets",
-		},
-		"Resource": "*",
-	}
-	return client.CreatePolicy(policyName, statement)
+		).
+		AddResource("*")
+	return client.CreatePolicyFromDocument(policyName, iampolicy.NewDocument().AddStatement(statement))
 }
 
 func (client *AWSClient) CreateRoleForAdditionalPrincipals(roleName string, installerRoleArn string) (types.Role, error) {
-	statement := map[string]interface{}{
-		"Sid":    "Statement1",
-		"Effect": "Allow",
-		"Principal": map[string]interface{}{
-			"AWS": []string{installerRoleArn},
-		},
-		"Action": "sts:AssumeRole",
-	}
+	statement := iampolicy.NewStatement().
+		SetSid("Statement1").
+		AddAWSPrincipal(installerRoleArn).
+		AddAction("sts:AssumeRole")
 
 	assumeRolePolicyDocument, err := completeRolePolicyDocument(statement)
 	if err != nil {
@@ -519,7 +516,7 @@ func (client *AWSClient) UpdateAssumeRolePolicy(roleName string, assumeRolePolic
 		PolicyDocument: &assumeRolePolicyDocument,
 	}
 
-	_, err := client.IamClient.UpdateAssumeRolePolicy(context.TODO(), input)
+	_, err := client.IamClient.UpdateAssumeRolePolicy(client.requestContext(), input)
 	if err != nil {
 		return err
 	}
@@ -529,14 +526,10 @@ func (client *AWSClient) UpdateAssumeRolePolicy(roleName string, assumeRolePolic
 func (client *AWSClient) UpdateAssumeRolePolicyForSharedVPCRole(roleName string, roleArns ...string) error {
 	roleArnsList := []string{}
 	roleArnsList = append(roleArnsList, roleArns...)
-	statement := map[string]interface{}{
-		"Sid":    "Statement1",
-		"Effect": "Allow",
-		"Principal": map[string]interface{}{
-			"AWS": roleArnsList,
-		},
-		"Action": "sts:AssumeRole",
-	}
+	statement := iampolicy.NewStatement().
+		SetSid("Statement1").
+		AddAWSPrincipal(roleArnsList...).
+		AddAction("sts:AssumeRole")
 
 	assumeRolePolicyDocument, err := completeRolePolicyDocument(statement)
 	if err != nil {
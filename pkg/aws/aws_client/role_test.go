@@ -0,0 +1,109 @@
+package aws_client_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+)
+
+var _ = Describe("Roles", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockIAMClient *MockIAMClientAPI
+		client        *AWSClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockIAMClient = NewMockIAMClientAPI(mockCtrl)
+		client = &AWSClient{
+			IamClient: mockIAMClient,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should return the role", func() {
+		mockIAMClient.EXPECT().
+			GetRole(gomock.Any(), &iam.GetRoleInput{RoleName: aws.String("installer")}).
+			Return(&iam.GetRoleOutput{Role: &types.Role{RoleName: aws.String("installer")}}, nil)
+
+		role, err := client.GetRole("installer")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.ToString(role.RoleName)).To(Equal("installer"))
+	})
+
+	It("should return the GetRole error", func() {
+		mockIAMClient.EXPECT().
+			GetRole(gomock.Any(), gomock.Any()).
+			Return(nil, &types.NoSuchEntityException{Message: aws.String("not found")})
+
+		role, err := client.GetRole("missing")
+		Expect(role).To(BeNil())
+		var notFound *types.NoSuchEntityException
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+	})
+
+	It("should send untyped policy statements without validating them", func() {
+		mockIAMClient.EXPECT().
+			CreatePolicy(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, input *iam.CreatePolicyInput, _ ...func(*iam.Options)) (*iam.CreatePolicyOutput, error) {
+				Expect(aws.ToString(input.PolicyDocument)).To(Equal(
+					`{"Statement":[{"Action":"s3:GetObject","Custom":true}],"Version":"2012-10-17"}`))
+				return &iam.CreatePolicyOutput{Policy: &types.Policy{Arn: aws.String("arn:aws:iam::123456789012:policy/custom")}}, nil
+			})
+
+		policyArn, err := client.CreatePolicy("custom", map[string]interface{}{"Action": "s3:GetObject", "Custom": true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(policyArn).To(Equal("arn:aws:iam::123456789012:policy/custom"))
+	})
+
+	It("should detach and delete customer policies before deleting the role", func() {
+		policyArn := "arn:aws:iam::123456789012:policy/custom"
+		gomock.InOrder(
+			mockIAMClient.EXPECT().
+				ListAttachedRolePolicies(gomock.Any(), gomock.Any()).
+				Return(&iam.ListAttachedRolePoliciesOutput{
+					AttachedPolicies: []types.AttachedPolicy{{PolicyArn: aws.String(policyArn)}},
+				}, nil),
+			mockIAMClient.EXPECT().
+				DetachRolePolicy(gomock.Any(), &iam.DetachRolePolicyInput{
+					RoleName:  aws.String("worker"),
+					PolicyArn: aws.String(policyArn),
+				}).
+				Return(&iam.DetachRolePolicyOutput{}, nil),
+			mockIAMClient.EXPECT().
+				ListPolicyVersions(gomock.Any(), gomock.Any()).
+				Return(&iam.ListPolicyVersionsOutput{}, nil),
+			mockIAMClient.EXPECT().
+				DeletePolicy(gomock.Any(), &iam.DeletePolicyInput{PolicyArn: aws.String(policyArn)}).
+				Return(&iam.DeletePolicyOutput{}, nil),
+			mockIAMClient.EXPECT().
+				DeleteRole(gomock.Any(), &iam.DeleteRoleInput{RoleName: aws.String("worker")}).
+				Return(&iam.DeleteRoleOutput{}, nil),
+		)
+
+		Expect(client.DeleteRoleAndPolicy("worker", false)).To(Succeed())
+	})
+
+	It("should only detach managed policies", func() {
+		mockIAMClient.EXPECT().
+			ListAttachedRolePolicies(gomock.Any(), gomock.Any()).
+			Return(&iam.ListAttachedRolePoliciesOutput{
+				AttachedPolicies: []types.AttachedPolicy{{PolicyArn: aws.String("arn:aws:iam::aws:policy/ReadOnlyAccess")}},
+			}, nil)
+		mockIAMClient.EXPECT().DetachRolePolicy(gomock.Any(), gomock.Any()).Return(&iam.DetachRolePolicyOutput{}, nil)
+		mockIAMClient.EXPECT().DeleteRole(gomock.Any(), gomock.Any()).Return(&iam.DeleteRoleOutput{}, nil)
+
+		Expect(client.DeleteRoleAndPolicy("worker", true)).To(Succeed())
+	})
+})
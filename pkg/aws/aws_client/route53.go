@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	"github.com/aws/aws-sdk-go-v2/service/route53/types"
 	"github.com/openshift-online/ocm-common/pkg/log"
@@ -27,7 +25,7 @@ func (awsClient AWSClient) CreateHostedZone(hostedZoneName string, callerReferen
 		input.VPC = vpc
 	}
 
-	resp, err := awsClient.Route53Client.CreateHostedZone(context.TODO(), input)
+	resp, err := awsClient.Route53Client.CreateHostedZone(awsClient.requestContext(), input)
 	if err != nil {
 		log.LogError("Create hosted zone failed for vpc %s with name %s: %s", vpcID, hostedZoneName, err.Error())
 	} else {
@@ -41,7 +39,7 @@ func (awsClient AWSClient) GetHostedZone(hostedZoneID string) (*route53.GetHoste
 		Id: &hostedZoneID,
 	}
 
-	return awsClient.Route53Client.GetHostedZone(context.TODO(), input)
+	return awsClient.Route53Client.GetHostedZone(awsClient.requestContext(), input)
 }
 
 func (awsClient AWSClient) ListHostedZoneByDNSName(hostedZoneName string) (*route53.ListHostedZonesByNameOutput, error) {
@@ -51,7 +49,7 @@ func (awsClient AWSClient) ListHostedZoneByDNSName(hostedZoneName string) (*rout
 		MaxItems: &maxItems,
 	}
 
-	return awsClient.Route53Client.ListHostedZonesByName(context.TODO(), input)
+	return awsClient.Route53Client.ListHostedZonesByName(awsClient.requestContext(), input)
 }
 
 func (awsClient AWSClient) DeleteHostedZone(hostedZoneID string) error {
@@ -59,6 +57,59 @@ func (awsClient AWSClient) DeleteHostedZone(hostedZoneID string) error {
 		Id: &hostedZoneID,
 	}
 
-	_, err := awsClient.Route53Client.DeleteHostedZone(context.TODO(), input)
+	_, err := awsClient.Route53Client.DeleteHostedZone(awsClient.requestContext(), input)
 	return err
 }
+
+// ListResourceRecordSets returns every record set of the hosted zone.
+func (awsClient AWSClient) ListResourceRecordSets(hostedZoneID string) ([]types.ResourceRecordSet, error) {
+	recordSets := []types.ResourceRecordSet{}
+	input := &route53.ListResourceRecordSetsInput{
+		HostedZoneId: &hostedZoneID,
+	}
+	for {
+		resp, err := awsClient.Route53Client.ListResourceRecordSets(awsClient.requestContext(), input)
+		if err != nil {
+			return nil, err
+		}
+		recordSets = append(recordSets, resp.ResourceRecordSets...)
+		if !resp.IsTruncated {
+			return recordSets, nil
+		}
+		input.StartRecordName = resp.NextRecordName
+		input.StartRecordType = resp.NextRecordType
+		input.StartRecordIdentifier = resp.NextRecordIdentifier
+	}
+}
+
+// EmptyHostedZone deletes the record sets of the hosted zone but its SOA and NS records, in one
+// batch, as Route53 refuses to delete a zone holding other records.
+func (awsClient AWSClient) EmptyHostedZone(hostedZoneID string) error {
+	recordSets, err := awsClient.ListResourceRecordSets(hostedZoneID)
+	if err != nil {
+		return err
+	}
+	changes := []types.Change{}
+	for _, recordSet := range recordSets {
+		if recordSet.Type == types.RRTypeSoa || recordSet.Type == types.RRTypeNs {
+			continue
+		}
+		changes = append(changes, types.Change{
+			Action:            types.ChangeActionDelete,
+			ResourceRecordSet: &recordSet,
+		})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	_, err = awsClient.Route53Client.ChangeResourceRecordSets(awsClient.requestContext(), &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: &hostedZoneID,
+		ChangeBatch:  &types.ChangeBatch{Changes: changes},
+	})
+	if err != nil {
+		log.LogError("Delete the records of hosted zone %s failed: %s", hostedZoneID, err.Error())
+		return err
+	}
+	log.LogInfo("Deleted %d records of hosted zone %s", len(changes), hostedZoneID)
+	return nil
+}
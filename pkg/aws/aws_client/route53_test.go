@@ -0,0 +1,59 @@
+package aws_client_test
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+)
+
+var _ = Describe("Hosted zones", func() {
+	var (
+		mockCtrl          *gomock.Controller
+		mockRoute53Client *MockRoute53ClientAPI
+		client            *AWSClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRoute53Client = NewMockRoute53ClientAPI(mockCtrl)
+		client = &AWSClient{
+			Route53Client: mockRoute53Client,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should associate a private hosted zone with the VPC", func() {
+		mockRoute53Client.EXPECT().
+			CreateHostedZone(gomock.Any(), &route53.CreateHostedZoneInput{
+				Name:             aws.String("example.com"),
+				CallerReference:  aws.String("ref-1"),
+				HostedZoneConfig: &types.HostedZoneConfig{PrivateZone: true},
+				VPC:              &types.VPC{VPCId: aws.String("vpc-1"), VPCRegion: types.VPCRegionUsEast1},
+			}).
+			Return(&route53.CreateHostedZoneOutput{HostedZone: &types.HostedZone{Id: aws.String("Z1")}}, nil)
+
+		out, err := client.CreateHostedZone("example.com", "ref-1", "vpc-1", "us-east-1", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.ToString(out.HostedZone.Id)).To(Equal("Z1"))
+	})
+
+	It("should create a public hosted zone without a VPC", func() {
+		mockRoute53Client.EXPECT().
+			CreateHostedZone(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, input *route53.CreateHostedZoneInput, _ ...func(*route53.Options)) (*route53.CreateHostedZoneOutput, error) {
+				Expect(input.VPC).To(BeNil())
+				return &route53.CreateHostedZoneOutput{}, nil
+			})
+
+		_, err := client.CreateHostedZone("example.com", "ref-1", "", "", false)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -1,7 +1,6 @@
 package aws_client
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -19,7 +18,7 @@ func (client *AWSClient) CreateRouteTable(vpcID string) (*ec2.CreateRouteTableOu
 		TagSpecifications: nil,
 	}
 
-	respCreateRT, err := client.Ec2Client.CreateRouteTable(context.TODO(), inputCreateRouteTable)
+	respCreateRT, err := client.Ec2Client.CreateRouteTable(client.requestContext(), inputCreateRouteTable)
 	if err != nil {
 		log.LogError("Create route table failed %s", err.Error())
 		return nil, err
@@ -36,7 +35,7 @@ func (client *AWSClient) AssociateRouteTable(routeTableID string, subnetID strin
 		SubnetId:     aws.String(subnetID),
 	}
 
-	respAssociateRouteTable, err := client.Ec2Client.AssociateRouteTable(context.TODO(), inputAssociateRouteTable)
+	respAssociateRouteTable, err := client.Ec2Client.AssociateRouteTable(client.requestContext(), inputAssociateRouteTable)
 	if err != nil {
 		log.LogError("Associate route table failed %s", err.Error())
 		return nil, err
@@ -59,7 +58,7 @@ func (client *AWSClient) ListCustomerRouteTables(vpcID string) ([]types.RouteTab
 	ListRouteTable := &ec2.DescribeRouteTablesInput{
 		Filters: Filters,
 	}
-	resp, err := client.Ec2Client.DescribeRouteTables(context.TODO(), ListRouteTable)
+	resp, err := client.Ec2Client.DescribeRouteTables(client.requestContext(), ListRouteTable)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +84,7 @@ func (client *AWSClient) ListRTAssociations(routeTableID string) ([]string, erro
 	ListRouteTable := &ec2.DescribeRouteTablesInput{
 		RouteTableIds: []string{routeTableID},
 	}
-	resp, err := client.Ec2Client.DescribeRouteTables(context.TODO(), ListRouteTable)
+	resp, err := client.Ec2Client.DescribeRouteTables(client.requestContext(), ListRouteTable)
 	if err != nil {
 		return associations, err
 	}
@@ -103,7 +102,7 @@ func (client *AWSClient) DisassociateRouteTableAssociation(associationID string)
 		DryRun:        nil,
 	}
 
-	resp, err := client.Ec2Client.DisassociateRouteTable(context.TODO(), input)
+	resp, err := client.Ec2Client.DisassociateRouteTable(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Disassociate route table failed %s", err.Error())
 		return nil, err
@@ -128,11 +127,23 @@ func (client *AWSClient) DisassociateRouteTableAssociations(routeTableID string)
 }
 
 func (client *AWSClient) CreateRoute(routeTableID string, targetID string) (*types.Route, error) {
+	return client.CreateRouteWithDestination(routeTableID, CON.RouteDestinationCidrBlock, targetID)
+}
+
+// CreateRouteWithDestination routes the destination CIDR block to the target. IPv6 destinations
+// such as "::/0" are sent as DestinationIpv6CidrBlock.
+func (client *AWSClient) CreateRouteWithDestination(routeTableID string, destination string, targetID string) (*types.Route, error) {
 	prefix := strings.Split(targetID, "-")[0]
 	route := &types.Route{}
 	createRouteInput := &ec2.CreateRouteInput{
-		RouteTableId:         aws.String(routeTableID),
-		DestinationCidrBlock: aws.String(CON.RouteDestinationCidrBlock),
+		RouteTableId: aws.String(routeTableID),
+	}
+	if isIPv6Destination(destination) {
+		createRouteInput.DestinationIpv6CidrBlock = aws.String(destination)
+		route.DestinationIpv6CidrBlock = aws.String(destination)
+	} else {
+		createRouteInput.DestinationCidrBlock = aws.String(destination)
+		route.DestinationCidrBlock = aws.String(destination)
 	}
 	switch prefix {
 	case "cagw":
@@ -159,24 +170,46 @@ func (client *AWSClient) CreateRoute(routeTableID string, targetID string) (*typ
 	case "tgw":
 		createRouteInput.TransitGatewayId = &targetID
 		route.TransitGatewayId = &targetID
+	case "pcx":
+		createRouteInput.VpcPeeringConnectionId = &targetID
+		route.VpcPeeringConnectionId = &targetID
 	default:
 		return nil, fmt.Errorf("the type %s is not define in the route creation func, please define it in CreateRoute", prefix)
 	}
 
-	_, err := client.Ec2Client.CreateRoute(context.TODO(), createRouteInput)
+	_, err := client.Ec2Client.CreateRoute(client.requestContext(), createRouteInput)
 	if err != nil {
 		log.LogError("Create route failed %s", err.Error())
 		return nil, err
 	}
-	log.LogInfo("Create route success for route table: %s", routeTableID)
+	log.LogInfo("Create route %s success for route table: %s", destination, routeTableID)
 	return route, err
 }
 
+// DeleteRoute removes the route to the destination CIDR block from the route table.
+func (client *AWSClient) DeleteRoute(routeTableID string, destinationCidrBlock string) error {
+	input := &ec2.DeleteRouteInput{
+		RouteTableId: aws.String(routeTableID),
+	}
+	if isIPv6Destination(destinationCidrBlock) {
+		input.DestinationIpv6CidrBlock = aws.String(destinationCidrBlock)
+	} else {
+		input.DestinationCidrBlock = aws.String(destinationCidrBlock)
+	}
+	_, err := client.Ec2Client.DeleteRoute(client.requestContext(), input)
+	if err != nil {
+		log.LogError("Delete route %s from route table %s failed %s", destinationCidrBlock, routeTableID, err.Error())
+		return err
+	}
+	log.LogInfo("Delete route %s success for route table: %s", destinationCidrBlock, routeTableID)
+	return nil
+}
+
 func (client *AWSClient) DeleteRouteTable(routeTableID string) error {
 	input := &ec2.DeleteRouteTableInput{
 		RouteTableId: &routeTableID,
 	}
-	_, err := client.Ec2Client.DeleteRouteTable(context.TODO(), input)
+	_, err := client.Ec2Client.DeleteRouteTable(client.requestContext(), input)
 	if err != nil {
 		return err
 	}
@@ -196,3 +229,8 @@ func (client *AWSClient) DeleteRouteTableChain(routeTableID string) error {
 	}
 	return err
 }
+
+// isIPv6Destination tells IPv6 route destinations apart from IPv4 ones.
+func isIPv6Destination(destination string) bool {
+	return strings.Contains(destination, ":")
+}
@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -24,7 +22,7 @@ func (client *AWSClient) ListSecurityGroups(vpcID string) ([]types.SecurityGroup
 	describeSGInput := &ec2.DescribeSecurityGroupsInput{
 		Filters: filter,
 	}
-	output, err := client.Ec2Client.DescribeSecurityGroups(context.TODO(), describeSGInput)
+	output, err := client.Ec2Client.DescribeSecurityGroups(client.requestContext(), describeSGInput)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +48,7 @@ func (client *AWSClient) ReleaseInboundOutboundRules(sgID string) error {
 	describeSGInput := &ec2.DescribeSecurityGroupRulesInput{
 		Filters: filter,
 	}
-	resp, err := client.Ec2Client.DescribeSecurityGroupRules(context.TODO(), describeSGInput)
+	resp, err := client.Ec2Client.DescribeSecurityGroupRules(client.requestContext(), describeSGInput)
 	if err != nil {
 		log.LogError("Describe  rules failed for SG %s: %s", sgID, err.Error())
 		return err
@@ -71,7 +69,7 @@ func (client *AWSClient) ReleaseInboundOutboundRules(sgID string) error {
 			GroupId:              &sgID,
 			SecurityGroupRuleIds: ingressRules,
 		}
-		_, err = client.Ec2Client.RevokeSecurityGroupIngress(context.TODO(), releaseIngressRuleInput)
+		_, err = client.Ec2Client.RevokeSecurityGroupIngress(client.requestContext(), releaseIngressRuleInput)
 		if err != nil {
 			log.LogError("Release inbound rules failed for SG %s: %s", sgID, err.Error())
 			return err
@@ -82,7 +80,7 @@ func (client *AWSClient) ReleaseInboundOutboundRules(sgID string) error {
 			GroupId:              &sgID,
 			SecurityGroupRuleIds: egressRules,
 		}
-		_, err = client.Ec2Client.RevokeSecurityGroupEgress(context.TODO(), releaseEgressRuleInput)
+		_, err = client.Ec2Client.RevokeSecurityGroupEgress(client.requestContext(), releaseEgressRuleInput)
 		if err != nil {
 			log.LogError("Release outbound rules failed for SG %s: %s", sgID, err.Error())
 			return err
@@ -105,7 +103,7 @@ func (client *AWSClient) DeleteSecurityGroup(groupID string) (*ec2.DeleteSecurit
 		GroupName: nil,
 	}
 
-	resp, err := client.Ec2Client.DeleteSecurityGroup(context.TODO(), input)
+	resp, err := client.Ec2Client.DeleteSecurityGroup(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Delete security group %s failed %s", groupID, err.Error())
 		return nil, err
@@ -128,7 +126,7 @@ func (client *AWSClient) AuthorizeSecurityGroupIngress(groupID string, cidr stri
 		ToPort:                     aws.Int32(toPort),
 	}
 
-	resp, err := client.Ec2Client.AuthorizeSecurityGroupIngress(context.TODO(), input)
+	resp, err := client.Ec2Client.AuthorizeSecurityGroupIngress(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Authorize security group failed %s", err.Error())
 		return nil, err
@@ -146,7 +144,7 @@ func (client *AWSClient) CreateSecurityGroup(vpcID string, groupName string, sgD
 		VpcId:             aws.String(vpcID),
 	}
 
-	resp, err := client.Ec2Client.CreateSecurityGroup(context.TODO(), input)
+	resp, err := client.Ec2Client.CreateSecurityGroup(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Create security group failed %s", err.Error())
 		return nil, err
@@ -172,7 +170,7 @@ func (client *AWSClient) GetSecurityGroupWithID(sgID string) (*ec2.DescribeSecur
 	describeSGInput := &ec2.DescribeSecurityGroupsInput{
 		GroupIds: []string{sgID},
 	}
-	output, err := client.Ec2Client.DescribeSecurityGroups(context.TODO(), describeSGInput)
+	output, err := client.Ec2Client.DescribeSecurityGroups(client.requestContext(), describeSGInput)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,15 @@
+package aws_client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// STSClientAPI defines the STS methods used by AWSClient.
+// This interface allows us to mock AWS STS calls in unit tests.
+//
+//go:generate mockgen -source=sts_client_interface.go -package=aws_client -destination=mock_sts_client.go
+type STSClientAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
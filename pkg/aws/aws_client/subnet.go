@@ -18,7 +18,12 @@ const (
 )
 
 func (client *AWSClient) CreateSubnet(vpcID string, zone string, subnetCidr string) (*types.Subnet, error) {
+	return client.CreateDualStackSubnet(vpcID, zone, subnetCidr, "")
+}
 
+// CreateDualStackSubnet creates a subnet with the IPv4 subnetCidr and, when ipv6Cidr is not empty,
+// the /64 IPv6 block carved from the IPv6 CIDR block of the vpc.
+func (client *AWSClient) CreateDualStackSubnet(vpcID string, zone string, subnetCidr string, ipv6Cidr string) (*types.Subnet, error) {
 	if zone == "" {
 		return nil, fmt.Errorf("zone must be not empty for subnet creation")
 	}
@@ -34,7 +39,10 @@ func (client *AWSClient) CreateSubnet(vpcID string, zone string, subnetCidr stri
 		OutpostArn:         nil,
 		TagSpecifications:  nil,
 	}
-	respCreateSubnet, err := client.Ec2Client.CreateSubnet(context.TODO(), input)
+	if ipv6Cidr != "" {
+		input.Ipv6CidrBlock = aws.String(ipv6Cidr)
+	}
+	respCreateSubnet, err := client.Ec2Client.CreateSubnet(client.requestContext(), input)
 	if err != nil {
 		log.LogError("create subnet error %s", err.Error())
 		return nil, err
@@ -66,7 +74,7 @@ func (client *AWSClient) DeleteSubnet(subnetID string) (*ec2.DeleteSubnetOutput,
 		DryRun:   nil,
 	}
 
-	resp, err := client.Ec2Client.DeleteSubnet(context.TODO(), input)
+	resp, err := client.Ec2Client.DeleteSubnet(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Delete subnet %s meets error %s", subnetID, err.Error())
 		return nil, err
@@ -90,7 +98,7 @@ func (client *AWSClient) ListSubnetDetail(subnetIDs ...string) ([]types.Subnet,
 		SubnetIds:  subnetIDs,
 	}
 
-	resp, err := client.Ec2Client.DescribeSubnets(context.TODO(), input)
+	resp, err := client.Ec2Client.DescribeSubnets(client.requestContext(), input)
 
 	if err != nil {
 		return subs, err
@@ -109,7 +117,7 @@ func (client *AWSClient) ListSubnetsByFilter(filter []types.Filter) ([]types.Sub
 		SubnetIds:  nil,
 	}
 
-	resp, err := client.Ec2Client.DescribeSubnets(context.TODO(), input)
+	resp, err := client.Ec2Client.DescribeSubnets(client.requestContext(), input)
 	if err != nil {
 		return nil, fmt.Errorf("describe subnet by filter error %s", err.Error())
 	}
@@ -132,15 +140,15 @@ func (client *AWSClient) WaitForSubnetAccessibility(subnetIDs []string, timeout
 		}
 	}
 
-	now := time.Now()
-	for now.Add(time.Duration(timeout) * time.Second).After(time.Now()) {
-		subs, err := client.ListSubnetDetail(uniqueSubnetIDs...)
-		if err != nil {
-			log.LogError("failed to list subnet detail %s", err.Error())
-		} else if len(subs) == len(uniqueSubnetIDs) {
-			return nil
-		}
-		time.Sleep(ClientWaiterDelay)
-	}
-	return fmt.Errorf("timeout after %d seconds waiting for subnets to be accessible: %v", timeout, uniqueSubnetIDs)
+	waiter := NewWaiter(time.Duration(timeout) * time.Second)
+	waiter.MinDelay = ClientWaiterDelay
+	return waiter.Wait(client.requestContext(), fmt.Sprintf("subnets to be accessible: %v", uniqueSubnetIDs),
+		func(context.Context) (bool, string, error) {
+			subs, err := client.ListSubnetDetail(uniqueSubnetIDs...)
+			if err != nil {
+				log.LogError("failed to list subnet detail %s", err.Error())
+				return false, err.Error(), nil
+			}
+			return len(subs) == len(uniqueSubnetIDs), fmt.Sprintf("%d of %d subnets accessible", len(subs), len(uniqueSubnetIDs)), nil
+		})
 }
@@ -0,0 +1,822 @@
+package aws_client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/log"
+)
+
+// SweepKind is a type of resource handled by the sweeper.
+type SweepKind string
+
+const (
+	SweepInstance     SweepKind = "instance"
+	SweepNatGateway   SweepKind = "nat-gateway"
+	SweepElasticIP    SweepKind = "elastic-ip"
+	SweepSubnet       SweepKind = "subnet"
+	SweepVpc          SweepKind = "vpc"
+	SweepKeyPair      SweepKind = "key-pair"
+	SweepRole         SweepKind = "role"
+	SweepOIDCProvider SweepKind = "oidc-provider"
+	SweepHostedZone   SweepKind = "hosted-zone"
+	SweepLogGroup     SweepKind = "log-group"
+	SweepKMSKey       SweepKind = "kms-key"
+)
+
+// SweepOrder is the order resources are deleted in, so that every resource goes before the ones it
+// depends on: instances and NAT gateways before their subnets and addresses, subnets before VPCs.
+var SweepOrder = []SweepKind{
+	SweepInstance,
+	SweepNatGateway,
+	SweepElasticIP,
+	SweepSubnet,
+	SweepVpc,
+	SweepKeyPair,
+	SweepRole,
+	SweepOIDCProvider,
+	SweepHostedZone,
+	SweepLogGroup,
+	SweepKMSKey,
+}
+
+// SweepKMSKeyPendingWindow is the number of days before a swept KMS key is deleted.
+const SweepKMSKeyPendingWindow = 7
+
+// SweepFilter selects the resources to sweep.
+type SweepFilter struct {
+	// TagKey is the tag marking swept resources. Empty means consts.QEFlagKey.
+	TagKey string
+	// TagValue, when set, is the value TagKey must have. Empty matches any value.
+	TagValue string
+	// OlderThan is the minimum age of swept resources.
+	OlderThan time.Duration
+	// IncludeUndated sweeps tagged resources AWS reports no creation time for: VPCs, subnets,
+	// elastic IPs and hosted zones. They are skipped otherwise, as their age is unknown. A VPC
+	// holding an instance or a NAT gateway younger than OlderThan is skipped all the same.
+	IncludeUndated bool
+	// Kinds limits the sweep to the given kinds. Empty means every kind.
+	Kinds []SweepKind
+}
+
+// SweepItem is a resource found by the sweeper.
+type SweepItem struct {
+	Kind SweepKind
+	// ID is what the resource is deleted by: an ID, a name or an ARN depending on the kind.
+	ID   string
+	Name string
+	// VpcID is the VPC holding the resource, if any.
+	VpcID string
+	// CreatedAt is the creation time, nil when AWS does not report it.
+	CreatedAt *time.Time
+	Tags      map[string]string
+	// Parent is set on resources swept because the VPC holding them was, whatever their own tags.
+	Parent string
+}
+
+// SweepFailure is a resource the sweeper failed to delete.
+type SweepFailure struct {
+	Item SweepItem
+	Err  error
+}
+
+// SweepReport describes what a sweep found and deleted.
+type SweepReport struct {
+	DryRun   bool
+	Items    []SweepItem
+	Deleted  []SweepItem
+	Failures []SweepFailure
+}
+
+// String renders the report one resource per line, in deletion order.
+func (report *SweepReport) String() string {
+	var builder strings.Builder
+	action := "delete"
+	if report.DryRun {
+		action = "would delete"
+	}
+	failed := map[string]error{}
+	for _, failure := range report.Failures {
+		failed[string(failure.Item.Kind)+"/"+failure.Item.ID] = failure.Err
+	}
+	fmt.Fprintf(&builder, "%d resources to sweep\n", len(report.Items))
+	for _, item := range report.Items {
+		age := "unknown"
+		if item.CreatedAt != nil {
+			age = time.Since(*item.CreatedAt).Truncate(time.Second).String()
+		}
+		line := fmt.Sprintf("%s %s %s name=%q age=%s", action, item.Kind, item.ID, item.Name, age)
+		if item.Parent != "" {
+			line += " parent=" + item.Parent
+		}
+		if err, ok := failed[string(item.Kind)+"/"+item.ID]; ok {
+			line += " error: " + err.Error()
+		}
+		builder.WriteString(line + "\n")
+	}
+	return builder.String()
+}
+
+func (filter SweepFilter) tagKey() string {
+	if filter.TagKey == "" {
+		return CON.QEFlagKey
+	}
+	return filter.TagKey
+}
+
+func (filter SweepFilter) includes(kind SweepKind) bool {
+	if len(filter.Kinds) == 0 {
+		return true
+	}
+	for _, included := range filter.Kinds {
+		if included == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTags tells whether tags carry the tag of the filter.
+func (filter SweepFilter) matchesTags(tags map[string]string) bool {
+	value, ok := tags[filter.tagKey()]
+	return ok && (filter.TagValue == "" || value == filter.TagValue)
+}
+
+// oldEnough tells whether a resource created at createdAt is old enough to be swept.
+func (filter SweepFilter) oldEnough(createdAt *time.Time) bool {
+	if createdAt == nil {
+		return filter.IncludeUndated
+	}
+	return time.Since(*createdAt) >= filter.OlderThan
+}
+
+// ec2Filters returns the EC2 filters selecting the tagged resources.
+func (filter SweepFilter) ec2Filters(extra ...types.Filter) []types.Filter {
+	filters := []types.Filter{{Name: aws.String("tag-key"), Values: []string{filter.tagKey()}}}
+	if filter.TagValue != "" {
+		filters = append(filters, types.Filter{
+			Name:   aws.String("tag:" + filter.tagKey()),
+			Values: []string{filter.TagValue},
+		})
+	}
+	return append(filters, extra...)
+}
+
+func ec2TagMap(tags []types.Tag) map[string]string {
+	result := map[string]string{}
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
+func iamTagMap(tags []iamtypes.Tag) map[string]string {
+	result := map[string]string{}
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
+// sweepInventory collects sweep items without duplicates.
+type sweepInventory struct {
+	items map[SweepKind][]SweepItem
+	seen  map[string]bool
+}
+
+func (inventory *sweepInventory) add(item SweepItem) {
+	key := string(item.Kind) + "/" + item.ID
+	if inventory.seen[key] {
+		return
+	}
+	inventory.seen[key] = true
+	inventory.items[item.Kind] = append(inventory.items[item.Kind], item)
+}
+
+// InventoryResources lists the resources selected by filter, in SweepOrder. Resources held by a
+// selected VPC are listed too whatever their tags and age, as the VPC cannot go without them.
+func (client *AWSClient) InventoryResources(filter SweepFilter) ([]SweepItem, error) {
+	inventory := &sweepInventory{items: map[SweepKind][]SweepItem{}, seen: map[string]bool{}}
+	listers := []struct {
+		kind SweepKind
+		list func(SweepFilter, *sweepInventory) error
+	}{
+		{SweepVpc, client.inventoryVpcs},
+		{SweepInstance, client.inventoryInstances},
+		{SweepNatGateway, client.inventoryNatGateways},
+		{SweepElasticIP, client.inventoryAddresses},
+		{SweepSubnet, client.inventorySubnets},
+		{SweepKeyPair, client.inventoryKeyPairs},
+		{SweepRole, client.inventoryRoles},
+		{SweepOIDCProvider, client.inventoryOIDCProviders},
+		{SweepHostedZone, client.inventoryHostedZones},
+		{SweepLogGroup, client.inventoryLogGroups},
+		{SweepKMSKey, client.inventoryKMSKeys},
+	}
+	for _, lister := range listers {
+		if !filter.includes(lister.kind) {
+			continue
+		}
+		if err := lister.list(filter, inventory); err != nil {
+			return nil, fmt.Errorf("list %s resources: %w", lister.kind, err)
+		}
+	}
+	items := []SweepItem{}
+	for _, kind := range SweepOrder {
+		items = append(items, inventory.items[kind]...)
+	}
+	return items, nil
+}
+
+// SweepResources deletes the resources selected by filter in SweepOrder. With dryRun nothing is
+// deleted and the report only lists what would be. Deletion goes on after failures, which are
+// listed in the report and joined in the returned error.
+func (client *AWSClient) SweepResources(filter SweepFilter, dryRun bool) (*SweepReport, error) {
+	items, err := client.InventoryResources(filter)
+	if err != nil {
+		return nil, err
+	}
+	report := &SweepReport{DryRun: dryRun, Items: items}
+	if dryRun {
+		log.LogInfo("Dry run sweep found %d resources", len(items))
+		return report, nil
+	}
+	var errs []error
+	for _, item := range items {
+		log.LogInfo("Sweeping %s %s", item.Kind, item.ID)
+		if err := client.deleteSweepItem(item); err != nil {
+			log.LogError("Sweep %s %s meets error: %s", item.Kind, item.ID, err.Error())
+			report.Failures = append(report.Failures, SweepFailure{Item: item, Err: err})
+			errs = append(errs, fmt.Errorf("delete %s %s: %w", item.Kind, item.ID, err))
+			continue
+		}
+		report.Deleted = append(report.Deleted, item)
+	}
+	return report, errors.Join(errs...)
+}
+
+func (client *AWSClient) inventoryVpcs(filter SweepFilter, inventory *sweepInventory) error {
+	output, err := client.Ec2Client.DescribeVpcs(client.requestContext(), &ec2.DescribeVpcsInput{
+		Filters: filter.ec2Filters(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, vpc := range output.Vpcs {
+		tags := ec2TagMap(vpc.Tags)
+		if !filter.oldEnough(nil) {
+			continue
+		}
+		vpcID := aws.ToString(vpc.VpcId)
+		young, err := client.vpcHoldsYoungResources(filter, vpcID)
+		if err != nil {
+			return err
+		}
+		if young {
+			log.LogInfo("Skip sweeping VPC %s as it holds resources younger than %s", vpcID, filter.OlderThan)
+			continue
+		}
+		inventory.add(SweepItem{Kind: SweepVpc, ID: vpcID, Name: tags["Name"], VpcID: vpcID, Tags: tags})
+		if err := client.inventoryVpcResources(filter, vpcID, inventory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vpcHoldsYoungResources tells whether the VPC holds an instance or a NAT gateway younger than the
+// filter allows. AWS reports no creation time for VPCs, so their content is what dates them.
+func (client *AWSClient) vpcHoldsYoungResources(filter SweepFilter, vpcID string) (bool, error) {
+	if filter.OlderThan <= 0 {
+		return false, nil
+	}
+	vpcFilter := types.Filter{Name: aws.String("vpc-id"), Values: []string{vpcID}}
+	instances, err := client.describeLiveInstances([]types.Filter{vpcFilter})
+	if err != nil {
+		return false, err
+	}
+	for _, instance := range instances {
+		if !filter.oldEnough(instance.LaunchTime) {
+			return true, nil
+		}
+	}
+	gateways, err := client.ListNatGateways(vpcID)
+	if err != nil {
+		return false, err
+	}
+	for _, gateway := range gateways {
+		if gateway.State == types.NatGatewayStateDeleted || gateway.State == types.NatGatewayStateDeleting {
+			continue
+		}
+		if !filter.oldEnough(gateway.CreateTime) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// inventoryVpcResources adds the instances, NAT gateways with their addresses and subnets of a VPC.
+func (client *AWSClient) inventoryVpcResources(filter SweepFilter, vpcID string, inventory *sweepInventory) error {
+	vpcFilter := types.Filter{Name: aws.String("vpc-id"), Values: []string{vpcID}}
+	if filter.includes(SweepInstance) {
+		instances, err := client.describeLiveInstances([]types.Filter{vpcFilter})
+		if err != nil {
+			return err
+		}
+		for _, instance := range instances {
+			inventory.add(instanceSweepItem(instance, vpcID))
+		}
+	}
+	if filter.includes(SweepNatGateway) {
+		gateways, err := client.ListNatGateways(vpcID)
+		if err != nil {
+			return err
+		}
+		for _, gateway := range gateways {
+			if gateway.State == types.NatGatewayStateDeleted || gateway.State == types.NatGatewayStateDeleting {
+				continue
+			}
+			inventory.add(natGatewaySweepItem(gateway, vpcID))
+			if !filter.includes(SweepElasticIP) {
+				continue
+			}
+			for _, address := range gateway.NatGatewayAddresses {
+				if address.AllocationId == nil {
+					continue
+				}
+				inventory.add(SweepItem{
+					Kind:   SweepElasticIP,
+					ID:     aws.ToString(address.AllocationId),
+					Name:   aws.ToString(address.PublicIp),
+					VpcID:  vpcID,
+					Tags:   map[string]string{},
+					Parent: vpcID,
+				})
+			}
+		}
+	}
+	if filter.includes(SweepSubnet) {
+		subnets, err := client.ListSubnetByVpcID(vpcID)
+		if err != nil {
+			return err
+		}
+		for _, subnet := range subnets {
+			tags := ec2TagMap(subnet.Tags)
+			inventory.add(SweepItem{
+				Kind:   SweepSubnet,
+				ID:     aws.ToString(subnet.SubnetId),
+				Name:   tags["Name"],
+				VpcID:  vpcID,
+				Tags:   tags,
+				Parent: vpcID,
+			})
+		}
+	}
+	return nil
+}
+
+// describeLiveInstances returns the instances matching filters that are not terminated.
+func (client *AWSClient) describeLiveInstances(filters []types.Filter) ([]types.Instance, error) {
+	filters = append(filters, types.Filter{
+		Name:   aws.String("instance-state-name"),
+		Values: []string{"pending", "running", "stopping", "stopped"},
+	})
+	instances := []types.Instance{}
+	paginator := ec2.NewDescribeInstancesPaginator(client.Ec2Client, &ec2.DescribeInstancesInput{Filters: filters})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(client.requestContext())
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range output.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
+	}
+	return instances, nil
+}
+
+func instanceSweepItem(instance types.Instance, parent string) SweepItem {
+	tags := ec2TagMap(instance.Tags)
+	return SweepItem{
+		Kind:      SweepInstance,
+		ID:        aws.ToString(instance.InstanceId),
+		Name:      tags["Name"],
+		VpcID:     aws.ToString(instance.VpcId),
+		CreatedAt: instance.LaunchTime,
+		Tags:      tags,
+		Parent:    parent,
+	}
+}
+
+func natGatewaySweepItem(gateway types.NatGateway, parent string) SweepItem {
+	tags := ec2TagMap(gateway.Tags)
+	return SweepItem{
+		Kind:      SweepNatGateway,
+		ID:        aws.ToString(gateway.NatGatewayId),
+		Name:      tags["Name"],
+		VpcID:     aws.ToString(gateway.VpcId),
+		CreatedAt: gateway.CreateTime,
+		Tags:      tags,
+		Parent:    parent,
+	}
+}
+
+func (client *AWSClient) inventoryInstances(filter SweepFilter, inventory *sweepInventory) error {
+	instances, err := client.describeLiveInstances(filter.ec2Filters())
+	if err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		if filter.oldEnough(instance.LaunchTime) {
+			inventory.add(instanceSweepItem(instance, ""))
+		}
+	}
+	return nil
+}
+
+func (client *AWSClient) inventoryNatGateways(filter SweepFilter, inventory *sweepInventory) error {
+	output, err := client.Ec2Client.DescribeNatGateways(client.requestContext(), &ec2.DescribeNatGatewaysInput{
+		Filter: filter.ec2Filters(types.Filter{
+			Name:   aws.String("state"),
+			Values: []string{string(types.NatGatewayStatePending), string(types.NatGatewayStateAvailable), string(types.NatGatewayStateFailed)},
+		}),
+	})
+	if err != nil {
+		return err
+	}
+	for _, gateway := range output.NatGateways {
+		if filter.oldEnough(gateway.CreateTime) {
+			inventory.add(natGatewaySweepItem(gateway, ""))
+		}
+	}
+	return nil
+}
+
+func (client *AWSClient) inventoryAddresses(filter SweepFilter, inventory *sweepInventory) error {
+	output, err := client.Ec2Client.DescribeAddresses(client.requestContext(), &ec2.DescribeAddressesInput{
+		Filters: filter.ec2Filters(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, address := range output.Addresses {
+		if address.AllocationId == nil || !filter.oldEnough(nil) {
+			continue
+		}
+		tags := ec2TagMap(address.Tags)
+		inventory.add(SweepItem{
+			Kind: SweepElasticIP,
+			ID:   aws.ToString(address.AllocationId),
+			Name: aws.ToString(address.PublicIp),
+			Tags: tags,
+		})
+	}
+	return nil
+}
+
+func (client *AWSClient) inventorySubnets(filter SweepFilter, inventory *sweepInventory) error {
+	subnets, err := client.ListSubnetsByFilter(filter.ec2Filters())
+	if err != nil {
+		return err
+	}
+	for _, subnet := range subnets {
+		if !filter.oldEnough(nil) {
+			continue
+		}
+		tags := ec2TagMap(subnet.Tags)
+		inventory.add(SweepItem{
+			Kind:  SweepSubnet,
+			ID:    aws.ToString(subnet.SubnetId),
+			Name:  tags["Name"],
+			VpcID: aws.ToString(subnet.VpcId),
+			Tags:  tags,
+		})
+	}
+	return nil
+}
+
+func (client *AWSClient) inventoryKeyPairs(filter SweepFilter, inventory *sweepInventory) error {
+	output, err := client.Ec2Client.DescribeKeyPairs(client.requestContext(), &ec2.DescribeKeyPairsInput{
+		Filters: filter.ec2Filters(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, keyPair := range output.KeyPairs {
+		if !filter.oldEnough(keyPair.CreateTime) {
+			continue
+		}
+		inventory.add(SweepItem{
+			Kind:      SweepKeyPair,
+			ID:        aws.ToString(keyPair.KeyName),
+			Name:      aws.ToString(keyPair.KeyName),
+			CreatedAt: keyPair.CreateTime,
+			Tags:      ec2TagMap(keyPair.Tags),
+		})
+	}
+	return nil
+}
+
+// inventoryRoles lists the roles old enough, then reads their tags, which ListRoles does not return.
+func (client *AWSClient) inventoryRoles(filter SweepFilter, inventory *sweepInventory) error {
+	paginator := iam.NewListRolesPaginator(client.IamClient, &iam.ListRolesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(client.requestContext())
+		if err != nil {
+			return err
+		}
+		for _, role := range output.Roles {
+			if !filter.oldEnough(role.CreateDate) {
+				continue
+			}
+			detail, err := client.GetRole(aws.ToString(role.RoleName))
+			if err != nil {
+				return err
+			}
+			tags := iamTagMap(detail.Tags)
+			if !filter.matchesTags(tags) {
+				continue
+			}
+			inventory.add(SweepItem{
+				Kind:      SweepRole,
+				ID:        aws.ToString(role.RoleName),
+				Name:      aws.ToString(role.RoleName),
+				CreatedAt: role.CreateDate,
+				Tags:      tags,
+			})
+		}
+	}
+	return nil
+}
+
+func (client *AWSClient) inventoryOIDCProviders(filter SweepFilter, inventory *sweepInventory) error {
+	output, err := client.IamClient.ListOpenIDConnectProviders(client.requestContext(), &iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return err
+	}
+	for _, entry := range output.OpenIDConnectProviderList {
+		provider, err := client.IamClient.GetOpenIDConnectProvider(client.requestContext(), &iam.GetOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: entry.Arn,
+		})
+		if err != nil {
+			return err
+		}
+		tags := iamTagMap(provider.Tags)
+		if !filter.matchesTags(tags) || !filter.oldEnough(provider.CreateDate) {
+			continue
+		}
+		inventory.add(SweepItem{
+			Kind:      SweepOIDCProvider,
+			ID:        aws.ToString(entry.Arn),
+			Name:      aws.ToString(provider.Url),
+			CreatedAt: provider.CreateDate,
+			Tags:      tags,
+		})
+	}
+	return nil
+}
+
+func (client *AWSClient) inventoryHostedZones(filter SweepFilter, inventory *sweepInventory) error {
+	if !filter.oldEnough(nil) {
+		return nil
+	}
+	paginator := route53.NewListHostedZonesPaginator(client.Route53Client, &route53.ListHostedZonesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(client.requestContext())
+		if err != nil {
+			return err
+		}
+		for _, zone := range output.HostedZones {
+			zoneID := strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/")
+			tagsOutput, err := client.Route53Client.ListTagsForResource(client.requestContext(), &route53.ListTagsForResourceInput{
+				ResourceId:   aws.String(zoneID),
+				ResourceType: route53types.TagResourceTypeHostedzone,
+			})
+			if err != nil {
+				return err
+			}
+			tags := map[string]string{}
+			if tagsOutput.ResourceTagSet != nil {
+				for _, tag := range tagsOutput.ResourceTagSet.Tags {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+			if !filter.matchesTags(tags) {
+				continue
+			}
+			inventory.add(SweepItem{
+				Kind: SweepHostedZone,
+				ID:   zoneID,
+				Name: aws.ToString(zone.Name),
+				Tags: tags,
+			})
+		}
+	}
+	return nil
+}
+
+func (client *AWSClient) inventoryLogGroups(filter SweepFilter, inventory *sweepInventory) error {
+	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(client.CloudWatchLogsClient, &cloudwatchlogs.DescribeLogGroupsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(client.requestContext())
+		if err != nil {
+			return err
+		}
+		for _, group := range output.LogGroups {
+			var createdAt *time.Time
+			if group.CreationTime != nil {
+				created := time.UnixMilli(*group.CreationTime)
+				createdAt = &created
+			}
+			if !filter.oldEnough(createdAt) {
+				continue
+			}
+			tagsOutput, err := client.CloudWatchLogsClient.ListTagsForResource(client.requestContext(), &cloudwatchlogs.ListTagsForResourceInput{
+				ResourceArn: group.LogGroupArn,
+			})
+			if err != nil {
+				return err
+			}
+			if !filter.matchesTags(tagsOutput.Tags) {
+				continue
+			}
+			inventory.add(SweepItem{
+				Kind:      SweepLogGroup,
+				ID:        aws.ToString(group.LogGroupName),
+				Name:      aws.ToString(group.LogGroupName),
+				CreatedAt: createdAt,
+				Tags:      tagsOutput.Tags,
+			})
+		}
+	}
+	return nil
+}
+
+// inventoryKMSKeys lists the customer managed keys old enough and not already pending deletion,
+// then reads their tags.
+func (client *AWSClient) inventoryKMSKeys(filter SweepFilter, inventory *sweepInventory) error {
+	paginator := kms.NewListKeysPaginator(client.KmsClient, &kms.ListKeysInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(client.requestContext())
+		if err != nil {
+			return err
+		}
+		for _, key := range output.Keys {
+			described, err := client.KmsClient.DescribeKey(client.requestContext(), &kms.DescribeKeyInput{KeyId: key.KeyId})
+			if err != nil {
+				return err
+			}
+			metadata := described.KeyMetadata
+			if metadata == nil || metadata.KeyManager == kmstypes.KeyManagerTypeAws ||
+				metadata.KeyState == kmstypes.KeyStatePendingDeletion || !filter.oldEnough(metadata.CreationDate) {
+				continue
+			}
+			tagsOutput, err := client.KmsClient.ListResourceTags(client.requestContext(), &kms.ListResourceTagsInput{KeyId: key.KeyId})
+			if err != nil {
+				return err
+			}
+			tags := map[string]string{}
+			for _, tag := range tagsOutput.Tags {
+				tags[aws.ToString(tag.TagKey)] = aws.ToString(tag.TagValue)
+			}
+			if !filter.matchesTags(tags) {
+				continue
+			}
+			inventory.add(SweepItem{
+				Kind:      SweepKMSKey,
+				ID:        aws.ToString(key.KeyId),
+				Name:      aws.ToString(metadata.Description),
+				CreatedAt: metadata.CreationDate,
+				Tags:      tags,
+			})
+		}
+	}
+	return nil
+}
+
+// deleteSweepItem deletes one swept resource, waiting for the deletions later ones depend on.
+func (client *AWSClient) deleteSweepItem(item SweepItem) error {
+	var err error
+	switch item.Kind {
+	case SweepInstance:
+		err = client.TerminateInstances([]string{item.ID}, true, 20)
+	case SweepNatGateway:
+		_, err = client.DeleteNatGateway(item.ID, 180)
+	case SweepElasticIP:
+		err = client.ReleaseAddressWithAllocationID(item.ID)
+	case SweepSubnet:
+		_, err = client.DeleteSubnet(item.ID)
+	case SweepVpc:
+		err = client.deleteSweptVpc(item.ID)
+	case SweepKeyPair:
+		_, err = client.DeleteKeyPair(item.ID)
+	case SweepRole:
+		err = client.deleteSweptRole(item.ID)
+	case SweepOIDCProvider:
+		err = client.DeleteOIDCProvider(item.ID)
+	case SweepHostedZone:
+		if err = client.EmptyHostedZone(item.ID); err == nil {
+			err = client.DeleteHostedZone(item.ID)
+		}
+	case SweepLogGroup:
+		_, err = client.DeleteLogGroupByName(item.ID)
+	case SweepKMSKey:
+		_, err = client.ScheduleKeyDeletion(item.ID, SweepKMSKeyPendingWindow)
+	default:
+		err = fmt.Errorf("unknown sweep kind %s", item.Kind)
+	}
+	return err
+}
+
+// deleteSweptVpc deletes a VPC once its instances, NAT gateways and subnets are gone, clearing the
+// endpoints, network interfaces, internet gateways, egress-only internet gateways, route tables
+// and security groups left in it.
+func (client *AWSClient) deleteSweptVpc(vpcID string) error {
+	if err := client.DeleteVPCEndpoints(vpcID); err != nil {
+		return err
+	}
+	interfaces, err := client.DescribeNetWorkInterface(vpcID)
+	if err != nil {
+		return err
+	}
+	for _, networkInterface := range interfaces {
+		if err := client.DeleteNetworkInterface(networkInterface); err != nil {
+			return err
+		}
+	}
+	gateways, err := client.ListInternetGateWay(vpcID)
+	if err != nil {
+		return err
+	}
+	for _, gateway := range gateways {
+		if _, err := client.DetachInternetGateway(aws.ToString(gateway.InternetGatewayId), vpcID); err != nil {
+			return err
+		}
+		if _, err := client.DeleteInternetGateway(aws.ToString(gateway.InternetGatewayId)); err != nil {
+			return err
+		}
+	}
+	egressOnlyGateways, err := client.ListEgressOnlyInternetGateways(vpcID)
+	if err != nil {
+		return err
+	}
+	for _, gateway := range egressOnlyGateways {
+		if err := client.DeleteEgressOnlyInternetGateway(aws.ToString(gateway.EgressOnlyInternetGatewayId)); err != nil {
+			return err
+		}
+	}
+	routeTables, err := client.ListCustomerRouteTables(vpcID)
+	if err != nil {
+		return err
+	}
+	for _, routeTable := range routeTables {
+		if err := client.DeleteRouteTableChain(aws.ToString(routeTable.RouteTableId)); err != nil {
+			return err
+		}
+	}
+	securityGroups, err := client.ListSecurityGroups(vpcID)
+	if err != nil {
+		return err
+	}
+	for _, securityGroup := range securityGroups {
+		if err := client.ReleaseInboundOutboundRules(aws.ToString(securityGroup.GroupId)); err != nil {
+			return err
+		}
+	}
+	for _, securityGroup := range securityGroups {
+		if _, err := client.DeleteSecurityGroup(aws.ToString(securityGroup.GroupId)); err != nil {
+			return err
+		}
+	}
+	_, err = client.DeleteVpc(vpcID)
+	return err
+}
+
+// deleteSweptRole deletes a role after detaching its managed policies, deleting its inline policies
+// and removing it from its instance profiles. The policies are kept, as other roles may use them; CleanPolicies sweeps them.
+func (client *AWSClient) deleteSweptRole(roleName string) error {
+	if err := client.DetachRolePolicies(roleName); err != nil {
+		return err
+	}
+	if err := client.DeleteRoleInlinePolicies(roleName); err != nil {
+		return err
+	}
+	if err := client.DeleteRoleInstanceProfiles(roleName); err != nil {
+		return err
+	}
+	return client.DeleteRole(roleName)
+}
@@ -0,0 +1,282 @@
+package aws_client_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+	"github.com/openshift-online/ocm-common/pkg/test/vpc_client"
+)
+
+var _ = Describe("Sweeper", func() {
+	var (
+		ctx        context.Context
+		mockCtrl   *gomock.Controller
+		ec2Fake    *aws_fake.EC2
+		iamFake    *aws_fake.IAM
+		mockKMS    *MockKMSClientAPI
+		mockRoute  *MockRoute53ClientAPI
+		mockLogs   *MockCloudWatchLogsClientAPI
+		client     *AWSClient
+		vpcID      string
+		subnetID   string
+		clock      time.Time
+		created    = time.Now().Add(-48 * time.Hour)
+		flagTags   = map[string]string{CON.QEFlagKey: "ci"}
+		everything = SweepFilter{IncludeUndated: true}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockCtrl = gomock.NewController(GinkgoT())
+		clock = created
+		ec2Fake = aws_fake.NewEC2(aws_fake.WithRegion("us-east-2"), aws_fake.WithClock(func() time.Time { return clock }))
+		iamFake = aws_fake.NewIAM()
+		mockKMS = NewMockKMSClientAPI(mockCtrl)
+		mockRoute = NewMockRoute53ClientAPI(mockCtrl)
+		mockLogs = NewMockCloudWatchLogsClientAPI(mockCtrl)
+		client = &AWSClient{
+			Ec2Client:            ec2Fake,
+			IamClient:            iamFake,
+			KmsClient:            mockKMS,
+			Route53Client:        mockRoute,
+			CloudWatchLogsClient: mockLogs,
+		}
+
+		vpc, err := vpc_client.NewVPC().
+			AWSclient(client).
+			Name("leaked-vpc").
+			CIDR(CON.DefaultVPCCIDR).
+			SetRegion(ec2Fake.Region()).
+			NewCIDRPool().
+			CreateVPCChain(ec2Fake.Zones()[0])
+		Expect(err).ToNot(HaveOccurred())
+		vpcID = vpc.VpcID
+		subnetID = vpc.AllPrivateSubnetIDs()[0]
+
+		trust, err := iampolicy.NewDocument().AddStatement(iampolicy.NewStatement().
+			AddServicePrincipal("ec2.amazonaws.com").AddAction("sts:AssumeRole")).JSON()
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.CreateRole("leaked-role", trust, "", flagTags, "")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.CreateRole("kept-role", trust, "", map[string]string{"owner": "ocm"}, "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	expectListings := func() {
+		mockKMS.EXPECT().ListKeys(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&kms.ListKeysOutput{Keys: []kmstypes.KeyListEntry{{KeyId: aws.String("key-1")}, {KeyId: aws.String("key-2")}}}, nil)
+		mockKMS.EXPECT().DescribeKey(gomock.Any(), &kms.DescribeKeyInput{KeyId: aws.String("key-1")}).
+			Return(&kms.DescribeKeyOutput{KeyMetadata: &kmstypes.KeyMetadata{
+				KeyId: aws.String("key-1"), KeyManager: kmstypes.KeyManagerTypeCustomer,
+				KeyState: kmstypes.KeyStateEnabled, CreationDate: aws.Time(created),
+			}}, nil)
+		mockKMS.EXPECT().DescribeKey(gomock.Any(), &kms.DescribeKeyInput{KeyId: aws.String("key-2")}).
+			Return(&kms.DescribeKeyOutput{KeyMetadata: &kmstypes.KeyMetadata{
+				KeyId: aws.String("key-2"), KeyManager: kmstypes.KeyManagerTypeAws,
+				KeyState: kmstypes.KeyStateEnabled, CreationDate: aws.Time(created),
+			}}, nil)
+		mockKMS.EXPECT().ListResourceTags(gomock.Any(), &kms.ListResourceTagsInput{KeyId: aws.String("key-1")}).
+			Return(&kms.ListResourceTagsOutput{Tags: []kmstypes.Tag{{TagKey: aws.String(CON.QEFlagKey), TagValue: aws.String("ci")}}}, nil)
+
+		mockRoute.EXPECT().ListHostedZones(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&route53.ListHostedZonesOutput{HostedZones: []route53types.HostedZone{
+				{Id: aws.String("/hostedzone/Z1"), Name: aws.String("leaked.example.com.")},
+				{Id: aws.String("/hostedzone/Z2"), Name: aws.String("kept.example.com.")},
+			}}, nil)
+		mockRoute.EXPECT().ListTagsForResource(gomock.Any(), &route53.ListTagsForResourceInput{
+			ResourceId: aws.String("Z1"), ResourceType: route53types.TagResourceTypeHostedzone,
+		}).Return(&route53.ListTagsForResourceOutput{ResourceTagSet: &route53types.ResourceTagSet{
+			Tags: []route53types.Tag{{Key: aws.String(CON.QEFlagKey), Value: aws.String("ci")}},
+		}}, nil)
+		mockRoute.EXPECT().ListTagsForResource(gomock.Any(), gomock.Any()).
+			Return(&route53.ListTagsForResourceOutput{ResourceTagSet: &route53types.ResourceTagSet{}}, nil)
+
+		mockLogs.EXPECT().DescribeLogGroups(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&cloudwatchlogs.DescribeLogGroupsOutput{LogGroups: []logtypes.LogGroup{{
+				LogGroupName: aws.String("leaked-group"),
+				LogGroupArn:  aws.String("arn:aws:logs:us-east-2:123456789012:log-group:leaked-group"),
+				CreationTime: aws.Int64(created.UnixMilli()),
+			}}}, nil)
+		mockLogs.EXPECT().ListTagsForResource(gomock.Any(), gomock.Any()).
+			Return(&cloudwatchlogs.ListTagsForResourceOutput{Tags: flagTags}, nil)
+	}
+
+	It("should list a leaked VPC chain with its resources in deletion order", func() {
+		expectListings()
+
+		report, err := client.SweepResources(everything, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Deleted).To(BeEmpty())
+
+		kinds := []SweepKind{}
+		for _, item := range report.Items {
+			if len(kinds) == 0 || kinds[len(kinds)-1] != item.Kind {
+				kinds = append(kinds, item.Kind)
+			}
+			if item.Kind != SweepVpc && item.VpcID == vpcID {
+				Expect(item.Parent).To(Equal(vpcID))
+			}
+		}
+		Expect(kinds).To(Equal([]SweepKind{
+			SweepNatGateway, SweepElasticIP, SweepSubnet, SweepVpc, SweepRole, SweepHostedZone, SweepLogGroup, SweepKMSKey,
+		}))
+		Expect(report.String()).To(ContainSubstring("would delete vpc " + vpcID))
+		Expect(report.String()).To(ContainSubstring("would delete role leaked-role"))
+		Expect(report.String()).ToNot(ContainSubstring("kept"))
+
+		vpcs, err := ec2Fake.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpcs.Vpcs).To(HaveLen(1))
+	})
+
+	apexRecords := []route53types.ResourceRecordSet{
+		{Name: aws.String("leaked.example.com."), Type: route53types.RRTypeSoa},
+		{Name: aws.String("leaked.example.com."), Type: route53types.RRTypeNs},
+	}
+
+	It("should delete the swept resources", func() {
+		expectListings()
+		mockRoute.EXPECT().ListResourceRecordSets(gomock.Any(), &route53.ListResourceRecordSetsInput{HostedZoneId: aws.String("Z1")}).
+			Return(&route53.ListResourceRecordSetsOutput{ResourceRecordSets: apexRecords}, nil)
+		mockRoute.EXPECT().DeleteHostedZone(gomock.Any(), &route53.DeleteHostedZoneInput{Id: aws.String("Z1")}).
+			Return(&route53.DeleteHostedZoneOutput{}, nil)
+		mockLogs.EXPECT().DeleteLogGroup(gomock.Any(), &cloudwatchlogs.DeleteLogGroupInput{LogGroupName: aws.String("leaked-group")}).
+			Return(&cloudwatchlogs.DeleteLogGroupOutput{}, nil)
+		mockKMS.EXPECT().ScheduleKeyDeletion(gomock.Any(), &kms.ScheduleKeyDeletionInput{
+			KeyId: aws.String("key-1"), PendingWindowInDays: aws.Int32(SweepKMSKeyPendingWindow),
+		}).Return(&kms.ScheduleKeyDeletionOutput{}, nil)
+
+		report, err := client.SweepResources(everything, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Failures).To(BeEmpty())
+		Expect(report.Deleted).To(HaveLen(len(report.Items)))
+
+		vpcs, err := ec2Fake.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpcs.Vpcs).To(BeEmpty())
+		addresses, err := ec2Fake.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(addresses.Addresses).To(BeEmpty())
+		roles, err := client.ListRoles()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(roles).To(HaveLen(1))
+		Expect(aws.ToString(roles[0].RoleName)).To(Equal("kept-role"))
+	})
+
+	It("should clear the records, inline policies and egress-only gateways blocking deletions", func() {
+		expectListings()
+		record := route53types.ResourceRecordSet{
+			Name:            aws.String("api.leaked.example.com."),
+			Type:            route53types.RRTypeA,
+			TTL:             aws.Int64(300),
+			ResourceRecords: []route53types.ResourceRecord{{Value: aws.String("10.0.0.1")}},
+		}
+		gomock.InOrder(
+			mockRoute.EXPECT().ListResourceRecordSets(gomock.Any(), &route53.ListResourceRecordSetsInput{HostedZoneId: aws.String("Z1")}).
+				Return(&route53.ListResourceRecordSetsOutput{
+					ResourceRecordSets: append(apexRecords, record),
+				}, nil),
+			mockRoute.EXPECT().ChangeResourceRecordSets(gomock.Any(), &route53.ChangeResourceRecordSetsInput{
+				HostedZoneId: aws.String("Z1"),
+				ChangeBatch: &route53types.ChangeBatch{Changes: []route53types.Change{{
+					Action:            route53types.ChangeActionDelete,
+					ResourceRecordSet: &record,
+				}}},
+			}).Return(&route53.ChangeResourceRecordSetsOutput{}, nil),
+			mockRoute.EXPECT().DeleteHostedZone(gomock.Any(), &route53.DeleteHostedZoneInput{Id: aws.String("Z1")}).
+				Return(&route53.DeleteHostedZoneOutput{}, nil),
+		)
+		mockLogs.EXPECT().DeleteLogGroup(gomock.Any(), gomock.Any()).Return(&cloudwatchlogs.DeleteLogGroupOutput{}, nil)
+		mockKMS.EXPECT().ScheduleKeyDeletion(gomock.Any(), gomock.Any()).Return(&kms.ScheduleKeyDeletionOutput{}, nil)
+
+		permissions, err := iampolicy.NewDocument().AddStatement(iampolicy.NewStatement().
+			AddAction("s3:GetObject").AddResource("*")).JSON()
+		Expect(err).ToNot(HaveOccurred())
+		_, err = iamFake.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+			RoleName:       aws.String("leaked-role"),
+			PolicyName:     aws.String("inline"),
+			PolicyDocument: aws.String(permissions),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.CreateEgressOnlyInternetGateway(vpcID)
+		Expect(err).ToNot(HaveOccurred())
+
+		report, err := client.SweepResources(everything, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Failures).To(BeEmpty())
+		Expect(report.Deleted).To(HaveLen(len(report.Items)))
+
+		vpcs, err := ec2Fake.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpcs.Vpcs).To(BeEmpty())
+		gateways, err := ec2Fake.DescribeEgressOnlyInternetGateways(ctx, &ec2.DescribeEgressOnlyInternetGatewaysInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gateways.EgressOnlyInternetGateways).To(BeEmpty())
+		roles, err := client.ListRoles()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(roles).To(HaveLen(1))
+	})
+
+	It("should skip resources that are too young or undated", func() {
+		items, err := client.InventoryResources(SweepFilter{
+			OlderThan: time.Hour,
+			Kinds:     []SweepKind{SweepVpc, SweepSubnet, SweepRole},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(BeEmpty())
+
+		items, err = client.InventoryResources(SweepFilter{
+			TagValue:       "other",
+			IncludeUndated: true,
+			Kinds:          []SweepKind{SweepVpc, SweepRole},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(BeEmpty())
+	})
+
+	It("should skip a tagged VPC holding a young instance", func() {
+		filter := SweepFilter{
+			OlderThan:      time.Hour,
+			IncludeUndated: true,
+			Kinds:          []SweepKind{SweepInstance, SweepNatGateway, SweepElasticIP, SweepSubnet, SweepVpc},
+		}
+		items, err := client.InventoryResources(filter)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(ContainElement(HaveField("ID", vpcID)))
+
+		clock = time.Now()
+		_, err = ec2Fake.RunInstances(ctx, &ec2.RunInstancesInput{
+			ImageId:  aws.String(ec2Fake.AddImage(ec2types.Image{})),
+			SubnetId: aws.String(subnetID),
+			MinCount: aws.Int32(1),
+			MaxCount: aws.Int32(1),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		items, err = client.InventoryResources(filter)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(items).To(BeEmpty())
+	})
+})
@@ -1,8 +1,6 @@
 package aws_client
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/openshift-online/ocm-common/pkg/log"
@@ -24,7 +22,7 @@ func (client *AWSClient) TagResource(resourceID string, tags map[string]string)
 		Tags:      awsTags,
 	}
 
-	output, err := client.Ec2Client.CreateTags(context.TODO(), updateBody)
+	output, err := client.Ec2Client.CreateTags(client.requestContext(), updateBody)
 	if err != nil {
 		log.LogError("Tag resource %s failed: %s", resourceID, err.Error())
 	} else {
@@ -44,7 +42,7 @@ func (client *AWSClient) RemoveResourceTag(resourceID string, tagKey string, tag
 		Resources: []string{resourceID},
 		Tags:      tags,
 	}
-	output, err := client.Ec2Client.DeleteTags(context.TODO(), updateBody)
+	output, err := client.Ec2Client.DeleteTags(client.requestContext(), updateBody)
 	if err != nil {
 		log.LogError("Remove resource tag %s:%s from %s failed", tagKey, tagValue, resourceID)
 	} else {
@@ -0,0 +1,300 @@
+package aws_client
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/log"
+)
+
+// ListTransitGatewayVpcAttachments lists the transit gateway attachments of the VPC which are not
+// deleted or being deleted.
+func (client *AWSClient) ListTransitGatewayVpcAttachments(vpcID string) ([]types.TransitGatewayVpcAttachment, error) {
+	output, err := client.Ec2Client.DescribeTransitGatewayVpcAttachments(client.requestContext(), &ec2.DescribeTransitGatewayVpcAttachmentsInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []string{vpcID},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	attachments := []types.TransitGatewayVpcAttachment{}
+	for _, attachment := range output.TransitGatewayVpcAttachments {
+		switch attachment.State {
+		case types.TransitGatewayAttachmentStateDeleted, types.TransitGatewayAttachmentStateDeleting,
+			types.TransitGatewayAttachmentStateRejected, types.TransitGatewayAttachmentStateFailed:
+			continue
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// DeleteTransitGatewayVpcAttachment deletes the attachment and waits for <timeout> seconds for it
+// to be gone, 300 by default, as its network interfaces stay in the VPC until then.
+func (client *AWSClient) DeleteTransitGatewayVpcAttachment(attachmentID string, timeout ...int) error {
+	_, err := client.Ec2Client.DeleteTransitGatewayVpcAttachment(client.requestContext(), &ec2.DeleteTransitGatewayVpcAttachmentInput{
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+	})
+	if err != nil {
+		log.LogError("Delete transit gateway attachment %s failed: %s", attachmentID, err.Error())
+		return err
+	}
+	timeoutTime := 300
+	if len(timeout) != 0 {
+		timeoutTime = timeout[0]
+	}
+	err = client.WaitForResourceDeleted(attachmentID, timeoutTime)
+	if err != nil {
+		return err
+	}
+	log.LogInfo("Delete transit gateway attachment %s successfully", attachmentID)
+	return nil
+}
+
+// CreateTransitGateway creates a transit gateway named name and waits for it to be available. The
+// gateway associates the new attachments with its default route table and propagates them to it,
+// so attached VPCs reach each other once their route tables send the traffic to the gateway.
+func (client *AWSClient) CreateTransitGateway(name string) (*types.TransitGateway, error) {
+	output, err := client.Ec2Client.CreateTransitGateway(client.requestContext(), &ec2.CreateTransitGatewayInput{
+		Description: aws.String(name),
+		Options: &types.TransitGatewayRequestOptions{
+			DefaultRouteTableAssociation: types.DefaultRouteTableAssociationValueEnable,
+			DefaultRouteTablePropagation: types.DefaultRouteTablePropagationValueEnable,
+		},
+	})
+	if err != nil {
+		log.LogError("Create transit gateway %s failed: %s", name, err.Error())
+		return nil, err
+	}
+	gatewayID := aws.ToString(output.TransitGateway.TransitGatewayId)
+	err = client.WaitForResourceExisting(gatewayID, 10*60)
+	if err != nil {
+		return nil, err
+	}
+	_, err = client.TagResource(gatewayID, map[string]string{
+		"Name":        name,
+		CON.QEFlagKey: CON.QEFLAG,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.LogInfo("Create transit gateway %s successfully", gatewayID)
+	return output.TransitGateway, nil
+}
+
+// DescribeTransitGateway returns the transit gateway with the given ID.
+func (client *AWSClient) DescribeTransitGateway(gatewayID string) (*types.TransitGateway, error) {
+	output, err := client.Ec2Client.DescribeTransitGateways(client.requestContext(), &ec2.DescribeTransitGatewaysInput{
+		TransitGatewayIds: []string{gatewayID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.TransitGateways) == 0 {
+		return nil, fmt.Errorf("transit gateway %s not found", gatewayID)
+	}
+	return &output.TransitGateways[0], nil
+}
+
+// DeleteTransitGateway deletes the transit gateway and waits for <timeout> seconds for it to be
+// gone, 600 by default. Its attachments must be deleted first.
+func (client *AWSClient) DeleteTransitGateway(gatewayID string, timeout ...int) error {
+	_, err := client.Ec2Client.DeleteTransitGateway(client.requestContext(), &ec2.DeleteTransitGatewayInput{
+		TransitGatewayId: aws.String(gatewayID),
+	})
+	if err != nil {
+		log.LogError("Delete transit gateway %s failed: %s", gatewayID, err.Error())
+		return err
+	}
+	timeoutTime := 600
+	if len(timeout) != 0 {
+		timeoutTime = timeout[0]
+	}
+	err = client.WaitForResourceDeleted(gatewayID, timeoutTime)
+	if err != nil {
+		return err
+	}
+	log.LogInfo("Delete transit gateway %s successfully", gatewayID)
+	return nil
+}
+
+// CreateTransitGatewayVpcAttachment attaches the VPC to the transit gateway through the subnets,
+// at most one per zone, and waits for the attachment to be available.
+func (client *AWSClient) CreateTransitGatewayVpcAttachment(gatewayID string, vpcID string, subnetIDs ...string) (*types.TransitGatewayVpcAttachment, error) {
+	output, err := client.Ec2Client.CreateTransitGatewayVpcAttachment(client.requestContext(), &ec2.CreateTransitGatewayVpcAttachmentInput{
+		TransitGatewayId: aws.String(gatewayID),
+		VpcId:            aws.String(vpcID),
+		SubnetIds:        subnetIDs,
+	})
+	if err != nil {
+		log.LogError("Attach vpc %s to transit gateway %s failed: %s", vpcID, gatewayID, err.Error())
+		return nil, err
+	}
+	attachmentID := aws.ToString(output.TransitGatewayVpcAttachment.TransitGatewayAttachmentId)
+	err = client.WaitForResourceExisting(attachmentID, 10*60)
+	if err != nil {
+		return nil, err
+	}
+	log.LogInfo("Attach vpc %s to transit gateway %s successfully: %s", vpcID, gatewayID, attachmentID)
+	return output.TransitGatewayVpcAttachment, nil
+}
+
+// CreateTransitGatewayRouteTable creates a route table of the transit gateway and waits for it to
+// be available.
+func (client *AWSClient) CreateTransitGatewayRouteTable(gatewayID string) (*types.TransitGatewayRouteTable, error) {
+	output, err := client.Ec2Client.CreateTransitGatewayRouteTable(client.requestContext(), &ec2.CreateTransitGatewayRouteTableInput{
+		TransitGatewayId: aws.String(gatewayID),
+	})
+	if err != nil {
+		log.LogError("Create route table of transit gateway %s failed: %s", gatewayID, err.Error())
+		return nil, err
+	}
+	routeTableID := aws.ToString(output.TransitGatewayRouteTable.TransitGatewayRouteTableId)
+	err = client.WaitForResourceExisting(routeTableID, 5*60)
+	if err != nil {
+		return nil, err
+	}
+	log.LogInfo("Create route table %s of transit gateway %s successfully", routeTableID, gatewayID)
+	return output.TransitGatewayRouteTable, nil
+}
+
+// DeleteTransitGatewayRouteTable deletes the transit gateway route table. Its associations must
+// be removed first.
+func (client *AWSClient) DeleteTransitGatewayRouteTable(routeTableID string) error {
+	_, err := client.Ec2Client.DeleteTransitGatewayRouteTable(client.requestContext(), &ec2.DeleteTransitGatewayRouteTableInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+	})
+	if err != nil {
+		log.LogError("Delete transit gateway route table %s failed: %s", routeTableID, err.Error())
+		return err
+	}
+	log.LogInfo("Delete transit gateway route table %s successfully", routeTableID)
+	return nil
+}
+
+// AssociateTransitGatewayRouteTable makes the route table route the traffic coming from the
+// attachment. An attachment is associated with one route table at most.
+func (client *AWSClient) AssociateTransitGatewayRouteTable(routeTableID string, attachmentID string) error {
+	_, err := client.Ec2Client.AssociateTransitGatewayRouteTable(client.requestContext(), &ec2.AssociateTransitGatewayRouteTableInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+	})
+	if err != nil {
+		log.LogError("Associate transit gateway attachment %s with route table %s failed: %s", attachmentID, routeTableID, err.Error())
+		return err
+	}
+	log.LogInfo("Associate transit gateway attachment %s with route table %s successfully", attachmentID, routeTableID)
+	return nil
+}
+
+func (client *AWSClient) DisassociateTransitGatewayRouteTable(routeTableID string, attachmentID string) error {
+	_, err := client.Ec2Client.DisassociateTransitGatewayRouteTable(client.requestContext(), &ec2.DisassociateTransitGatewayRouteTableInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+	})
+	if err != nil {
+		log.LogError("Disassociate transit gateway attachment %s from route table %s failed: %s", attachmentID, routeTableID, err.Error())
+		return err
+	}
+	log.LogInfo("Disassociate transit gateway attachment %s from route table %s successfully", attachmentID, routeTableID)
+	return nil
+}
+
+// EnableTransitGatewayRouteTablePropagation makes the route table learn routes to the CIDR
+// blocks of the VPC of the attachment.
+func (client *AWSClient) EnableTransitGatewayRouteTablePropagation(routeTableID string, attachmentID string) error {
+	_, err := client.Ec2Client.EnableTransitGatewayRouteTablePropagation(client.requestContext(), &ec2.EnableTransitGatewayRouteTablePropagationInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+	})
+	if err != nil {
+		log.LogError("Enable propagation of transit gateway attachment %s to route table %s failed: %s", attachmentID, routeTableID, err.Error())
+		return err
+	}
+	log.LogInfo("Enable propagation of transit gateway attachment %s to route table %s successfully", attachmentID, routeTableID)
+	return nil
+}
+
+func (client *AWSClient) DisableTransitGatewayRouteTablePropagation(routeTableID string, attachmentID string) error {
+	_, err := client.Ec2Client.DisableTransitGatewayRouteTablePropagation(client.requestContext(), &ec2.DisableTransitGatewayRouteTablePropagationInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+	})
+	if err != nil {
+		log.LogError("Disable propagation of transit gateway attachment %s to route table %s failed: %s", attachmentID, routeTableID, err.Error())
+		return err
+	}
+	log.LogInfo("Disable propagation of transit gateway attachment %s to route table %s successfully", attachmentID, routeTableID)
+	return nil
+}
+
+// ListTransitGatewayRouteTablePropagations lists the attachments propagating to the route table.
+func (client *AWSClient) ListTransitGatewayRouteTablePropagations(routeTableID string) ([]types.TransitGatewayRouteTablePropagation, error) {
+	output, err := client.Ec2Client.GetTransitGatewayRouteTablePropagations(client.requestContext(), &ec2.GetTransitGatewayRouteTablePropagationsInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.TransitGatewayRouteTablePropagations, nil
+}
+
+// CreateTransitGatewayRoute adds a static route of the destination CIDR block to the attachment
+// in the transit gateway route table. An empty attachmentID creates a blackhole route.
+func (client *AWSClient) CreateTransitGatewayRoute(routeTableID string, destination string, attachmentID string) error {
+	input := &ec2.CreateTransitGatewayRouteInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		DestinationCidrBlock:       aws.String(destination),
+	}
+	if attachmentID == "" {
+		input.Blackhole = aws.Bool(true)
+	} else {
+		input.TransitGatewayAttachmentId = aws.String(attachmentID)
+	}
+	_, err := client.Ec2Client.CreateTransitGatewayRoute(client.requestContext(), input)
+	if err != nil {
+		log.LogError("Create route %s in transit gateway route table %s failed: %s", destination, routeTableID, err.Error())
+		return err
+	}
+	log.LogInfo("Create route %s in transit gateway route table %s successfully", destination, routeTableID)
+	return nil
+}
+
+func (client *AWSClient) DeleteTransitGatewayRoute(routeTableID string, destination string) error {
+	_, err := client.Ec2Client.DeleteTransitGatewayRoute(client.requestContext(), &ec2.DeleteTransitGatewayRouteInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		DestinationCidrBlock:       aws.String(destination),
+	})
+	if err != nil {
+		log.LogError("Delete route %s from transit gateway route table %s failed: %s", destination, routeTableID, err.Error())
+		return err
+	}
+	log.LogInfo("Delete route %s from transit gateway route table %s successfully", destination, routeTableID)
+	return nil
+}
+
+// ListTransitGatewayRoutes lists the static and propagated routes of the transit gateway route
+// table, including the blackhole ones.
+func (client *AWSClient) ListTransitGatewayRoutes(routeTableID string) ([]types.TransitGatewayRoute, error) {
+	output, err := client.Ec2Client.SearchTransitGatewayRoutes(client.requestContext(), &ec2.SearchTransitGatewayRoutesInput{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: []string{string(types.TransitGatewayRouteStateActive), string(types.TransitGatewayRouteStateBlackhole)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Routes, nil
+}
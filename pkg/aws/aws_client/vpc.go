@@ -2,12 +2,15 @@ package aws_client
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
 	"github.com/openshift-online/ocm-common/pkg/log"
 )
 
@@ -17,7 +20,7 @@ func (client *AWSClient) ListVPCs(filter ...types.Filter) ([]types.Vpc, error) {
 	if len(filter) != 0 {
 		input.Filters = filter
 	}
-	resp, err := client.Ec2Client.DescribeVpcs(context.TODO(), input)
+	resp, err := client.Ec2Client.DescribeVpcs(client.requestContext(), input)
 	if err != nil {
 		return vpcs, err
 	}
@@ -39,6 +42,15 @@ func (client *AWSClient) ListVPCByName(vpcName string) ([]types.Vpc, error) {
 }
 
 func (client *AWSClient) CreateVpc(cidr string, name ...string) (*ec2.CreateVpcOutput, error) {
+	return client.createVpc(cidr, false, name...)
+}
+
+// CreateDualStackVpc creates a vpc with the IPv4 cidr and an Amazon provided /56 IPv6 CIDR block.
+func (client *AWSClient) CreateDualStackVpc(cidr string, name ...string) (*ec2.CreateVpcOutput, error) {
+	return client.createVpc(cidr, true, name...)
+}
+
+func (client *AWSClient) createVpc(cidr string, dualStack bool, name ...string) (*ec2.CreateVpcOutput, error) {
 	vpcName := CON.VpcDefaultName
 	if len(name) == 1 {
 		vpcName = name[0]
@@ -55,8 +67,11 @@ func (client *AWSClient) CreateVpc(cidr string, name ...string) (*ec2.CreateVpcO
 		Ipv4NetmaskLength: nil,
 		TagSpecifications: nil,
 	}
+	if dualStack {
+		input.AmazonProvidedIpv6CidrBlock = aws.Bool(true)
+	}
 
-	resp, err := client.Ec2Client.CreateVpc(context.TODO(), input)
+	resp, err := client.Ec2Client.CreateVpc(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Create vpc error %s", err.Error())
 		return nil, err
@@ -93,7 +108,7 @@ func (client *AWSClient) ModifyVpcDnsAttribute(vpcID string, dnsAttribute string
 		}
 	}
 
-	resp, err := client.Ec2Client.ModifyVpcAttribute(context.TODO(), inputModifyVpc)
+	resp, err := client.Ec2Client.ModifyVpcAttribute(client.requestContext(), inputModifyVpc)
 	if err != nil {
 		log.LogError("Modify vpc dns attribute failed %s", err.Error())
 		return nil, err
@@ -102,13 +117,56 @@ func (client *AWSClient) ModifyVpcDnsAttribute(vpcID string, dnsAttribute string
 	return resp, err
 }
 
+// DescribeVpcDnsAttribute returns whether the DNS attribute is enabled on the vpc
+// dnsAttribute should be the value of "DnsHostnames" and "DnsSupport"
+func (client *AWSClient) DescribeVpcDnsAttribute(vpcID string, dnsAttribute string) (bool, error) {
+	input := &ec2.DescribeVpcAttributeInput{
+		VpcId: aws.String(vpcID),
+	}
+	switch dnsAttribute {
+	case CON.VpcDnsHostnamesAttribute:
+		input.Attribute = types.VpcAttributeNameEnableDnsHostnames
+	case CON.VpcDnsSupportAttribute:
+		input.Attribute = types.VpcAttributeNameEnableDnsSupport
+	default:
+		return false, fmt.Errorf("unsupported vpc dns attribute %s", dnsAttribute)
+	}
+	resp, err := client.Ec2Client.DescribeVpcAttribute(client.requestContext(), input)
+	if err != nil {
+		log.LogError("Describe vpc dns attribute %s failed %s", dnsAttribute, err.Error())
+		return false, err
+	}
+	if resp.EnableDnsHostnames != nil {
+		return aws.ToBool(resp.EnableDnsHostnames.Value), nil
+	}
+	if resp.EnableDnsSupport != nil {
+		return aws.ToBool(resp.EnableDnsSupport.Value), nil
+	}
+	return false, nil
+}
+
+// AssociateVpcCidrBlock associates the secondary IPv4 cidr to the vpc
+func (client *AWSClient) AssociateVpcCidrBlock(vpcID string, cidr string) (*types.VpcCidrBlockAssociation, error) {
+	input := &ec2.AssociateVpcCidrBlockInput{
+		VpcId:     aws.String(vpcID),
+		CidrBlock: aws.String(cidr),
+	}
+	resp, err := client.Ec2Client.AssociateVpcCidrBlock(client.requestContext(), input)
+	if err != nil {
+		log.LogError("Associate cidr %s to vpc %s failed %s", cidr, vpcID, err.Error())
+		return nil, err
+	}
+	log.LogInfo("Associate cidr %s to vpc %s successfully", cidr, vpcID)
+	return resp.CidrBlockAssociation, err
+}
+
 func (client *AWSClient) DeleteVpc(vpcID string) (*ec2.DeleteVpcOutput, error) {
 	input := &ec2.DeleteVpcInput{
 		VpcId:  aws.String(vpcID),
 		DryRun: nil,
 	}
 
-	resp, err := client.Ec2Client.DeleteVpc(context.TODO(), input)
+	resp, err := client.Ec2Client.DeleteVpc(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Delete vpc %s failed with error %s", vpcID, err.Error())
 		return nil, err
@@ -123,7 +181,7 @@ func (client *AWSClient) DescribeVPC(vpcID string) (types.Vpc, error) {
 		VpcIds: []string{vpcID},
 	}
 
-	resp, err := client.Ec2Client.DescribeVpcs(context.TODO(), input)
+	resp, err := client.Ec2Client.DescribeVpcs(client.requestContext(), input)
 	if err != nil {
 		return vpc, err
 	}
@@ -143,7 +201,7 @@ func (client *AWSClient) ListEndpointAssociation(vpcID string) ([]types.VpcEndpo
 	input := ec2.DescribeVpcEndpointsInput{
 		Filters: filters,
 	}
-	resp, err := client.Ec2Client.DescribeVpcEndpoints(context.TODO(), &input)
+	resp, err := client.Ec2Client.DescribeVpcEndpoints(client.requestContext(), &input)
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +221,7 @@ func (client *AWSClient) DeleteVPCEndpoints(vpcID string) error {
 		input := &ec2.DeleteVpcEndpointsInput{
 			VpcEndpointIds: endpoints,
 		}
-		_, err = client.Ec2Client.DeleteVpcEndpoints(context.TODO(), input)
+		_, err = client.Ec2Client.DeleteVpcEndpoints(client.requestContext(), input)
 		if err != nil {
 			log.LogError("Delete vpc endpoints %s failed: %s", strings.Join(endpoints, ","), err.Error())
 		} else {
@@ -179,7 +237,7 @@ func (client *AWSClient) CreateVPCEndpoint(vpcID string, serviceName string, vpc
 		ServiceName:     &serviceName,
 		VpcEndpointType: vpcEndpointType,
 	}
-	output, err := client.Ec2Client.CreateVpcEndpoint(context.TODO(), input)
+	output, err := client.Ec2Client.CreateVpcEndpoint(client.requestContext(), input)
 	if err != nil {
 		log.LogError("Create vpc endpoints failed: %s", err.Error())
 	} else {
@@ -187,3 +245,77 @@ func (client *AWSClient) CreateVPCEndpoint(vpcID string, serviceName string, vpc
 	}
 	return err
 }
+
+// VpcCidrBlocks returns the associated IPv4 CIDR blocks of the vpc, the primary one first.
+func VpcCidrBlocks(vpc types.Vpc) []string {
+	cidrs := []string{aws.ToString(vpc.CidrBlock)}
+	for _, association := range vpc.CidrBlockAssociationSet {
+		cidr := aws.ToString(association.CidrBlock)
+		if cidr == cidrs[0] || association.CidrBlockState == nil ||
+			association.CidrBlockState.State != types.VpcCidrBlockStateCodeAssociated {
+			continue
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	return cidrs
+}
+
+// VpcIPv6CidrBlock returns the first associated IPv6 CIDR block of the vpc, or an empty string
+// when the vpc is IPv4 only or its block is still associating (see WaitForVpcIPv6CidrBlock).
+func VpcIPv6CidrBlock(vpc types.Vpc) string {
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState != nil &&
+			association.Ipv6CidrBlockState.State == types.VpcCidrBlockStateCodeAssociated {
+			return aws.ToString(association.Ipv6CidrBlock)
+		}
+	}
+	return ""
+}
+
+// WaitForVpcIPv6CidrBlock waits for <timeout> seconds until the Amazon provided IPv6 block of the vpc
+// is associated, and returns it. AWS creates the association in the associating state, so the block
+// of a new dual-stack vpc cannot be used right away.
+func (client *AWSClient) WaitForVpcIPv6CidrBlock(vpcID string, timeout ...int) (string, error) {
+	timeoutTime := 60
+	if len(timeout) != 0 {
+		timeoutTime = timeout[0]
+	}
+	var ipv6CIDR string
+	err := client.WaitFor("vpc IPv6 CIDR block associated: "+vpcID, time.Duration(timeoutTime)*time.Second,
+		func(ctx context.Context) (bool, string, error) {
+			output, err := client.Ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{VpcIds: []string{vpcID}})
+			if awserrors.IsErrorCode(err, awserrors.InvalidVpcID) || transientError(err) {
+				return false, err.Error(), nil
+			}
+			if err != nil {
+				return false, "", err
+			}
+			if len(output.Vpcs) == 0 {
+				return false, "not found", nil
+			}
+			states := []string{}
+			for _, association := range output.Vpcs[0].Ipv6CidrBlockAssociationSet {
+				state := types.VpcCidrBlockStateCode("")
+				if association.Ipv6CidrBlockState != nil {
+					state = association.Ipv6CidrBlockState.State
+				}
+				switch state {
+				case types.VpcCidrBlockStateCodeAssociated:
+					ipv6CIDR = aws.ToString(association.Ipv6CidrBlock)
+					return true, string(state), nil
+				case types.VpcCidrBlockStateCodeAssociating:
+					states = append(states, string(state))
+				}
+			}
+			if len(states) == 0 {
+				return false, "", fmt.Errorf("vpc %s got no IPv6 CIDR block associated", vpcID)
+			}
+			return false, strings.Join(states, ", "), nil
+		})
+	if err != nil {
+		log.LogError("Wait for IPv6 CIDR block of vpc %s failed: %s", vpcID, err.Error())
+		return "", err
+	}
+	log.LogInfo("VPC %s got IPv6 CIDR block %s", vpcID, ipv6CIDR)
+	return ipv6CIDR, nil
+}
@@ -0,0 +1,139 @@
+package aws_client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+	"github.com/openshift-online/ocm-common/pkg/log"
+)
+
+// ListVpcPeeringConnections lists the peering connections the VPC takes part in, either as
+// requester or as accepter. Deleted, rejected, expired and failed connections are skipped.
+func (client *AWSClient) ListVpcPeeringConnections(vpcID string) ([]types.VpcPeeringConnection, error) {
+	connections := []types.VpcPeeringConnection{}
+	seen := map[string]bool{}
+	for _, side := range []string{"requester-vpc-info.vpc-id", "accepter-vpc-info.vpc-id"} {
+		output, err := client.Ec2Client.DescribeVpcPeeringConnections(client.requestContext(), &ec2.DescribeVpcPeeringConnectionsInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String(side),
+					Values: []string{vpcID},
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, connection := range output.VpcPeeringConnections {
+			connectionID := aws.ToString(connection.VpcPeeringConnectionId)
+			if seen[connectionID] || connection.Status == nil {
+				continue
+			}
+			switch connection.Status.Code {
+			case types.VpcPeeringConnectionStateReasonCodeDeleted, types.VpcPeeringConnectionStateReasonCodeDeleting,
+				types.VpcPeeringConnectionStateReasonCodeRejected, types.VpcPeeringConnectionStateReasonCodeExpired,
+				types.VpcPeeringConnectionStateReasonCodeFailed:
+				continue
+			}
+			seen[connectionID] = true
+			connections = append(connections, connection)
+		}
+	}
+	return connections, nil
+}
+
+// CreateVpcPeeringConnection requests a peering connection from the VPC to the peer VPC of the
+// same account and region. The connection is pending until the owner of the peer VPC accepts it
+// with AcceptVpcPeeringConnection.
+func (client *AWSClient) CreateVpcPeeringConnection(vpcID string, peerVpcID string) (*types.VpcPeeringConnection, error) {
+	output, err := client.Ec2Client.CreateVpcPeeringConnection(client.requestContext(), &ec2.CreateVpcPeeringConnectionInput{
+		VpcId:     aws.String(vpcID),
+		PeerVpcId: aws.String(peerVpcID),
+	})
+	if err != nil {
+		log.LogError("Create vpc peering connection from %s to %s failed: %s", vpcID, peerVpcID, err.Error())
+		return nil, err
+	}
+	connection := output.VpcPeeringConnection
+	if connection.Status != nil && connection.Status.Code == types.VpcPeeringConnectionStateReasonCodeFailed {
+		return nil, fmt.Errorf("vpc peering connection %s from %s to %s failed: %s",
+			aws.ToString(connection.VpcPeeringConnectionId), vpcID, peerVpcID, aws.ToString(connection.Status.Message))
+	}
+	log.LogInfo("Create vpc peering connection %s from %s to %s successfully",
+		aws.ToString(connection.VpcPeeringConnectionId), vpcID, peerVpcID)
+	return connection, nil
+}
+
+// WaitForVpcPeeringConnectionPendingAcceptance waits for <timeout> seconds, 60 by default, until the
+// peering connection can be accepted. AWS creates connections in the initiating-request state and
+// refuses to accept them until they are pending acceptance.
+func (client *AWSClient) WaitForVpcPeeringConnectionPendingAcceptance(connectionID string, timeout ...int) error {
+	timeoutTime := 60
+	if len(timeout) != 0 {
+		timeoutTime = timeout[0]
+	}
+	return client.WaitFor("vpc peering connection pending acceptance: "+connectionID, time.Duration(timeoutTime)*time.Second,
+		func(ctx context.Context) (bool, string, error) {
+			output, err := client.Ec2Client.DescribeVpcPeeringConnections(ctx, &ec2.DescribeVpcPeeringConnectionsInput{
+				VpcPeeringConnectionIds: []string{connectionID},
+			})
+			if awserrors.IsErrorCode(err, awserrors.InvalidVpcPeeringConnectionID) || transientError(err) {
+				return false, err.Error(), nil
+			}
+			if err != nil {
+				return false, "", err
+			}
+			if len(output.VpcPeeringConnections) == 0 || output.VpcPeeringConnections[0].Status == nil {
+				return false, "not found", nil
+			}
+			status := output.VpcPeeringConnections[0].Status
+			switch status.Code {
+			case types.VpcPeeringConnectionStateReasonCodePendingAcceptance:
+				return true, string(status.Code), nil
+			case types.VpcPeeringConnectionStateReasonCodeInitiatingRequest, types.VpcPeeringConnectionStateReasonCodeProvisioning:
+				return false, string(status.Code), nil
+			}
+			return false, "", fmt.Errorf("vpc peering connection %s is %s: %s",
+				connectionID, status.Code, aws.ToString(status.Message))
+		})
+}
+
+// AcceptVpcPeeringConnection accepts the peering connection and waits for <timeout> seconds for
+// it to be active, 60 by default.
+func (client *AWSClient) AcceptVpcPeeringConnection(connectionID string, timeout ...int) (*types.VpcPeeringConnection, error) {
+	output, err := client.Ec2Client.AcceptVpcPeeringConnection(client.requestContext(), &ec2.AcceptVpcPeeringConnectionInput{
+		VpcPeeringConnectionId: aws.String(connectionID),
+	})
+	if err != nil {
+		log.LogError("Accept vpc peering connection %s failed: %s", connectionID, err.Error())
+		return nil, err
+	}
+	timeoutTime := 60
+	if len(timeout) != 0 {
+		timeoutTime = timeout[0]
+	}
+	err = client.WaitForResourceExisting(connectionID, timeoutTime)
+	if err != nil {
+		return nil, err
+	}
+	log.LogInfo("Accept vpc peering connection %s successfully", connectionID)
+	return output.VpcPeeringConnection, nil
+}
+
+func (client *AWSClient) DeleteVpcPeeringConnection(connectionID string) error {
+	_, err := client.Ec2Client.DeleteVpcPeeringConnection(client.requestContext(), &ec2.DeleteVpcPeeringConnectionInput{
+		VpcPeeringConnectionId: aws.String(connectionID),
+	})
+	if err != nil {
+		log.LogError("Delete vpc peering connection %s failed: %s", connectionID, err.Error())
+		return err
+	}
+	log.LogInfo("Delete vpc peering connection %s successfully", connectionID)
+	return nil
+}
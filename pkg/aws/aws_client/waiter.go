@@ -0,0 +1,176 @@
+package aws_client
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// Default waiter settings. Polling starts at DefaultWaitMinDelay and doubles after every attempt
+// up to DefaultWaitMaxDelay.
+const (
+	DefaultWaitMinDelay   = 2 * time.Second
+	DefaultWaitMaxDelay   = 30 * time.Second
+	DefaultWaitMultiplier = 2.0
+	DefaultWaitJitter     = 0.2
+)
+
+// WaitCondition checks whether the awaited state is reached. It also returns a short description of
+// the state it observed, used in progress reports and timeout errors. An error stops the wait; a
+// condition that should survive transient failures reports them as its state instead.
+type WaitCondition func(ctx context.Context) (done bool, state string, err error)
+
+// WaitProgress describes one unsuccessful attempt of a waiter.
+type WaitProgress struct {
+	// Description names what is being waited for.
+	Description string
+	// Attempt counts the checks made so far, starting at 1.
+	Attempt int
+	// Elapsed is the time since the wait started.
+	Elapsed time.Duration
+	// State is the state observed by the last check.
+	State string
+	// NextDelay is the time until the next check.
+	NextDelay time.Duration
+}
+
+// WaitTimeoutError is returned when the condition of a waiter is not met before its timeout.
+type WaitTimeoutError struct {
+	// Description names what was being waited for.
+	Description string
+	// Timeout is the time the waiter waited.
+	Timeout time.Duration
+	// Attempts is the number of checks made.
+	Attempts int
+	// LastState is the state observed by the last check.
+	LastState string
+}
+
+func (e *WaitTimeoutError) Error() string {
+	message := fmt.Sprintf("timeout after %s seconds waiting for %s",
+		strconv.FormatFloat(e.Timeout.Seconds(), 'f', -1, 64), e.Description)
+	if e.LastState != "" {
+		message = fmt.Sprintf("%s (last state: %s)", message, e.LastState)
+	}
+	return message
+}
+
+// Waiter polls a WaitCondition with exponential backoff until it is met, the timeout expires or
+// the context is done.
+type Waiter struct {
+	// Timeout bounds the whole wait. The condition is checked one last time when it expires.
+	Timeout time.Duration
+	// MinDelay is the delay after the first check. Zero means DefaultWaitMinDelay.
+	MinDelay time.Duration
+	// MaxDelay caps the delay between checks.
+	MaxDelay time.Duration
+	// Multiplier grows the delay after every check. Values below 1 keep the delay constant.
+	Multiplier float64
+	// Jitter randomly shortens each delay by up to this fraction, so that concurrent waiters do
+	// not poll in lockstep.
+	Jitter float64
+	// Progress, when set, is called after every check that did not meet the condition.
+	Progress func(WaitProgress)
+}
+
+// NewWaiter returns a waiter bounded by timeout with the default backoff settings.
+func NewWaiter(timeout time.Duration) *Waiter {
+	return &Waiter{
+		Timeout:    timeout,
+		MinDelay:   DefaultWaitMinDelay,
+		MaxDelay:   DefaultWaitMaxDelay,
+		Multiplier: DefaultWaitMultiplier,
+		Jitter:     DefaultWaitJitter,
+	}
+}
+
+// Wait checks condition until it is met. It returns the condition error, the context error, or a
+// *WaitTimeoutError carrying the last observed state. The waiter never gives up before Timeout.
+func (w *Waiter) Wait(ctx context.Context, description string, condition WaitCondition) error {
+	start := time.Now()
+	deadline := start.Add(w.Timeout)
+	delay := w.MinDelay
+	if delay <= 0 {
+		delay = DefaultWaitMinDelay
+	}
+	if w.MaxDelay > 0 && delay > w.MaxDelay {
+		delay = w.MaxDelay
+	}
+	for attempt := 1; ; attempt++ {
+		done, state, err := condition(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return &WaitTimeoutError{
+				Description: description,
+				Timeout:     w.Timeout,
+				Attempts:    attempt,
+				LastState:   state,
+			}
+		}
+		next := w.jittered(delay)
+		if next > remaining {
+			next = remaining
+		}
+		if w.Progress != nil {
+			w.Progress(WaitProgress{
+				Description: description,
+				Attempt:     attempt,
+				Elapsed:     time.Since(start),
+				State:       state,
+				NextDelay:   next,
+			})
+		}
+		if err := sleepContext(ctx, next); err != nil {
+			return err
+		}
+		delay = w.backoff(delay)
+	}
+}
+
+// backoff returns the delay following delay.
+func (w *Waiter) backoff(delay time.Duration) time.Duration {
+	if w.Multiplier > 1 {
+		delay = time.Duration(float64(delay) * w.Multiplier)
+	}
+	if w.MaxDelay > 0 && delay > w.MaxDelay {
+		delay = w.MaxDelay
+	}
+	return delay
+}
+
+// jittered shortens delay by a random fraction of up to Jitter.
+func (w *Waiter) jittered(delay time.Duration) time.Duration {
+	if w.Jitter <= 0 {
+		return delay
+	}
+	jitter := w.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	return delay - time.Duration(rand.Float64()*jitter*float64(delay))
+}
+
+// sleepContext waits for d, returning early with the context error when ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// WaitFor waits for condition with the default waiter bounded by timeout, using the client context.
+func (client *AWSClient) WaitFor(description string, timeout time.Duration, condition WaitCondition) error {
+	return NewWaiter(timeout).Wait(client.requestContext(), description, condition)
+}
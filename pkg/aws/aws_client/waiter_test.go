@@ -0,0 +1,157 @@
+package aws_client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	smithy "github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+var _ = Describe("Waiter", func() {
+	var waiter *Waiter
+
+	BeforeEach(func() {
+		waiter = &Waiter{
+			Timeout:    time.Second,
+			MinDelay:   time.Millisecond,
+			MaxDelay:   8 * time.Millisecond,
+			Multiplier: 2,
+		}
+	})
+
+	It("should return once the condition is met", func() {
+		attempts := 0
+		err := waiter.Wait(context.Background(), "three attempts", func(context.Context) (bool, string, error) {
+			attempts++
+			return attempts == 3, fmt.Sprintf("attempt %d", attempts), nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("should back off exponentially up to the maximum delay and report progress", func() {
+		progress := []WaitProgress{}
+		waiter.Progress = func(p WaitProgress) {
+			progress = append(progress, p)
+		}
+		attempts := 0
+		err := waiter.Wait(context.Background(), "six attempts", func(context.Context) (bool, string, error) {
+			attempts++
+			return attempts == 6, fmt.Sprintf("attempt %d", attempts), nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(progress).To(HaveLen(5))
+		delays := []time.Duration{}
+		for i, p := range progress {
+			Expect(p.Attempt).To(Equal(i + 1))
+			Expect(p.State).To(Equal(fmt.Sprintf("attempt %d", i+1)))
+			Expect(p.Description).To(Equal("six attempts"))
+			delays = append(delays, p.NextDelay)
+		}
+		Expect(delays).To(Equal([]time.Duration{
+			time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 8 * time.Millisecond, 8 * time.Millisecond,
+		}))
+	})
+
+	It("should only shorten delays with jitter", func() {
+		waiter.Jitter = 0.5
+		waiter.MinDelay = 10 * time.Millisecond
+		waiter.MaxDelay = 10 * time.Millisecond
+		waiter.Multiplier = 1
+		waiter.Progress = func(p WaitProgress) {
+			Expect(p.NextDelay).To(BeNumerically(">=", 5*time.Millisecond))
+			Expect(p.NextDelay).To(BeNumerically("<=", 10*time.Millisecond))
+		}
+		attempts := 0
+		Expect(waiter.Wait(context.Background(), "jitter", func(context.Context) (bool, string, error) {
+			attempts++
+			return attempts == 5, "", nil
+		})).To(Succeed())
+	})
+
+	It("should return a timeout error with the last observed state", func() {
+		waiter.Timeout = 20 * time.Millisecond
+		start := time.Now()
+		attempts := 0
+		err := waiter.Wait(context.Background(), "never", func(context.Context) (bool, string, error) {
+			attempts++
+			return false, fmt.Sprintf("pending %d", attempts), nil
+		})
+		Expect(time.Since(start)).To(BeNumerically(">=", 20*time.Millisecond))
+		var timeout *WaitTimeoutError
+		Expect(errors.As(err, &timeout)).To(BeTrue())
+		Expect(timeout.Attempts).To(Equal(attempts))
+		Expect(timeout.LastState).To(Equal(fmt.Sprintf("pending %d", attempts)))
+		Expect(err.Error()).To(Equal(fmt.Sprintf("timeout after 0.02 seconds waiting for never (last state: pending %d)", attempts)))
+	})
+
+	It("should stop on condition errors", func() {
+		boom := errors.New("boom")
+		attempts := 0
+		err := waiter.Wait(context.Background(), "failing", func(context.Context) (bool, string, error) {
+			attempts++
+			return false, "", boom
+		})
+		Expect(err).To(MatchError(boom))
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("should stop when the context is done", func() {
+		waiter.Timeout = time.Hour
+		waiter.MinDelay = time.Hour
+		ctx, cancel := context.WithCancel(context.Background())
+		err := waiter.Wait(ctx, "cancelled", func(context.Context) (bool, string, error) {
+			cancel()
+			return false, "", nil
+		})
+		Expect(err).To(MatchError(context.Canceled))
+	})
+
+	Context("with instances", func() {
+		var (
+			mockCtrl      *gomock.Controller
+			mockEC2Client *MockEC2ClientAPI
+			client        *AWSClient
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockEC2Client = NewMockEC2ClientAPI(mockCtrl)
+			client = &AWSClient{Ec2Client: mockEC2Client}
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("should wait for instances that are not visible yet", func() {
+			gomock.InOrder(
+				mockEC2Client.EXPECT().
+					DescribeInstanceStatus(gomock.Any(), gomock.Any()).
+					Return(nil, &smithy.GenericAPIError{Code: awserrors.InvalidInstanceID, Message: "not found"}),
+				mockEC2Client.EXPECT().
+					DescribeInstanceStatus(gomock.Any(), gomock.Any()).
+					Return(&ec2.DescribeInstanceStatusOutput{
+						InstanceStatuses: []types.InstanceStatus{{
+							InstanceId:     aws.String("i-0abc123"),
+							InstanceStatus: &types.InstanceStatusSummary{Status: types.SummaryStatusOk},
+						}},
+					}, nil),
+			)
+			running, err := client.WaitForInstancesRunning(context.Background(), []string{"i-0abc123"}, 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(running).To(BeTrue())
+		})
+	})
+})
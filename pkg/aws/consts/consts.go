@@ -23,9 +23,11 @@ const (
 var AWSCredentialsFilePath = HomeDir + AWSCredentialsFileRelativePath
 
 const (
-	DefaultVPCCIDR            = "10.0.0.0/16"
-	DefaultCIDRPrefix         = 24
-	RouteDestinationCidrBlock = "0.0.0.0/0"
+	DefaultVPCCIDR                = "10.0.0.0/16"
+	DefaultCIDRPrefix             = 24
+	DefaultIPv6CIDRPrefix         = 64
+	RouteDestinationCidrBlock     = "0.0.0.0/0"
+	RouteDestinationIpv6CidrBlock = "::/0"
 
 	VpcDefaultName = "ocm-ci-vpc"
 
@@ -2,42 +2,89 @@ package aws
 
 import (
 	"errors"
+	"strings"
 
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/aws/smithy-go"
 )
 
 const (
-	SignatureDoesNotMatch        = "SignatureDoesNotMatch"
-	InvalidClientTokenID         = "InvalidClientTokenId"
-	AccessDenied                 = "AccessDenied"
-	Forbidden                    = "Forbidden"
-	DryRunOperation              = "DryRunOperation"
-	UnauthorizedOperation        = "UnauthorizedOperation"
-	AuthFailure                  = "AuthFailure"
-	OptInRequired                = "OptInRequired"
-	VpcLimitExceeded             = "VpcLimitExceeded"
-	LimitExceeded                = "LimitExceeded"
-	UnrecognizedClientException  = "UnrecognizedClientException"
-	IncompleteSignature          = "IncompleteSignature"
-	AccessDeniedException        = "AccessDeniedException"
-	NoSuchResourceException      = "NoSuchResourceException"
-	Throttling                   = "Throttling"
-	SubnetNotFound               = "InvalidSubnetID.NotFound"
-	VolumeTypeNotAvailableInZone = "VolumeTypeNotAvailableInZone"
-	InvalidParameterValue        = "InvalidParameterValue"
-	NoSuchHostedZone             = "NoSuchHostedZone"
-	DependencyViolation          = "DependencyViolation"
-	NoSuchEntity                 = "NoSuchEntity"
-	InvalidRouteTableID          = "InvalidRouteTableID.NotFound"
-	InvalidAssociationID         = "InvalidAssociationID.NotFound"
-	InvalidInternetGatewayID     = "InvalidInternetGatewayID.NotFound"
-	InvalidVpcID                 = "InvalidVpcID.NotFound"
-	InvalidAllocationID          = "InvalidAllocationID.NotFound"
-	InvalidGroup                 = "InvalidGroup.NotFound"
-	InvalidGroupDuplicate        = "InvalidGroup.Duplicate"
-	InvalidSubnetID              = "InvalidSubnetId.NotFound"
-	InvalidNatGatewayID          = "InvalidNatGatewayID.NotFound"
+	SignatureDoesNotMatch             = "SignatureDoesNotMatch"
+	InvalidClientTokenID              = "InvalidClientTokenId"
+	AccessDenied                      = "AccessDenied"
+	Forbidden                         = "Forbidden"
+	DryRunOperation                   = "DryRunOperation"
+	UnauthorizedOperation             = "UnauthorizedOperation"
+	AuthFailure                       = "AuthFailure"
+	OptInRequired                     = "OptInRequired"
+	VpcLimitExceeded                  = "VpcLimitExceeded"
+	LimitExceeded                     = "LimitExceeded"
+	UnrecognizedClientException       = "UnrecognizedClientException"
+	IncompleteSignature               = "IncompleteSignature"
+	AccessDeniedException             = "AccessDeniedException"
+	NoSuchResourceException           = "NoSuchResourceException"
+	Throttling                        = "Throttling"
+	SubnetNotFound                    = "InvalidSubnetID.NotFound"
+	VolumeTypeNotAvailableInZone      = "VolumeTypeNotAvailableInZone"
+	InvalidParameterValue             = "InvalidParameterValue"
+	NoSuchHostedZone                  = "NoSuchHostedZone"
+	DependencyViolation               = "DependencyViolation"
+	NoSuchEntity                      = "NoSuchEntity"
+	InvalidRouteTableID               = "InvalidRouteTableID.NotFound"
+	InvalidAssociationID              = "InvalidAssociationID.NotFound"
+	InvalidInternetGatewayID          = "InvalidInternetGatewayID.NotFound"
+	InvalidGatewayID                  = "InvalidGatewayID.NotFound"
+	InvalidVpcID                      = "InvalidVpcID.NotFound"
+	InvalidAllocationID               = "InvalidAllocationID.NotFound"
+	InvalidGroup                      = "InvalidGroup.NotFound"
+	InvalidGroupDuplicate             = "InvalidGroup.Duplicate"
+	InvalidSubnetID                   = "InvalidSubnetId.NotFound"
+	InvalidNatGatewayID               = "InvalidNatGatewayID.NotFound"
+	NatGatewayNotFound                = "NatGatewayNotFound"
+	LoadBalancerNotFound              = "LoadBalancerNotFound"
+	InvalidInstanceID                 = "InvalidInstanceID.NotFound"
+	InvalidNetworkInterfaceID         = "InvalidNetworkInterfaceID.NotFound"
+	InvalidNetworkAclID               = "InvalidNetworkAclID.NotFound"
+	InvalidVpcEndpointID              = "InvalidVpcEndpointId.NotFound"
+	InvalidKeyPair                    = "InvalidKeyPair.NotFound"
+	InvalidKeyPairDuplicate           = "InvalidKeyPair.Duplicate"
+	InvalidVolume                     = "InvalidVolume.NotFound"
+	InvalidAMIID                      = "InvalidAMIID.NotFound"
+	InvalidAddress                    = "InvalidAddress.NotFound"
+	InvalidAttachmentID               = "InvalidAttachmentID.NotFound"
+	InvalidCapacityReservationID      = "InvalidCapacityReservationId.NotFound"
+	InvalidSecurityGroupRuleID        = "InvalidSecurityGroupRuleId.NotFound"
+	InvalidVpcPeeringConnectionID     = "InvalidVpcPeeringConnectionID.NotFound"
+	InvalidTransitGatewayID           = "InvalidTransitGatewayID.NotFound"
+	InvalidTransitGatewayAttachmentID = "InvalidTransitGatewayAttachmentID.NotFound"
+	InvalidPermissionDuplicate        = "InvalidPermission.Duplicate"
+	InvalidPermissionNotFound         = "InvalidPermission.NotFound"
+	InvalidNetworkAclEntry            = "InvalidNetworkAclEntry.NotFound"
+	NetworkAclEntryAlreadyExists      = "NetworkAclEntryAlreadyExists"
+	InvalidVpcRange                   = "InvalidVpc.Range"
+	InvalidSubnetRange                = "InvalidSubnet.Range"
+	InvalidSubnetConflict             = "InvalidSubnet.Conflict"
+	CidrConflict                      = "CidrConflict"
+	CidrLimitExceeded                 = "CidrLimitExceeded"
+	InvalidIPAddressInUse             = "InvalidIPAddress.InUse"
+	InvalidNetworkInterfaceInUse      = "InvalidNetworkInterface.InUse"
+	ResourceAlreadyAssociated         = "Resource.AlreadyAssociated"
+	GatewayNotAttached                = "Gateway.NotAttached"
+	RouteAlreadyExists                = "RouteAlreadyExists"
+	InvalidRouteNotFound              = "InvalidRoute.NotFound"
+	OperationNotPermitted             = "OperationNotPermitted"
+	IncorrectState                    = "IncorrectState"
+	DuplicateTransitGatewayAttachment = "DuplicateTransitGatewayAttachment"
+	TransitGatewayPropagationExists   = "TransitGatewayRouteTablePropagation.Duplicate"
+	TransitGatewayPropagationNotFound = "TransitGatewayRouteTablePropagation.NotFound"
+	CannotDelete                      = "CannotDelete"
+	VolumeInUse                       = "VolumeInUse"
+	MissingParameter                  = "MissingParameter"
+	InvalidID                         = "InvalidID"
+	EntityAlreadyExists               = "EntityAlreadyExists"
+	DeleteConflict                    = "DeleteConflict"
+	MalformedPolicyDocument           = "MalformedPolicyDocument"
+	InvalidInput                      = "InvalidInput"
 )
 
 func IsErrorCode(err error, code string) bool {
@@ -49,6 +96,17 @@ func IsSubnetNotFoundError(err error) bool {
 	return IsErrorCode(err, SubnetNotFound)
 }
 
+// IsNotFoundError tells whether err reports that the resource the call was about does not exist.
+func IsNotFoundError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	code := apiErr.ErrorCode()
+	return strings.HasSuffix(code, ".NotFound") || code == NatGatewayNotFound ||
+		code == LoadBalancerNotFound || code == NoSuchEntity
+}
+
 func IsThrottle(err error) bool {
 	return IsErrorCode(err, Throttling)
 }
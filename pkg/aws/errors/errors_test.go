@@ -59,6 +59,13 @@ var _ = Describe("Error Checks", func() {
 		Expect(IsSubnetNotFoundError(errors.New("random error"))).To(BeFalse())
 	})
 
+	It("should identify IsNotFoundError", func() {
+		Expect(IsNotFoundError(&smithy.GenericAPIError{Code: "InvalidVpcID.NotFound"})).To(BeTrue())
+		Expect(IsNotFoundError(&smithy.GenericAPIError{Code: "NatGatewayNotFound"})).To(BeTrue())
+		Expect(IsNotFoundError(&smithy.GenericAPIError{Code: "DependencyViolation"})).To(BeFalse())
+		Expect(IsNotFoundError(errors.New("random error"))).To(BeFalse())
+	})
+
 	It("should identify ErrorCode", func() {
 		err := &smithy.GenericAPIError{Code: "AccessDenied"}
 		Expect(IsErrorCode(err, "AccessDenied")).To(BeTrue())
@@ -0,0 +1,111 @@
+package iampolicy
+
+// NewDocument returns an empty document using the current policy language version.
+func NewDocument() *Document {
+	return &Document{Version: Version20121017}
+}
+
+// SetID sets the policy identifier.
+func (doc *Document) SetID(id string) *Document {
+	doc.ID = id
+	return doc
+}
+
+// AddStatement appends copies of statements to the document.
+func (doc *Document) AddStatement(statements ...*Statement) *Document {
+	for _, statement := range statements {
+		doc.Statement = append(doc.Statement, *statement)
+	}
+	return doc
+}
+
+// NewStatement returns an empty Allow statement.
+func NewStatement() *Statement {
+	return &Statement{Effect: EffectAllow}
+}
+
+// SetSid sets the statement identifier.
+func (s *Statement) SetSid(sid string) *Statement {
+	s.Sid = sid
+	return s
+}
+
+// Allow sets the statement effect to Allow.
+func (s *Statement) Allow() *Statement {
+	s.Effect = EffectAllow
+	return s
+}
+
+// Deny sets the statement effect to Deny.
+func (s *Statement) Deny() *Statement {
+	s.Effect = EffectDeny
+	return s
+}
+
+// AddAction appends actions to Action.
+func (s *Statement) AddAction(actions ...string) *Statement {
+	s.Action = append(s.Action, actions...)
+	return s
+}
+
+// AddNotAction appends actions to NotAction.
+func (s *Statement) AddNotAction(actions ...string) *Statement {
+	s.NotAction = append(s.NotAction, actions...)
+	return s
+}
+
+// AddResource appends resources to Resource.
+func (s *Statement) AddResource(resources ...string) *Statement {
+	s.Resource = append(s.Resource, resources...)
+	return s
+}
+
+// AddNotResource appends resources to NotResource.
+func (s *Statement) AddNotResource(resources ...string) *Statement {
+	s.NotResource = append(s.NotResource, resources...)
+	return s
+}
+
+// AnyPrincipal sets Principal to the bare "*" wildcard.
+func (s *Statement) AnyPrincipal() *Statement {
+	s.Principal = &Principal{Wildcard: true}
+	return s
+}
+
+// AddAWSPrincipal appends AWS principal ARNs or account IDs to Principal.
+func (s *Statement) AddAWSPrincipal(principals ...string) *Statement {
+	s.principal().AWS = append(s.principal().AWS, principals...)
+	return s
+}
+
+// AddServicePrincipal appends service principals such as ec2.amazonaws.com to Principal.
+func (s *Statement) AddServicePrincipal(services ...string) *Statement {
+	s.principal().Service = append(s.principal().Service, services...)
+	return s
+}
+
+// AddFederatedPrincipal appends identity provider ARNs to Principal.
+func (s *Statement) AddFederatedPrincipal(providers ...string) *Statement {
+	s.principal().Federated = append(s.principal().Federated, providers...)
+	return s
+}
+
+// AddCondition appends values to a condition key under operator, creating both as needed.
+func (s *Statement) AddCondition(operator, key string, values ...string) *Statement {
+	if s.Condition == nil {
+		s.Condition = Condition{}
+	}
+	if s.Condition[operator] == nil {
+		s.Condition[operator] = map[string]StringList{}
+	}
+	s.Condition[operator][key] = append(s.Condition[operator][key], values...)
+	return s
+}
+
+// principal returns the Principal element, creating it when absent.
+func (s *Statement) principal() *Principal {
+	if s.Principal == nil {
+		s.Principal = &Principal{}
+	}
+	return s.Principal
+}
@@ -0,0 +1,102 @@
+package iampolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DocumentDiff describes how two policy documents differ.
+type DocumentDiff struct {
+	// Added lists statements only present in the newer document.
+	Added []Statement
+	// Removed lists statements only present in the older document.
+	Removed []Statement
+	// AddedActions lists actions allowed only by the newer document, sorted.
+	AddedActions []string
+	// RemovedActions lists actions allowed only by the older document, sorted.
+	RemovedActions []string
+}
+
+// IsEmpty reports whether the documents have the same statements.
+func (d *DocumentDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// DiffDocuments compares from and to statement by statement, ignoring statement order, and summarizes
+// the actions allowed by each.
+func DiffDocuments(from, to *Document) (*DocumentDiff, error) {
+	fromKeys, err := statementKeys(from)
+	if err != nil {
+		return nil, err
+	}
+	toKeys, err := statementKeys(to)
+	if err != nil {
+		return nil, err
+	}
+	diff := &DocumentDiff{}
+	for i, key := range toKeys {
+		if !fromKeys.contains(key) {
+			diff.Added = append(diff.Added, to.Statement[i])
+		}
+	}
+	for i, key := range fromKeys {
+		if !toKeys.contains(key) {
+			diff.Removed = append(diff.Removed, from.Statement[i])
+		}
+	}
+	fromActions, toActions := allowedActions(from), allowedActions(to)
+	for action := range toActions {
+		if _, ok := fromActions[action]; !ok {
+			diff.AddedActions = append(diff.AddedActions, action)
+		}
+	}
+	for action := range fromActions {
+		if _, ok := toActions[action]; !ok {
+			diff.RemovedActions = append(diff.RemovedActions, action)
+		}
+	}
+	sort.Strings(diff.AddedActions)
+	sort.Strings(diff.RemovedActions)
+	return diff, nil
+}
+
+// keyList holds canonical statement encodings in document order.
+type keyList []string
+
+// contains reports whether key is in the list.
+func (l keyList) contains(key string) bool {
+	for _, k := range l {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// statementKeys returns the canonical JSON of each statement of doc.
+func statementKeys(doc *Document) (keyList, error) {
+	keys := make(keyList, 0, len(doc.Statement))
+	for _, statement := range doc.Statement {
+		key, err := json.Marshal(statement)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal policy statement: %w", err)
+		}
+		keys = append(keys, string(key))
+	}
+	return keys, nil
+}
+
+// allowedActions returns the set of actions listed by Allow statements.
+func allowedActions(doc *Document) map[string]struct{} {
+	actions := map[string]struct{}{}
+	for _, statement := range doc.Statement {
+		if statement.Effect != EffectAllow {
+			continue
+		}
+		for _, action := range statement.Action {
+			actions[action] = struct{}{}
+		}
+	}
+	return actions
+}
@@ -0,0 +1,37 @@
+package iampolicy_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+)
+
+var _ = Describe("DiffDocuments", func() {
+	It("reports nothing for reordered statements", func() {
+		a := iampolicy.NewStatement().SetSid("A").AddAction("ec2:DescribeVpcs").AddResource("*")
+		b := iampolicy.NewStatement().SetSid("B").AddAction("s3:GetObject").AddResource("*")
+		diff, err := iampolicy.DiffDocuments(iampolicy.NewDocument().AddStatement(a, b), iampolicy.NewDocument().AddStatement(b, a))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.IsEmpty()).To(BeTrue())
+		Expect(diff.AddedActions).To(BeEmpty())
+	})
+
+	It("reports changed statements and allowed actions", func() {
+		from := iampolicy.NewDocument().AddStatement(
+			iampolicy.NewStatement().SetSid("Read").AddAction("ec2:DescribeVpcs", "ec2:DescribeSubnets").AddResource("*"),
+			iampolicy.NewStatement().SetSid("Guard").Deny().AddAction("iam:*").AddResource("*"),
+		)
+		to := iampolicy.NewDocument().AddStatement(
+			iampolicy.NewStatement().SetSid("Read").AddAction("ec2:DescribeVpcs", "ec2:DescribeRouteTables").AddResource("*"),
+			iampolicy.NewStatement().SetSid("Guard").Deny().AddAction("iam:*").AddResource("*"),
+		)
+		diff, err := iampolicy.DiffDocuments(from, to)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.IsEmpty()).To(BeFalse())
+		Expect(diff.Added).To(HaveLen(1))
+		Expect(diff.Removed).To(HaveLen(1))
+		Expect(diff.AddedActions).To(Equal([]string{"ec2:DescribeRouteTables"}))
+		Expect(diff.RemovedActions).To(Equal([]string{"ec2:DescribeSubnets"}))
+	})
+})
@@ -0,0 +1,6 @@
+// Package iampolicy models IAM policy documents shared by permission, trust and KMS key policies.
+//
+// Elements IAM accepts as either a string or a list are modelled by StringList, which reads both forms
+// and writes a single value as a string. Documents are assembled with NewDocument and NewStatement and
+// checked with Validate before they are sent to AWS.
+package iampolicy
@@ -0,0 +1,156 @@
+package iampolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Policy grammar constants.
+const (
+	// Version20121017 is the current IAM policy language version.
+	Version20121017 = "2012-10-17"
+	// Version20081017 is the legacy IAM policy language version.
+	Version20081017 = "2008-10-17"
+	// EffectAllow grants the statement's actions.
+	EffectAllow = "Allow"
+	// EffectDeny denies the statement's actions.
+	EffectDeny = "Deny"
+	// Wildcard matches every principal, action or resource.
+	Wildcard = "*"
+)
+
+// Document models an IAM policy document.
+type Document struct {
+	// Version is the IAM policy language version.
+	Version string `json:"Version,omitempty"`
+	// ID is the optional policy identifier.
+	ID string `json:"Id,omitempty"`
+	// Statement holds the policy statements.
+	Statement []Statement `json:"Statement"`
+}
+
+// documentFields mirrors Document with a raw Statement element.
+type documentFields struct {
+	Version   string          `json:"Version,omitempty"`
+	ID        string          `json:"Id,omitempty"`
+	Statement json.RawMessage `json:"Statement"`
+}
+
+// UnmarshalJSON accepts Statement as a single object as well as a list, as IAM does.
+func (doc *Document) UnmarshalJSON(data []byte) error {
+	fields := documentFields{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*doc = Document{Version: fields.Version, ID: fields.ID}
+	if len(fields.Statement) == 0 || string(fields.Statement) == "null" {
+		return nil
+	}
+	var single map[string]json.RawMessage
+	if err := json.Unmarshal(fields.Statement, &single); err == nil {
+		statement := Statement{}
+		if err := json.Unmarshal(fields.Statement, &statement); err != nil {
+			return err
+		}
+		doc.Statement = []Statement{statement}
+		return nil
+	}
+	return json.Unmarshal(fields.Statement, &doc.Statement)
+}
+
+// Statement models a single IAM policy statement.
+type Statement struct {
+	// Sid is the optional statement identifier.
+	Sid string `json:"Sid,omitempty"`
+	// Effect is Allow or Deny.
+	Effect string `json:"Effect"`
+	// Principal identifies who the statement applies to in resource-based and trust policies.
+	Principal *Principal `json:"Principal,omitempty"`
+	// NotPrincipal identifies principals the statement does not apply to.
+	NotPrincipal *Principal `json:"NotPrincipal,omitempty"`
+	// Action lists the actions the statement covers.
+	Action StringList `json:"Action,omitempty"`
+	// NotAction lists actions the statement does not cover.
+	NotAction StringList `json:"NotAction,omitempty"`
+	// Resource lists the resources the statement covers.
+	Resource StringList `json:"Resource,omitempty"`
+	// NotResource lists resources the statement does not cover.
+	NotResource StringList `json:"NotResource,omitempty"`
+	// Condition holds condition operators such as StringEquals.
+	Condition Condition `json:"Condition,omitempty"`
+}
+
+// Principal models the Principal and NotPrincipal elements.
+type Principal struct {
+	// Wildcard is true when the element is the bare string "*", which matches every principal.
+	Wildcard bool `json:"-"`
+	// AWS lists AWS principal ARNs or account identifiers.
+	AWS StringList `json:"AWS,omitempty"`
+	// Service lists AWS service principals.
+	Service StringList `json:"Service,omitempty"`
+	// Federated lists federated identity providers.
+	Federated StringList `json:"Federated,omitempty"`
+	// CanonicalUser lists canonical user IDs.
+	CanonicalUser StringList `json:"CanonicalUser,omitempty"`
+}
+
+// principalFields avoids recursion when (un)marshalling Principal.
+type principalFields Principal
+
+// UnmarshalJSON accepts the bare "*" principal in addition to the object form.
+func (p *Principal) UnmarshalJSON(data []byte) error {
+	var wildcard string
+	if err := json.Unmarshal(data, &wildcard); err == nil {
+		if wildcard != Wildcard {
+			return fmt.Errorf("unsupported principal %q", wildcard)
+		}
+		*p = Principal{Wildcard: true}
+		return nil
+	}
+	fields := principalFields{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*p = Principal(fields)
+	return nil
+}
+
+// MarshalJSON emits "*" for wildcard principals and the object form otherwise.
+func (p Principal) MarshalJSON() ([]byte, error) {
+	if p.Wildcard {
+		return marshalJSON(Wildcard)
+	}
+	return marshalJSON(principalFields(p))
+}
+
+// IsEmpty reports whether the principal names nobody.
+func (p *Principal) IsEmpty() bool {
+	return p == nil || (!p.Wildcard && len(p.AWS) == 0 && len(p.Service) == 0 &&
+		len(p.Federated) == 0 && len(p.CanonicalUser) == 0)
+}
+
+// Condition models the Condition element as operator -> condition key -> values.
+type Condition map[string]map[string]StringList
+
+// ParseDocument unmarshals a policy document. The document may be percent-encoded as returned by IAM.
+func ParseDocument(policyJSON string) (*Document, error) {
+	decoded, err := url.PathUnescape(policyJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode policy document: %w", err)
+	}
+	doc := &Document{}
+	if err := json.Unmarshal([]byte(decoded), doc); err != nil {
+		return nil, fmt.Errorf("failed to parse policy JSON: %w", err)
+	}
+	return doc, nil
+}
+
+// JSON returns the compact JSON form of the document.
+func (doc *Document) JSON() (string, error) {
+	out, err := marshalJSON(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal policy document: %w", err)
+	}
+	return string(out), nil
+}
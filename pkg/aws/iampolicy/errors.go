@@ -0,0 +1,11 @@
+package iampolicy
+
+import "errors"
+
+// Sentinel errors for policy document validation.
+var (
+	// ErrInvalidPolicyDocument is returned when a document violates the IAM policy grammar.
+	ErrInvalidPolicyDocument = errors.New("invalid IAM policy document")
+	// ErrPolicySizeExceeded is returned when a document is larger than the applicable IAM quota.
+	ErrPolicySizeExceeded = errors.New("IAM policy document exceeds size limit")
+)
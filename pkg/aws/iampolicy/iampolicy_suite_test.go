@@ -0,0 +1,13 @@
+package iampolicy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestIAMPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IAM policy documents")
+}
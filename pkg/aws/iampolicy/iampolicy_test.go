@@ -0,0 +1,125 @@
+package iampolicy_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+)
+
+var _ = Describe("IAM policy documents", func() {
+	Describe("StringList JSON", func() {
+		It("accepts scalars and arrays", func() {
+			var values struct {
+				A iampolicy.StringList
+				B iampolicy.StringList
+				C iampolicy.StringList
+			}
+			Expect(json.Unmarshal([]byte(`{"A":"x","B":["x","y"],"C":true}`), &values)).To(Succeed())
+			Expect(values.A).To(Equal(iampolicy.StringList{"x"}))
+			Expect(values.B).To(Equal(iampolicy.StringList{"x", "y"}))
+			Expect(values.C).To(Equal(iampolicy.StringList{"true"}))
+		})
+
+		It("writes a single value as a string", func() {
+			out, err := json.Marshal(iampolicy.StringList{"x"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal(`"x"`))
+			out, err = json.Marshal(iampolicy.StringList{"x", "y"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal(`["x","y"]`))
+		})
+	})
+
+	Describe("Builder", func() {
+		It("builds a permission policy", func() {
+			doc := iampolicy.NewDocument().AddStatement(
+				iampolicy.NewStatement().
+					SetSid("Logs").
+					AddAction("logs:PutLogEvents", "logs:CreateLogStream").
+					AddResource("arn:aws:logs:*:*:*"),
+			)
+			Expect(doc.Validate()).To(Succeed())
+			out, err := doc.JSON()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(Equal(`{"Version":"2012-10-17","Statement":[{"Sid":"Logs","Effect":"Allow",` +
+				`"Action":["logs:PutLogEvents","logs:CreateLogStream"],"Resource":"arn:aws:logs:*:*:*"}]}`))
+		})
+
+		It("builds a trust policy with principals and conditions", func() {
+			doc := iampolicy.NewDocument().AddStatement(
+				iampolicy.NewStatement().
+					AddAWSPrincipal("arn:aws:iam::123456789012:role/installer").
+					AddServicePrincipal("ec2.amazonaws.com").
+					AddAction("sts:AssumeRole").
+					AddCondition("StringEquals", "sts:ExternalId", "abc"),
+			)
+			Expect(doc.Validate()).To(Succeed())
+			out, err := doc.JSON()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(ContainSubstring(`"Principal":{"AWS":"arn:aws:iam::123456789012:role/installer","Service":"ec2.amazonaws.com"}`))
+			Expect(out).To(ContainSubstring(`"Condition":{"StringEquals":{"sts:ExternalId":"abc"}}`))
+		})
+	})
+
+	Describe("ParseDocument", func() {
+		It("round-trips a KMS key policy with a wildcard principal and a single statement object", func() {
+			policy := `{"Version":"2012-10-17","Id":"key-default-1","Statement":{"Sid":"Enable IAM User Permissions",` +
+				`"Effect":"Allow","Principal":"*","Action":"kms:*","Resource":"*","Condition":{"Bool":{"kms:GrantIsForAWSResource":true}}}}`
+			doc, err := iampolicy.ParseDocument(policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(doc.ID).To(Equal("key-default-1"))
+			Expect(doc.Statement).To(HaveLen(1))
+			Expect(doc.Statement[0].Principal.Wildcard).To(BeTrue())
+			Expect(doc.Statement[0].Condition["Bool"]["kms:GrantIsForAWSResource"]).To(Equal(iampolicy.StringList{"true"}))
+			out, err := doc.JSON()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(ContainSubstring(`"Principal":"*"`))
+		})
+
+		It("decodes percent-encoded documents", func() {
+			doc, err := iampolicy.ParseDocument(url.PathEscape(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(doc.Statement[0].Action).To(Equal(iampolicy.StringList{"s3:GetObject"}))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("reports every problem", func() {
+			doc := &iampolicy.Document{Version: "2020-01-01", Statement: []iampolicy.Statement{
+				{Sid: "A", Effect: "Maybe", Action: iampolicy.StringList{"s3"}, Resource: iampolicy.StringList{"bucket"}},
+				{Sid: "A", Effect: iampolicy.EffectAllow, Action: iampolicy.StringList{"s3:*"}, NotAction: iampolicy.StringList{"iam:*"}},
+			}}
+			err := doc.Validate()
+			Expect(errors.Is(err, iampolicy.ErrInvalidPolicyDocument)).To(BeTrue())
+			for _, message := range []string{
+				"unsupported Version", "statement 0: Effect", "malformed action 's3'", "malformed resource 'bucket'",
+				"statement 1: Sid 'A' already used", "statement 1: exactly one of Action and NotAction",
+			} {
+				Expect(err.Error()).To(ContainSubstring(message))
+			}
+		})
+
+		It("rejects empty documents and principals", func() {
+			Expect(iampolicy.NewDocument().Validate()).To(MatchError(ContainSubstring("no statements")))
+			statement := iampolicy.NewStatement().AddAction("sts:AssumeRole")
+			statement.Principal = &iampolicy.Principal{}
+			Expect(iampolicy.NewDocument().AddStatement(statement).Validate()).To(MatchError(ContainSubstring("Principal is empty")))
+		})
+
+		It("checks the document size", func() {
+			statement := iampolicy.NewStatement().AddAction("s3:GetObject")
+			for i := 0; i < 100; i++ {
+				statement.AddResource("arn:aws:s3:::" + strings.Repeat("b", 20) + "/*")
+			}
+			doc := iampolicy.NewDocument().AddStatement(statement)
+			Expect(doc.ValidateSize(iampolicy.ManagedPolicySizeLimit)).To(Succeed())
+			Expect(errors.Is(doc.ValidateSize(iampolicy.TrustPolicySizeLimit), iampolicy.ErrPolicySizeExceeded)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,169 @@
+package iampolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// sizeReportTopStatements is the number of contributing statements named in size errors.
+const sizeReportTopStatements = 3
+
+// StatementSize is the effective size of one statement.
+type StatementSize struct {
+	// Index is the position of the statement in the document.
+	Index int
+	// Sid is the statement identifier, if it has one.
+	Sid string
+	// Size is the whitespace-stripped length of the statement in characters.
+	Size int
+}
+
+// SizeReport describes the effective size of a policy document against an IAM quota.
+type SizeReport struct {
+	// Size is the whitespace-stripped length of the document in characters, as IAM counts it.
+	Size int
+	// Limit is the quota the document was measured against.
+	Limit int
+	// Statements lists statement sizes, largest first.
+	Statements []StatementSize
+}
+
+// Exceeded reports whether the document is larger than the limit.
+func (r *SizeReport) Exceeded() bool {
+	return r.Size > r.Limit
+}
+
+// Top returns the n largest statements.
+func (r *SizeReport) Top(n int) []StatementSize {
+	if n > len(r.Statements) {
+		n = len(r.Statements)
+	}
+	return r.Statements[:n]
+}
+
+// Err returns an error wrapping ErrPolicySizeExceeded that names the largest statements, or nil when the
+// document fits.
+func (r *SizeReport) Err() error {
+	if !r.Exceeded() {
+		return nil
+	}
+	top := make([]string, 0, sizeReportTopStatements)
+	for _, statement := range r.Top(sizeReportTopStatements) {
+		label := fmt.Sprintf("statement %d", statement.Index)
+		if statement.Sid != "" {
+			label += fmt.Sprintf(" (%s)", statement.Sid)
+		}
+		top = append(top, fmt.Sprintf("%s: %d", label, statement.Size))
+	}
+	return fmt.Errorf("%w: %d characters, limit is %d; largest statements: %s",
+		ErrPolicySizeExceeded, r.Size, r.Limit, strings.Join(top, ", "))
+}
+
+// MeasurePolicySize computes the effective size of policyJSON as IAM does, ignoring whitespace outside
+// strings, and the size of each statement. policyJSON is taken literally: percent-decode the documents
+// returned by IAM, such as GetPolicyVersion output, before measuring them.
+func MeasurePolicySize(policyJSON string, limit int) (*SizeReport, error) {
+	size, err := compactSize([]byte(policyJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy JSON: %w", err)
+	}
+	fields := documentFields{}
+	if err := json.Unmarshal([]byte(policyJSON), &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse policy JSON: %w", err)
+	}
+	var statements []json.RawMessage
+	if bytes.HasPrefix(bytes.TrimSpace(fields.Statement), []byte("{")) {
+		statements = []json.RawMessage{fields.Statement}
+	} else if len(fields.Statement) > 0 {
+		if err := json.Unmarshal(fields.Statement, &statements); err != nil {
+			return nil, fmt.Errorf("failed to parse policy statements: %w", err)
+		}
+	}
+	report := &SizeReport{Size: size, Limit: limit}
+	for i, raw := range statements {
+		statementSize, err := compactSize(raw)
+		if err != nil {
+			return nil, err
+		}
+		var sid struct {
+			Sid string `json:"Sid"`
+		}
+		_ = json.Unmarshal(raw, &sid)
+		report.Statements = append(report.Statements, StatementSize{Index: i, Sid: sid.Sid, Size: statementSize})
+	}
+	sort.SliceStable(report.Statements, func(i, j int) bool {
+		return report.Statements[i].Size > report.Statements[j].Size
+	})
+	return report, nil
+}
+
+// CheckPolicySize returns an error wrapping ErrPolicySizeExceeded, naming the largest statements, when
+// policyJSON is larger than limit.
+func CheckPolicySize(policyJSON string, limit int) error {
+	report, err := MeasurePolicySize(policyJSON, limit)
+	if err != nil {
+		return err
+	}
+	return report.Err()
+}
+
+// SplitDocument distributes the statements of a permission policy over as few documents as needed for each
+// to fit in limit, keeping statement order. Statements with a Principal cannot be split across documents,
+// and a statement that does not fit on its own is an error.
+func SplitDocument(doc *Document, limit int) ([]*Document, error) {
+	var out []*Document
+	current := &Document{Version: doc.Version}
+	for i := range doc.Statement {
+		statement := doc.Statement[i]
+		if statement.Principal != nil || statement.NotPrincipal != nil {
+			return nil, fmt.Errorf("%w: statement %d has a principal; only permission policies can be split",
+				ErrInvalidPolicyDocument, i)
+		}
+		candidate := &Document{Version: doc.Version, Statement: append(append([]Statement{}, current.Statement...), statement)}
+		size, err := documentSize(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if size <= limit {
+			current = candidate
+			continue
+		}
+		if len(current.Statement) == 0 {
+			return nil, fmt.Errorf("%w: statement %d alone is %d characters, limit is %d", ErrPolicySizeExceeded, i, size, limit)
+		}
+		out = append(out, current)
+		current = &Document{Version: doc.Version, Statement: []Statement{statement}}
+		if size, err = documentSize(current); err != nil {
+			return nil, err
+		}
+		if size > limit {
+			return nil, fmt.Errorf("%w: statement %d alone is %d characters, limit is %d", ErrPolicySizeExceeded, i, size, limit)
+		}
+	}
+	if len(current.Statement) > 0 {
+		out = append(out, current)
+	}
+	return out, nil
+}
+
+// documentSize returns the effective size of doc.
+func documentSize(doc *Document) (int, error) {
+	out, err := marshalJSON(doc)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal policy document: %w", err)
+	}
+	return utf8.RuneCount(out), nil
+}
+
+// compactSize returns the length in characters of raw with insignificant whitespace removed.
+func compactSize(raw []byte) (int, error) {
+	compacted := &bytes.Buffer{}
+	if err := json.Compact(compacted, raw); err != nil {
+		return 0, err
+	}
+	return utf8.RuneCount(compacted.Bytes()), nil
+}
@@ -0,0 +1,98 @@
+package iampolicy_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+)
+
+var _ = Describe("Policy size", func() {
+	statementWithResources := func(sid string, count int) *iampolicy.Statement {
+		statement := iampolicy.NewStatement().SetSid(sid).AddAction("s3:GetObject")
+		for i := 0; i < count; i++ {
+			statement.AddResource(fmt.Sprintf("arn:aws:s3:::bucket-%03d/*", i))
+		}
+		return statement
+	}
+
+	It("ignores whitespace outside strings", func() {
+		pretty := "{\n  \"Version\": \"2012-10-17\",\n  \"Statement\": [\n    {\"Effect\": \"Allow\", \"Action\": \"s3:Get Object\", \"Resource\": \"*\"}\n  ]\n}"
+		report, err := iampolicy.MeasurePolicySize(pretty, iampolicy.ManagedPolicySizeLimit)
+		Expect(err).NotTo(HaveOccurred())
+		compact := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:Get Object","Resource":"*"}]}`
+		Expect(report.Size).To(Equal(len(compact)))
+		Expect(report.Exceeded()).To(BeFalse())
+		Expect(report.Err()).To(Succeed())
+	})
+
+	It("reports the largest statements first", func() {
+		doc := iampolicy.NewDocument().AddStatement(
+			statementWithResources("Small", 1),
+			statementWithResources("Large", 80),
+			statementWithResources("Medium", 20),
+		)
+		out, err := doc.JSON()
+		Expect(err).NotTo(HaveOccurred())
+		report, err := iampolicy.MeasurePolicySize(out, iampolicy.TrustPolicySizeLimit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Top(2)).To(HaveLen(2))
+		Expect(report.Top(2)[0].Sid).To(Equal("Large"))
+		Expect(report.Top(2)[0].Index).To(Equal(1))
+		Expect(report.Top(2)[1].Sid).To(Equal("Medium"))
+		err = iampolicy.CheckPolicySize(out, iampolicy.TrustPolicySizeLimit)
+		Expect(errors.Is(err, iampolicy.ErrPolicySizeExceeded)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("largest statements: statement 1 (Large)"))
+	})
+
+	It("takes documents literally", func() {
+		policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::bucket/100%"}]}`
+		report, err := iampolicy.MeasurePolicySize(policy, iampolicy.ManagedPolicySizeLimit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Size).To(Equal(len(policy)))
+		Expect(iampolicy.CheckPolicySize(policy, iampolicy.ManagedPolicySizeLimit)).To(Succeed())
+	})
+
+	It("counts <, > and & as one character", func() {
+		statement := iampolicy.NewStatement().SetSid("Html").AddAction("s3:GetObject").
+			AddResource("arn:aws:s3:::bucket/<a&b>")
+		doc := iampolicy.NewDocument().AddStatement(statement)
+		out, err := doc.JSON()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("<a&b>"))
+		docs, err := iampolicy.SplitDocument(doc, len(out))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(docs).To(HaveLen(1))
+		Expect(doc.ValidateSize(len(out))).To(Succeed())
+	})
+
+	It("splits a permission policy in statement order", func() {
+		doc := iampolicy.NewDocument().AddStatement(
+			statementWithResources("A", 60),
+			statementWithResources("B", 60),
+			statementWithResources("C", 10),
+		)
+		docs, err := iampolicy.SplitDocument(doc, 3000)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(docs).To(HaveLen(2))
+		Expect(docs[0].Statement).To(HaveLen(1))
+		Expect(docs[0].Statement[0].Sid).To(Equal("A"))
+		Expect(docs[1].Statement).To(HaveLen(2))
+		for _, part := range docs {
+			Expect(part.ValidateSize(3000)).To(Succeed())
+		}
+	})
+
+	It("refuses to split oversized statements and trust policies", func() {
+		_, err := iampolicy.SplitDocument(iampolicy.NewDocument().AddStatement(statementWithResources("Huge", 300)), iampolicy.ManagedPolicySizeLimit)
+		Expect(errors.Is(err, iampolicy.ErrPolicySizeExceeded)).To(BeTrue())
+		trust := iampolicy.NewDocument().AddStatement(iampolicy.NewStatement().AddServicePrincipal("ec2.amazonaws.com").AddAction("sts:AssumeRole"))
+		_, err = iampolicy.SplitDocument(trust, iampolicy.ManagedPolicySizeLimit)
+		Expect(errors.Is(err, iampolicy.ErrInvalidPolicyDocument)).To(BeTrue())
+		Expect(strings.Contains(err.Error(), "principal")).To(BeTrue())
+	})
+})
@@ -0,0 +1,96 @@
+package iampolicy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Default IAM quotas for policy document size, in characters, counted after whitespace is stripped.
+const (
+	// ManagedPolicySizeLimit is the maximum size of a customer managed policy.
+	ManagedPolicySizeLimit = 6144
+	// TrustPolicySizeLimit is the default maximum size of a role trust policy. The quota can be raised.
+	TrustPolicySizeLimit = 2048
+)
+
+// actionRegex matches "*" and service-prefixed actions such as s3:Get*.
+var actionRegex = regexp.MustCompile(`^(\*|[a-zA-Z0-9-]+:[a-zA-Z0-9*?]+)$`)
+
+// Validate checks the document against the IAM policy grammar: a known Version, at least one statement,
+// unique Sids, an Allow or Deny effect, exactly one of Action and NotAction, mutually exclusive
+// Principal/NotPrincipal and Resource/NotResource, and well-formed actions and resource ARNs.
+// All problems are reported, each wrapping ErrInvalidPolicyDocument.
+func (doc *Document) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidPolicyDocument, fmt.Sprintf(format, args...)))
+	}
+	if doc.Version != Version20121017 && doc.Version != Version20081017 {
+		invalid("unsupported Version '%s'", doc.Version)
+	}
+	if len(doc.Statement) == 0 {
+		invalid("no statements")
+	}
+	sids := map[string]int{}
+	for i, statement := range doc.Statement {
+		prefix := fmt.Sprintf("statement %d", i)
+		if statement.Sid != "" {
+			if first, ok := sids[statement.Sid]; ok {
+				invalid("%s: Sid '%s' already used by statement %d", prefix, statement.Sid, first)
+			} else {
+				sids[statement.Sid] = i
+			}
+		}
+		if statement.Effect != EffectAllow && statement.Effect != EffectDeny {
+			invalid("%s: Effect must be %s or %s, got '%s'", prefix, EffectAllow, EffectDeny, statement.Effect)
+		}
+		if (len(statement.Action) == 0) == (len(statement.NotAction) == 0) {
+			invalid("%s: exactly one of Action and NotAction is required", prefix)
+		}
+		if len(statement.Resource) > 0 && len(statement.NotResource) > 0 {
+			invalid("%s: Resource and NotResource are mutually exclusive", prefix)
+		}
+		if statement.Principal != nil && statement.NotPrincipal != nil {
+			invalid("%s: Principal and NotPrincipal are mutually exclusive", prefix)
+		}
+		if statement.Principal != nil && statement.Principal.IsEmpty() {
+			invalid("%s: Principal is empty", prefix)
+		}
+		for _, action := range append(append(StringList{}, statement.Action...), statement.NotAction...) {
+			if !actionRegex.MatchString(action) {
+				invalid("%s: malformed action '%s'", prefix, action)
+			}
+		}
+		for _, resource := range append(append(StringList{}, statement.Resource...), statement.NotResource...) {
+			if !validResource(resource) {
+				invalid("%s: malformed resource '%s'", prefix, resource)
+			}
+		}
+		for operator, block := range statement.Condition {
+			if operator == "" || len(block) == 0 {
+				invalid("%s: empty condition operator block '%s'", prefix, operator)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateSize checks that the effective size of the document fits in limit characters.
+// The error names the statements that contribute most.
+func (doc *Document) ValidateSize(limit int) error {
+	out, err := doc.JSON()
+	if err != nil {
+		return err
+	}
+	return CheckPolicySize(out, limit)
+}
+
+// validResource reports whether resource is "*" or an ARN with six colon-separated segments.
+func validResource(resource string) bool {
+	if resource == Wildcard {
+		return true
+	}
+	return strings.HasPrefix(resource, "arn:") && len(strings.SplitN(resource, ":", 6)) == 6
+}
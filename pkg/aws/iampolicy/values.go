@@ -0,0 +1,85 @@
+package iampolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// StringList holds an element IAM accepts as a single value or a list, such as Action, Resource or
+// condition values. Boolean and number scalars are normalized to strings.
+type StringList []string
+
+// UnmarshalJSON accepts a scalar or an array of scalars.
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch value := raw.(type) {
+	case nil:
+		*l = nil
+	case []interface{}:
+		values := make(StringList, 0, len(value))
+		for _, el := range value {
+			s, err := scalarString(el)
+			if err != nil {
+				return err
+			}
+			values = append(values, s)
+		}
+		*l = values
+	default:
+		s, err := scalarString(value)
+		if err != nil {
+			return err
+		}
+		*l = StringList{s}
+	}
+	return nil
+}
+
+// MarshalJSON emits a single value as a string and multiple values as an array.
+func (l StringList) MarshalJSON() ([]byte, error) {
+	if len(l) == 1 {
+		return marshalJSON(l[0])
+	}
+	return marshalJSON([]string(l))
+}
+
+// Contains reports whether value is in the list.
+func (l StringList) Contains(value string) bool {
+	for _, v := range l {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalJSON returns the JSON encoding of v without escaping <, > and &, which IAM takes literally and
+// counts as one character each.
+func marshalJSON(v interface{}) ([]byte, error) {
+	out := &bytes.Buffer{}
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+// scalarString converts a JSON scalar to its string form.
+func scalarString(value interface{}) (string, error) {
+	switch s := value.(type) {
+	case string:
+		return s, nil
+	case bool:
+		return strconv.FormatBool(s), nil
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported policy value type %T", value)
+	}
+}
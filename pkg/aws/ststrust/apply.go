@@ -2,12 +2,15 @@ package ststrust
 
 import (
 	"fmt"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
 )
 
 // ApplySTSExternalIDToTrustPolicy adds or updates sts:ExternalId on Allow sts:AssumeRole statements.
-// If entered is already present in the policy, the document is returned unchanged.
-// If the policy already defines other ExternalIds and entered is not among them, injection fails.
-func ApplySTSExternalIDToTrustPolicy(policyJSON, entered string) (string, error) {
+// If entered already satisfies the policy ExternalId conditions, the document is returned unchanged.
+// If the policy already defines other ExternalIds and entered does not match them, injection fails.
+// Elements other than the updated conditions are preserved; see RewriteOption for output formatting.
+func ApplySTSExternalIDToTrustPolicy(policyJSON, entered string, opts ...RewriteOption) (string, error) {
 	if entered == "" {
 		return "", ErrExternalIDEmpty
 	}
@@ -20,42 +23,39 @@ func ApplySTSExternalIDToTrustPolicy(policyJSON, entered string) (string, error)
 	if err := CanInjectSTSExternalID(policyJSON, entered); err != nil {
 		return "", err
 	}
-	ids, err := CollectSTSExternalIDsFromTrustPolicy(policyJSON)
+	doc, err := ParsePolicyDocument(policyJSON)
 	if err != nil {
 		return "", err
 	}
-	for _, id := range ids {
-		if id == entered {
-			return policyJSON, nil
-		}
-	}
-	doc, err := parsePolicyDocument(policyJSON)
+	allowed, err := externalIDAllowedByPolicy(doc, entered)
 	if err != nil {
 		return "", err
 	}
-	updated := false
-	for i := range doc.Statement {
-		if !statementAllowsAssumeRole(doc.Statement[i]) {
-			continue
-		}
-		setExternalIDCondition(&doc.Statement[i], entered)
-		updated = true
-	}
-	if !updated {
+	if allowed {
 		return policyJSON, nil
 	}
-	return marshalPolicyDocument(doc)
+	return rewritePolicyDocument(policyJSON, func(doc *PolicyDocument) (bool, error) {
+		updated := false
+		for i := range doc.Statement {
+			if !statementAllowsAssumeRole(doc.Statement[i]) {
+				continue
+			}
+			setExternalIDCondition(&doc.Statement[i], entered)
+			updated = true
+		}
+		return updated, nil
+	}, opts...)
 }
 
 // setExternalIDCondition sets sts:ExternalId on the statement StringEquals block.
 func setExternalIDCondition(statement *PolicyStatement, externalID string) {
 	if statement.Condition == nil {
-		statement.Condition = map[string]interface{}{}
+		statement.Condition = iampolicy.Condition{}
 	}
-	stringEquals, ok := statement.Condition["StringEquals"].(map[string]interface{})
-	if !ok || stringEquals == nil {
-		stringEquals = map[string]interface{}{}
+	stringEquals := statement.Condition[OperatorStringEquals]
+	if stringEquals == nil {
+		stringEquals = map[string]ConditionValues{}
 	}
-	stringEquals[externalIDCondition] = externalID
-	statement.Condition["StringEquals"] = stringEquals
+	stringEquals[externalIDCondition] = ConditionValues{externalID}
+	statement.Condition[OperatorStringEquals] = stringEquals
 }
@@ -2,112 +2,148 @@ package ststrust
 
 import (
 	"fmt"
+	"strings"
 )
 
 // CollectSTSExternalIDsFromTrustPolicy returns unique sts:ExternalId values from Allow statements
-// that include sts:AssumeRole. Values come from StringEquals and StringEqualsIfExists conditions.
+// that include sts:AssumeRole. Values come from StringEquals and StringLike conditions, including their
+// IfExists and ForAnyValue/ForAllValues variants. StringLike wildcard patterns are not concrete IDs and are skipped.
 func CollectSTSExternalIDsFromTrustPolicy(policyJSON string) ([]string, error) {
 	if policyJSON == "" {
 		return nil, nil
 	}
-	doc, err := parsePolicyDocument(policyJSON)
+	doc, err := ParsePolicyDocument(policyJSON)
 	if err != nil {
 		return nil, err
 	}
 	var ids []string
-	for _, statement := range doc.Statement {
-		if !statementAllowsAssumeRole(statement) {
+	for _, value := range externalIDConditionValues(doc) {
+		if hasWildcard(value) {
 			continue
 		}
-		ids = append(ids, collectExternalIDsFromCondition(statement.Condition)...)
+		ids = append(ids, value)
 	}
 	return uniqueSorted(ids), nil
 }
 
-// ExternalIDMatchesTrustPolicy reports whether entered appears in the trust policy ExternalId set.
+// ExternalIDMatchesTrustPolicy reports whether entered satisfies the sts:ExternalId conditions of at least
+// one Allow sts:AssumeRole statement. StringLike wildcards and negated operators are honored.
 func ExternalIDMatchesTrustPolicy(entered, policyJSON string) (bool, error) {
 	if entered == "" {
 		return false, ErrExternalIDEmpty
 	}
-	ids, err := CollectSTSExternalIDsFromTrustPolicy(policyJSON)
+	if policyJSON == "" {
+		return false, nil
+	}
+	doc, err := ParsePolicyDocument(policyJSON)
 	if err != nil {
 		return false, err
 	}
-	for _, id := range ids {
-		if id == entered {
-			return true, nil
+	return externalIDAllowedByPolicy(doc, entered)
+}
+
+// externalIDConditionValues returns every sts:ExternalId value, including wildcard patterns, that
+// Allow sts:AssumeRole statements accept through positive string operators.
+func externalIDConditionValues(doc *PolicyDocument) []string {
+	var values []string
+	for _, statement := range doc.Statement {
+		if !statementAllowsAssumeRole(statement) {
+			continue
 		}
+		values = append(values, collectExternalIDsFromCondition(Condition(statement.Condition))...)
 	}
-	return false, nil
+	return uniqueSorted(values)
 }
 
-// collectExternalIDsFromCondition extracts sts:ExternalId values from IAM Condition blocks.
-func collectExternalIDsFromCondition(condition map[string]interface{}) []string {
-	if condition == nil {
-		return nil
-	}
+// collectExternalIDsFromCondition extracts sts:ExternalId values from positive string operators.
+func collectExternalIDsFromCondition(condition Condition) []string {
 	var ids []string
-	for _, key := range []string{"StringEquals", "StringEqualsIfExists"} {
-		raw, ok := condition[key]
-		if !ok {
+	for raw, block := range condition {
+		operator, err := ParseConditionOperator(raw)
+		if err != nil || !operatorListsExternalIDs(operator) {
 			continue
 		}
-		ids = append(ids, externalIDsFromConditionBlock(raw)...)
+		for key, values := range block {
+			if strings.EqualFold(key, externalIDCondition) {
+				ids = append(ids, values...)
+			}
+		}
 	}
 	return ids
 }
 
-// externalIDsFromConditionBlock extracts sts:ExternalId from a single condition operator value.
-func externalIDsFromConditionBlock(raw interface{}) []string {
-	m, ok := raw.(map[string]interface{})
-	if !ok {
-		return nil
-	}
-	value, ok := m[externalIDCondition]
-	if !ok {
-		return nil
+// operatorListsExternalIDs reports whether operator values name accepted external IDs.
+func operatorListsExternalIDs(operator ConditionOperator) bool {
+	switch operator.Name {
+	case OperatorStringEquals, OperatorStringEqualsIgnoreCase, OperatorStringLike:
+		return true
+	default:
+		return false
 	}
-	return externalIDsFromValue(value)
 }
 
-// externalIDsFromValue normalizes string or array ExternalId condition values.
-func externalIDsFromValue(value interface{}) []string {
-	switch v := value.(type) {
-	case string:
-		if v == "" {
-			return nil
+// externalIDAllowedByPolicy reports whether entered satisfies the sts:ExternalId conditions of an
+// Allow sts:AssumeRole statement that constrains the external ID. Other condition keys are ignored.
+func externalIDAllowedByPolicy(doc *PolicyDocument, entered string) (bool, error) {
+	ctx := RequestContext{externalIDCondition: {entered}}
+	for _, statement := range doc.Statement {
+		if !statementAllowsAssumeRole(statement) {
+			continue
+		}
+		if len(collectExternalIDsFromCondition(Condition(statement.Condition))) == 0 {
+			continue
+		}
+		matched, err := externalIDOnlyCondition(Condition(statement.Condition)).Evaluate(ctx)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
 		}
-		return []string{v}
-	case []interface{}:
-		var ids []string
-		for _, el := range v {
-			if s, ok := el.(string); ok && s != "" {
-				ids = append(ids, s)
+	}
+	return false, nil
+}
+
+// externalIDOnlyCondition returns the subset of condition that constrains sts:ExternalId.
+func externalIDOnlyCondition(condition Condition) Condition {
+	out := Condition{}
+	for operator, block := range condition {
+		for key, values := range block {
+			if !strings.EqualFold(key, externalIDCondition) {
+				continue
 			}
+			if out[operator] == nil {
+				out[operator] = map[string]ConditionValues{}
+			}
+			out[operator][key] = values
 		}
-		return ids
-	default:
-		return nil
 	}
+	return out
 }
 
 // CanInjectSTSExternalID checks whether entered may be applied to an existing trust policy.
-// Injection is allowed when the policy has no ExternalId conditions or entered is already listed.
+// Injection is allowed when the policy has no ExternalId conditions or entered already satisfies them.
 func CanInjectSTSExternalID(existingPolicyJSON, entered string) error {
 	if entered == "" {
 		return ErrExternalIDEmpty
 	}
-	ids, err := CollectSTSExternalIDsFromTrustPolicy(existingPolicyJSON)
+	if existingPolicyJSON == "" {
+		return nil
+	}
+	doc, err := ParsePolicyDocument(existingPolicyJSON)
 	if err != nil {
 		return err
 	}
-	if len(ids) == 0 {
+	values := externalIDConditionValues(doc)
+	if len(values) == 0 {
 		return nil
 	}
-	for _, id := range ids {
-		if id == entered {
-			return nil
-		}
+	allowed, err := externalIDAllowedByPolicy(doc, entered)
+	if err != nil {
+		return err
+	}
+	if allowed {
+		return nil
 	}
-	return fmt.Errorf("%w: existing trust policy defines %s", ErrExternalIDConflictOnInject, formatIDList(ids))
+	return fmt.Errorf("%w: existing trust policy defines %s", ErrExternalIDConflictOnInject, formatIDList(values))
 }
@@ -0,0 +1,152 @@
+package ststrust
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+)
+
+// Condition operator names supported by the evaluator.
+const (
+	OperatorStringEquals              = "StringEquals"
+	OperatorStringNotEquals           = "StringNotEquals"
+	OperatorStringEqualsIgnoreCase    = "StringEqualsIgnoreCase"
+	OperatorStringNotEqualsIgnoreCase = "StringNotEqualsIgnoreCase"
+	OperatorStringLike                = "StringLike"
+	OperatorStringNotLike             = "StringNotLike"
+	OperatorArnEquals                 = "ArnEquals"
+	OperatorArnNotEquals              = "ArnNotEquals"
+	OperatorArnLike                   = "ArnLike"
+	OperatorArnNotLike                = "ArnNotLike"
+	OperatorBool                      = "Bool"
+	OperatorNull                      = "Null"
+)
+
+// SetQualifier is the optional ForAnyValue or ForAllValues prefix of a condition operator.
+type SetQualifier string
+
+// Set qualifiers for multivalued condition keys.
+const (
+	QualifierNone         SetQualifier = ""
+	QualifierForAnyValue  SetQualifier = "ForAnyValue"
+	QualifierForAllValues SetQualifier = "ForAllValues"
+)
+
+// ifExistsSuffix marks operators that evaluate to true when the key is absent.
+const ifExistsSuffix = "IfExists"
+
+// supportedOperators lists the base operator names ParseConditionOperator accepts.
+var supportedOperators = map[string]struct{}{
+	OperatorStringEquals:              {},
+	OperatorStringNotEquals:           {},
+	OperatorStringEqualsIgnoreCase:    {},
+	OperatorStringNotEqualsIgnoreCase: {},
+	OperatorStringLike:                {},
+	OperatorStringNotLike:             {},
+	OperatorArnEquals:                 {},
+	OperatorArnNotEquals:              {},
+	OperatorArnLike:                   {},
+	OperatorArnNotLike:                {},
+	OperatorBool:                      {},
+	OperatorNull:                      {},
+}
+
+// ConditionOperator is a parsed IAM condition operator such as "ForAnyValue:StringLikeIfExists".
+type ConditionOperator struct {
+	// Qualifier is the optional set qualifier.
+	Qualifier SetQualifier
+	// Name is the base operator, e.g. StringLike.
+	Name string
+	// IfExists is true when the operator carries the IfExists suffix.
+	IfExists bool
+}
+
+// ParseConditionOperator parses an IAM condition operator string.
+// Unknown operators return ErrUnsupportedConditionOperator so callers never silently allow a request.
+func ParseConditionOperator(operator string) (ConditionOperator, error) {
+	parsed := ConditionOperator{}
+	name := operator
+	if qualifier, rest, found := strings.Cut(operator, ":"); found {
+		switch SetQualifier(qualifier) {
+		case QualifierForAnyValue, QualifierForAllValues:
+			parsed.Qualifier = SetQualifier(qualifier)
+		default:
+			return ConditionOperator{}, fmt.Errorf("%w: %s", ErrUnsupportedConditionOperator, operator)
+		}
+		name = rest
+	}
+	if strings.HasSuffix(name, ifExistsSuffix) {
+		parsed.IfExists = true
+		name = strings.TrimSuffix(name, ifExistsSuffix)
+	}
+	if _, ok := supportedOperators[name]; !ok {
+		return ConditionOperator{}, fmt.Errorf("%w: %s", ErrUnsupportedConditionOperator, operator)
+	}
+	if name == OperatorNull && (parsed.Qualifier != QualifierNone || parsed.IfExists) {
+		return ConditionOperator{}, fmt.Errorf("%w: %s", ErrUnsupportedConditionOperator, operator)
+	}
+	parsed.Name = name
+	return parsed, nil
+}
+
+// String returns the operator in IAM policy syntax.
+func (o ConditionOperator) String() string {
+	out := o.Name
+	if o.IfExists {
+		out += ifExistsSuffix
+	}
+	if o.Qualifier != QualifierNone {
+		out = string(o.Qualifier) + ":" + out
+	}
+	return out
+}
+
+// Negated reports whether the operator matches when values differ, e.g. StringNotEquals.
+func (o ConditionOperator) Negated() bool {
+	switch o.Name {
+	case OperatorStringNotEquals, OperatorStringNotEqualsIgnoreCase, OperatorStringNotLike,
+		OperatorArnNotEquals, OperatorArnNotLike:
+		return true
+	default:
+		return false
+	}
+}
+
+// Condition models the IAM Condition element as operator -> condition key -> values.
+type Condition map[string]map[string]ConditionValues
+
+// ConditionValues holds the values of a single condition key.
+// IAM accepts a string, boolean, number or list; all are normalized to strings.
+type ConditionValues = iampolicy.StringList
+
+// ValuesFor returns the values for key under the given operator. Keys are matched case-insensitively.
+func (c Condition) ValuesFor(operator, key string) (ConditionValues, bool) {
+	block, ok := c[operator]
+	if !ok {
+		return nil, false
+	}
+	for k, values := range block {
+		if strings.EqualFold(k, key) {
+			return values, true
+		}
+	}
+	return nil, false
+}
+
+// RequestContext holds request context keys and their values, e.g. sts:ExternalId.
+// Keys are matched case-insensitively as IAM does; values are case-sensitive.
+type RequestContext map[string][]string
+
+// lookup returns the values of key and whether it is present in the context.
+func (r RequestContext) lookup(key string) ([]string, bool) {
+	if values, ok := r[key]; ok {
+		return values, true
+	}
+	for k, values := range r {
+		if strings.EqualFold(k, key) {
+			return values, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,232 @@
+package ststrust_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+)
+
+func policyWithCondition(condition map[string]interface{}) string {
+	policy := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Effect": "Allow",
+				"Principal": map[string]interface{}{
+					"AWS": "arn:aws:iam::123456789012:role/test",
+				},
+				"Action":    "sts:AssumeRole",
+				"Condition": condition,
+			},
+		},
+	}
+	out, err := json.Marshal(policy)
+	Expect(err).NotTo(HaveOccurred())
+	return string(out)
+}
+
+var _ = Describe("IAM condition model", func() {
+	Describe("ParseConditionOperator", func() {
+		It("parses set qualifiers and IfExists", func() {
+			operator, err := ststrust.ParseConditionOperator("ForAnyValue:StringLikeIfExists")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operator.Qualifier).To(Equal(ststrust.QualifierForAnyValue))
+			Expect(operator.Name).To(Equal(ststrust.OperatorStringLike))
+			Expect(operator.IfExists).To(BeTrue())
+			Expect(operator.String()).To(Equal("ForAnyValue:StringLikeIfExists"))
+		})
+
+		It("rejects unknown operators", func() {
+			_, err := ststrust.ParseConditionOperator("NumericEquals")
+			Expect(errors.Is(err, ststrust.ErrUnsupportedConditionOperator)).To(BeTrue())
+		})
+
+		It("rejects unknown set qualifiers", func() {
+			_, err := ststrust.ParseConditionOperator("ForSomeValues:StringEquals")
+			Expect(errors.Is(err, ststrust.ErrUnsupportedConditionOperator)).To(BeTrue())
+		})
+
+		It("rejects qualified Null", func() {
+			_, err := ststrust.ParseConditionOperator("ForAnyValue:Null")
+			Expect(errors.Is(err, ststrust.ErrUnsupportedConditionOperator)).To(BeTrue())
+		})
+	})
+
+	Describe("ConditionValues JSON", func() {
+		It("accepts scalars and arrays", func() {
+			var condition ststrust.Condition
+			err := json.Unmarshal([]byte(`{"Bool":{"aws:SecureTransport":true},"StringEquals":{"sts:ExternalId":["a","b"]}}`), &condition)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(condition["Bool"]["aws:SecureTransport"]).To(Equal(ststrust.ConditionValues{"true"}))
+			Expect(condition["StringEquals"]["sts:ExternalId"]).To(Equal(ststrust.ConditionValues{"a", "b"}))
+		})
+
+		It("marshals a single value as a string", func() {
+			out, err := json.Marshal(ststrust.ConditionValues{"a"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal(`"a"`))
+		})
+	})
+
+	Describe("Condition.Evaluate", func() {
+		evaluate := func(condition string, ctx ststrust.RequestContext) bool {
+			var c ststrust.Condition
+			Expect(json.Unmarshal([]byte(condition), &c)).To(Succeed())
+			matched, err := c.Evaluate(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			return matched
+		}
+
+		It("matches StringLike wildcards", func() {
+			condition := `{"StringLike":{"sts:ExternalId":"tenant-*-prod"}}`
+			Expect(evaluate(condition, ststrust.RequestContext{"sts:ExternalId": {"tenant-42-prod"}})).To(BeTrue())
+			Expect(evaluate(condition, ststrust.RequestContext{"sts:ExternalId": {"tenant-42-dev"}})).To(BeFalse())
+			Expect(evaluate(`{"StringLike":{"sts:ExternalId":"ab?"}}`, ststrust.RequestContext{"sts:ExternalId": {"abc"}})).To(BeTrue())
+		})
+
+		It("matches condition keys case-insensitively", func() {
+			condition := `{"StringEquals":{"STS:EXTERNALID":"abc"}}`
+			Expect(evaluate(condition, ststrust.RequestContext{"sts:ExternalId": {"abc"}})).To(BeTrue())
+		})
+
+		It("treats StringNotEquals on a missing key as satisfied", func() {
+			condition := `{"StringNotEquals":{"sts:ExternalId":"abc"}}`
+			Expect(evaluate(condition, ststrust.RequestContext{})).To(BeTrue())
+			Expect(evaluate(condition, ststrust.RequestContext{"sts:ExternalId": {"abc"}})).To(BeFalse())
+			Expect(evaluate(condition, ststrust.RequestContext{"sts:ExternalId": {"xyz"}})).To(BeTrue())
+		})
+
+		It("fails positive operators on a missing key unless IfExists", func() {
+			Expect(evaluate(`{"StringEquals":{"sts:ExternalId":"abc"}}`, ststrust.RequestContext{})).To(BeFalse())
+			Expect(evaluate(`{"StringEqualsIfExists":{"sts:ExternalId":"abc"}}`, ststrust.RequestContext{})).To(BeTrue())
+		})
+
+		It("applies ForAnyValue and ForAllValues set semantics", func() {
+			ctx := ststrust.RequestContext{"aws:TagKeys": {"team", "env"}}
+			Expect(evaluate(`{"ForAnyValue:StringEquals":{"aws:TagKeys":["env"]}}`, ctx)).To(BeTrue())
+			Expect(evaluate(`{"ForAllValues:StringEquals":{"aws:TagKeys":["env"]}}`, ctx)).To(BeFalse())
+			Expect(evaluate(`{"ForAllValues:StringEquals":{"aws:TagKeys":["env","team"]}}`, ctx)).To(BeTrue())
+			Expect(evaluate(`{"ForAllValues:StringEquals":{"aws:TagKeys":["env"]}}`, ststrust.RequestContext{})).To(BeTrue())
+			Expect(evaluate(`{"ForAnyValue:StringEquals":{"aws:TagKeys":["env"]}}`, ststrust.RequestContext{})).To(BeFalse())
+		})
+
+		It("evaluates Null against key presence", func() {
+			condition := `{"Null":{"sts:ExternalId":"false"}}`
+			Expect(evaluate(condition, ststrust.RequestContext{"sts:ExternalId": {"abc"}})).To(BeTrue())
+			Expect(evaluate(condition, ststrust.RequestContext{})).To(BeFalse())
+			Expect(evaluate(`{"Null":{"sts:ExternalId":"true"}}`, ststrust.RequestContext{})).To(BeTrue())
+		})
+
+		It("matches ArnLike segment by segment", func() {
+			condition := `{"ArnLike":{"aws:SourceArn":"arn:aws:iam::*:role/ManagedOpenShift-*"}}`
+			Expect(evaluate(condition, ststrust.RequestContext{"aws:SourceArn": {"arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"}})).To(BeTrue())
+			Expect(evaluate(condition, ststrust.RequestContext{"aws:SourceArn": {"arn:aws:iam::123456789012:user/ManagedOpenShift-x"}})).To(BeFalse())
+			Expect(evaluate(condition, ststrust.RequestContext{"aws:SourceArn": {"not-an-arn"}})).To(BeFalse())
+		})
+
+		It("requires every operator to match", func() {
+			condition := `{"StringLike":{"sts:ExternalId":"abc*"},"StringNotEquals":{"sts:ExternalId":"abc-blocked"}}`
+			Expect(evaluate(condition, ststrust.RequestContext{"sts:ExternalId": {"abc-ok"}})).To(BeTrue())
+			Expect(evaluate(condition, ststrust.RequestContext{"sts:ExternalId": {"abc-blocked"}})).To(BeFalse())
+		})
+
+		It("returns an error for unsupported operators", func() {
+			c := ststrust.Condition{"DateGreaterThan": {"aws:CurrentTime": {"2020-01-01T00:00:00Z"}}}
+			_, err := c.Evaluate(ststrust.RequestContext{})
+			Expect(errors.Is(err, ststrust.ErrUnsupportedConditionOperator)).To(BeTrue())
+		})
+	})
+
+	Describe("TrustPolicyAllowsAssumeRole", func() {
+		It("allows when conditions match", func() {
+			policy := policyWithCondition(map[string]interface{}{
+				"StringLike": map[string]interface{}{"sts:ExternalId": "tenant-*"},
+			})
+			allowed, err := ststrust.TrustPolicyAllowsAssumeRole(policy, ststrust.RequestContext{"sts:ExternalId": {"tenant-1"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+
+		It("lets a matching Deny override an Allow", func() {
+			policy := `{
+				"Version": "2012-10-17",
+				"Statement": [
+					{"Effect": "Allow", "Action": "sts:AssumeRole"},
+					{"Effect": "Deny", "Action": "sts:AssumeRole", "Condition": {"Null": {"sts:ExternalId": "true"}}}
+				]
+			}`
+			allowed, err := ststrust.TrustPolicyAllowsAssumeRole(policy, ststrust.RequestContext{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			allowed, err = ststrust.TrustPolicyAllowsAssumeRole(policy, ststrust.RequestContext{"sts:ExternalId": {"abc"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+	})
+
+	Describe("external ID helpers with StringLike", func() {
+		policy := func() string {
+			return policyWithCondition(map[string]interface{}{
+				"StringLike": map[string]interface{}{"sts:ExternalId": "223B9588-*"},
+			})
+		}
+
+		It("matches entered IDs against wildcard patterns", func() {
+			match, err := ststrust.ExternalIDMatchesTrustPolicy(externalIDA, policy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(BeTrue())
+			match, err = ststrust.ExternalIDMatchesTrustPolicy(externalIDB, policy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(BeFalse())
+		})
+
+		It("does not report wildcard patterns as collected IDs", func() {
+			ids, err := ststrust.CollectSTSExternalIDsFromTrustPolicy(policy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(BeEmpty())
+		})
+
+		It("validates entered IDs that satisfy a wildcard", func() {
+			Expect(ststrust.ValidateEnteredForRoleTrustPolicies(externalIDA, policy(), "")).To(Succeed())
+		})
+
+		It("reports the pattern when validation fails", func() {
+			err := ststrust.ValidateEnteredForRoleTrustPolicies(externalIDB, policy(), "")
+			var mismatch *ststrust.ExternalIDMismatchError
+			Expect(errors.As(err, &mismatch)).To(BeTrue())
+			Expect(mismatch.FoundInRole).To(Equal([]string{"223B9588-*"}))
+		})
+
+		It("leaves the policy unchanged when applying a matching ID", func() {
+			updated, err := ststrust.ApplySTSExternalIDToTrustPolicy(policy(), externalIDA)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(Equal(policy()))
+		})
+
+		It("collects IDs from set-qualified operators", func() {
+			p := policyWithCondition(map[string]interface{}{
+				"ForAnyValue:StringEquals": map[string]interface{}{"sts:ExternalId": []string{externalIDA}},
+			})
+			ids, err := ststrust.CollectSTSExternalIDsFromTrustPolicy(p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]string{externalIDA}))
+		})
+
+		It("honors StringNotEquals exclusions", func() {
+			p := policyWithCondition(map[string]interface{}{
+				"StringLike":      map[string]interface{}{"sts:ExternalId": "*"},
+				"StringNotEquals": map[string]interface{}{"sts:ExternalId": externalIDB},
+			})
+			match, err := ststrust.ExternalIDMatchesTrustPolicy(externalIDB, p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(BeFalse())
+			match, err = ststrust.ExternalIDMatchesTrustPolicy(externalIDA, p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(match).To(BeTrue())
+		})
+	})
+})
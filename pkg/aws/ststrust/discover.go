@@ -1,27 +1,121 @@
 package ststrust
 
+import (
+	"fmt"
+	"strings"
+)
+
 // DiscoverSTSExternalID returns a single external ID when discovery is unambiguous across
 // installer and support trust policies. Empty policies are skipped. Returns ("", nil) when
 // no ID should be sent to OCM (zero IDs or ambiguous multiple candidates without a unique intersection).
 func DiscoverSTSExternalID(installerPolicy, supportPolicy string) (string, error) {
-	installerIDs, err := CollectSTSExternalIDsFromTrustPolicy(installerPolicy)
-	if err != nil {
-		return "", err
-	}
-	supportIDs, err := CollectSTSExternalIDsFromTrustPolicy(supportPolicy)
+	discovery, err := DiscoverSTSExternalIDForRoles([]LabelledTrustPolicy{
+		{Label: roleLabelInstaller, Policy: installerPolicy},
+		{Label: roleLabelSupport, Policy: supportPolicy},
+	})
 	if err != nil {
 		return "", err
 	}
-	union := setUnion(installerIDs, supportIDs)
-	if len(union) == 0 {
-		return "", nil
+	return discovery.ExternalID, nil
+}
+
+// DiscoveryReason explains how DiscoverSTSExternalIDForRoles chose, or failed to choose, an external ID.
+type DiscoveryReason string
+
+// External ID discovery reasons.
+const (
+	// DiscoveryReasonSingle means exactly one ID is listed across all roles.
+	DiscoveryReasonSingle DiscoveryReason = "single"
+	// DiscoveryReasonIntersection means several IDs are listed but exactly one is shared by every role listing any.
+	DiscoveryReasonIntersection DiscoveryReason = "intersection"
+	// DiscoveryReasonAmbiguous means several IDs are listed and no single one is shared by every role listing any.
+	DiscoveryReasonAmbiguous DiscoveryReason = "ambiguous"
+	// DiscoveryReasonNone means no role lists an ID.
+	DiscoveryReasonNone DiscoveryReason = "none"
+)
+
+// LabelledTrustPolicy is a trust policy document with the label of the role it belongs to.
+type LabelledTrustPolicy struct {
+	// Label identifies the role, e.g. "installer role" or an operator role name.
+	Label string
+	// Policy is the trust policy JSON. Empty policies are skipped.
+	Policy string
+}
+
+// ExternalIDCandidates lists the external IDs found in one role's trust policy.
+type ExternalIDCandidates struct {
+	// Label identifies the role.
+	Label string
+	// IDs lists the sorted literal sts:ExternalId values of the role.
+	IDs []string
+}
+
+// ExternalIDDiscovery is the result of DiscoverSTSExternalIDForRoles.
+type ExternalIDDiscovery struct {
+	// ExternalID is the chosen ID, or empty when Reason is ambiguous or none.
+	ExternalID string
+	// Reason explains the choice.
+	Reason DiscoveryReason
+	// Candidates lists per-role IDs in input order, for every non-empty policy.
+	Candidates []ExternalIDCandidates
+}
+
+// Explain returns a human-readable description of the discovery outcome.
+func (d *ExternalIDDiscovery) Explain() string {
+	switch d.Reason {
+	case DiscoveryReasonSingle:
+		return fmt.Sprintf("using STS external ID '%s', the only one found in role trust policies", d.ExternalID)
+	case DiscoveryReasonIntersection:
+		return fmt.Sprintf("using STS external ID '%s', the only one shared by all role trust policies", d.ExternalID)
+	case DiscoveryReasonAmbiguous:
+		parts := make([]string, 0, len(d.Candidates))
+		for _, candidates := range d.Candidates {
+			if len(candidates.IDs) > 0 {
+				parts = append(parts, candidates.Label+": "+formatIDList(candidates.IDs))
+			}
+		}
+		return "multiple STS external IDs found in role trust policies (" + strings.Join(parts, ", ") +
+			"); specify the external ID explicitly"
+	default:
+		return "no STS external ID found in role trust policies"
 	}
-	if len(union) == 1 {
-		return union[0], nil
+}
+
+// DiscoverSTSExternalIDForRoles chooses an external ID across any number of labelled trust policies.
+// A single ID across all roles is chosen; otherwise the unique ID shared by every role that lists any
+// IDs is chosen. Roles that list no IDs, such as operator roles, do not constrain the intersection.
+func DiscoverSTSExternalIDForRoles(policies []LabelledTrustPolicy) (*ExternalIDDiscovery, error) {
+	discovery := &ExternalIDDiscovery{Reason: DiscoveryReasonNone}
+	var union, intersection []string
+	listing := 0
+	for _, policy := range policies {
+		if policy.Policy == "" {
+			continue
+		}
+		ids, err := CollectSTSExternalIDsFromTrustPolicy(policy.Policy)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", policy.Label, err)
+		}
+		discovery.Candidates = append(discovery.Candidates, ExternalIDCandidates{Label: policy.Label, IDs: ids})
+		if len(ids) == 0 {
+			continue
+		}
+		union = setUnion(union, ids)
+		if listing == 0 {
+			intersection = ids
+		} else {
+			intersection = setIntersection(intersection, ids)
+		}
+		listing++
 	}
-	intersection := setIntersection(installerIDs, supportIDs)
-	if len(intersection) == 1 {
-		return intersection[0], nil
+	switch {
+	case len(union) == 0:
+	case len(union) == 1:
+		discovery.ExternalID, discovery.Reason = union[0], DiscoveryReasonSingle
+	case len(intersection) == 1:
+		discovery.ExternalID, discovery.Reason = intersection[0], DiscoveryReasonIntersection
+	default:
+		discovery.Reason = DiscoveryReasonAmbiguous
 	}
-	return "", nil
+	return discovery, nil
 }
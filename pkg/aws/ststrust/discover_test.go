@@ -0,0 +1,66 @@
+package ststrust_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+)
+
+var _ = Describe("DiscoverSTSExternalIDForRoles", func() {
+	It("chooses the only ID and ignores roles without IDs", func() {
+		discovery, err := ststrust.DiscoverSTSExternalIDForRoles([]ststrust.LabelledTrustPolicy{
+			{Label: "installer role", Policy: policyWithExternalID(externalIDA)},
+			{Label: "worker role", Policy: loadFixture("sts_support_trust_policy.json")},
+			{Label: "ingress operator role", Policy: operatorTrustPolicy},
+			{Label: "control plane role"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(discovery.ExternalID).To(Equal(externalIDA))
+		Expect(discovery.Reason).To(Equal(ststrust.DiscoveryReasonSingle))
+		Expect(discovery.Candidates).To(Equal([]ststrust.ExternalIDCandidates{
+			{Label: "installer role", IDs: []string{externalIDA}},
+			{Label: "worker role", IDs: []string{}},
+			{Label: "ingress operator role", IDs: []string{}},
+		}))
+	})
+
+	It("chooses the ID shared by every role listing IDs", func() {
+		discovery, err := ststrust.DiscoverSTSExternalIDForRoles([]ststrust.LabelledTrustPolicy{
+			{Label: "installer role", Policy: policyWithMultipleExternalIDs([]string{externalIDA, externalIDB})},
+			{Label: "support role", Policy: policyWithMultipleExternalIDs([]string{externalIDB, externalIDC})},
+			{Label: "worker role", Policy: policyWithExternalID(externalIDB)},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(discovery.ExternalID).To(Equal(externalIDB))
+		Expect(discovery.Reason).To(Equal(ststrust.DiscoveryReasonIntersection))
+	})
+
+	It("reports ambiguity with per-role candidates", func() {
+		discovery, err := ststrust.DiscoverSTSExternalIDForRoles([]ststrust.LabelledTrustPolicy{
+			{Label: "installer role", Policy: policyWithExternalID(externalIDA)},
+			{Label: "support role", Policy: policyWithExternalID(externalIDB)},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(discovery.ExternalID).To(BeEmpty())
+		Expect(discovery.Reason).To(Equal(ststrust.DiscoveryReasonAmbiguous))
+		Expect(discovery.Explain()).To(ContainSubstring("installer role: [" + externalIDA + "]"))
+		Expect(discovery.Explain()).To(ContainSubstring("support role: [" + externalIDB + "]"))
+	})
+
+	It("reports none when no role lists an ID", func() {
+		discovery, err := ststrust.DiscoverSTSExternalIDForRoles([]ststrust.LabelledTrustPolicy{
+			{Label: "installer role", Policy: loadFixture("sts_installer_trust_policy.json")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(discovery.Reason).To(Equal(ststrust.DiscoveryReasonNone))
+		Expect(discovery.Explain()).To(Equal("no STS external ID found in role trust policies"))
+	})
+
+	It("labels parse errors with the role", func() {
+		_, err := ststrust.DiscoverSTSExternalIDForRoles([]ststrust.LabelledTrustPolicy{
+			{Label: "worker role", Policy: "{bad"},
+		})
+		Expect(err).To(MatchError(ContainSubstring("worker role")))
+	})
+})
@@ -2,4 +2,7 @@
 //
 // External IDs are never auto-generated; callers supply user-provided values. Trust policy JSON may
 // be percent-encoded (see decodePolicyDocument); PathUnescape is used so '+' in external IDs is preserved.
+// Condition elements are modelled by Condition and evaluated against a RequestContext. Rewrites keep
+// untouched elements, member order and the original encoding so updated policies produce minimal diffs.
+// LintTrustPolicy reports typed findings for tooling that needs a machine-readable trust policy review.
 package ststrust
@@ -14,6 +14,10 @@ var (
 	ErrExternalIDConflictOnInject = errors.New("STS external ID is not compatible with existing trust policy conditions")
 	// ErrNoTrustPolicyExternalID is returned when validation requires ExternalId conditions but none were found.
 	ErrNoTrustPolicyExternalID = errors.New("role trust policy has no sts:ExternalId condition")
+	// ErrExternalIDLastValue is returned when a removal would leave a statement without any sts:ExternalId value.
+	ErrExternalIDLastValue = errors.New("removing the STS external ID would drop the sts:ExternalId condition")
+	// ErrUnsupportedConditionOperator is returned when a trust policy uses a condition operator the evaluator does not model.
+	ErrUnsupportedConditionOperator = errors.New("unsupported IAM condition operator")
 )
 
 // ExternalIDMismatchError describes a membership validation failure for a specific role.
@@ -22,7 +26,7 @@ type ExternalIDMismatchError struct {
 	RoleLabel string
 	// Entered is the user-supplied external ID.
 	Entered string
-	// FoundInRole lists sts:ExternalId values present in that role's trust policy.
+	// FoundInRole lists sts:ExternalId values and patterns present in that role's trust policy.
 	FoundInRole []string
 }
 
@@ -0,0 +1,212 @@
+package ststrust
+
+import (
+	"sort"
+	"strings"
+)
+
+// Evaluate reports whether every operator and key in the condition is satisfied by ctx.
+// An empty condition is always satisfied.
+func (c Condition) Evaluate(ctx RequestContext) (bool, error) {
+	operators := make([]string, 0, len(c))
+	for operator := range c {
+		operators = append(operators, operator)
+	}
+	sort.Strings(operators)
+	for _, raw := range operators {
+		operator, err := ParseConditionOperator(raw)
+		if err != nil {
+			return false, err
+		}
+		for key, values := range c[raw] {
+			contextValues, present := ctx.lookup(key)
+			if !evaluateConditionKey(operator, values, contextValues, present) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// evaluateConditionKey applies a single operator to one condition key.
+// Policy values are ORed; the set qualifier decides how multiple request values combine.
+func evaluateConditionKey(operator ConditionOperator, policyValues ConditionValues, requestValues []string, present bool) bool {
+	if operator.Name == OperatorNull {
+		return evaluateNull(policyValues, present)
+	}
+	if !present {
+		if operator.IfExists {
+			return true
+		}
+		switch operator.Qualifier {
+		case QualifierForAllValues:
+			return true
+		case QualifierForAnyValue:
+			return false
+		default:
+			return operator.Negated()
+		}
+	}
+	switch operator.Qualifier {
+	case QualifierForAllValues:
+		for _, value := range requestValues {
+			if !matchesPolicyValues(operator, policyValues, value) {
+				return false
+			}
+		}
+		return true
+	case QualifierForAnyValue:
+		for _, value := range requestValues {
+			if matchesPolicyValues(operator, policyValues, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		if operator.Negated() {
+			for _, value := range requestValues {
+				if !matchesPolicyValues(operator, policyValues, value) {
+					return false
+				}
+			}
+			return true
+		}
+		for _, value := range requestValues {
+			if matchesPolicyValues(operator, policyValues, value) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// evaluateNull checks key presence: "true" requires the key to be absent, "false" requires it.
+func evaluateNull(policyValues ConditionValues, present bool) bool {
+	for _, value := range policyValues {
+		wantAbsent := strings.EqualFold(value, "true")
+		if wantAbsent != present {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPolicyValues reports whether value satisfies the operator against any policy value.
+// For negated operators it reports whether value differs from every policy value.
+func matchesPolicyValues(operator ConditionOperator, policyValues ConditionValues, value string) bool {
+	matched := false
+	for _, policyValue := range policyValues {
+		if matchConditionValue(operator.Name, policyValue, value) {
+			matched = true
+			break
+		}
+	}
+	if operator.Negated() {
+		return !matched
+	}
+	return matched
+}
+
+// matchConditionValue compares a single policy value with a request value using the base operator.
+func matchConditionValue(name, policyValue, value string) bool {
+	switch name {
+	case OperatorStringEquals, OperatorStringNotEquals:
+		return policyValue == value
+	case OperatorStringEqualsIgnoreCase, OperatorStringNotEqualsIgnoreCase:
+		return strings.EqualFold(policyValue, value)
+	case OperatorStringLike, OperatorStringNotLike:
+		return matchWildcard(policyValue, value)
+	case OperatorArnEquals, OperatorArnNotEquals, OperatorArnLike, OperatorArnNotLike:
+		return matchArn(policyValue, value)
+	case OperatorBool:
+		return strings.EqualFold(policyValue, value)
+	default:
+		return false
+	}
+}
+
+// arnSegments is the number of colon-separated ARN segments compared by Arn operators.
+const arnSegments = 6
+
+// matchArn matches ARNs segment by segment; each segment may contain * and ? wildcards.
+func matchArn(pattern, value string) bool {
+	patternParts := strings.SplitN(pattern, ":", arnSegments)
+	valueParts := strings.SplitN(value, ":", arnSegments)
+	if len(patternParts) != arnSegments || len(valueParts) != arnSegments {
+		return false
+	}
+	for i := range patternParts {
+		if !matchWildcard(patternParts[i], valueParts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchWildcard matches value against pattern where * matches any run of characters
+// and ? matches exactly one character.
+func matchWildcard(pattern, value string) bool {
+	p, v := []rune(pattern), []rune(value)
+	pi, vi := 0, 0
+	star, mark := -1, 0
+	for vi < len(v) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == v[vi]):
+			pi++
+			vi++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, vi
+			pi++
+		case star >= 0:
+			pi = star + 1
+			mark++
+			vi = mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// hasWildcard reports whether a StringLike or ArnLike value contains wildcard characters.
+func hasWildcard(value string) bool {
+	return strings.ContainsAny(value, "*?")
+}
+
+// AllowsAssumeRole reports whether the policy allows sts:AssumeRole for a request with ctx.
+// A matching Deny statement overrides any Allow. Principal is not evaluated.
+func (doc *PolicyDocument) AllowsAssumeRole(ctx RequestContext) (bool, error) {
+	allowed := false
+	for _, statement := range doc.Statement {
+		if !actionIncludesAssumeRole(statement.Action) {
+			continue
+		}
+		matched, err := Condition(statement.Condition).Evaluate(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			continue
+		}
+		switch statement.Effect {
+		case effectDeny:
+			return false, nil
+		case effectAllow:
+			allowed = true
+		}
+	}
+	return allowed, nil
+}
+
+// TrustPolicyAllowsAssumeRole parses policyJSON and reports whether it allows sts:AssumeRole
+// for a request carrying the given context keys.
+func TrustPolicyAllowsAssumeRole(policyJSON string, ctx RequestContext) (bool, error) {
+	doc, err := ParsePolicyDocument(policyJSON)
+	if err != nil {
+		return false, err
+	}
+	return doc.AllowsAssumeRole(ctx)
+}
@@ -0,0 +1,173 @@
+package ststrust
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// Severity ranks how serious a lint finding is.
+type Severity string
+
+// Lint finding severities.
+const (
+	// SeverityError marks trust that is broader than intended and should be fixed.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a weakness or inconsistency worth reviewing.
+	SeverityWarning Severity = "warning"
+)
+
+// LintRule identifies the check that produced a finding.
+type LintRule string
+
+// Trust policy lint rules.
+const (
+	// LintRuleWildcardPrincipal flags Allow sts:AssumeRole statements trusting every principal.
+	LintRuleWildcardPrincipal LintRule = "wildcard-principal"
+	// LintRuleMissingExternalID flags cross-account AWS principals trusted without an sts:ExternalId condition.
+	LintRuleMissingExternalID LintRule = "missing-external-id"
+	// LintRuleMissingAudience flags federated principals trusted without an aud condition.
+	LintRuleMissingAudience LintRule = "missing-audience"
+	// LintRuleDuplicateStatement flags statements identical to an earlier one apart from Sid.
+	LintRuleDuplicateStatement LintRule = "duplicate-statement"
+	// LintRulePolicyVersion flags documents whose Version is not 2012-10-17.
+	LintRulePolicyVersion LintRule = "policy-version"
+)
+
+// LintFinding is a single problem reported by LintTrustPolicy.
+type LintFinding struct {
+	// Rule is the check that produced the finding.
+	Rule LintRule `json:"rule"`
+	// Severity is error or warning.
+	Severity Severity `json:"severity"`
+	// StatementIndex is the index of the offending statement, or -1 for document-level findings.
+	StatementIndex int `json:"statementIndex"`
+	// Sid is the Sid of the offending statement, if it has one.
+	Sid string `json:"sid,omitempty"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// LintOption customizes LintTrustPolicy.
+type LintOption func(*lintOptions)
+
+// lintOptions holds lint settings.
+type lintOptions struct {
+	roleAccountID string
+}
+
+// WithRoleAccountID sets the account that owns the role, so principals of that account are not treated as
+// cross-account. Without it every account-scoped AWS principal is considered cross-account.
+func WithRoleAccountID(accountID string) LintOption {
+	return func(o *lintOptions) {
+		o.roleAccountID = accountID
+	}
+}
+
+// LintTrustPolicy parses policyJSON and returns its lint findings in statement order.
+func LintTrustPolicy(policyJSON string, opts ...LintOption) ([]LintFinding, error) {
+	doc, err := ParsePolicyDocument(policyJSON)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Lint(opts...), nil
+}
+
+// Lint checks the trust policy for wildcard and cross-account trust without safeguards, federated trust
+// without an audience, duplicate statements and an unexpected Version. Document-level findings come first.
+func (doc *PolicyDocument) Lint(opts ...LintOption) []LintFinding {
+	options := lintOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	findings := []LintFinding{}
+	if doc.Version != policyVersionDefault {
+		findings = append(findings, LintFinding{
+			Rule:           LintRulePolicyVersion,
+			Severity:       SeverityWarning,
+			StatementIndex: -1,
+			Message:        fmt.Sprintf("policy Version is '%s', expected '%s'", doc.Version, policyVersionDefault),
+		})
+	}
+	seen := map[string]int{}
+	for i, statement := range doc.Statement {
+		finding := func(rule LintRule, severity Severity, format string, args ...interface{}) LintFinding {
+			return LintFinding{Rule: rule, Severity: severity, StatementIndex: i, Sid: statement.Sid, Message: fmt.Sprintf(format, args...)}
+		}
+		if key, ok := statementLintKey(statement); ok {
+			if first, duplicate := seen[key]; duplicate {
+				findings = append(findings, finding(LintRuleDuplicateStatement, SeverityWarning,
+					"statement duplicates statement %d", first))
+			} else {
+				seen[key] = i
+			}
+		}
+		if statement.Effect != effectAllow || statement.Principal == nil {
+			continue
+		}
+		if actionMatches(statement.Action, assumeRoleAction) {
+			if principalIsWildcard(statement.Principal) {
+				findings = append(findings, finding(LintRuleWildcardPrincipal, SeverityError,
+					"statement allows %s for any principal", assumeRoleAction))
+			}
+			if !conditionHasKey(Condition(statement.Condition), func(key string) bool { return strings.EqualFold(key, externalIDCondition) }) {
+				accounts := crossAccountPrincipals(statement.Principal, options.roleAccountID)
+				if len(accounts) > 0 {
+					findings = append(findings, finding(LintRuleMissingExternalID, SeverityWarning,
+						"statement trusts cross-account principals %v without an %s condition", accounts, externalIDCondition))
+				}
+			}
+		}
+		if len(statement.Principal.Federated) > 0 &&
+			!conditionHasKey(Condition(statement.Condition), func(key string) bool { return strings.HasSuffix(strings.ToLower(key), ":aud") }) {
+			findings = append(findings, finding(LintRuleMissingAudience, SeverityWarning,
+				"statement trusts federated principals %v without an aud condition", statement.Principal.Federated))
+		}
+	}
+	return findings
+}
+
+// statementLintKey returns a comparison key for duplicate detection that ignores Sid.
+func statementLintKey(statement PolicyStatement) (string, bool) {
+	statement.Sid = ""
+	key, err := json.Marshal(statement)
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
+
+// principalIsWildcard reports whether the principal matches every AWS principal.
+func principalIsWildcard(principal *PolicyStatementPrincipal) bool {
+	return principal.Wildcard || principal.AWS.Contains(principalWildcard)
+}
+
+// crossAccountPrincipals returns AWS principals whose account differs from roleAccountID.
+func crossAccountPrincipals(principal *PolicyStatementPrincipal, roleAccountID string) []string {
+	var out []string
+	for _, value := range principal.AWS {
+		account := value
+		if parsed, err := arn.Parse(value); err == nil {
+			account = parsed.AccountID
+		}
+		if value == principalWildcard || account == "" || account == roleAccountID {
+			continue
+		}
+		out = append(out, value)
+	}
+	return out
+}
+
+// conditionHasKey reports whether any operator block of condition has a key accepted by match.
+func conditionHasKey(condition Condition, match func(key string) bool) bool {
+	for _, block := range condition {
+		for key := range block {
+			if match(key) {
+				return true
+			}
+		}
+	}
+	return false
+}
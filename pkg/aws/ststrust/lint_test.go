@@ -0,0 +1,79 @@
+package ststrust_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+)
+
+var _ = Describe("Trust policy lint", func() {
+	rules := func(findings []ststrust.LintFinding) []ststrust.LintRule {
+		out := []ststrust.LintRule{}
+		for _, finding := range findings {
+			out = append(out, finding.Rule)
+		}
+		return out
+	}
+
+	It("reports nothing for a well-formed policy", func() {
+		findings, err := ststrust.LintTrustPolicy(policyWithExternalID(externalIDA))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("reports nothing for a federated principal with an audience", func() {
+		findings, err := ststrust.LintTrustPolicy(operatorTrustPolicy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("flags wildcard principals", func() {
+		findings, err := ststrust.LintTrustPolicy(`{"Version":"2012-10-17","Statement":[` +
+			`{"Sid":"Open","Effect":"Allow","Principal":"*","Action":"sts:AssumeRole"}]}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Rule).To(Equal(ststrust.LintRuleWildcardPrincipal))
+		Expect(findings[0].Severity).To(Equal(ststrust.SeverityError))
+		Expect(findings[0].StatementIndex).To(Equal(0))
+		Expect(findings[0].Sid).To(Equal("Open"))
+	})
+
+	It("flags cross-account principals without an external ID", func() {
+		policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":[` +
+			`"arn:aws:iam::123456789012:role/local","arn:aws:iam::710019948333:role/remote"]},"Action":"sts:AssumeRole"}]}`
+		findings, err := ststrust.LintTrustPolicy(policy, ststrust.WithRoleAccountID("123456789012"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules(findings)).To(Equal([]ststrust.LintRule{ststrust.LintRuleMissingExternalID}))
+		Expect(findings[0].Message).To(ContainSubstring("710019948333"))
+		Expect(findings[0].Message).NotTo(ContainSubstring("role/local"))
+	})
+
+	It("does not flag same-account principals", func() {
+		policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"123456789012"},"Action":"sts:AssumeRole"}]}`
+		findings, err := ststrust.LintTrustPolicy(policy, ststrust.WithRoleAccountID("123456789012"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("flags federated principals without an audience", func() {
+		policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Federated":"` + oidcProviderArn +
+			`"},"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"StringEquals":{"oidc.example.com/abc123:sub":"` + operatorSubject + `"}}}]}`
+		findings, err := ststrust.LintTrustPolicy(policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules(findings)).To(Equal([]ststrust.LintRule{ststrust.LintRuleMissingAudience}))
+	})
+
+	It("flags duplicate statements and unexpected versions", func() {
+		statement := `{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}`
+		policy := `{"Version":"2008-10-17","Statement":[` + statement + `,` +
+			`{"Sid":"Again","Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}`
+		findings, err := ststrust.LintTrustPolicy(policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules(findings)).To(Equal([]ststrust.LintRule{ststrust.LintRulePolicyVersion, ststrust.LintRuleDuplicateStatement}))
+		Expect(findings[0].StatementIndex).To(Equal(-1))
+		Expect(findings[1].StatementIndex).To(Equal(1))
+		Expect(findings[1].Sid).To(Equal("Again"))
+		Expect(findings[1].Message).To(ContainSubstring("statement 0"))
+	})
+})
@@ -5,51 +5,34 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
 )
 
 // Trust policy JSON constants for AssumeRole and ExternalId handling.
 const (
 	assumeRoleAction     = "sts:AssumeRole"
 	externalIDCondition  = "sts:ExternalId"
-	policyVersionDefault = "2012-10-17"
+	policyVersionDefault = iampolicy.Version20121017
+	effectAllow          = iampolicy.EffectAllow
+	effectDeny           = iampolicy.EffectDeny
+	principalWildcard    = iampolicy.Wildcard
 )
 
-// PolicyDocument models an IAM trust policy document.
+// PolicyDocument models an IAM trust policy document. It embeds iampolicy.Document, which parses it.
 type PolicyDocument struct {
-	// Version is the IAM policy version, typically "2012-10-17".
-	Version string `json:"Version,omitempty"`
-	// Statement holds trust policy statements.
-	Statement []PolicyStatement `json:"Statement"`
+	iampolicy.Document
 }
 
-// PolicyStatement models a single IAM policy statement.
-type PolicyStatement struct {
-	// Sid is the optional statement identifier.
-	Sid string `json:"Sid,omitempty"`
-	// Effect is typically "Allow" or "Deny".
-	Effect string `json:"Effect"`
-	// Principal identifies who may assume the role.
-	Principal *PolicyStatementPrincipal `json:"Principal,omitempty"`
-	// Action is sts:AssumeRole or a list of actions.
-	Action interface{} `json:"Action,omitempty"`
-	// Resource is the optional resource element.
-	Resource interface{} `json:"Resource,omitempty"`
-	// Condition holds IAM condition operators such as StringEquals.
-	Condition map[string]interface{} `json:"Condition,omitempty"`
-}
+// PolicyStatement models a single IAM trust policy statement.
+type PolicyStatement = iampolicy.Statement
 
 // PolicyStatementPrincipal models the Principal element in a trust policy statement.
-type PolicyStatementPrincipal struct {
-	// AWS lists AWS principal ARNs or account identifiers.
-	AWS interface{} `json:"AWS,omitempty"`
-	// Service lists AWS service principals.
-	Service interface{} `json:"Service,omitempty"`
-	// Federated is a federated identity provider ARN.
-	Federated string `json:"Federated,omitempty"`
-}
+type PolicyStatementPrincipal = iampolicy.Principal
 
-// parsePolicyDocument decodes and unmarshals a trust policy JSON document.
-func parsePolicyDocument(policyJSON string) (*PolicyDocument, error) {
+// ParsePolicyDocument decodes and unmarshals a trust policy JSON document.
+// The document may be percent-encoded as returned by IAM GetRole.
+func ParsePolicyDocument(policyJSON string) (*PolicyDocument, error) {
 	decoded, err := decodePolicyDocument(policyJSON)
 	if err != nil {
 		return nil, err
@@ -61,18 +44,6 @@ func parsePolicyDocument(policyJSON string) (*PolicyDocument, error) {
 	return doc, nil
 }
 
-// marshalPolicyDocument serializes a trust policy document to JSON.
-func marshalPolicyDocument(doc *PolicyDocument) (string, error) {
-	if doc.Version == "" {
-		doc.Version = policyVersionDefault
-	}
-	out, err := json.Marshal(doc)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal trust policy JSON: %w", err)
-	}
-	return string(out), nil
-}
-
 // decodePolicyDocument percent-decodes policy JSON using PathUnescape.
 func decodePolicyDocument(policyJSON string) (string, error) {
 	decoded, err := url.PathUnescape(policyJSON)
@@ -84,25 +55,15 @@ func decodePolicyDocument(policyJSON string) (string, error) {
 
 // statementAllowsAssumeRole reports whether the statement allows sts:AssumeRole.
 func statementAllowsAssumeRole(statement PolicyStatement) bool {
-	if statement.Effect != "Allow" {
+	if statement.Effect != effectAllow {
 		return false
 	}
 	return actionIncludesAssumeRole(statement.Action)
 }
 
 // actionIncludesAssumeRole reports whether the Action element includes sts:AssumeRole.
-func actionIncludesAssumeRole(action interface{}) bool {
-	switch v := action.(type) {
-	case string:
-		return v == assumeRoleAction
-	case []interface{}:
-		for _, el := range v {
-			if s, ok := el.(string); ok && s == assumeRoleAction {
-				return true
-			}
-		}
-	}
-	return false
+func actionIncludesAssumeRole(action iampolicy.StringList) bool {
+	return action.Contains(assumeRoleAction)
 }
 
 // uniqueSorted returns non-empty IDs in sorted order without duplicates.
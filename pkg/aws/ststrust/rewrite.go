@@ -0,0 +1,303 @@
+package ststrust
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+)
+
+// RewriteOption customizes how rewritten trust policy documents are serialized.
+type RewriteOption func(*rewriteOptions)
+
+// rewriteOptions holds serialization settings for rewritten documents.
+type rewriteOptions struct {
+	indent                  string
+	allowDroppingExternalID bool
+}
+
+// WithIndent pretty-prints rewritten documents using indent for each nesting level.
+// Documents are compact by default.
+func WithIndent(indent string) RewriteOption {
+	return func(o *rewriteOptions) {
+		o.indent = indent
+	}
+}
+
+// AllowDroppingExternalID lets RemoveSTSExternalIDFromTrustPolicy remove the last sts:ExternalId value of a
+// statement, which drops the ExternalId requirement altogether. Removals refuse to do so by default.
+func AllowDroppingExternalID() RewriteOption {
+	return func(o *rewriteOptions) {
+		o.allowDroppingExternalID = true
+	}
+}
+
+// rewritePolicyDocument applies edit to the parsed document and writes back only what edit changed.
+// Unknown elements and member order are preserved, and percent-encoded input stays percent-encoded.
+// When edit reports no change the original string is returned untouched.
+func rewritePolicyDocument(policyJSON string, edit func(doc *PolicyDocument) (bool, error), opts ...RewriteOption) (string, error) {
+	options := rewriteOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	decoded, err := decodePolicyDocument(policyJSON)
+	if err != nil {
+		return "", err
+	}
+	doc := &PolicyDocument{}
+	if err := json.Unmarshal([]byte(decoded), doc); err != nil {
+		return "", fmt.Errorf("failed to parse trust policy JSON: %w", err)
+	}
+	before := make([][]byte, len(doc.Statement))
+	for i := range doc.Statement {
+		if before[i], err = marshalJSON(doc.Statement[i].Condition); err != nil {
+			return "", fmt.Errorf("failed to marshal trust policy condition: %w", err)
+		}
+	}
+	changed, err := edit(doc)
+	if err != nil {
+		return "", err
+	}
+	if !changed {
+		return policyJSON, nil
+	}
+
+	raw := jsonObject{}
+	if err := json.Unmarshal([]byte(decoded), &raw); err != nil {
+		return "", fmt.Errorf("failed to parse trust policy JSON: %w", err)
+	}
+	statementsRaw, _ := raw.get("Statement")
+	single := isJSONObject(statementsRaw)
+	var statements []jsonObject
+	if single {
+		statement := jsonObject{}
+		if err := json.Unmarshal(statementsRaw, &statement); err != nil {
+			return "", fmt.Errorf("failed to parse trust policy statement: %w", err)
+		}
+		statements = []jsonObject{statement}
+	} else if err := json.Unmarshal(statementsRaw, &statements); err != nil {
+		return "", fmt.Errorf("failed to parse trust policy statements: %w", err)
+	}
+	if len(statements) != len(doc.Statement) {
+		return "", fmt.Errorf("trust policy rewrite cannot add or remove statements")
+	}
+	for i := range statements {
+		updated := doc.Statement[i].Condition
+		updatedRaw, err := marshalJSON(updated)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal trust policy condition: %w", err)
+		}
+		if bytes.Equal(before[i], updatedRaw) {
+			continue
+		}
+		if len(updated) == 0 {
+			statements[i].remove("Condition")
+			continue
+		}
+		if original, ok := statements[i].get("Condition"); ok {
+			updatedRaw, err = mergeJSON(original, updatedRaw)
+			if err != nil {
+				return "", err
+			}
+		}
+		statements[i].set("Condition", updatedRaw)
+	}
+	if single {
+		statementsRaw, err = marshalJSON(statements[0])
+	} else {
+		statementsRaw, err = marshalJSON(statements)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal trust policy statements: %w", err)
+	}
+	raw.set("Statement", statementsRaw)
+	if _, ok := raw.get("Version"); !ok {
+		version, _ := marshalJSON(policyVersionDefault)
+		raw = append(jsonObject{{Key: "Version", Value: version}}, raw...)
+	}
+
+	out, err := marshalJSON(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal trust policy JSON: %w", err)
+	}
+	formatted := &bytes.Buffer{}
+	if options.indent != "" {
+		err = json.Indent(formatted, out, "", options.indent)
+	} else {
+		err = json.Compact(formatted, out)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to format trust policy JSON: %w", err)
+	}
+	if decoded != policyJSON {
+		return url.PathEscape(formatted.String()), nil
+	}
+	return formatted.String(), nil
+}
+
+// marshalJSON is json.Marshal without HTML escaping, so that a condition value such as "a&b" is
+// written back as it was read instead of as "a\u0026b".
+func marshalJSON(v interface{}) ([]byte, error) {
+	out := &bytes.Buffer{}
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+// jsonMember is a single member of a jsonObject.
+type jsonMember struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// jsonObject is a JSON object that keeps member order and raw member values.
+type jsonObject []jsonMember
+
+// UnmarshalJSON reads object members in document order.
+func (o *jsonObject) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected JSON object")
+	}
+	members := jsonObject{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("expected JSON object key")
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		members = append(members, jsonMember{Key: key, Value: value})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	*o = members
+	return nil
+}
+
+// MarshalJSON writes members in their stored order.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	out := &bytes.Buffer{}
+	out.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		key, err := marshalJSON(member.Key)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(key)
+		out.WriteByte(':')
+		out.Write(member.Value)
+	}
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
+
+// get returns the raw value stored under key.
+func (o jsonObject) get(key string) (json.RawMessage, bool) {
+	for _, member := range o {
+		if member.Key == key {
+			return member.Value, true
+		}
+	}
+	return nil, false
+}
+
+// set replaces the value under key in place, or appends it when key is new.
+func (o *jsonObject) set(key string, value json.RawMessage) {
+	for i := range *o {
+		if (*o)[i].Key == key {
+			(*o)[i].Value = value
+			return
+		}
+	}
+	*o = append(*o, jsonMember{Key: key, Value: value})
+}
+
+// remove deletes key from the object.
+func (o *jsonObject) remove(key string) {
+	out := (*o)[:0]
+	for _, member := range *o {
+		if member.Key != key {
+			out = append(out, member)
+		}
+	}
+	*o = out
+}
+
+// mergeJSON returns updated, reusing original wherever the two are equivalent so that untouched members
+// keep their order and their scalar-or-list form. Keys new in updated are appended in sorted order.
+func mergeJSON(original, updated json.RawMessage) (json.RawMessage, error) {
+	if isJSONObject(original) && isJSONObject(updated) {
+		originalObject, updatedObject := jsonObject{}, jsonObject{}
+		if err := json.Unmarshal(original, &originalObject); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(updated, &updatedObject); err != nil {
+			return nil, err
+		}
+		merged := jsonObject{}
+		for _, member := range originalObject {
+			value, ok := updatedObject.get(member.Key)
+			if !ok {
+				continue
+			}
+			value, err := mergeJSON(member.Value, value)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, jsonMember{Key: member.Key, Value: value})
+		}
+		var added []jsonMember
+		for _, member := range updatedObject {
+			if _, ok := originalObject.get(member.Key); !ok {
+				added = append(added, member)
+			}
+		}
+		sort.Slice(added, func(i, j int) bool { return added[i].Key < added[j].Key })
+		return marshalJSON(append(merged, added...))
+	}
+	var originalValue, updatedValue interface{}
+	if err := json.Unmarshal(original, &originalValue); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(updated, &updatedValue); err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(unwrapSingleton(originalValue), unwrapSingleton(updatedValue)) {
+		return original, nil
+	}
+	return updated, nil
+}
+
+// unwrapSingleton treats a one-element list as its only element, since IAM does.
+func unwrapSingleton(value interface{}) interface{} {
+	if list, ok := value.([]interface{}); ok && len(list) == 1 {
+		return list[0]
+	}
+	return value
+}
+
+// isJSONObject reports whether raw holds a JSON object.
+func isJSONObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimLeft(raw, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
@@ -0,0 +1,97 @@
+package ststrust_test
+
+import (
+	"net/url"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+)
+
+const policyWithExtraElements = `{"Id":"trust-policy","Version":"2012-10-17","Statement":[` +
+	`{"Sid":"Trust","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:role/test"},"Action":["sts:AssumeRole"],` +
+	`"Condition":{"Bool":{"aws:SecureTransport":["true"]},"StringEquals":{"aws:PrincipalTag/team":["sre"],"sts:ExternalId":"` + externalIDA + `"}}},` +
+	`{"Effect":"Deny","NotPrincipal":{"AWS":"arn:aws:iam::123456789012:root"},"NotAction":"sts:TagSession","X-Custom":true}]}`
+
+// trustPolicyWithTeam returns a trust policy requiring externalIDA and the given team tag.
+func trustPolicyWithTeam(team string) string {
+	return `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},` +
+		`"Action":"sts:AssumeRole","Condition":{"StringLike":{"aws:PrincipalTag/team":"` + team + `"},` +
+		`"StringEquals":{"sts:ExternalId":"` + externalIDA + `"}}}]}`
+}
+
+var _ = Describe("Round-trip-safe trust policy rewrites", func() {
+	It("preserves unknown elements and member order", func() {
+		updated, err := ststrust.ReplaceSTSExternalIDInTrustPolicy(policyWithExtraElements, externalIDA, externalIDB)
+		Expect(err).NotTo(HaveOccurred())
+		expected := strings.Replace(policyWithExtraElements, externalIDA, externalIDB, 1)
+		Expect(updated).To(Equal(expected))
+	})
+
+	It("keeps untouched condition values in list form", func() {
+		updated, err := ststrust.RemoveSTSExternalIDFromTrustPolicy(policyWithExtraElements, externalIDA,
+			ststrust.AllowDroppingExternalID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(ContainSubstring(`"Condition":{"Bool":{"aws:SecureTransport":["true"]},"StringEquals":{"aws:PrincipalTag/team":["sre"]}}`))
+		Expect(updated).To(ContainSubstring(`"NotPrincipal"`))
+		Expect(updated).To(ContainSubstring(`"X-Custom":true`))
+	})
+
+	It("keeps percent-encoded documents percent-encoded", func() {
+		encoded := url.PathEscape(policyWithExtraElements)
+		updated, err := ststrust.ReplaceSTSExternalIDInTrustPolicy(encoded, externalIDA, externalIDB)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).NotTo(ContainSubstring("{"))
+		decoded, err := url.PathUnescape(updated)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal(strings.Replace(policyWithExtraElements, externalIDA, externalIDB, 1)))
+	})
+
+	It("keeps condition values with HTML characters as they were", func() {
+		policy := trustPolicyWithTeam("r&d <sre>")
+		updated, err := ststrust.ReplaceSTSExternalIDInTrustPolicy(policy, externalIDA, externalIDB)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(Equal(strings.Replace(policy, externalIDA, externalIDB, 1)))
+	})
+
+	It("decodes percent-encoded documents only once", func() {
+		policy := trustPolicyWithTeam("100%")
+		updated, err := ststrust.ReplaceSTSExternalIDInTrustPolicy(url.PathEscape(policy), externalIDA, externalIDB)
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := url.PathUnescape(updated)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal(strings.Replace(policy, externalIDA, externalIDB, 1)))
+	})
+
+	It("pretty-prints when requested", func() {
+		updated, err := ststrust.ApplySTSExternalIDToTrustPolicy(loadFixture("sts_installer_trust_policy.json"), externalIDA, ststrust.WithIndent("    "))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(HavePrefix("{\n    \"Version\": \"2012-10-17\",\n    \"Statement\": [\n"))
+		Expect(updated).To(ContainSubstring("\"sts:ExternalId\": \"" + externalIDA + "\""))
+	})
+
+	It("keeps a single Statement object as an object", func() {
+		policy := `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}}`
+		updated, err := ststrust.ApplySTSExternalIDToTrustPolicy(policy, externalIDA)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(Equal(`{"Version":"2012-10-17","Statement":{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},` +
+			`"Action":"sts:AssumeRole","Condition":{"StringEquals":{"sts:ExternalId":"` + externalIDA + `"}}}}`))
+	})
+
+	It("adds a default Version when the document has none", func() {
+		policy := `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole"}]}`
+		updated, err := ststrust.ApplySTSExternalIDToTrustPolicy(policy, externalIDA)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(HavePrefix(`{"Version":"2012-10-17","Statement":[`))
+	})
+
+	It("honors NotPrincipal in simulations", func() {
+		result, err := ststrust.SimulateTrustPolicy(policyWithExtraElements, ststrust.SimulationRequest{
+			PrincipalArn: "arn:aws:iam::999999999999:role/outsider",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Decision).To(Equal(ststrust.DecisionExplicitDeny))
+	})
+})
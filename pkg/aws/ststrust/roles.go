@@ -0,0 +1,72 @@
+package ststrust
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// RoleGetter fetches an IAM role by name. *aws_client.AWSClient implements it.
+type RoleGetter interface {
+	GetRole(roleName string) (*iamtypes.Role, error)
+}
+
+// ValidateExternalIDForRoles fetches the roles named by roleArns concurrently and checks entered against each
+// trust policy. Empty ARNs are skipped. The returned error joins one *ExternalIDMismatchError per role whose trust
+// policy does not allow entered, labelled with the role name, plus any lookup or parse failures, in the order of
+// roleArns.
+func ValidateExternalIDForRoles(client RoleGetter, roleArns []string, entered string) error {
+	if err := ValidateSTSExternalIDFormat(entered); err != nil {
+		return err
+	}
+	var arns []string
+	for _, roleArn := range roleArns {
+		if roleArn != "" {
+			arns = append(arns, roleArn)
+		}
+	}
+	if len(arns) == 0 {
+		return fmt.Errorf("%w: no role to validate", ErrNoTrustPolicyExternalID)
+	}
+
+	results := make([]error, len(arns))
+	var wg sync.WaitGroup
+	for i := range arns {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = validateExternalIDForRole(client, arns[i], entered)
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(results...)
+}
+
+// validateExternalIDForRole fetches the role named by roleArn and checks entered against its trust policy.
+func validateExternalIDForRole(client RoleGetter, roleArn, entered string) error {
+	roleName := roleArn[strings.LastIndex(roleArn, "/")+1:]
+	role, err := client.GetRole(roleName)
+	if err != nil {
+		return fmt.Errorf("failed to get role '%s': %w", roleArn, err)
+	}
+	if role == nil {
+		return fmt.Errorf("role '%s' not found", roleArn)
+	}
+	policyJSON := aws.ToString(role.AssumeRolePolicyDocument)
+	matches, err := ExternalIDMatchesTrustPolicy(entered, policyJSON)
+	if err != nil {
+		return fmt.Errorf("role '%s': %w", roleArn, err)
+	}
+	if matches {
+		return nil
+	}
+	var found []string
+	if doc, err := ParsePolicyDocument(policyJSON); err == nil {
+		found = externalIDConditionValues(doc)
+	}
+	return &ExternalIDMismatchError{RoleLabel: roleName, Entered: entered, FoundInRole: found}
+}
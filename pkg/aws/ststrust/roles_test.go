@@ -0,0 +1,71 @@
+package ststrust_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+)
+
+var _ ststrust.RoleGetter = &aws_client.AWSClient{}
+
+var _ = Describe("ValidateExternalIDForRoles", func() {
+	roleArns := []string{
+		"arn:aws:iam::123456789012:role/path/Installer-Role",
+		"",
+		"arn:aws:iam::123456789012:role/Support-Role",
+	}
+
+	It("passes when every role allows the ID and skips empty ARNs", func() {
+		client := aws_fake.NewRoleGetter(map[string]string{
+			"Installer-Role": policyWithExternalID(externalIDA),
+			"Support-Role":   policyWithMultipleExternalIDs([]string{externalIDA, externalIDB}),
+		})
+		Expect(ststrust.ValidateExternalIDForRoles(client, roleArns, externalIDA)).To(Succeed())
+		Expect(client.Fetched()).To(ConsistOf("Installer-Role", "Support-Role"))
+	})
+
+	It("wraps one mismatch error per failing role", func() {
+		client := aws_fake.NewRoleGetter(map[string]string{
+			"Installer-Role": policyWithExternalID(externalIDB),
+			"Support-Role":   loadFixture("sts_support_trust_policy.json"),
+		})
+		err := ststrust.ValidateExternalIDForRoles(client, roleArns, externalIDA)
+		Expect(errors.Is(err, ststrust.ErrExternalIDNotInTrustPolicy)).To(BeTrue())
+		joined, ok := err.(interface{ Unwrap() []error })
+		Expect(ok).To(BeTrue())
+		Expect(joined.Unwrap()).To(HaveLen(2))
+		var mismatch *ststrust.ExternalIDMismatchError
+		Expect(errors.As(joined.Unwrap()[0], &mismatch)).To(BeTrue())
+		Expect(mismatch.RoleLabel).To(Equal("Installer-Role"))
+		Expect(mismatch.FoundInRole).To(Equal([]string{externalIDB}))
+		Expect(errors.As(joined.Unwrap()[1], &mismatch)).To(BeTrue())
+		Expect(mismatch.RoleLabel).To(Equal("Support-Role"))
+		Expect(mismatch.FoundInRole).To(BeEmpty())
+	})
+
+	It("reports lookup failures alongside mismatches", func() {
+		client := aws_fake.NewRoleGetter(map[string]string{
+			"Installer-Role": policyWithExternalID(externalIDB),
+		})
+		err := ststrust.ValidateExternalIDForRoles(client, roleArns, externalIDA)
+		Expect(err).To(MatchError(ContainSubstring("failed to get role 'arn:aws:iam::123456789012:role/Support-Role'")))
+		Expect(errors.Is(err, ststrust.ErrExternalIDNotInTrustPolicy)).To(BeTrue())
+	})
+
+	It("validates the ID format before calling IAM", func() {
+		client := aws_fake.NewRoleGetter(nil)
+		err := ststrust.ValidateExternalIDForRoles(client, roleArns, "x")
+		Expect(errors.Is(err, ststrust.ErrExternalIDFormat)).To(BeTrue())
+		Expect(client.Fetched()).To(BeEmpty())
+	})
+
+	It("fails without any role", func() {
+		err := ststrust.ValidateExternalIDForRoles(aws_fake.NewRoleGetter(nil), []string{""}, externalIDA)
+		Expect(errors.Is(err, ststrust.ErrNoTrustPolicyExternalID)).To(BeTrue())
+	})
+})
@@ -0,0 +1,227 @@
+package ststrust
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RemoveSTSExternalIDFromTrustPolicy removes externalID from the sts:ExternalId conditions of Allow sts:AssumeRole
+// statements. Fails with ErrExternalIDNotInTrustPolicy when externalID is not listed, and with
+// ErrExternalIDLastValue when a statement would be left without any sts:ExternalId value, unless
+// AllowDroppingExternalID is passed, in which case the empty condition keys and operators are dropped.
+func RemoveSTSExternalIDFromTrustPolicy(policyJSON, externalID string, opts ...RewriteOption) (string, error) {
+	if externalID == "" {
+		return "", ErrExternalIDEmpty
+	}
+	return rewriteExternalIDValues(policyJSON, externalID, func(values ConditionValues) ConditionValues {
+		out := ConditionValues{}
+		for _, value := range values {
+			if value != externalID {
+				out = append(out, value)
+			}
+		}
+		return out
+	}, opts...)
+}
+
+// ReplaceSTSExternalIDInTrustPolicy replaces oldID with newID wherever oldID is listed in sts:ExternalId conditions
+// of Allow sts:AssumeRole statements. Fails with ErrExternalIDNotInTrustPolicy when oldID is not listed.
+func ReplaceSTSExternalIDInTrustPolicy(policyJSON, oldID, newID string, opts ...RewriteOption) (string, error) {
+	if oldID == "" {
+		return "", ErrExternalIDEmpty
+	}
+	if err := ValidateSTSExternalIDFormat(newID); err != nil {
+		return "", err
+	}
+	return rewriteExternalIDValues(policyJSON, oldID, func(values ConditionValues) ConditionValues {
+		out := ConditionValues{}
+		for _, value := range values {
+			if value == oldID {
+				value = newID
+			}
+			out = appendUnique(out, value)
+		}
+		return out
+	}, opts...)
+}
+
+// addSTSExternalIDAlongside lists newID next to every occurrence of existingID.
+func addSTSExternalIDAlongside(policyJSON, existingID, newID string, opts ...RewriteOption) (string, error) {
+	return rewriteExternalIDValues(policyJSON, existingID, func(values ConditionValues) ConditionValues {
+		return appendUnique(values, newID)
+	}, opts...)
+}
+
+// rewriteExternalIDValues applies edit to every sts:ExternalId value list that contains target.
+func rewriteExternalIDValues(policyJSON, target string, edit func(ConditionValues) ConditionValues, opts ...RewriteOption) (string, error) {
+	if policyJSON == "" {
+		return "", fmt.Errorf("trust policy document is empty")
+	}
+	options := rewriteOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return rewritePolicyDocument(policyJSON, func(doc *PolicyDocument) (bool, error) {
+		found := false
+		for i := range doc.Statement {
+			statement := &doc.Statement[i]
+			if !statementAllowsAssumeRole(*statement) {
+				continue
+			}
+			emptied := false
+			for raw, block := range statement.Condition {
+				operator, err := ParseConditionOperator(raw)
+				if err != nil || !operatorListsExternalIDs(operator) {
+					continue
+				}
+				for key, values := range block {
+					if !strings.EqualFold(key, externalIDCondition) || !containsString(values, target) {
+						continue
+					}
+					found = true
+					edited := edit(values)
+					if len(edited) == 0 {
+						emptied = true
+						delete(block, key)
+					} else {
+						block[key] = edited
+					}
+				}
+				if len(block) == 0 {
+					delete(statement.Condition, raw)
+				}
+			}
+			if emptied && !options.allowDroppingExternalID && len(collectExternalIDsFromCondition(Condition(statement.Condition))) == 0 {
+				return false, fmt.Errorf("%w: '%s' is the last external ID of statement %d", ErrExternalIDLastValue, target, i)
+			}
+			if len(statement.Condition) == 0 {
+				statement.Condition = nil
+			}
+		}
+		if !found {
+			return false, fmt.Errorf("%w: '%s'", ErrExternalIDNotInTrustPolicy, target)
+		}
+		return true, nil
+	}, opts...)
+}
+
+// TrustPolicyPair holds installer and support role trust policy documents. Empty strings mean the role is absent.
+type TrustPolicyPair struct {
+	// Installer is the installer role trust policy.
+	Installer string
+	// Support is the support role trust policy.
+	Support string
+}
+
+// ExternalIDChange describes how the sts:ExternalId set of one role changed.
+type ExternalIDChange struct {
+	// RoleLabel identifies the installer or support role.
+	RoleLabel string
+	// Before lists the IDs in the original document.
+	Before []string
+	// After lists the IDs in the rewritten document.
+	After []string
+	// Added lists IDs present only after the rewrite.
+	Added []string
+	// Removed lists IDs present only before the rewrite.
+	Removed []string
+}
+
+// ExternalIDRotation is the result of RotateSTSExternalID.
+type ExternalIDRotation struct {
+	// Staged accepts both the old and the new ID. Apply it first, then switch consumers to the new ID.
+	Staged TrustPolicyPair
+	// Final accepts only the new ID. Apply it once no consumer uses the old ID.
+	Final TrustPolicyPair
+	// Changes describes per-role differences between the original and the final documents.
+	Changes []ExternalIDChange
+}
+
+// RotateSTSExternalID prepares a two-phase rotation from oldID to newID across installer and support trust policies.
+// Empty policies are skipped; every non-empty policy must list oldID. Neither returned phase is applied to AWS.
+func RotateSTSExternalID(policies TrustPolicyPair, oldID, newID string, opts ...RewriteOption) (*ExternalIDRotation, error) {
+	if oldID == "" {
+		return nil, ErrExternalIDEmpty
+	}
+	if err := ValidateSTSExternalIDFormat(newID); err != nil {
+		return nil, err
+	}
+	if oldID == newID {
+		return nil, fmt.Errorf("%w: new external ID matches the current one", ErrExternalIDFormat)
+	}
+	if policies.Installer == "" && policies.Support == "" {
+		return nil, fmt.Errorf("%w: no installer or support trust policy provided", ErrNoTrustPolicyExternalID)
+	}
+	rotation := &ExternalIDRotation{}
+	roles := []struct {
+		label  string
+		policy string
+		staged *string
+		final  *string
+	}{
+		{roleLabelInstaller, policies.Installer, &rotation.Staged.Installer, &rotation.Final.Installer},
+		{roleLabelSupport, policies.Support, &rotation.Staged.Support, &rotation.Final.Support},
+	}
+	for _, role := range roles {
+		if role.policy == "" {
+			continue
+		}
+		before, err := CollectSTSExternalIDsFromTrustPolicy(role.policy)
+		if err != nil {
+			return nil, err
+		}
+		if !containsString(before, oldID) {
+			return nil, &ExternalIDMismatchError{RoleLabel: role.label, Entered: oldID, FoundInRole: before}
+		}
+		staged, err := addSTSExternalIDAlongside(role.policy, oldID, newID, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("stage %s: %w", role.label, err)
+		}
+		final, err := RemoveSTSExternalIDFromTrustPolicy(staged, oldID, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("finalize %s: %w", role.label, err)
+		}
+		after, err := CollectSTSExternalIDsFromTrustPolicy(final)
+		if err != nil {
+			return nil, err
+		}
+		*role.staged = staged
+		*role.final = final
+		rotation.Changes = append(rotation.Changes, diffExternalIDs(role.label, before, after))
+	}
+	return rotation, nil
+}
+
+// diffExternalIDs builds an ExternalIDChange from sorted before and after ID lists.
+func diffExternalIDs(roleLabel string, before, after []string) ExternalIDChange {
+	change := ExternalIDChange{RoleLabel: roleLabel, Before: before, After: after}
+	for _, id := range after {
+		if !containsString(before, id) {
+			change.Added = append(change.Added, id)
+		}
+	}
+	for _, id := range before {
+		if !containsString(after, id) {
+			change.Removed = append(change.Removed, id)
+		}
+	}
+	return change
+}
+
+// appendUnique appends value unless it is already present.
+func appendUnique(values ConditionValues, value string) ConditionValues {
+	if containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+// containsString reports whether values contains value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
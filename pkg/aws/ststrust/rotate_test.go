@@ -0,0 +1,130 @@
+package ststrust_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+)
+
+const externalIDC = "443B9588-36A5-ECA4-BE8D-7C673B77CEEE"
+
+var _ = Describe("External ID removal and rotation", func() {
+	Describe("RemoveSTSExternalIDFromTrustPolicy", func() {
+		It("removes one ID from a list", func() {
+			updated, err := ststrust.RemoveSTSExternalIDFromTrustPolicy(
+				policyWithMultipleExternalIDs([]string{externalIDA, externalIDB}), externalIDA)
+			Expect(err).NotTo(HaveOccurred())
+			ids, err := ststrust.CollectSTSExternalIDsFromTrustPolicy(updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]string{externalIDB}))
+		})
+
+		It("refuses to remove the last ID of a statement", func() {
+			_, err := ststrust.RemoveSTSExternalIDFromTrustPolicy(policyWithExternalID(externalIDA), externalIDA)
+			Expect(errors.Is(err, ststrust.ErrExternalIDLastValue)).To(BeTrue())
+
+			_, err = ststrust.RemoveSTSExternalIDFromTrustPolicy(policyWithTwoStatements(externalIDA, externalIDB), externalIDA)
+			Expect(errors.Is(err, ststrust.ErrExternalIDLastValue)).To(BeTrue())
+		})
+
+		It("drops the condition when the last ID is removed on request", func() {
+			updated, err := ststrust.RemoveSTSExternalIDFromTrustPolicy(policyWithExternalID(externalIDA), externalIDA,
+				ststrust.AllowDroppingExternalID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).NotTo(ContainSubstring("Condition"))
+		})
+
+		It("fails when the ID is not listed", func() {
+			_, err := ststrust.RemoveSTSExternalIDFromTrustPolicy(policyWithExternalID(externalIDB), externalIDA)
+			Expect(errors.Is(err, ststrust.ErrExternalIDNotInTrustPolicy)).To(BeTrue())
+		})
+
+		It("rejects an empty ID", func() {
+			_, err := ststrust.RemoveSTSExternalIDFromTrustPolicy(policyWithExternalID(externalIDB), "")
+			Expect(errors.Is(err, ststrust.ErrExternalIDEmpty)).To(BeTrue())
+		})
+	})
+
+	Describe("ReplaceSTSExternalIDInTrustPolicy", func() {
+		It("replaces the ID in every statement", func() {
+			updated, err := ststrust.ReplaceSTSExternalIDInTrustPolicy(policyWithTwoStatements(externalIDA, externalIDA), externalIDA, externalIDC)
+			Expect(err).NotTo(HaveOccurred())
+			ids, err := ststrust.CollectSTSExternalIDsFromTrustPolicy(updated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]string{externalIDC}))
+		})
+
+		It("does not duplicate an ID that is already listed", func() {
+			updated, err := ststrust.ReplaceSTSExternalIDInTrustPolicy(
+				policyWithMultipleExternalIDs([]string{externalIDA, externalIDB}), externalIDA, externalIDB)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(ContainSubstring(`"sts:ExternalId":"` + externalIDB + `"`))
+		})
+
+		It("validates the new ID format", func() {
+			_, err := ststrust.ReplaceSTSExternalIDInTrustPolicy(policyWithExternalID(externalIDA), externalIDA, "x")
+			Expect(errors.Is(err, ststrust.ErrExternalIDFormat)).To(BeTrue())
+		})
+	})
+
+	Describe("RotateSTSExternalID", func() {
+		It("stages both IDs and finalizes with only the new ID", func() {
+			rotation, err := ststrust.RotateSTSExternalID(ststrust.TrustPolicyPair{
+				Installer: policyWithExternalID(externalIDA),
+				Support:   policyWithMultipleExternalIDs([]string{externalIDA, externalIDB}),
+			}, externalIDA, externalIDC)
+			Expect(err).NotTo(HaveOccurred())
+
+			ids, err := ststrust.CollectSTSExternalIDsFromTrustPolicy(rotation.Staged.Installer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]string{externalIDA, externalIDC}))
+			ids, err = ststrust.CollectSTSExternalIDsFromTrustPolicy(rotation.Final.Installer)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]string{externalIDC}))
+			ids, err = ststrust.CollectSTSExternalIDsFromTrustPolicy(rotation.Final.Support)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]string{externalIDB, externalIDC}))
+
+			Expect(rotation.Changes).To(HaveLen(2))
+			Expect(rotation.Changes[0].RoleLabel).To(Equal("installer role"))
+			Expect(rotation.Changes[0].Added).To(Equal([]string{externalIDC}))
+			Expect(rotation.Changes[0].Removed).To(Equal([]string{externalIDA}))
+			Expect(rotation.Changes[1].Before).To(Equal([]string{externalIDA, externalIDB}))
+			Expect(rotation.Changes[1].After).To(Equal([]string{externalIDB, externalIDC}))
+		})
+
+		It("skips empty policies", func() {
+			rotation, err := ststrust.RotateSTSExternalID(ststrust.TrustPolicyPair{
+				Support: policyWithExternalID(externalIDA),
+			}, externalIDA, externalIDC)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotation.Final.Installer).To(BeEmpty())
+			Expect(rotation.Changes).To(HaveLen(1))
+		})
+
+		It("fails when a role does not list the old ID", func() {
+			_, err := ststrust.RotateSTSExternalID(ststrust.TrustPolicyPair{
+				Installer: policyWithExternalID(externalIDA),
+				Support:   policyWithExternalID(externalIDB),
+			}, externalIDA, externalIDC)
+			var mismatch *ststrust.ExternalIDMismatchError
+			Expect(errors.As(err, &mismatch)).To(BeTrue())
+			Expect(mismatch.RoleLabel).To(Equal("support role"))
+		})
+
+		It("fails when no policy is provided", func() {
+			_, err := ststrust.RotateSTSExternalID(ststrust.TrustPolicyPair{}, externalIDA, externalIDC)
+			Expect(errors.Is(err, ststrust.ErrNoTrustPolicyExternalID)).To(BeTrue())
+		})
+
+		It("rejects rotating to the same ID", func() {
+			_, err := ststrust.RotateSTSExternalID(ststrust.TrustPolicyPair{
+				Installer: policyWithExternalID(externalIDA),
+			}, externalIDA, externalIDA)
+			Expect(errors.Is(err, ststrust.ErrExternalIDFormat)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,238 @@
+package ststrust
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+)
+
+// AssumeRoleWithWebIdentityAction is the STS action used by OIDC federated principals.
+const AssumeRoleWithWebIdentityAction = "sts:AssumeRoleWithWebIdentity"
+
+// Request context keys populated by the simulator.
+const (
+	contextKeyPrincipalArn     = "aws:PrincipalArn"
+	contextKeyPrincipalAccount = "aws:PrincipalAccount"
+	oidcProviderResourcePrefix = "oidc-provider/"
+)
+
+// Decision is the outcome of a trust policy simulation.
+type Decision string
+
+// Simulation decisions, matching the IAM policy evaluation vocabulary.
+const (
+	// DecisionAllow means an Allow statement matched and no Deny statement did.
+	DecisionAllow Decision = "Allow"
+	// DecisionImplicitDeny means no statement matched the request.
+	DecisionImplicitDeny Decision = "ImplicitDeny"
+	// DecisionExplicitDeny means a Deny statement matched the request.
+	DecisionExplicitDeny Decision = "ExplicitDeny"
+)
+
+// SimulationRequest describes an AssumeRole or AssumeRoleWithWebIdentity call to evaluate offline.
+type SimulationRequest struct {
+	// Action defaults to sts:AssumeRoleWithWebIdentity when FederatedProvider is set, sts:AssumeRole otherwise.
+	Action string
+	// PrincipalArn is the calling IAM role, user or assumed-role session ARN.
+	PrincipalArn string
+	// Service is the calling AWS service principal, e.g. ec2.amazonaws.com.
+	Service string
+	// FederatedProvider is the OIDC provider ARN for web identity requests.
+	FederatedProvider string
+	// Subject is the token sub claim, e.g. system:serviceaccount:<namespace>:<name>.
+	Subject string
+	// Audience is the token aud claim, e.g. sts.amazonaws.com.
+	Audience string
+	// ExternalID is sent as sts:ExternalId when not empty.
+	ExternalID string
+	// Context holds any additional request context keys.
+	Context RequestContext
+}
+
+// SimulationResult reports the decision and the statement that produced it.
+type SimulationResult struct {
+	// Decision is Allow, ImplicitDeny or ExplicitDeny.
+	Decision Decision
+	// StatementIndex is the index of the deciding statement, or -1 for ImplicitDeny.
+	StatementIndex int
+	// Sid is the Sid of the deciding statement, if it has one.
+	Sid string
+}
+
+// SimulateTrustPolicy parses policyJSON and simulates request against it.
+func SimulateTrustPolicy(policyJSON string, request SimulationRequest) (SimulationResult, error) {
+	doc, err := ParsePolicyDocument(policyJSON)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	return doc.Simulate(request)
+}
+
+// Simulate evaluates request against the trust policy, honoring Principal, Action, Condition and Deny statements.
+// The first matching Deny wins; otherwise the first matching Allow is reported.
+func (doc *PolicyDocument) Simulate(request SimulationRequest) (SimulationResult, error) {
+	ctx, err := request.requestContext()
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	action := request.action()
+	result := SimulationResult{Decision: DecisionImplicitDeny, StatementIndex: -1}
+	for i, statement := range doc.Statement {
+		if !statementCoversAction(statement, action) || !statementCoversPrincipal(statement, request) {
+			continue
+		}
+		matched, err := Condition(statement.Condition).Evaluate(ctx)
+		if err != nil {
+			return SimulationResult{}, fmt.Errorf("statement %d: %w", i, err)
+		}
+		if !matched {
+			continue
+		}
+		switch statement.Effect {
+		case effectDeny:
+			return SimulationResult{Decision: DecisionExplicitDeny, StatementIndex: i, Sid: statement.Sid}, nil
+		case effectAllow:
+			if result.Decision != DecisionAllow {
+				result = SimulationResult{Decision: DecisionAllow, StatementIndex: i, Sid: statement.Sid}
+			}
+		}
+	}
+	return result, nil
+}
+
+// action returns the requested STS action, inferring it from the principal type when unset.
+func (request SimulationRequest) action() string {
+	if request.Action != "" {
+		return request.Action
+	}
+	if request.FederatedProvider != "" {
+		return AssumeRoleWithWebIdentityAction
+	}
+	return assumeRoleAction
+}
+
+// requestContext builds the request context keys IAM would populate for the request.
+func (request SimulationRequest) requestContext() (RequestContext, error) {
+	ctx := RequestContext{}
+	for key, values := range request.Context {
+		ctx[key] = values
+	}
+	if request.PrincipalArn != "" {
+		parsed, err := arn.Parse(request.PrincipalArn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid principal ARN '%s': %w", request.PrincipalArn, err)
+		}
+		ctx[contextKeyPrincipalArn] = []string{request.PrincipalArn}
+		ctx[contextKeyPrincipalAccount] = []string{parsed.AccountID}
+	}
+	if request.ExternalID != "" {
+		ctx[externalIDCondition] = []string{request.ExternalID}
+	}
+	if request.FederatedProvider != "" {
+		issuer, err := oidcProviderIssuer(request.FederatedProvider)
+		if err != nil {
+			return nil, err
+		}
+		if request.Subject != "" {
+			ctx[issuer+":sub"] = []string{request.Subject}
+		}
+		if request.Audience != "" {
+			ctx[issuer+":aud"] = []string{request.Audience}
+		}
+	}
+	return ctx, nil
+}
+
+// oidcProviderIssuer returns the issuer host and path used as the condition key prefix for an OIDC provider ARN.
+func oidcProviderIssuer(providerArn string) (string, error) {
+	parsed, err := arn.Parse(providerArn)
+	if err != nil {
+		return "", fmt.Errorf("invalid OIDC provider ARN '%s': %w", providerArn, err)
+	}
+	if !strings.HasPrefix(parsed.Resource, oidcProviderResourcePrefix) {
+		return "", fmt.Errorf("ARN '%s' is not an OIDC provider", providerArn)
+	}
+	return strings.TrimPrefix(parsed.Resource, oidcProviderResourcePrefix), nil
+}
+
+// statementCoversAction evaluates Action, or NotAction when the statement uses it.
+func statementCoversAction(statement PolicyStatement, action string) bool {
+	if statement.NotAction != nil {
+		return !actionMatches(statement.NotAction, action)
+	}
+	return actionMatches(statement.Action, action)
+}
+
+// statementCoversPrincipal evaluates Principal, or NotPrincipal when the statement uses it.
+func statementCoversPrincipal(statement PolicyStatement, request SimulationRequest) bool {
+	if statement.NotPrincipal != nil {
+		return !principalMatches(statement.NotPrincipal, request)
+	}
+	return principalMatches(statement.Principal, request)
+}
+
+// actionMatches reports whether the Action element covers requested. Actions are case-insensitive and may use wildcards.
+func actionMatches(action iampolicy.StringList, requested string) bool {
+	for _, value := range action {
+		if matchWildcard(strings.ToLower(value), strings.ToLower(requested)) {
+			return true
+		}
+	}
+	return false
+}
+
+// principalMatches reports whether the Principal element covers the caller described by request.
+func principalMatches(principal *PolicyStatementPrincipal, request SimulationRequest) bool {
+	if principal == nil {
+		return false
+	}
+	if principal.Wildcard {
+		return true
+	}
+	if request.FederatedProvider != "" && principal.Federated.Contains(request.FederatedProvider) {
+		return true
+	}
+	if request.Service != "" {
+		if principal.Service.Contains(request.Service) {
+			return true
+		}
+	}
+	if request.PrincipalArn != "" {
+		for _, value := range principal.AWS {
+			if awsPrincipalMatches(value, request.PrincipalArn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// awsPrincipalMatches compares a policy AWS principal with the caller ARN.
+// Account IDs and root ARNs match any principal of that account; role ARNs also match their assumed-role sessions.
+func awsPrincipalMatches(policyValue, callerArn string) bool {
+	if policyValue == principalWildcard || policyValue == callerArn {
+		return true
+	}
+	caller, err := arn.Parse(callerArn)
+	if err != nil {
+		return false
+	}
+	if policyValue == caller.AccountID {
+		return true
+	}
+	policy, err := arn.Parse(policyValue)
+	if err != nil || policy.AccountID != caller.AccountID || policy.Partition != caller.Partition {
+		return false
+	}
+	if policy.Service == "iam" && policy.Resource == "root" {
+		return true
+	}
+	if caller.Service == "sts" && strings.HasPrefix(caller.Resource, "assumed-role/") && strings.HasPrefix(policy.Resource, "role/") {
+		sessionRole := strings.SplitN(strings.TrimPrefix(caller.Resource, "assumed-role/"), "/", 2)[0]
+		return policy.Resource[strings.LastIndex(policy.Resource, "/")+1:] == sessionRole
+	}
+	return false
+}
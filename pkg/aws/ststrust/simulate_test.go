@@ -0,0 +1,158 @@
+package ststrust_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+)
+
+const (
+	installerRoleArn = "arn:aws:iam::123456789012:role/RH-Managed-OpenShift-Installer"
+	oidcProviderArn  = "arn:aws:iam::111111111111:oidc-provider/oidc.example.com/abc123"
+	operatorSubject  = "system:serviceaccount:openshift-ingress-operator:ingress-operator"
+)
+
+const operatorTrustPolicy = `{
+	"Version": "2012-10-17",
+	"Statement": [{
+		"Sid": "OperatorAccess",
+		"Effect": "Allow",
+		"Principal": {"Federated": "` + oidcProviderArn + `"},
+		"Action": "sts:AssumeRoleWithWebIdentity",
+		"Condition": {
+			"StringEquals": {
+				"oidc.example.com/abc123:sub": "` + operatorSubject + `",
+				"oidc.example.com/abc123:aud": "openshift"
+			}
+		}
+	}]
+}`
+
+var _ = Describe("Trust policy simulator", func() {
+	Describe("AssumeRole", func() {
+		It("allows the trusted installer role and reports the statement", func() {
+			result, err := ststrust.SimulateTrustPolicy(loadFixture("sts_installer_trust_policy.json"), ststrust.SimulationRequest{
+				PrincipalArn: installerRoleArn,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionAllow))
+			Expect(result.StatementIndex).To(Equal(0))
+		})
+
+		It("implicitly denies an untrusted principal", func() {
+			result, err := ststrust.SimulateTrustPolicy(loadFixture("sts_installer_trust_policy.json"), ststrust.SimulationRequest{
+				PrincipalArn: "arn:aws:iam::123456789012:role/someone-else",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionImplicitDeny))
+			Expect(result.StatementIndex).To(Equal(-1))
+		})
+
+		It("matches assumed-role sessions of a trusted role", func() {
+			result, err := ststrust.SimulateTrustPolicy(loadFixture("sts_installer_trust_policy.json"), ststrust.SimulationRequest{
+				PrincipalArn: "arn:aws:sts::123456789012:assumed-role/RH-Managed-OpenShift-Installer/session",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionAllow))
+		})
+
+		It("matches account root principals", func() {
+			policy := `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"sts:AssumeRole"}]}`
+			result, err := ststrust.SimulateTrustPolicy(policy, ststrust.SimulationRequest{PrincipalArn: installerRoleArn})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionAllow))
+		})
+
+		It("requires the external ID when the policy sets one", func() {
+			policy := policyWithExternalID(externalIDA)
+			request := ststrust.SimulationRequest{PrincipalArn: "arn:aws:iam::123456789012:role/test"}
+			result, err := ststrust.SimulateTrustPolicy(policy, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionImplicitDeny))
+
+			request.ExternalID = externalIDA
+			result, err = ststrust.SimulateTrustPolicy(policy, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionAllow))
+		})
+
+		It("reports explicit Deny statements with their Sid", func() {
+			policy := `{
+				"Version": "2012-10-17",
+				"Statement": [
+					{"Sid": "AllowAll", "Effect": "Allow", "Principal": "*", "Action": "sts:*"},
+					{"Sid": "BlockSupport", "Effect": "Deny", "Principal": {"AWS": "arn:aws:iam::123456789012:role/support"}, "Action": "sts:AssumeRole"}
+				]
+			}`
+			result, err := ststrust.SimulateTrustPolicy(policy, ststrust.SimulationRequest{
+				PrincipalArn: "arn:aws:iam::123456789012:role/support",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionExplicitDeny))
+			Expect(result.Sid).To(Equal("BlockSupport"))
+			Expect(result.StatementIndex).To(Equal(1))
+
+			result, err = ststrust.SimulateTrustPolicy(policy, ststrust.SimulationRequest{PrincipalArn: installerRoleArn})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionAllow))
+			Expect(result.Sid).To(Equal("AllowAll"))
+		})
+
+		It("matches service principals", func() {
+			policy := `{"Statement":[{"Effect":"Allow","Principal":{"Service":["ec2.amazonaws.com"]},"Action":"sts:AssumeRole"}]}`
+			result, err := ststrust.SimulateTrustPolicy(policy, ststrust.SimulationRequest{Service: "ec2.amazonaws.com"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionAllow))
+		})
+
+		It("rejects invalid principal ARNs", func() {
+			_, err := ststrust.SimulateTrustPolicy(policyWithExternalID(externalIDA), ststrust.SimulationRequest{PrincipalArn: "not-an-arn"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("AssumeRoleWithWebIdentity", func() {
+		It("allows the operator service account", func() {
+			result, err := ststrust.SimulateTrustPolicy(operatorTrustPolicy, ststrust.SimulationRequest{
+				FederatedProvider: oidcProviderArn,
+				Subject:           operatorSubject,
+				Audience:          "openshift",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionAllow))
+			Expect(result.Sid).To(Equal("OperatorAccess"))
+		})
+
+		It("denies a different service account", func() {
+			result, err := ststrust.SimulateTrustPolicy(operatorTrustPolicy, ststrust.SimulationRequest{
+				FederatedProvider: oidcProviderArn,
+				Subject:           "system:serviceaccount:default:builder",
+				Audience:          "openshift",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionImplicitDeny))
+		})
+
+		It("denies a different provider", func() {
+			result, err := ststrust.SimulateTrustPolicy(operatorTrustPolicy, ststrust.SimulationRequest{
+				FederatedProvider: "arn:aws:iam::111111111111:oidc-provider/other.example.com",
+				Subject:           operatorSubject,
+				Audience:          "openshift",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionImplicitDeny))
+		})
+
+		It("does not allow plain AssumeRole through a web identity statement", func() {
+			result, err := ststrust.SimulateTrustPolicy(operatorTrustPolicy, ststrust.SimulationRequest{
+				Action:            "sts:AssumeRole",
+				FederatedProvider: oidcProviderArn,
+				Subject:           operatorSubject,
+				Audience:          "openshift",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Decision).To(Equal(ststrust.DecisionImplicitDeny))
+		})
+	})
+})
@@ -0,0 +1,22 @@
+package ststrust
+
+import "github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+
+// CheckTrustPolicySize measures policyJSON against the role trust policy quota, using the IAM default of
+// iampolicy.TrustPolicySizeLimit when quota is not positive. The report lists the largest statements; the
+// error wraps iampolicy.ErrPolicySizeExceeded when the document does not fit. The document may be
+// percent-encoded as returned by IAM GetRole.
+func CheckTrustPolicySize(policyJSON string, quota int) (*iampolicy.SizeReport, error) {
+	if quota <= 0 {
+		quota = iampolicy.TrustPolicySizeLimit
+	}
+	decoded, err := decodePolicyDocument(policyJSON)
+	if err != nil {
+		return nil, err
+	}
+	report, err := iampolicy.MeasurePolicySize(decoded, quota)
+	if err != nil {
+		return nil, err
+	}
+	return report, report.Err()
+}
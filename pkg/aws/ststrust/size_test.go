@@ -0,0 +1,28 @@
+package ststrust_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+)
+
+var _ = Describe("CheckTrustPolicySize", func() {
+	It("accepts a policy within the default quota", func() {
+		report, err := ststrust.CheckTrustPolicySize(loadFixture("sts_installer_trust_policy.json"), 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Limit).To(Equal(iampolicy.TrustPolicySizeLimit))
+		Expect(report.Statements).To(HaveLen(1))
+	})
+
+	It("reports policies over a raised quota", func() {
+		policy := policyWithMultipleExternalIDs([]string{externalIDA, externalIDB, externalIDC})
+		report, err := ststrust.CheckTrustPolicySize(policy, 100)
+		Expect(errors.Is(err, iampolicy.ErrPolicySizeExceeded)).To(BeTrue())
+		Expect(report.Exceeded()).To(BeTrue())
+		Expect(report.Top(1)[0].Index).To(Equal(0))
+	})
+})
@@ -49,23 +49,26 @@ func ValidateEnteredForRoleTrustPolicies(entered, installerPolicy, supportPolicy
 	return nil
 }
 
-// validateEnteredForPolicy checks that entered appears in a single role trust policy.
+// validateEnteredForPolicy checks that entered satisfies the ExternalId conditions of a single role trust policy.
 func validateEnteredForPolicy(entered, policyJSON, roleLabel string) error {
-	ids, err := CollectSTSExternalIDsFromTrustPolicy(policyJSON)
+	doc, err := ParsePolicyDocument(policyJSON)
 	if err != nil {
 		return err
 	}
-	if len(ids) == 0 {
+	values := externalIDConditionValues(doc)
+	if len(values) == 0 {
 		return fmt.Errorf("%w for %s", ErrNoTrustPolicyExternalID, roleLabel)
 	}
-	for _, id := range ids {
-		if id == entered {
-			return nil
-		}
+	allowed, err := externalIDAllowedByPolicy(doc, entered)
+	if err != nil {
+		return err
+	}
+	if allowed {
+		return nil
 	}
 	return &ExternalIDMismatchError{
 		RoleLabel:   roleLabel,
 		Entered:     entered,
-		FoundInRole: ids,
+		FoundInRole: values,
 	}
 }
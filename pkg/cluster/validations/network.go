@@ -0,0 +1,205 @@
+package validations
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+const (
+	DefaultMachineCIDR = "10.0.0.0/16"
+	DefaultServiceCIDR = "172.30.0.0/16"
+	DefaultPodCIDR     = "10.128.0.0/14"
+	DefaultHostPrefix  = 23
+	MinHostPrefix      = 23
+	MaxHostPrefix      = 26
+)
+
+// ReservedRange is an address range the cluster networks must stay out of.
+type ReservedRange struct {
+	Name string
+	CIDR string
+}
+
+// DefaultReservedRanges are the ranges used by the hosts and OVN-Kubernetes.
+var DefaultReservedRanges = []ReservedRange{
+	{Name: "loopback", CIDR: "127.0.0.0/8"},
+	{Name: "link-local", CIDR: "169.254.0.0/16"},
+	{Name: "OVN-Kubernetes join switch", CIDR: "100.64.0.0/16"},
+	{Name: "OVN-Kubernetes transit switch", CIDR: "100.88.0.0/16"},
+	{Name: "multicast", CIDR: "224.0.0.0/4"},
+}
+
+// NetworkSubnet is a subnet of the VPC the cluster is installed in.
+type NetworkSubnet struct {
+	ID   string
+	CIDR string
+}
+
+// ClusterNetworks is the network configuration of a cluster. Empty CIDRs and a zero HostPrefix
+// take the OpenShift defaults, and nil ReservedRanges takes DefaultReservedRanges.
+type ClusterNetworks struct {
+	MachineCIDR    string
+	ServiceCIDR    string
+	PodCIDR        string
+	HostPrefix     int
+	Subnets        []NetworkSubnet
+	ReservedRanges []ReservedRange
+}
+
+// NetworkIssueKind classifies the problems ValidateClusterNetworks finds.
+type NetworkIssueKind string
+
+const (
+	NetworkInvalidCIDR              NetworkIssueKind = "invalid-cidr"
+	NetworkOverlap                  NetworkIssueKind = "overlap"
+	NetworkSubnetOutsideMachineCIDR NetworkIssueKind = "subnet-outside-machine-cidr"
+	NetworkReservedRange            NetworkIssueKind = "reserved-range"
+	NetworkHostPrefix               NetworkIssueKind = "host-prefix"
+)
+
+type NetworkIssue struct {
+	Kind    NetworkIssueKind
+	Message string
+}
+
+// NetworkReport lists the problems of a network configuration and the node capacity it allows.
+type NetworkReport struct {
+	Issues []NetworkIssue
+	// MaxNodes is the number of host-prefix blocks the pod network holds, one per node.
+	MaxNodes int
+	// PodsPerNode is the number of pod IPs in a host-prefix block.
+	PodsPerNode int
+}
+
+// Valid reports whether no issue was found.
+func (report *NetworkReport) Valid() bool {
+	return len(report.Issues) == 0
+}
+
+// Err joins the issues in one error, or returns nil when the configuration is valid.
+func (report *NetworkReport) Err() error {
+	errs := []error{}
+	for _, issue := range report.Issues {
+		errs = append(errs, errors.New(issue.Message))
+	}
+	return errors.Join(errs...)
+}
+
+func (report *NetworkReport) add(kind NetworkIssueKind, format string, args ...interface{}) {
+	report.Issues = append(report.Issues, NetworkIssue{Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+// namedNetwork is a parsed CIDR with the name used in the messages.
+type namedNetwork struct {
+	name    string
+	cidr    string
+	network *net.IPNet
+}
+
+// ValidateClusterNetworks checks the machine, service and pod networks of a cluster:
+//
+// * The three networks must be valid IPv4 CIDRs and must not overlap each other.
+// * The subnets of the VPC must lie within the machine network.
+// * No network nor subnet may overlap a reserved range.
+// * The host prefix must be within MinHostPrefix and MaxHostPrefix and leave room for nodes in the
+// pod network.
+func ValidateClusterNetworks(networks ClusterNetworks) *NetworkReport {
+	report := &NetworkReport{}
+	parse := func(name string, cidr string, defaultCIDR string) *namedNetwork {
+		if cidr == "" {
+			cidr = defaultCIDR
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil || network.IP.To4() == nil {
+			report.add(NetworkInvalidCIDR, "The %s '%s' is not a valid IPv4 CIDR", name, cidr)
+			return nil
+		}
+		if network.String() != cidr {
+			report.add(NetworkInvalidCIDR, "The %s '%s' has host bits set, expected '%s'", name, cidr, network)
+		}
+		return &namedNetwork{name: name, cidr: cidr, network: network}
+	}
+
+	machine := parse("machine CIDR", networks.MachineCIDR, DefaultMachineCIDR)
+	service := parse("service CIDR", networks.ServiceCIDR, DefaultServiceCIDR)
+	pod := parse("pod CIDR", networks.PodCIDR, DefaultPodCIDR)
+	clusterNetworks := []*namedNetwork{}
+	for _, network := range []*namedNetwork{machine, service, pod} {
+		if network != nil {
+			clusterNetworks = append(clusterNetworks, network)
+		}
+	}
+	for i, network := range clusterNetworks {
+		for _, other := range clusterNetworks[i+1:] {
+			if overlaps(network.network, other.network) {
+				report.add(NetworkOverlap, "The %s '%s' overlaps the %s '%s'", network.name, network.cidr, other.name, other.cidr)
+			}
+		}
+	}
+
+	subnets := []*namedNetwork{}
+	for _, subnet := range networks.Subnets {
+		parsed := parse("subnet "+subnet.ID+" CIDR", subnet.CIDR, "")
+		if parsed == nil {
+			continue
+		}
+		subnets = append(subnets, parsed)
+		if machine != nil && !contains(machine.network, parsed.network) {
+			report.add(NetworkSubnetOutsideMachineCIDR, "The subnet %s '%s' is outside the machine CIDR '%s'",
+				subnet.ID, subnet.CIDR, machine.cidr)
+		}
+		for _, network := range []*namedNetwork{service, pod} {
+			if network != nil && overlaps(parsed.network, network.network) {
+				report.add(NetworkOverlap, "The subnet %s '%s' overlaps the %s '%s'", subnet.ID, subnet.CIDR, network.name, network.cidr)
+			}
+		}
+	}
+
+	reservedRanges := networks.ReservedRanges
+	if reservedRanges == nil {
+		reservedRanges = DefaultReservedRanges
+	}
+	checked := append(append([]*namedNetwork{}, clusterNetworks...), subnets...)
+	for _, reserved := range reservedRanges {
+		_, reservedNetwork, err := net.ParseCIDR(reserved.CIDR)
+		if err != nil {
+			report.add(NetworkInvalidCIDR, "The reserved %s range '%s' is not a valid CIDR", reserved.Name, reserved.CIDR)
+			continue
+		}
+		for _, network := range checked {
+			if overlaps(network.network, reservedNetwork) {
+				report.add(NetworkReservedRange, "The %s '%s' overlaps the reserved %s range '%s'",
+					network.name, network.cidr, reserved.Name, reserved.CIDR)
+			}
+		}
+	}
+
+	hostPrefix := networks.HostPrefix
+	if hostPrefix == 0 {
+		hostPrefix = DefaultHostPrefix
+	}
+	if hostPrefix < MinHostPrefix || hostPrefix > MaxHostPrefix {
+		report.add(NetworkHostPrefix, "The host prefix /%d is out of the /%d-/%d range", hostPrefix, MinHostPrefix, MaxHostPrefix)
+	} else if pod != nil {
+		podPrefix, bits := pod.network.Mask.Size()
+		if hostPrefix < podPrefix {
+			report.add(NetworkHostPrefix, "The host prefix /%d is larger than the pod CIDR '%s'", hostPrefix, pod.cidr)
+		} else {
+			report.MaxNodes = 1 << uint(hostPrefix-podPrefix)
+			report.PodsPerNode = 1 << uint(bits-hostPrefix)
+		}
+	}
+	return report
+}
+
+func overlaps(n1, n2 *net.IPNet) bool {
+	return n1.Contains(n2.IP) || n2.Contains(n1.IP)
+}
+
+// contains reports whether inner lies entirely in outer.
+func contains(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && innerOnes >= outerOnes && outer.Contains(inner.IP)
+}
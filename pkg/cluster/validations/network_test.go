@@ -0,0 +1,79 @@
+package validations
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster Network Validations", func() {
+	kinds := func(report *NetworkReport) []NetworkIssueKind {
+		result := []NetworkIssueKind{}
+		for _, issue := range report.Issues {
+			result = append(result, issue.Kind)
+		}
+		return result
+	}
+
+	It("defaults (success)", func() {
+		report := ValidateClusterNetworks(ClusterNetworks{
+			Subnets: []NetworkSubnet{{ID: "subnet-a", CIDR: "10.0.0.0/24"}, {ID: "subnet-b", CIDR: "10.0.1.0/24"}},
+		})
+		Expect(report.Valid()).To(BeTrue())
+		Expect(report.Err()).ToNot(HaveOccurred())
+		Expect(report.MaxNodes).To(Equal(512))
+		Expect(report.PodsPerNode).To(Equal(512))
+	})
+	It("host prefix capacity", func() {
+		report := ValidateClusterNetworks(ClusterNetworks{PodCIDR: "10.128.0.0/16", HostPrefix: 26})
+		Expect(report.Valid()).To(BeTrue())
+		Expect(report.MaxNodes).To(Equal(1024))
+		Expect(report.PodsPerNode).To(Equal(64))
+	})
+	It("host prefix out of range or larger than the pod CIDR (failure)", func() {
+		report := ValidateClusterNetworks(ClusterNetworks{HostPrefix: 27})
+		Expect(kinds(report)).To(Equal([]NetworkIssueKind{NetworkHostPrefix}))
+		Expect(report.Err()).To(MatchError("The host prefix /27 is out of the /23-/26 range"))
+		report = ValidateClusterNetworks(ClusterNetworks{PodCIDR: "10.128.0.0/24"})
+		Expect(kinds(report)).To(Equal([]NetworkIssueKind{NetworkHostPrefix}))
+		Expect(report.MaxNodes).To(BeZero())
+	})
+	It("overlapping networks (failure)", func() {
+		report := ValidateClusterNetworks(ClusterNetworks{MachineCIDR: "10.128.0.0/16", ServiceCIDR: "10.128.0.0/24"})
+		Expect(kinds(report)).To(ConsistOf(NetworkOverlap, NetworkOverlap, NetworkOverlap))
+		Expect(report.Issues[0].Message).To(Equal("The machine CIDR '10.128.0.0/16' overlaps the service CIDR '10.128.0.0/24'"))
+	})
+	It("subnets outside the machine CIDR (failure)", func() {
+		report := ValidateClusterNetworks(ClusterNetworks{
+			MachineCIDR: "10.0.0.0/24",
+			Subnets: []NetworkSubnet{
+				{ID: "subnet-inside", CIDR: "10.0.0.0/25"},
+				{ID: "subnet-larger", CIDR: "10.0.0.0/23"},
+				{ID: "subnet-service", CIDR: "172.30.1.0/24"},
+				{ID: "subnet-invalid", CIDR: "10.0.0.0"},
+			},
+		})
+		Expect(kinds(report)).To(Equal([]NetworkIssueKind{
+			NetworkSubnetOutsideMachineCIDR,
+			NetworkSubnetOutsideMachineCIDR, NetworkOverlap,
+			NetworkInvalidCIDR,
+		}))
+		Expect(report.Issues[1].Message).To(Equal("The subnet subnet-service '172.30.1.0/24' is outside the machine CIDR '10.0.0.0/24'"))
+	})
+	It("reserved ranges (failure)", func() {
+		report := ValidateClusterNetworks(ClusterNetworks{MachineCIDR: "169.254.0.0/20", PodCIDR: "100.64.0.0/14"})
+		Expect(kinds(report)).To(Equal([]NetworkIssueKind{NetworkReservedRange, NetworkReservedRange}))
+		Expect(report.Issues[0].Message).To(Equal("The machine CIDR '169.254.0.0/20' overlaps the reserved link-local range '169.254.0.0/16'"))
+
+		report = ValidateClusterNetworks(ClusterNetworks{
+			MachineCIDR:    "169.254.0.0/20",
+			ReservedRanges: []ReservedRange{{Name: "corporate", CIDR: "10.0.0.0/8"}},
+		})
+		Expect(kinds(report)).To(Equal([]NetworkIssueKind{NetworkReservedRange}))
+		Expect(report.Issues[0].Message).To(ContainSubstring("pod CIDR"))
+	})
+	It("invalid CIDRs (failure)", func() {
+		report := ValidateClusterNetworks(ClusterNetworks{MachineCIDR: "10.0.0.1/16", ServiceCIDR: "fd00::/112"})
+		Expect(kinds(report)).To(Equal([]NetworkIssueKind{NetworkInvalidCIDR, NetworkInvalidCIDR}))
+		Expect(report.Err().Error()).To(ContainSubstring("has host bits set, expected '10.0.0.0/16'"))
+	})
+})
@@ -1,5 +1,12 @@
 package accountroles
 
+import (
+	"fmt"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
 // RequiresSTSExternalIDInTrustPolicy reports whether account role creation should embed
 // sts:ExternalId in the trust policy when an external ID is provided by the user.
 func RequiresSTSExternalIDInTrustPolicy(roleKey string) bool {
@@ -10,3 +17,23 @@ func RequiresSTSExternalIDInTrustPolicy(roleKey string) bool {
 		return false
 	}
 }
+
+// ValidateExternalIDForAccountRoles checks entered against the trust policies of the cluster account roles
+// that carry sts:ExternalId conditions, installer then support. Roles without an ARN are skipped. See
+// ststrust.ValidateExternalIDForRoles for the returned errors.
+func ValidateExternalIDForAccountRoles(client ststrust.RoleGetter, cluster *cmv1.Cluster, entered string) error {
+	arns := GetAccountRolesArnsMap(cluster)
+	var roleArns []string
+	for _, key := range []string{InstallerAccountRole, SupportAccountRole, ControlPlaneAccountRole, WorkerAccountRole} {
+		if RequiresSTSExternalIDInTrustPolicy(key) && arns[AccountRoles[key].Name] != "" {
+			roleArns = append(roleArns, arns[AccountRoles[key].Name])
+		}
+	}
+	if len(roleArns) == 0 {
+		if err := ststrust.ValidateSTSExternalIDFormat(entered); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: cluster has no installer or support role", ststrust.ErrNoTrustPolicyExternalID)
+	}
+	return ststrust.ValidateExternalIDForRoles(client, roleArns, entered)
+}
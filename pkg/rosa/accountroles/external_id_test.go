@@ -1,11 +1,25 @@
 package accountroles_test
 
 import (
+	"errors"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
 	. "github.com/openshift-online/ocm-common/pkg/rosa/accountroles"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 )
 
+const externalID = "8E4BFC6F-2B9B-4F52-9D6A-F4A1F3B2C1D0"
+
+// trustPolicyWithExternalID returns an sts:AssumeRole trust policy requiring externalID.
+func trustPolicyWithExternalID(externalID string) string {
+	return `{"Version":"2012-10-17","Statement":[{"Effect":"Allow",` +
+		`"Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"sts:AssumeRole",` +
+		`"Condition":{"StringEquals":{"sts:ExternalId":"` + externalID + `"}}}]}`
+}
+
 var _ = Describe("RequiresSTSExternalIDInTrustPolicy", func() {
 	It("returns true for installer and support", func() {
 		Expect(RequiresSTSExternalIDInTrustPolicy(InstallerAccountRole)).To(BeTrue())
@@ -21,3 +35,28 @@ var _ = Describe("RequiresSTSExternalIDInTrustPolicy", func() {
 		Expect(RequiresSTSExternalIDInTrustPolicy("unknown")).To(BeFalse())
 	})
 })
+
+var _ = Describe("ValidateExternalIDForAccountRoles", func() {
+	It("only validates the roles carrying external IDs", func() {
+		cluster, err := cmv1.NewCluster().AWS(cmv1.NewAWS().STS(cmv1.NewSTS().
+			RoleARN("arn:aws:iam::123456789012:role/path/Installer-Role").
+			SupportRoleARN("arn:aws:iam::123456789012:role/Support-Role").
+			InstanceIAMRoles(cmv1.NewInstanceIAMRoles().
+				MasterRoleARN("arn:aws:iam::123456789012:role/ControlPlane-Role").
+				WorkerRoleARN("arn:aws:iam::123456789012:role/Worker-Role")))).Build()
+		Expect(err).NotTo(HaveOccurred())
+		client := aws_fake.NewRoleGetter(map[string]string{
+			"Installer-Role": trustPolicyWithExternalID(externalID),
+			"Support-Role":   trustPolicyWithExternalID(externalID),
+		})
+		Expect(ValidateExternalIDForAccountRoles(client, cluster, externalID)).To(Succeed())
+		Expect(client.Fetched()).To(ConsistOf("Installer-Role", "Support-Role"))
+	})
+
+	It("fails when the cluster has no installer or support role", func() {
+		cluster, err := cmv1.NewCluster().AWS(cmv1.NewAWS().STS(cmv1.NewSTS())).Build()
+		Expect(err).NotTo(HaveOccurred())
+		err = ValidateExternalIDForAccountRoles(aws_fake.NewRoleGetter(nil), cluster, externalID)
+		Expect(errors.Is(err, ststrust.ErrNoTrustPolicyExternalID)).To(BeTrue())
+	})
+})
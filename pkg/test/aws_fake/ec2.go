@@ -0,0 +1,472 @@
+// Package aws_fake provides stateful in-memory fakes of the AWS service APIs used by
+// aws_client.AWSClient, so whole resource lifecycles can be exercised in unit tests without
+// scripting every call.
+package aws_fake
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+const (
+	// DefaultAccountID is the account owning the resources of a fake unless WithAccountID is given.
+	DefaultAccountID = "123456789012"
+
+	zoneLetters = "abc"
+)
+
+var compassAbbreviations = strings.NewReplacer("north", "n", "south", "s", "east", "e", "west", "w", "central", "c")
+
+// DefaultInstanceTypes are the instance types offered by a fake unless WithInstanceTypes is given.
+var DefaultInstanceTypes = []string{
+	"t3.micro", "t3.medium", "t3.large", "m5.large", "m5.xlarge", "m5.2xlarge", "m6i.xlarge", "c5.xlarge", "r5.xlarge",
+}
+
+var _ aws_client.EC2ClientAPI = &EC2{}
+
+// EC2Option configures a fake built by NewEC2.
+type EC2Option func(*EC2)
+
+// WithRegion sets the region of the fake. Its availability zones are the region name suffixed with a, b and c.
+func WithRegion(region string) EC2Option {
+	return func(f *EC2) {
+		f.region = region
+	}
+}
+
+// WithAccountID sets the account owning the resources of the fake.
+func WithAccountID(accountID string) EC2Option {
+	return func(f *EC2) {
+		f.accountID = accountID
+	}
+}
+
+// WithZones replaces the availability zones of the fake.
+func WithZones(zones ...string) EC2Option {
+	return func(f *EC2) {
+		f.zones = zones
+	}
+}
+
+// WithInstanceTypes replaces the instance types offered in every zone of the fake.
+func WithInstanceTypes(instanceTypes ...string) EC2Option {
+	return func(f *EC2) {
+		f.instanceTypes = instanceTypes
+	}
+}
+
+// WithClock sets the clock stamping the creation and launch times of resources. It is read under the
+// lock of the fake, so tests can move it between calls to make resources of different ages.
+func WithClock(clock func() time.Time) EC2Option {
+	return func(f *EC2) {
+		f.clock = clock
+	}
+}
+
+// EC2 is an in-memory implementation of aws_client.EC2ClientAPI. It keeps VPCs, subnets, route
+// tables, gateways, addresses, security groups, network interfaces, instances and the other
+// resources used by this module, assigns them realistic IDs and enforces the dependency rules of
+// EC2: a VPC with subnets cannot be deleted, an attached internet gateway cannot be deleted and so
+// on. Failures are smithy API errors carrying the codes defined in pkg/aws/errors, so callers can
+// check them with awserrors.IsErrorCode as they would against AWS.
+//
+// Every resource is created in its final state: VPCs and NAT gateways are available and instances
+// are running with passing status checks. The exception is the Amazon provided IPv6 block of a
+// VPC, which is associating until DescribeVpcs has reported it once, as AWS takes a moment to
+// associate it. Deleted NAT gateways and terminated instances stay visible, as they do on AWS for
+// a while. The fake is safe for concurrent use.
+type EC2 struct {
+	mutex         sync.Mutex
+	region        string
+	accountID     string
+	zones         []string
+	instanceTypes []string
+	clock         func() time.Time
+	counter       uint64
+	publicIPs     uint32
+	ipv6Blocks    uint32
+	privateIPs    map[string]uint32
+
+	vpcs                 map[string]*types.Vpc
+	vpcAttributes        map[string]*vpcAttributes
+	subnets              map[string]*types.Subnet
+	routeTables          map[string]*types.RouteTable
+	internetGateways     map[string]*types.InternetGateway
+	egressOnlyGateways   map[string]*types.EgressOnlyInternetGateway
+	natGateways          map[string]*types.NatGateway
+	addresses            map[string]*types.Address
+	securityGroups       map[string]*types.SecurityGroup
+	securityGroupRules   map[string]*types.SecurityGroupRule
+	networkInterfaces    map[string]*types.NetworkInterface
+	instances            map[string]*types.Instance
+	reservations         map[string]string
+	networkAcls          map[string]*types.NetworkAcl
+	vpcEndpoints         map[string]*types.VpcEndpoint
+	keyPairs             map[string]*types.KeyPairInfo
+	volumes              map[string]*types.Volume
+	images               map[string]*types.Image
+	capacityReservations map[string]*types.CapacityReservation
+	peeringConnections   map[string]*types.VpcPeeringConnection
+	transitGateways      map[string]*types.TransitGateway
+	transitAttachments   map[string]*types.TransitGatewayVpcAttachment
+	transitRouteTables   map[string]*types.TransitGatewayRouteTable
+	// transitAssociations maps an attachment to the transit gateway route table it is associated
+	// with, transitPropagations a route table to the attachments propagating to it.
+	transitAssociations map[string]string
+	transitPropagations map[string]map[string]bool
+	transitRoutes       map[string][]types.TransitGatewayRoute
+}
+
+type vpcAttributes struct {
+	enableDnsHostnames bool
+	enableDnsSupport   bool
+}
+
+// NewEC2 returns an empty fake for the default region.
+func NewEC2(opts ...EC2Option) *EC2 {
+	f := &EC2{
+		region:               CON.DefaultAWSRegion,
+		accountID:            DefaultAccountID,
+		instanceTypes:        DefaultInstanceTypes,
+		privateIPs:           map[string]uint32{},
+		vpcs:                 map[string]*types.Vpc{},
+		vpcAttributes:        map[string]*vpcAttributes{},
+		subnets:              map[string]*types.Subnet{},
+		routeTables:          map[string]*types.RouteTable{},
+		internetGateways:     map[string]*types.InternetGateway{},
+		egressOnlyGateways:   map[string]*types.EgressOnlyInternetGateway{},
+		natGateways:          map[string]*types.NatGateway{},
+		addresses:            map[string]*types.Address{},
+		securityGroups:       map[string]*types.SecurityGroup{},
+		securityGroupRules:   map[string]*types.SecurityGroupRule{},
+		networkInterfaces:    map[string]*types.NetworkInterface{},
+		instances:            map[string]*types.Instance{},
+		reservations:         map[string]string{},
+		networkAcls:          map[string]*types.NetworkAcl{},
+		vpcEndpoints:         map[string]*types.VpcEndpoint{},
+		keyPairs:             map[string]*types.KeyPairInfo{},
+		volumes:              map[string]*types.Volume{},
+		images:               map[string]*types.Image{},
+		capacityReservations: map[string]*types.CapacityReservation{},
+		peeringConnections:   map[string]*types.VpcPeeringConnection{},
+		transitGateways:      map[string]*types.TransitGateway{},
+		transitAttachments:   map[string]*types.TransitGatewayVpcAttachment{},
+		transitRouteTables:   map[string]*types.TransitGatewayRouteTable{},
+		transitAssociations:  map[string]string{},
+		transitPropagations:  map[string]map[string]bool{},
+		transitRoutes:        map[string][]types.TransitGatewayRoute{},
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if len(f.zones) == 0 {
+		for _, letter := range zoneLetters {
+			f.zones = append(f.zones, f.region+string(letter))
+		}
+	}
+	return f
+}
+
+// Region returns the region of the fake.
+func (f *EC2) Region() string {
+	return f.region
+}
+
+// Zones returns the availability zones of the fake.
+func (f *EC2) Zones() []string {
+	return append([]string{}, f.zones...)
+}
+
+// newID returns a new resource ID with the given prefix, formatted like the 17 hex digit IDs of EC2.
+func (f *EC2) newID(prefix string) string {
+	f.counter++
+	return fmt.Sprintf("%s-%017x", prefix, f.counter)
+}
+
+// arn returns the ARN of an EC2 resource of the fake.
+func (f *EC2) arn(resource string, id string) string {
+	return fmt.Sprintf("arn:aws:ec2:%s:%s:%s/%s", f.region, f.accountID, resource, id)
+}
+
+// zoneID returns the zone ID of zone, for example use2-az1 for us-east-2a.
+func (f *EC2) zoneID(zone string) string {
+	short := strings.ReplaceAll(compassAbbreviations.Replace(f.region), "-", "")
+	for i, known := range f.zones {
+		if known == zone {
+			return fmt.Sprintf("%s-az%d", short, i+1)
+		}
+	}
+	return ""
+}
+
+// validZone reports whether zone is an availability zone of the fake.
+func (f *EC2) validZone(zone string) bool {
+	for _, known := range f.zones {
+		if known == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// newPublicIP returns a public IPv4 address not handed out before.
+func (f *EC2) newPublicIP() string {
+	f.publicIPs++
+	return fmt.Sprintf("3.%d.%d.%d", f.publicIPs>>16&0xff, f.publicIPs>>8&0xff, f.publicIPs&0xff)
+}
+
+// newIPv6Block returns an Amazon provided /56 IPv6 block not handed out before.
+func (f *EC2) newIPv6Block() string {
+	f.ipv6Blocks++
+	return fmt.Sprintf("2600:1f16:%x:%x00::/56", f.ipv6Blocks>>8&0xffff, f.ipv6Blocks&0xff)
+}
+
+// newPrivateIP returns the next free address of subnet, skipping the first four addresses that
+// AWS reserves in every subnet.
+func (f *EC2) newPrivateIP(subnet *types.Subnet) string {
+	_, network, _ := net.ParseCIDR(aws.ToString(subnet.CidrBlock))
+	offset, ok := f.privateIPs[aws.ToString(subnet.SubnetId)]
+	if !ok {
+		offset = 4
+	}
+	f.privateIPs[aws.ToString(subnet.SubnetId)] = offset + 1
+	ip := binary.BigEndian.Uint32(network.IP.To4()) + offset
+	address := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(address, ip)
+	return address.String()
+}
+
+// overlaps reports whether two networks share any address.
+func overlaps(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// apiError returns an error shaped like the errors returned by the EC2 API.
+func apiError(code string, format string, args ...interface{}) error {
+	return &smithy.GenericAPIError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		Fault:   smithy.FaultClient,
+	}
+}
+
+func notFound(code string, format string) func(string) error {
+	return func(id string) error {
+		return apiError(code, format, id)
+	}
+}
+
+var (
+	vpcNotFound                 = notFound(awserrors.InvalidVpcID, "The vpc ID '%s' does not exist")
+	subnetNotFound              = notFound(awserrors.SubnetNotFound, "The subnet ID '%s' does not exist")
+	routeTableNotFound          = notFound(awserrors.InvalidRouteTableID, "The routeTable ID '%s' does not exist")
+	associationNotFound         = notFound(awserrors.InvalidAssociationID, "The association ID '%s' does not exist")
+	internetGatewayNotFound     = notFound(awserrors.InvalidInternetGatewayID, "The internetGateway ID '%s' does not exist")
+	egressOnlyGatewayNotFound   = notFound(awserrors.InvalidGatewayID, "The gateway ID '%s' does not exist")
+	natGatewayNotFound          = notFound(awserrors.InvalidNatGatewayID, "NAT gateway %s was not found")
+	allocationNotFound          = notFound(awserrors.InvalidAllocationID, "The allocation ID '%s' does not exist")
+	addressNotFound             = notFound(awserrors.InvalidAddress, "Address '%s' not found.")
+	securityGroupNotFound       = notFound(awserrors.InvalidGroup, "The security group '%s' does not exist")
+	securityGroupRuleNotFound   = notFound(awserrors.InvalidSecurityGroupRuleID, "The security group rule ID '%s' does not exist")
+	networkInterfaceNotFound    = notFound(awserrors.InvalidNetworkInterfaceID, "The networkInterface ID '%s' does not exist")
+	attachmentNotFound          = notFound(awserrors.InvalidAttachmentID, "Interface attachment '%s' does not exist")
+	instanceNotFound            = notFound(awserrors.InvalidInstanceID, "The instance ID '%s' does not exist")
+	networkAclNotFound          = notFound(awserrors.InvalidNetworkAclID, "The networkAcl ID '%s' does not exist")
+	vpcEndpointNotFound         = notFound(awserrors.InvalidVpcEndpointID, "The Vpc Endpoint Id '%s' does not exist")
+	keyPairNotFound             = notFound(awserrors.InvalidKeyPair, "The key pair '%s' does not exist")
+	volumeNotFound              = notFound(awserrors.InvalidVolume, "The volume '%s' does not exist.")
+	imageNotFound               = notFound(awserrors.InvalidAMIID, "The image id '[%s]' does not exist")
+	capacityReservationNotFound = notFound(awserrors.InvalidCapacityReservationID, "The capacity reservation ID '%s' does not exist")
+	peeringConnectionNotFound   = notFound(awserrors.InvalidVpcPeeringConnectionID, "The vpcPeeringConnection ID '%s' does not exist")
+	transitGatewayNotFound      = notFound(awserrors.InvalidTransitGatewayID, "Transit Gateway %s was deleted or does not exist.")
+	transitAttachmentNotFound   = notFound(awserrors.InvalidTransitGatewayAttachmentID, "Transit Gateway Attachment %s was deleted or does not exist.")
+	transitRouteTableNotFound   = notFound(awserrors.InvalidRouteTableID, "Transit Gateway Route Table %s was deleted or does not exist.")
+)
+
+func dependencyViolation(resource string, id string) error {
+	return apiError(awserrors.DependencyViolation, "The %s '%s' has dependencies and cannot be deleted.", resource, id)
+}
+
+func missingParameter(name string) error {
+	return apiError(awserrors.MissingParameter, "The request must contain the parameter %s", name)
+}
+
+// clone returns a deep copy of v, so callers never share state with the fake.
+func clone[T any](v T) T {
+	var copied T
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("aws_fake: cannot copy %T: %v", v, err))
+	}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		panic(fmt.Sprintf("aws_fake: cannot copy %T: %v", v, err))
+	}
+	return copied
+}
+
+// cloned returns a pointer to a deep copy of *v.
+func cloned[T any](v *T) *T {
+	copied := clone(*v)
+	return &copied
+}
+
+// sortedIDs returns the keys of m in creation order. IDs share a fixed width counter, so the
+// lexical order of IDs with the same prefix is the order they were created in.
+func sortedIDs[T any](m map[string]T) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// describe returns copies of the resources of m selected by ids and filters. Missing IDs fail
+// with notFound, unknown filter names with InvalidParameterValue.
+func describe[T any](m map[string]*T, ids []string, filters []types.Filter, notFound func(string) error,
+	attributes func(*T) map[string][]string, tags func(*T) []types.Tag) ([]T, error) {
+	selected := ids
+	if len(selected) == 0 {
+		selected = sortedIDs(m)
+	}
+	result := []T{}
+	for _, id := range selected {
+		resource, ok := m[id]
+		if !ok {
+			return nil, notFound(id)
+		}
+		match, err := matchFilters(filters, attributes(resource), tags(resource))
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			result = append(result, clone(*resource))
+		}
+	}
+	return result, nil
+}
+
+// matchFilters reports whether a resource with the given attributes and tags matches every
+// filter. Values of a filter are ORed and may use the * and ? wildcards.
+func matchFilters(filters []types.Filter, attributes map[string][]string, tags []types.Tag) (bool, error) {
+	for _, filter := range filters {
+		name := aws.ToString(filter.Name)
+		var candidates []string
+		switch {
+		case strings.HasPrefix(name, "tag:"):
+			key := strings.TrimPrefix(name, "tag:")
+			for _, tag := range tags {
+				if aws.ToString(tag.Key) == key {
+					candidates = append(candidates, aws.ToString(tag.Value))
+				}
+			}
+		case name == "tag-key":
+			for _, tag := range tags {
+				candidates = append(candidates, aws.ToString(tag.Key))
+			}
+		case name == "tag-value":
+			for _, tag := range tags {
+				candidates = append(candidates, aws.ToString(tag.Value))
+			}
+		default:
+			values, ok := attributes[name]
+			if !ok {
+				return false, apiError(awserrors.InvalidParameterValue, "The filter '%s' is invalid", name)
+			}
+			candidates = values
+		}
+		if !matchAny(filter.Values, candidates) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchAny(patterns []string, candidates []string) bool {
+	for _, pattern := range patterns {
+		expression := regexp.QuoteMeta(pattern)
+		expression = strings.ReplaceAll(expression, `\*`, ".*")
+		expression = strings.ReplaceAll(expression, `\?`, ".")
+		matcher := regexp.MustCompile("^" + expression + "$")
+		for _, candidate := range candidates {
+			if matcher.MatchString(candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tagsFor returns the tags of specs that apply to resources of resourceType.
+func tagsFor(specs []types.TagSpecification, resourceType types.ResourceType) []types.Tag {
+	var tags []types.Tag
+	for _, spec := range specs {
+		if spec.ResourceType == resourceType {
+			tags = append(tags, clone(spec.Tags)...)
+		}
+	}
+	return tags
+}
+
+func boolValue(value bool) []string {
+	return []string{fmt.Sprintf("%t", value)}
+}
+
+func optional(values ...*string) []string {
+	result := []string{}
+	for _, value := range values {
+		if value != nil {
+			result = append(result, *value)
+		}
+	}
+	return result
+}
+
+// containsID tells whether ids holds id.
+func containsID(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutIDs returns ids without the removed ones.
+func withoutIDs(ids []string, removed ...string) []string {
+	result := []string{}
+	for _, id := range ids {
+		if !containsID(removed, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// now returns the current time of the fake clock.
+func (f *EC2) now() *time.Time {
+	if f.clock != nil {
+		return aws.Time(f.clock().UTC())
+	}
+	return aws.Time(time.Now().UTC())
+}
+
+// dashed replaces the dots of an IP address with dashes, as in EC2 DNS names.
+func dashed(ip string) string {
+	return strings.ReplaceAll(ip, ".", "-")
+}
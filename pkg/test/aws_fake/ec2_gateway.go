@@ -0,0 +1,912 @@
+package aws_fake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+func routeTableFilterAttributes(routeTable *types.RouteTable) map[string][]string {
+	attributes := map[string][]string{
+		"route-table-id":                         {aws.ToString(routeTable.RouteTableId)},
+		"vpc-id":                                 {aws.ToString(routeTable.VpcId)},
+		"owner-id":                               {aws.ToString(routeTable.OwnerId)},
+		"association.route-table-association-id": {},
+		"association.route-table-id":             {},
+		"association.subnet-id":                  {},
+		"association.gateway-id":                 {},
+		"association.main":                       {},
+		"route.destination-cidr-block":           {},
+		"route.destination-ipv6-cidr-block":      {},
+		"route.gateway-id":                       {},
+		"route.nat-gateway-id":                   {},
+		"route.instance-id":                      {},
+		"route.transit-gateway-id":               {},
+		"route.vpc-peering-connection-id":        {},
+		"route.egress-only-internet-gateway-id":  {},
+		"route.state":                            {},
+		"route.origin":                           {},
+		"route.destination-prefix-list-id":       {},
+	}
+	add := func(name string, value *string) {
+		if value != nil {
+			attributes[name] = append(attributes[name], *value)
+		}
+	}
+	for _, association := range routeTable.Associations {
+		add("association.route-table-association-id", association.RouteTableAssociationId)
+		add("association.route-table-id", association.RouteTableId)
+		add("association.subnet-id", association.SubnetId)
+		add("association.gateway-id", association.GatewayId)
+		attributes["association.main"] = append(attributes["association.main"], boolValue(aws.ToBool(association.Main))...)
+	}
+	for _, route := range routeTable.Routes {
+		add("route.destination-cidr-block", route.DestinationCidrBlock)
+		add("route.destination-ipv6-cidr-block", route.DestinationIpv6CidrBlock)
+		add("route.destination-prefix-list-id", route.DestinationPrefixListId)
+		add("route.gateway-id", route.GatewayId)
+		add("route.nat-gateway-id", route.NatGatewayId)
+		add("route.instance-id", route.InstanceId)
+		add("route.transit-gateway-id", route.TransitGatewayId)
+		add("route.vpc-peering-connection-id", route.VpcPeeringConnectionId)
+		add("route.egress-only-internet-gateway-id", route.EgressOnlyInternetGatewayId)
+		attributes["route.state"] = append(attributes["route.state"], string(route.State))
+		attributes["route.origin"] = append(attributes["route.origin"], string(route.Origin))
+	}
+	return attributes
+}
+
+func routeTableTags(routeTable *types.RouteTable) []types.Tag {
+	return routeTable.Tags
+}
+
+func isMainRouteTable(routeTable *types.RouteTable) bool {
+	for _, association := range routeTable.Associations {
+		if aws.ToBool(association.Main) {
+			return true
+		}
+	}
+	return false
+}
+
+// createRouteTable creates a route table with the local routes of vpc, associated as the main
+// route table of the VPC when main is set.
+func (f *EC2) createRouteTable(vpc *types.Vpc, main bool, tags []types.Tag) *types.RouteTable {
+	routeTableID := f.newID("rtb")
+	routeTable := &types.RouteTable{
+		RouteTableId: aws.String(routeTableID),
+		VpcId:        vpc.VpcId,
+		OwnerId:      aws.String(f.accountID),
+		Associations: []types.RouteTableAssociation{},
+		Tags:         tags,
+	}
+	for _, association := range vpc.CidrBlockAssociationSet {
+		routeTable.Routes = append(routeTable.Routes, types.Route{
+			DestinationCidrBlock: association.CidrBlock,
+			GatewayId:            aws.String("local"),
+			Origin:               types.RouteOriginCreateRouteTable,
+			State:                types.RouteStateActive,
+		})
+	}
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		routeTable.Routes = append(routeTable.Routes, types.Route{
+			DestinationIpv6CidrBlock: association.Ipv6CidrBlock,
+			GatewayId:                aws.String("local"),
+			Origin:                   types.RouteOriginCreateRouteTable,
+			State:                    types.RouteStateActive,
+		})
+	}
+	if main {
+		routeTable.Associations = append(routeTable.Associations, types.RouteTableAssociation{
+			Main:                    aws.Bool(true),
+			RouteTableAssociationId: aws.String(f.newID("rtbassoc")),
+			RouteTableId:            aws.String(routeTableID),
+			AssociationState:        &types.RouteTableAssociationState{State: types.RouteTableAssociationStateCodeAssociated},
+		})
+	}
+	f.routeTables[routeTableID] = routeTable
+	return routeTable
+}
+
+// CreateRouteTable creates a route table holding the local route of the VPC.
+func (f *EC2) CreateRouteTable(ctx context.Context, params *ec2.CreateRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.CreateRouteTableOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.VpcId == nil {
+		return nil, missingParameter("vpcId")
+	}
+	vpc, ok := f.vpcs[*params.VpcId]
+	if !ok {
+		return nil, vpcNotFound(*params.VpcId)
+	}
+	routeTable := f.createRouteTable(vpc, false, tagsFor(params.TagSpecifications, types.ResourceTypeRouteTable))
+	return &ec2.CreateRouteTableOutput{RouteTable: cloned(routeTable)}, nil
+}
+
+// DescribeRouteTables returns the route tables selected by IDs and filters.
+func (f *EC2) DescribeRouteTables(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeRouteTablesInput{}
+	}
+	routeTables, err := describe(f.routeTables, params.RouteTableIds, params.Filters, routeTableNotFound,
+		routeTableFilterAttributes, routeTableTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeRouteTablesOutput{RouteTables: routeTables}, nil
+}
+
+// DeleteRouteTable deletes a route table without associations.
+func (f *EC2) DeleteRouteTable(ctx context.Context, params *ec2.DeleteRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.DeleteRouteTableOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.RouteTableId)
+	routeTable, ok := f.routeTables[routeTableID]
+	if !ok {
+		return nil, routeTableNotFound(routeTableID)
+	}
+	if len(routeTable.Associations) != 0 {
+		return nil, dependencyViolation("routeTable", routeTableID)
+	}
+	delete(f.routeTables, routeTableID)
+	return &ec2.DeleteRouteTableOutput{}, nil
+}
+
+// AssociateRouteTable explicitly associates a route table with a subnet or a gateway of its VPC.
+func (f *EC2) AssociateRouteTable(ctx context.Context, params *ec2.AssociateRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.AssociateRouteTableOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.RouteTableId)
+	routeTable, ok := f.routeTables[routeTableID]
+	if !ok {
+		return nil, routeTableNotFound(routeTableID)
+	}
+	association := types.RouteTableAssociation{
+		Main:             aws.Bool(false),
+		RouteTableId:     aws.String(routeTableID),
+		AssociationState: &types.RouteTableAssociationState{State: types.RouteTableAssociationStateCodeAssociated},
+	}
+	switch {
+	case params.SubnetId != nil:
+		subnet, ok := f.subnets[*params.SubnetId]
+		if !ok {
+			return nil, subnetNotFound(*params.SubnetId)
+		}
+		if aws.ToString(subnet.VpcId) != aws.ToString(routeTable.VpcId) {
+			return nil, apiError(awserrors.InvalidParameterValue, "Route table %s and subnet %s belong to different networks",
+				routeTableID, *params.SubnetId)
+		}
+		association.SubnetId = params.SubnetId
+	case params.GatewayId != nil:
+		gateway, ok := f.internetGateways[*params.GatewayId]
+		if !ok {
+			return nil, internetGatewayNotFound(*params.GatewayId)
+		}
+		if !attachedTo(gateway, aws.ToString(routeTable.VpcId)) {
+			return nil, apiError(awserrors.InvalidParameterValue, "Route table %s and network gateway %s belong to different networks",
+				routeTableID, *params.GatewayId)
+		}
+		association.GatewayId = params.GatewayId
+	default:
+		return nil, missingParameter("subnetId")
+	}
+	for _, existing := range f.routeTables {
+		for _, existingAssociation := range existing.Associations {
+			if (association.SubnetId != nil && aws.ToString(existingAssociation.SubnetId) == *association.SubnetId) ||
+				(association.GatewayId != nil && aws.ToString(existingAssociation.GatewayId) == *association.GatewayId) {
+				return nil, apiError(awserrors.ResourceAlreadyAssociated,
+					"the specified association for route table %s conflicts with an existing association", routeTableID)
+			}
+		}
+	}
+	association.RouteTableAssociationId = aws.String(f.newID("rtbassoc"))
+	routeTable.Associations = append(routeTable.Associations, association)
+	return &ec2.AssociateRouteTableOutput{
+		AssociationId:    association.RouteTableAssociationId,
+		AssociationState: clone(association.AssociationState),
+	}, nil
+}
+
+// DisassociateRouteTable removes an explicit route table association.
+func (f *EC2) DisassociateRouteTable(ctx context.Context, params *ec2.DisassociateRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.DisassociateRouteTableOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	associationID := aws.ToString(params.AssociationId)
+	for _, routeTable := range f.routeTables {
+		for i, association := range routeTable.Associations {
+			if aws.ToString(association.RouteTableAssociationId) != associationID {
+				continue
+			}
+			if aws.ToBool(association.Main) {
+				return nil, apiError(awserrors.InvalidParameterValue, "cannot disassociate the main route table association %s", associationID)
+			}
+			routeTable.Associations = append(routeTable.Associations[:i], routeTable.Associations[i+1:]...)
+			return &ec2.DisassociateRouteTableOutput{}, nil
+		}
+	}
+	return nil, associationNotFound(associationID)
+}
+
+// CreateRoute adds a route to a route table. Internet gateways and egress-only internet gateways
+// must be attached to the VPC of the route table and NAT gateways must belong to it. Egress-only
+// internet gateways only take IPv6 destinations.
+func (f *EC2) CreateRoute(ctx context.Context, params *ec2.CreateRouteInput, optFns ...func(*ec2.Options)) (*ec2.CreateRouteOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.RouteTableId)
+	routeTable, ok := f.routeTables[routeTableID]
+	if !ok {
+		return nil, routeTableNotFound(routeTableID)
+	}
+	route := types.Route{
+		DestinationCidrBlock:     params.DestinationCidrBlock,
+		DestinationIpv6CidrBlock: params.DestinationIpv6CidrBlock,
+		DestinationPrefixListId:  params.DestinationPrefixListId,
+		Origin:                   types.RouteOriginCreateRoute,
+		State:                    types.RouteStateActive,
+	}
+	destination := routeDestination(route)
+	if destination == "" {
+		return nil, missingParameter("destinationCidrBlock")
+	}
+	for _, existing := range routeTable.Routes {
+		if routeDestination(existing) == destination {
+			return nil, apiError(awserrors.RouteAlreadyExists, "The route identified by %s already exists.", destination)
+		}
+	}
+	vpcID := aws.ToString(routeTable.VpcId)
+	differentNetworks := func(target string) error {
+		return apiError(awserrors.InvalidParameterValue, "route table %s and network gateway %s belong to different networks",
+			routeTableID, target)
+	}
+	switch {
+	case params.GatewayId != nil:
+		if strings.HasPrefix(*params.GatewayId, "igw-") {
+			gateway, ok := f.internetGateways[*params.GatewayId]
+			if !ok {
+				return nil, internetGatewayNotFound(*params.GatewayId)
+			}
+			if !attachedTo(gateway, vpcID) {
+				return nil, differentNetworks(*params.GatewayId)
+			}
+		}
+		route.GatewayId = params.GatewayId
+	case params.NatGatewayId != nil:
+		gateway, ok := f.natGateways[*params.NatGatewayId]
+		if !ok || gateway.State == types.NatGatewayStateDeleted {
+			return nil, natGatewayNotFound(*params.NatGatewayId)
+		}
+		if aws.ToString(gateway.VpcId) != vpcID {
+			return nil, differentNetworks(*params.NatGatewayId)
+		}
+		route.NatGatewayId = params.NatGatewayId
+	case params.NetworkInterfaceId != nil:
+		networkInterface, ok := f.networkInterfaces[*params.NetworkInterfaceId]
+		if !ok {
+			return nil, networkInterfaceNotFound(*params.NetworkInterfaceId)
+		}
+		route.NetworkInterfaceId = params.NetworkInterfaceId
+		if networkInterface.Attachment != nil {
+			route.InstanceId = networkInterface.Attachment.InstanceId
+		}
+	case params.InstanceId != nil:
+		instance, ok := f.instances[*params.InstanceId]
+		if !ok {
+			return nil, instanceNotFound(*params.InstanceId)
+		}
+		route.InstanceId = params.InstanceId
+		route.InstanceOwnerId = aws.String(f.accountID)
+		if primary := f.primaryNetworkInterface(aws.ToString(instance.InstanceId)); primary != nil {
+			route.NetworkInterfaceId = primary.NetworkInterfaceId
+		}
+	case params.TransitGatewayId != nil:
+		gateway, ok := f.transitGateways[*params.TransitGatewayId]
+		if !ok || gateway.State == types.TransitGatewayStateDeleted {
+			return nil, transitGatewayNotFound(*params.TransitGatewayId)
+		}
+		if !f.transitGatewayAttached(*params.TransitGatewayId, vpcID) {
+			return nil, differentNetworks(*params.TransitGatewayId)
+		}
+		route.TransitGatewayId = params.TransitGatewayId
+	case params.VpcPeeringConnectionId != nil:
+		connection, ok := f.peeringConnections[*params.VpcPeeringConnectionId]
+		if !ok || connection.Status.Code == types.VpcPeeringConnectionStateReasonCodeDeleted {
+			return nil, peeringConnectionNotFound(*params.VpcPeeringConnectionId)
+		}
+		if aws.ToString(connection.RequesterVpcInfo.VpcId) != vpcID && aws.ToString(connection.AccepterVpcInfo.VpcId) != vpcID {
+			return nil, differentNetworks(*params.VpcPeeringConnectionId)
+		}
+		if connection.Status.Code != types.VpcPeeringConnectionStateReasonCodeActive {
+			return nil, apiError(awserrors.InvalidParameterValue, "The vpcPeeringConnection %s is not active",
+				*params.VpcPeeringConnectionId)
+		}
+		route.VpcPeeringConnectionId = params.VpcPeeringConnectionId
+	case params.EgressOnlyInternetGatewayId != nil:
+		gateway, ok := f.egressOnlyGateways[*params.EgressOnlyInternetGatewayId]
+		if !ok {
+			return nil, egressOnlyGatewayNotFound(*params.EgressOnlyInternetGatewayId)
+		}
+		if !egressOnlyAttachedTo(gateway, vpcID) {
+			return nil, differentNetworks(*params.EgressOnlyInternetGatewayId)
+		}
+		if params.DestinationIpv6CidrBlock == nil {
+			return nil, apiError(awserrors.InvalidParameterValue,
+				"Egress only internet gateways can only route IPv6 traffic, got destination %s", destination)
+		}
+		route.EgressOnlyInternetGatewayId = params.EgressOnlyInternetGatewayId
+	case params.CarrierGatewayId != nil:
+		route.CarrierGatewayId = params.CarrierGatewayId
+	case params.LocalGatewayId != nil:
+		route.LocalGatewayId = params.LocalGatewayId
+	case params.CoreNetworkArn != nil:
+		route.CoreNetworkArn = params.CoreNetworkArn
+	case params.VpcEndpointId != nil:
+		route.GatewayId = params.VpcEndpointId
+	default:
+		return nil, missingParameter("gatewayId")
+	}
+	routeTable.Routes = append(routeTable.Routes, route)
+	return &ec2.CreateRouteOutput{Return: aws.Bool(true)}, nil
+}
+
+// DeleteRoute removes a route from a route table. The local route of the VPC can't be removed.
+func (f *EC2) DeleteRoute(ctx context.Context, params *ec2.DeleteRouteInput, optFns ...func(*ec2.Options)) (*ec2.DeleteRouteOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.RouteTableId)
+	routeTable, ok := f.routeTables[routeTableID]
+	if !ok {
+		return nil, routeTableNotFound(routeTableID)
+	}
+	destination := routeDestination(types.Route{
+		DestinationCidrBlock:     params.DestinationCidrBlock,
+		DestinationIpv6CidrBlock: params.DestinationIpv6CidrBlock,
+		DestinationPrefixListId:  params.DestinationPrefixListId,
+	})
+	if destination == "" {
+		return nil, missingParameter("destinationCidrBlock")
+	}
+	for i, route := range routeTable.Routes {
+		if routeDestination(route) != destination {
+			continue
+		}
+		if route.Origin == types.RouteOriginCreateRouteTable {
+			return nil, apiError(awserrors.InvalidParameterValue, "cannot remove local route %s in route table %s",
+				destination, routeTableID)
+		}
+		routeTable.Routes = append(routeTable.Routes[:i], routeTable.Routes[i+1:]...)
+		return &ec2.DeleteRouteOutput{}, nil
+	}
+	return nil, apiError(awserrors.InvalidRouteNotFound, "no route with destination-cidr-block %s in route table %s",
+		destination, routeTableID)
+}
+
+func routeDestination(route types.Route) string {
+	switch {
+	case route.DestinationCidrBlock != nil:
+		return *route.DestinationCidrBlock
+	case route.DestinationIpv6CidrBlock != nil:
+		return *route.DestinationIpv6CidrBlock
+	default:
+		return aws.ToString(route.DestinationPrefixListId)
+	}
+}
+
+func internetGatewayFilterAttributes(gateway *types.InternetGateway) map[string][]string {
+	vpcIDs := []string{}
+	states := []string{}
+	for _, attachment := range gateway.Attachments {
+		vpcIDs = append(vpcIDs, aws.ToString(attachment.VpcId))
+		states = append(states, string(attachment.State))
+	}
+	return map[string][]string{
+		"internet-gateway-id": {aws.ToString(gateway.InternetGatewayId)},
+		"owner-id":            {aws.ToString(gateway.OwnerId)},
+		"attachment.vpc-id":   vpcIDs,
+		"attachment.state":    states,
+	}
+}
+
+func internetGatewayTags(gateway *types.InternetGateway) []types.Tag {
+	return gateway.Tags
+}
+
+func attachedTo(gateway *types.InternetGateway, vpcID string) bool {
+	for _, attachment := range gateway.Attachments {
+		if aws.ToString(attachment.VpcId) == vpcID {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateInternetGateway creates a detached internet gateway.
+func (f *EC2) CreateInternetGateway(ctx context.Context, params *ec2.CreateInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.CreateInternetGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.CreateInternetGatewayInput{}
+	}
+	gatewayID := f.newID("igw")
+	gateway := &types.InternetGateway{
+		InternetGatewayId: aws.String(gatewayID),
+		OwnerId:           aws.String(f.accountID),
+		Attachments:       []types.InternetGatewayAttachment{},
+		Tags:              tagsFor(params.TagSpecifications, types.ResourceTypeInternetGateway),
+	}
+	f.internetGateways[gatewayID] = gateway
+	return &ec2.CreateInternetGatewayOutput{InternetGateway: cloned(gateway)}, nil
+}
+
+// DescribeInternetGateways returns the internet gateways selected by IDs and filters.
+func (f *EC2) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeInternetGatewaysInput{}
+	}
+	gateways, err := describe(f.internetGateways, params.InternetGatewayIds, params.Filters, internetGatewayNotFound,
+		internetGatewayFilterAttributes, internetGatewayTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeInternetGatewaysOutput{InternetGateways: gateways}, nil
+}
+
+// AttachInternetGateway attaches an internet gateway to a VPC without one.
+func (f *EC2) AttachInternetGateway(ctx context.Context, params *ec2.AttachInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.AttachInternetGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	gatewayID := aws.ToString(params.InternetGatewayId)
+	gateway, ok := f.internetGateways[gatewayID]
+	if !ok {
+		return nil, internetGatewayNotFound(gatewayID)
+	}
+	vpcID := aws.ToString(params.VpcId)
+	if _, ok := f.vpcs[vpcID]; !ok {
+		return nil, vpcNotFound(vpcID)
+	}
+	if len(gateway.Attachments) != 0 {
+		return nil, apiError(awserrors.ResourceAlreadyAssociated, "resource %s is already attached to network %s",
+			gatewayID, aws.ToString(gateway.Attachments[0].VpcId))
+	}
+	for _, other := range f.internetGateways {
+		if attachedTo(other, vpcID) {
+			return nil, apiError(awserrors.ResourceAlreadyAssociated, "Network %s already has an internet gateway attached", vpcID)
+		}
+	}
+	gateway.Attachments = append(gateway.Attachments, types.InternetGatewayAttachment{
+		VpcId: aws.String(vpcID),
+		State: types.AttachmentStatus("available"),
+	})
+	return &ec2.AttachInternetGatewayOutput{}, nil
+}
+
+// DetachInternetGateway detaches an internet gateway from a VPC with no public addresses mapped.
+func (f *EC2) DetachInternetGateway(ctx context.Context, params *ec2.DetachInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.DetachInternetGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	gatewayID := aws.ToString(params.InternetGatewayId)
+	gateway, ok := f.internetGateways[gatewayID]
+	if !ok {
+		return nil, internetGatewayNotFound(gatewayID)
+	}
+	vpcID := aws.ToString(params.VpcId)
+	if !attachedTo(gateway, vpcID) {
+		return nil, apiError(awserrors.GatewayNotAttached, "resource %s is not attached to network %s", gatewayID, vpcID)
+	}
+	for _, address := range f.addresses {
+		if networkInterface, ok := f.networkInterfaces[aws.ToString(address.NetworkInterfaceId)]; ok &&
+			aws.ToString(networkInterface.VpcId) == vpcID {
+			return nil, apiError(awserrors.DependencyViolation,
+				"Network %s has some mapped public address(es). Please unmap those public address(es) before detaching the gateway.", vpcID)
+		}
+	}
+	gateway.Attachments = []types.InternetGatewayAttachment{}
+	return &ec2.DetachInternetGatewayOutput{}, nil
+}
+
+// DeleteInternetGateway deletes a detached internet gateway.
+func (f *EC2) DeleteInternetGateway(ctx context.Context, params *ec2.DeleteInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.DeleteInternetGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	gatewayID := aws.ToString(params.InternetGatewayId)
+	gateway, ok := f.internetGateways[gatewayID]
+	if !ok {
+		return nil, internetGatewayNotFound(gatewayID)
+	}
+	if len(gateway.Attachments) != 0 {
+		return nil, dependencyViolation("internetGateway", gatewayID)
+	}
+	delete(f.internetGateways, gatewayID)
+	return &ec2.DeleteInternetGatewayOutput{}, nil
+}
+
+func egressOnlyGatewayFilterAttributes(gateway *types.EgressOnlyInternetGateway) map[string][]string {
+	vpcIDs := []string{}
+	for _, attachment := range gateway.Attachments {
+		vpcIDs = append(vpcIDs, aws.ToString(attachment.VpcId))
+	}
+	return map[string][]string{
+		"egress-only-internet-gateway-id": {aws.ToString(gateway.EgressOnlyInternetGatewayId)},
+		"attachment.vpc-id":               vpcIDs,
+	}
+}
+
+func egressOnlyGatewayTags(gateway *types.EgressOnlyInternetGateway) []types.Tag {
+	return gateway.Tags
+}
+
+func egressOnlyAttachedTo(gateway *types.EgressOnlyInternetGateway, vpcID string) bool {
+	for _, attachment := range gateway.Attachments {
+		if aws.ToString(attachment.VpcId) == vpcID {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateEgressOnlyInternetGateway creates an egress-only internet gateway attached to a VPC.
+func (f *EC2) CreateEgressOnlyInternetGateway(ctx context.Context, params *ec2.CreateEgressOnlyInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.CreateEgressOnlyInternetGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.VpcId == nil {
+		return nil, missingParameter("vpcId")
+	}
+	if _, ok := f.vpcs[*params.VpcId]; !ok {
+		return nil, vpcNotFound(*params.VpcId)
+	}
+	gatewayID := f.newID("eigw")
+	gateway := &types.EgressOnlyInternetGateway{
+		EgressOnlyInternetGatewayId: aws.String(gatewayID),
+		Attachments: []types.InternetGatewayAttachment{{
+			VpcId: params.VpcId,
+			State: types.AttachmentStatusAttached,
+		}},
+		Tags: tagsFor(params.TagSpecifications, types.ResourceTypeEgressOnlyInternetGateway),
+	}
+	f.egressOnlyGateways[gatewayID] = gateway
+	return &ec2.CreateEgressOnlyInternetGatewayOutput{EgressOnlyInternetGateway: cloned(gateway)}, nil
+}
+
+// DescribeEgressOnlyInternetGateways returns the egress-only internet gateways selected by IDs and filters.
+func (f *EC2) DescribeEgressOnlyInternetGateways(ctx context.Context, params *ec2.DescribeEgressOnlyInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeEgressOnlyInternetGatewaysOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeEgressOnlyInternetGatewaysInput{}
+	}
+	gateways, err := describe(f.egressOnlyGateways, params.EgressOnlyInternetGatewayIds, params.Filters,
+		egressOnlyGatewayNotFound, egressOnlyGatewayFilterAttributes, egressOnlyGatewayTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeEgressOnlyInternetGatewaysOutput{EgressOnlyInternetGateways: gateways}, nil
+}
+
+// DeleteEgressOnlyInternetGateway deletes an egress-only internet gateway. Like AWS, the routes
+// targeting it are left behind.
+func (f *EC2) DeleteEgressOnlyInternetGateway(ctx context.Context, params *ec2.DeleteEgressOnlyInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.DeleteEgressOnlyInternetGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	gatewayID := aws.ToString(params.EgressOnlyInternetGatewayId)
+	if _, ok := f.egressOnlyGateways[gatewayID]; !ok {
+		return nil, egressOnlyGatewayNotFound(gatewayID)
+	}
+	delete(f.egressOnlyGateways, gatewayID)
+	return &ec2.DeleteEgressOnlyInternetGatewayOutput{ReturnCode: aws.Bool(true)}, nil
+}
+
+func addressFilterAttributes(address *types.Address) map[string][]string {
+	return map[string][]string{
+		"allocation-id":              {aws.ToString(address.AllocationId)},
+		"association-id":             optional(address.AssociationId),
+		"domain":                     {string(address.Domain)},
+		"instance-id":                optional(address.InstanceId),
+		"network-border-group":       optional(address.NetworkBorderGroup),
+		"network-interface-id":       optional(address.NetworkInterfaceId),
+		"network-interface-owner-id": optional(address.NetworkInterfaceOwnerId),
+		"private-ip-address":         optional(address.PrivateIpAddress),
+		"public-ip":                  {aws.ToString(address.PublicIp)},
+	}
+}
+
+func addressTags(address *types.Address) []types.Tag {
+	return address.Tags
+}
+
+// findAddress returns the address with the given allocation ID or, when it is empty, public IP.
+func (f *EC2) findAddress(allocationID *string, publicIP *string) (*types.Address, error) {
+	if allocationID != nil {
+		address, ok := f.addresses[*allocationID]
+		if !ok {
+			return nil, allocationNotFound(*allocationID)
+		}
+		return address, nil
+	}
+	if publicIP == nil {
+		return nil, missingParameter("allocationId")
+	}
+	for _, address := range f.addresses {
+		if aws.ToString(address.PublicIp) == *publicIP {
+			return address, nil
+		}
+	}
+	return nil, addressNotFound(*publicIP)
+}
+
+// associateAddress maps address to the primary private IP of networkInterface.
+func (f *EC2) associateAddress(address *types.Address, networkInterface *types.NetworkInterface) {
+	address.AssociationId = aws.String(f.newID("eipassoc"))
+	address.NetworkInterfaceId = networkInterface.NetworkInterfaceId
+	address.NetworkInterfaceOwnerId = aws.String(f.accountID)
+	address.PrivateIpAddress = networkInterface.PrivateIpAddress
+	if networkInterface.Attachment != nil {
+		address.InstanceId = networkInterface.Attachment.InstanceId
+	}
+	networkInterface.Association = &types.NetworkInterfaceAssociation{
+		AllocationId:  address.AllocationId,
+		AssociationId: address.AssociationId,
+		IpOwnerId:     aws.String(f.accountID),
+		PublicIp:      address.PublicIp,
+		PublicDnsName: aws.String(fmt.Sprintf("ec2-%s.%s.compute.amazonaws.com",
+			dashed(aws.ToString(address.PublicIp)), f.region)),
+	}
+}
+
+// disassociateAddress unmaps address from its network interface, if any.
+func (f *EC2) disassociateAddress(address *types.Address) {
+	if networkInterface, ok := f.networkInterfaces[aws.ToString(address.NetworkInterfaceId)]; ok {
+		networkInterface.Association = nil
+	}
+	address.AssociationId = nil
+	address.InstanceId = nil
+	address.NetworkInterfaceId = nil
+	address.NetworkInterfaceOwnerId = nil
+	address.PrivateIpAddress = nil
+}
+
+// AllocateAddress allocates an elastic IP address.
+func (f *EC2) AllocateAddress(ctx context.Context, params *ec2.AllocateAddressInput, optFns ...func(*ec2.Options)) (*ec2.AllocateAddressOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.AllocateAddressInput{}
+	}
+	allocationID := f.newID("eipalloc")
+	address := &types.Address{
+		AllocationId:       aws.String(allocationID),
+		PublicIp:           aws.String(f.newPublicIP()),
+		Domain:             types.DomainTypeVpc,
+		NetworkBorderGroup: aws.String(f.region),
+		PublicIpv4Pool:     aws.String("amazon"),
+		Tags:               tagsFor(params.TagSpecifications, types.ResourceTypeElasticIp),
+	}
+	f.addresses[allocationID] = address
+	return &ec2.AllocateAddressOutput{
+		AllocationId:       address.AllocationId,
+		PublicIp:           address.PublicIp,
+		Domain:             address.Domain,
+		NetworkBorderGroup: address.NetworkBorderGroup,
+		PublicIpv4Pool:     address.PublicIpv4Pool,
+	}, nil
+}
+
+// DescribeAddresses returns the elastic IP addresses selected by allocation IDs, public IPs and
+// filters.
+func (f *EC2) DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeAddressesInput{}
+	}
+	filters := params.Filters
+	for _, publicIP := range params.PublicIps {
+		if _, err := f.findAddress(nil, aws.String(publicIP)); err != nil {
+			return nil, err
+		}
+	}
+	if len(params.PublicIps) != 0 {
+		filters = append(append([]types.Filter{}, filters...), types.Filter{Name: aws.String("public-ip"), Values: params.PublicIps})
+	}
+	addresses, err := describe(f.addresses, params.AllocationIds, filters, allocationNotFound, addressFilterAttributes, addressTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeAddressesOutput{Addresses: addresses}, nil
+}
+
+// AssociateAddress maps an elastic IP address to an instance or a network interface.
+func (f *EC2) AssociateAddress(ctx context.Context, params *ec2.AssociateAddressInput, optFns ...func(*ec2.Options)) (*ec2.AssociateAddressOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	address, err := f.findAddress(params.AllocationId, params.PublicIp)
+	if err != nil {
+		return nil, err
+	}
+	var networkInterface *types.NetworkInterface
+	switch {
+	case params.InstanceId != nil:
+		instance, ok := f.instances[*params.InstanceId]
+		if !ok {
+			return nil, instanceNotFound(*params.InstanceId)
+		}
+		if instance.State == nil || instance.State.Name != types.InstanceStateNameRunning {
+			return nil, apiError(awserrors.InvalidInstanceID, "The instance '%s' is not in a valid state for this operation.", *params.InstanceId)
+		}
+		networkInterface = f.primaryNetworkInterface(*params.InstanceId)
+	case params.NetworkInterfaceId != nil:
+		var ok bool
+		networkInterface, ok = f.networkInterfaces[*params.NetworkInterfaceId]
+		if !ok {
+			return nil, networkInterfaceNotFound(*params.NetworkInterfaceId)
+		}
+	default:
+		return nil, missingParameter("instanceId")
+	}
+	if address.AssociationId != nil {
+		if !aws.ToBool(params.AllowReassociation) {
+			return nil, apiError(awserrors.ResourceAlreadyAssociated, "resource %s is already associated with associate-id %s",
+				aws.ToString(address.AllocationId), aws.ToString(address.AssociationId))
+		}
+		f.disassociateAddress(address)
+	}
+	if networkInterface.Association != nil && networkInterface.Association.AllocationId != nil {
+		if previous, ok := f.addresses[*networkInterface.Association.AllocationId]; ok {
+			f.disassociateAddress(previous)
+		}
+	}
+	f.associateAddress(address, networkInterface)
+	return &ec2.AssociateAddressOutput{AssociationId: address.AssociationId}, nil
+}
+
+// DisassociateAddress unmaps an elastic IP address.
+func (f *EC2) DisassociateAddress(ctx context.Context, params *ec2.DisassociateAddressInput, optFns ...func(*ec2.Options)) (*ec2.DisassociateAddressOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, address := range f.addresses {
+		if (params.AssociationId != nil && aws.ToString(address.AssociationId) == *params.AssociationId) ||
+			(params.PublicIp != nil && address.AssociationId != nil && aws.ToString(address.PublicIp) == *params.PublicIp) {
+			f.disassociateAddress(address)
+			return &ec2.DisassociateAddressOutput{}, nil
+		}
+	}
+	if params.AssociationId == nil && params.PublicIp == nil {
+		return nil, missingParameter("associationId")
+	}
+	return nil, associationNotFound(aws.ToString(params.AssociationId) + aws.ToString(params.PublicIp))
+}
+
+// ReleaseAddress releases an elastic IP address that is not associated.
+func (f *EC2) ReleaseAddress(ctx context.Context, params *ec2.ReleaseAddressInput, optFns ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	address, err := f.findAddress(params.AllocationId, params.PublicIp)
+	if err != nil {
+		return nil, err
+	}
+	if address.AssociationId != nil {
+		return nil, apiError(awserrors.InvalidIPAddressInUse, "Address %s is in use.", aws.ToString(address.PublicIp))
+	}
+	delete(f.addresses, aws.ToString(address.AllocationId))
+	return &ec2.ReleaseAddressOutput{}, nil
+}
+
+func natGatewayFilterAttributes(gateway *types.NatGateway) map[string][]string {
+	return map[string][]string{
+		"nat-gateway-id": {aws.ToString(gateway.NatGatewayId)},
+		"subnet-id":      {aws.ToString(gateway.SubnetId)},
+		"vpc-id":         {aws.ToString(gateway.VpcId)},
+		"state":          {string(gateway.State)},
+	}
+}
+
+func natGatewayTags(gateway *types.NatGateway) []types.Tag {
+	return gateway.Tags
+}
+
+// CreateNatGateway creates an available NAT gateway with a network interface in its subnet. Public
+// gateways get the given elastic IP address mapped to that interface.
+func (f *EC2) CreateNatGateway(ctx context.Context, params *ec2.CreateNatGatewayInput, optFns ...func(*ec2.Options)) (*ec2.CreateNatGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.SubnetId == nil {
+		return nil, missingParameter("subnetId")
+	}
+	subnet, ok := f.subnets[*params.SubnetId]
+	if !ok {
+		return nil, subnetNotFound(*params.SubnetId)
+	}
+	connectivity := params.ConnectivityType
+	if connectivity == "" {
+		connectivity = types.ConnectivityTypePublic
+	}
+	var address *types.Address
+	if connectivity == types.ConnectivityTypePublic {
+		if params.AllocationId == nil {
+			return nil, missingParameter("allocationId")
+		}
+		var err error
+		address, err = f.findAddress(params.AllocationId, nil)
+		if err != nil {
+			return nil, err
+		}
+		if address.AssociationId != nil {
+			return nil, apiError(awserrors.ResourceAlreadyAssociated, "Elastic IP address [%s] is already associated", *params.AllocationId)
+		}
+	}
+	gatewayID := f.newID("nat")
+	networkInterface := f.createNetworkInterface(subnet, types.NetworkInterfaceTypeNatGateway,
+		fmt.Sprintf("Interface for NAT Gateway %s", gatewayID), nil, true)
+	gatewayAddress := types.NatGatewayAddress{
+		NetworkInterfaceId: networkInterface.NetworkInterfaceId,
+		PrivateIp:          networkInterface.PrivateIpAddress,
+		IsPrimary:          aws.Bool(true),
+		Status:             types.NatGatewayAddressStatusSucceeded,
+	}
+	if address != nil {
+		f.associateAddress(address, networkInterface)
+		gatewayAddress.AllocationId = address.AllocationId
+		gatewayAddress.AssociationId = address.AssociationId
+		gatewayAddress.PublicIp = address.PublicIp
+	}
+	gateway := &types.NatGateway{
+		NatGatewayId:        aws.String(gatewayID),
+		SubnetId:            subnet.SubnetId,
+		VpcId:               subnet.VpcId,
+		ConnectivityType:    connectivity,
+		State:               types.NatGatewayStateAvailable,
+		CreateTime:          f.now(),
+		NatGatewayAddresses: []types.NatGatewayAddress{gatewayAddress},
+		Tags:                tagsFor(params.TagSpecifications, types.ResourceTypeNatgateway),
+	}
+	f.natGateways[gatewayID] = gateway
+	return &ec2.CreateNatGatewayOutput{NatGateway: cloned(gateway), ClientToken: params.ClientToken}, nil
+}
+
+// DescribeNatGateways returns the NAT gateways selected by IDs and filters, including deleted ones.
+func (f *EC2) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeNatGatewaysInput{}
+	}
+	gateways, err := describe(f.natGateways, params.NatGatewayIds, params.Filter, natGatewayNotFound,
+		natGatewayFilterAttributes, natGatewayTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeNatGatewaysOutput{NatGateways: gateways}, nil
+}
+
+// DeleteNatGateway moves a NAT gateway to the deleted state, removing its network interface and
+// unmapping its elastic IP address. Deleting a deleted gateway succeeds.
+func (f *EC2) DeleteNatGateway(ctx context.Context, params *ec2.DeleteNatGatewayInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNatGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	gatewayID := aws.ToString(params.NatGatewayId)
+	gateway, ok := f.natGateways[gatewayID]
+	if !ok {
+		return nil, natGatewayNotFound(gatewayID)
+	}
+	if gateway.State != types.NatGatewayStateDeleted {
+		for _, gatewayAddress := range gateway.NatGatewayAddresses {
+			if address, ok := f.addresses[aws.ToString(gatewayAddress.AllocationId)]; ok &&
+				aws.ToString(address.NetworkInterfaceId) == aws.ToString(gatewayAddress.NetworkInterfaceId) {
+				f.disassociateAddress(address)
+			}
+			f.deleteNetworkInterface(aws.ToString(gatewayAddress.NetworkInterfaceId))
+		}
+		gateway.State = types.NatGatewayStateDeleted
+		gateway.DeleteTime = f.now()
+	}
+	return &ec2.DeleteNatGatewayOutput{NatGatewayId: aws.String(gatewayID)}, nil
+}
@@ -0,0 +1,376 @@
+package aws_fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+func imageFilterAttributes(image *types.Image) map[string][]string {
+	return map[string][]string{
+		"image-id":            {aws.ToString(image.ImageId)},
+		"name":                optional(image.Name),
+		"description":         optional(image.Description),
+		"architecture":        {string(image.Architecture)},
+		"state":               {string(image.State)},
+		"image-type":          {string(image.ImageType)},
+		"is-public":           boolValue(aws.ToBool(image.Public)),
+		"virtualization-type": {string(image.VirtualizationType)},
+		"root-device-type":    {string(image.RootDeviceType)},
+		"hypervisor":          {string(image.Hypervisor)},
+		"owner-id":            optional(image.OwnerId),
+		"owner-alias":         optional(image.ImageOwnerAlias),
+		"platform-details":    optional(image.PlatformDetails),
+	}
+}
+
+func imageTags(image *types.Image) []types.Tag {
+	return image.Tags
+}
+
+// AddImage registers an AMI with the fake, so instances can be launched from it and it can be
+// found by DescribeImages. Unset fields get the values of a private x86_64 Linux image owned by
+// the account of the fake. It returns the ID of the image, generating one if ImageId is unset.
+func (f *EC2) AddImage(image types.Image) string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.addImage(image)
+}
+
+func (f *EC2) addImage(image types.Image) string {
+	if image.ImageId == nil {
+		image.ImageId = aws.String(f.newID("ami"))
+	}
+	if image.OwnerId == nil {
+		image.OwnerId = aws.String(f.accountID)
+	}
+	if image.Architecture == "" {
+		image.Architecture = types.ArchitectureValuesX8664
+	}
+	if image.State == "" {
+		image.State = types.ImageStateAvailable
+	}
+	if image.ImageType == "" {
+		image.ImageType = types.ImageTypeValuesMachine
+	}
+	if image.VirtualizationType == "" {
+		image.VirtualizationType = types.VirtualizationTypeHvm
+	}
+	if image.RootDeviceType == "" {
+		image.RootDeviceType = types.DeviceTypeEbs
+	}
+	if image.Hypervisor == "" {
+		image.Hypervisor = types.HypervisorTypeXen
+	}
+	if image.RootDeviceName == nil {
+		image.RootDeviceName = aws.String(rootDeviceName)
+	}
+	if image.PlatformDetails == nil {
+		image.PlatformDetails = aws.String("Linux/UNIX")
+	}
+	if image.Public == nil {
+		image.Public = aws.Bool(false)
+	}
+	if image.CreationDate == nil {
+		image.CreationDate = aws.String(f.now().Format("2006-01-02T15:04:05.000Z"))
+	}
+	f.images[*image.ImageId] = &image
+	return *image.ImageId
+}
+
+// ownedBy reports whether image matches one of the owners accepted by DescribeImages: self,
+// an alias such as amazon, or an account ID.
+func (f *EC2) ownedBy(image types.Image, owners []string) bool {
+	if len(owners) == 0 {
+		return true
+	}
+	for _, owner := range owners {
+		switch owner {
+		case "self":
+			if aws.ToString(image.OwnerId) == f.accountID {
+				return true
+			}
+		case aws.ToString(image.ImageOwnerAlias), aws.ToString(image.OwnerId):
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeImages returns the images selected by IDs, owners and filters.
+func (f *EC2) DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeImagesInput{}
+	}
+	images, err := describe(f.images, params.ImageIds, params.Filters, imageNotFound, imageFilterAttributes, imageTags)
+	if err != nil {
+		return nil, err
+	}
+	output := &ec2.DescribeImagesOutput{Images: []types.Image{}}
+	for _, image := range images {
+		if f.ownedBy(image, params.Owners) {
+			output.Images = append(output.Images, image)
+		}
+	}
+	return output, nil
+}
+
+// CopyImage copies an image into a new image owned by the account of the fake. The source
+// region is not checked, every image of the fake can be copied.
+func (f *EC2) CopyImage(ctx context.Context, params *ec2.CopyImageInput, optFns ...func(*ec2.Options)) (*ec2.CopyImageOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.Name == nil {
+		return nil, missingParameter("name")
+	}
+	source, ok := f.images[aws.ToString(params.SourceImageId)]
+	if !ok {
+		return nil, imageNotFound(aws.ToString(params.SourceImageId))
+	}
+	image := clone(*source)
+	image.ImageId = nil
+	image.OwnerId = nil
+	image.ImageOwnerAlias = nil
+	image.Public = nil
+	image.CreationDate = nil
+	image.Name = params.Name
+	image.Description = params.Description
+	image.Tags = tagsFor(params.TagSpecifications, types.ResourceTypeImage)
+	if aws.ToBool(params.CopyImageTags) {
+		image.Tags = append(clone(source.Tags), image.Tags...)
+	}
+	return &ec2.CopyImageOutput{ImageId: aws.String(f.addImage(image))}, nil
+}
+
+func volumeFilterAttributes(volume *types.Volume) map[string][]string {
+	attributes := map[string][]string{
+		"volume-id":              {aws.ToString(volume.VolumeId)},
+		"status":                 {string(volume.State)},
+		"availability-zone":      {aws.ToString(volume.AvailabilityZone)},
+		"volume-type":            {string(volume.VolumeType)},
+		"size":                   {fmt.Sprintf("%d", aws.ToInt32(volume.Size))},
+		"encrypted":              boolValue(aws.ToBool(volume.Encrypted)),
+		"snapshot-id":            optional(volume.SnapshotId),
+		"attachment.instance-id": {},
+		"attachment.status":      {},
+		"attachment.device":      {},
+	}
+	for _, attachment := range volume.Attachments {
+		attributes["attachment.instance-id"] = append(attributes["attachment.instance-id"], aws.ToString(attachment.InstanceId))
+		attributes["attachment.status"] = append(attributes["attachment.status"], string(attachment.State))
+		attributes["attachment.device"] = append(attributes["attachment.device"], aws.ToString(attachment.Device))
+	}
+	return attributes
+}
+
+func volumeTags(volume *types.Volume) []types.Tag {
+	return volume.Tags
+}
+
+// CreateVolume creates an available EBS volume in an availability zone of the fake.
+func (f *EC2) CreateVolume(ctx context.Context, params *ec2.CreateVolumeInput, optFns ...func(*ec2.Options)) (*ec2.CreateVolumeOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	zone := aws.ToString(params.AvailabilityZone)
+	if zone == "" {
+		return nil, missingParameter("AvailabilityZone")
+	}
+	if !f.validZone(zone) {
+		return nil, apiError(awserrors.InvalidParameterValue, "Invalid availability zone: [%s]", zone)
+	}
+	if params.Size == nil && params.SnapshotId == nil {
+		return nil, missingParameter("size or snapshotId")
+	}
+	size := params.Size
+	if size == nil {
+		size = aws.Int32(8)
+	}
+	volumeType := params.VolumeType
+	if volumeType == "" {
+		volumeType = types.VolumeTypeGp2
+	}
+	volumeID := f.newID("vol")
+	volume := &types.Volume{
+		VolumeId:           aws.String(volumeID),
+		AvailabilityZone:   aws.String(zone),
+		Size:               size,
+		VolumeType:         volumeType,
+		Iops:               params.Iops,
+		Throughput:         params.Throughput,
+		Encrypted:          aws.Bool(aws.ToBool(params.Encrypted)),
+		KmsKeyId:           params.KmsKeyId,
+		SnapshotId:         aws.String(aws.ToString(params.SnapshotId)),
+		MultiAttachEnabled: aws.Bool(aws.ToBool(params.MultiAttachEnabled)),
+		State:              types.VolumeStateAvailable,
+		CreateTime:         f.now(),
+		Attachments:        []types.VolumeAttachment{},
+		Tags:               tagsFor(params.TagSpecifications, types.ResourceTypeVolume),
+	}
+	f.volumes[volumeID] = volume
+	return &ec2.CreateVolumeOutput{
+		VolumeId:           volume.VolumeId,
+		AvailabilityZone:   volume.AvailabilityZone,
+		Size:               volume.Size,
+		VolumeType:         volume.VolumeType,
+		Iops:               volume.Iops,
+		Throughput:         volume.Throughput,
+		Encrypted:          volume.Encrypted,
+		KmsKeyId:           volume.KmsKeyId,
+		SnapshotId:         volume.SnapshotId,
+		MultiAttachEnabled: volume.MultiAttachEnabled,
+		State:              volume.State,
+		CreateTime:         volume.CreateTime,
+		Tags:               clone(volume.Tags),
+	}, nil
+}
+
+// DescribeVolumes returns the volumes selected by IDs and filters.
+func (f *EC2) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeVolumesInput{}
+	}
+	volumes, err := describe(f.volumes, params.VolumeIds, params.Filters, volumeNotFound, volumeFilterAttributes, volumeTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeVolumesOutput{Volumes: volumes}, nil
+}
+
+// DeleteVolume deletes a volume that is not attached to an instance.
+func (f *EC2) DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	volumeID := aws.ToString(params.VolumeId)
+	volume, ok := f.volumes[volumeID]
+	if !ok {
+		return nil, volumeNotFound(volumeID)
+	}
+	if len(volume.Attachments) != 0 {
+		return nil, apiError(awserrors.VolumeInUse, "Volume %s is currently attached to %s",
+			volumeID, aws.ToString(volume.Attachments[0].InstanceId))
+	}
+	delete(f.volumes, volumeID)
+	return &ec2.DeleteVolumeOutput{}, nil
+}
+
+func capacityReservationFilterAttributes(reservation *types.CapacityReservation) map[string][]string {
+	return map[string][]string{
+		"capacity-reservation-id": {aws.ToString(reservation.CapacityReservationId)},
+		"instance-type":           {aws.ToString(reservation.InstanceType)},
+		"availability-zone":       {aws.ToString(reservation.AvailabilityZone)},
+		"instance-platform":       {string(reservation.InstancePlatform)},
+		"tenancy":                 {string(reservation.Tenancy)},
+		"state":                   {string(reservation.State)},
+		"owner-id":                {aws.ToString(reservation.OwnerId)},
+		"end-date-type":           {string(reservation.EndDateType)},
+		"instance-match-criteria": {string(reservation.InstanceMatchCriteria)},
+	}
+}
+
+func capacityReservationTags(reservation *types.CapacityReservation) []types.Tag {
+	return reservation.Tags
+}
+
+// CreateCapacityReservation creates an active capacity reservation for an instance type the fake
+// offers.
+func (f *EC2) CreateCapacityReservation(ctx context.Context, params *ec2.CreateCapacityReservationInput, optFns ...func(*ec2.Options)) (*ec2.CreateCapacityReservationOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	zone := aws.ToString(params.AvailabilityZone)
+	if zone == "" && params.AvailabilityZoneId != nil {
+		for _, known := range f.zones {
+			if f.zoneID(known) == *params.AvailabilityZoneId {
+				zone = known
+			}
+		}
+	}
+	if !f.validZone(zone) {
+		return nil, apiError(awserrors.InvalidParameterValue, "Invalid availability zone: [%s]", zone)
+	}
+	instanceType := aws.ToString(params.InstanceType)
+	if !f.offers(instanceType) {
+		return nil, apiError(awserrors.InvalidParameterValue, "The instance type '%s' is not supported.", instanceType)
+	}
+	count := aws.ToInt32(params.InstanceCount)
+	if count < 1 {
+		return nil, apiError(awserrors.InvalidParameterValue, "Value (%d) for parameter instanceCount is invalid.", count)
+	}
+	tenancy := params.Tenancy
+	if tenancy == "" {
+		tenancy = types.CapacityReservationTenancyDefault
+	}
+	endDateType := params.EndDateType
+	if endDateType == "" {
+		endDateType = types.EndDateTypeUnlimited
+	}
+	matchCriteria := params.InstanceMatchCriteria
+	if matchCriteria == "" {
+		matchCriteria = types.InstanceMatchCriteriaOpen
+	}
+	reservationID := f.newID("cr")
+	reservation := &types.CapacityReservation{
+		CapacityReservationId:  aws.String(reservationID),
+		CapacityReservationArn: aws.String(f.arn("capacity-reservation", reservationID)),
+		OwnerId:                aws.String(f.accountID),
+		AvailabilityZone:       aws.String(zone),
+		AvailabilityZoneId:     aws.String(f.zoneID(zone)),
+		InstanceType:           aws.String(instanceType),
+		InstancePlatform:       params.InstancePlatform,
+		TotalInstanceCount:     aws.Int32(count),
+		AvailableInstanceCount: aws.Int32(count),
+		Tenancy:                tenancy,
+		EbsOptimized:           aws.Bool(aws.ToBool(params.EbsOptimized)),
+		EphemeralStorage:       aws.Bool(aws.ToBool(params.EphemeralStorage)),
+		EndDate:                params.EndDate,
+		EndDateType:            endDateType,
+		InstanceMatchCriteria:  matchCriteria,
+		State:                  types.CapacityReservationStateActive,
+		CreateDate:             f.now(),
+		StartDate:              f.now(),
+		Tags:                   tagsFor(params.TagSpecifications, types.ResourceTypeCapacityReservation),
+	}
+	f.capacityReservations[reservationID] = reservation
+	return &ec2.CreateCapacityReservationOutput{CapacityReservation: cloned(reservation)}, nil
+}
+
+// DescribeCapacityReservations returns the capacity reservations selected by IDs and filters.
+func (f *EC2) DescribeCapacityReservations(ctx context.Context, params *ec2.DescribeCapacityReservationsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeCapacityReservationsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeCapacityReservationsInput{}
+	}
+	reservations, err := describe(f.capacityReservations, params.CapacityReservationIds, params.Filters,
+		capacityReservationNotFound, capacityReservationFilterAttributes, capacityReservationTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeCapacityReservationsOutput{CapacityReservations: reservations}, nil
+}
+
+// CancelCapacityReservation moves a capacity reservation to the cancelled state.
+func (f *EC2) CancelCapacityReservation(ctx context.Context, params *ec2.CancelCapacityReservationInput, optFns ...func(*ec2.Options)) (*ec2.CancelCapacityReservationOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	reservationID := aws.ToString(params.CapacityReservationId)
+	reservation, ok := f.capacityReservations[reservationID]
+	if !ok {
+		return nil, capacityReservationNotFound(reservationID)
+	}
+	if reservation.State != types.CapacityReservationStateActive {
+		return nil, apiError(awserrors.InvalidParameterValue,
+			"The capacity reservation '%s' cannot be cancelled in its current state: %s", reservationID, reservation.State)
+	}
+	reservation.State = types.CapacityReservationStateCancelled
+	reservation.AvailableInstanceCount = aws.Int32(0)
+	return &ec2.CancelCapacityReservationOutput{Return: aws.Bool(true)}, nil
+}
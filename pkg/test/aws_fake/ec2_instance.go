@@ -0,0 +1,598 @@
+package aws_fake
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+const rootDeviceName = "/dev/xvda"
+
+func networkInterfaceFilterAttributes(networkInterface *types.NetworkInterface) map[string][]string {
+	attributes := map[string][]string{
+		"network-interface-id": {aws.ToString(networkInterface.NetworkInterfaceId)},
+		"vpc-id":               {aws.ToString(networkInterface.VpcId)},
+		"subnet-id":            {aws.ToString(networkInterface.SubnetId)},
+		"availability-zone":    {aws.ToString(networkInterface.AvailabilityZone)},
+		"interface-type":       {string(networkInterface.InterfaceType)},
+		"status":               {string(networkInterface.Status)},
+		"requester-managed":    boolValue(aws.ToBool(networkInterface.RequesterManaged)),
+		"private-ip-address":   {aws.ToString(networkInterface.PrivateIpAddress)},
+		"description":          {aws.ToString(networkInterface.Description)},
+		"owner-id":             {aws.ToString(networkInterface.OwnerId)},
+		"mac-address":          {aws.ToString(networkInterface.MacAddress)},
+		"group-id":             {},
+		"group-name":           {},
+	}
+	for _, group := range networkInterface.Groups {
+		attributes["group-id"] = append(attributes["group-id"], aws.ToString(group.GroupId))
+		attributes["group-name"] = append(attributes["group-name"], aws.ToString(group.GroupName))
+	}
+	if attachment := networkInterface.Attachment; attachment != nil {
+		attributes["attachment.instance-id"] = optional(attachment.InstanceId)
+		attributes["attachment.attachment-id"] = optional(attachment.AttachmentId)
+		attributes["attachment.status"] = []string{string(attachment.Status)}
+	} else {
+		attributes["attachment.instance-id"] = []string{}
+		attributes["attachment.attachment-id"] = []string{}
+		attributes["attachment.status"] = []string{}
+	}
+	if association := networkInterface.Association; association != nil {
+		attributes["association.allocation-id"] = optional(association.AllocationId)
+		attributes["association.public-ip"] = optional(association.PublicIp)
+	} else {
+		attributes["association.allocation-id"] = []string{}
+		attributes["association.public-ip"] = []string{}
+	}
+	return attributes
+}
+
+func networkInterfaceTags(networkInterface *types.NetworkInterface) []types.Tag {
+	return networkInterface.TagSet
+}
+
+// newMacAddress returns a random locally administered MAC address like those of AWS interfaces.
+func newMacAddress() string {
+	mac := make([]byte, 6)
+	_, _ = rand.Read(mac)
+	mac[0] = 0x02
+	return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", mac[0], mac[1], mac[2], mac[3], mac[4], mac[5])
+}
+
+// createNetworkInterface creates an available network interface in subnet with the next free
+// private IP of the subnet.
+func (f *EC2) createNetworkInterface(subnet *types.Subnet, interfaceType types.NetworkInterfaceType,
+	description string, groupIDs []string, requesterManaged bool) *types.NetworkInterface {
+	networkInterfaceID := f.newID("eni")
+	privateIP := f.newPrivateIP(subnet)
+	networkInterface := &types.NetworkInterface{
+		NetworkInterfaceId: aws.String(networkInterfaceID),
+		InterfaceType:      interfaceType,
+		Description:        aws.String(description),
+		SubnetId:           subnet.SubnetId,
+		VpcId:              subnet.VpcId,
+		AvailabilityZone:   subnet.AvailabilityZone,
+		OwnerId:            aws.String(f.accountID),
+		MacAddress:         aws.String(newMacAddress()),
+		PrivateIpAddress:   aws.String(privateIP),
+		PrivateIpAddresses: []types.NetworkInterfacePrivateIpAddress{{
+			Primary:          aws.Bool(true),
+			PrivateIpAddress: aws.String(privateIP),
+		}},
+		RequesterManaged: aws.Bool(requesterManaged),
+		SourceDestCheck:  aws.Bool(true),
+		Status:           types.NetworkInterfaceStatusAvailable,
+		TagSet:           []types.Tag{},
+	}
+	for _, groupID := range groupIDs {
+		if group, ok := f.securityGroups[groupID]; ok {
+			networkInterface.Groups = append(networkInterface.Groups, types.GroupIdentifier{
+				GroupId:   group.GroupId,
+				GroupName: group.GroupName,
+			})
+		}
+	}
+	if subnet.AvailableIpAddressCount != nil {
+		subnet.AvailableIpAddressCount = aws.Int32(*subnet.AvailableIpAddressCount - 1)
+	}
+	f.networkInterfaces[networkInterfaceID] = networkInterface
+	return networkInterface
+}
+
+// deleteNetworkInterface deletes a network interface, unmapping its elastic IP address.
+func (f *EC2) deleteNetworkInterface(networkInterfaceID string) {
+	networkInterface, ok := f.networkInterfaces[networkInterfaceID]
+	if !ok {
+		return
+	}
+	if networkInterface.Association != nil {
+		if address, ok := f.addresses[aws.ToString(networkInterface.Association.AllocationId)]; ok {
+			f.disassociateAddress(address)
+		}
+	}
+	if subnet, ok := f.subnets[aws.ToString(networkInterface.SubnetId)]; ok && subnet.AvailableIpAddressCount != nil {
+		subnet.AvailableIpAddressCount = aws.Int32(*subnet.AvailableIpAddressCount + 1)
+	}
+	delete(f.networkInterfaces, networkInterfaceID)
+}
+
+// primaryNetworkInterface returns the network interface attached to an instance at device index 0.
+func (f *EC2) primaryNetworkInterface(instanceID string) *types.NetworkInterface {
+	for _, id := range sortedIDs(f.networkInterfaces) {
+		networkInterface := f.networkInterfaces[id]
+		if attachment := networkInterface.Attachment; attachment != nil &&
+			aws.ToString(attachment.InstanceId) == instanceID && aws.ToInt32(attachment.DeviceIndex) == 0 {
+			return networkInterface
+		}
+	}
+	return nil
+}
+
+// DescribeNetworkInterfaces returns the network interfaces selected by IDs and filters.
+func (f *EC2) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeNetworkInterfacesInput{}
+	}
+	networkInterfaces, err := describe(f.networkInterfaces, params.NetworkInterfaceIds, params.Filters, networkInterfaceNotFound,
+		networkInterfaceFilterAttributes, networkInterfaceTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: networkInterfaces}, nil
+}
+
+// DetachNetworkInterface detaches a secondary network interface from its instance. The primary
+// interface of an instance cannot be detached.
+func (f *EC2) DetachNetworkInterface(ctx context.Context, params *ec2.DetachNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DetachNetworkInterfaceOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	attachmentID := aws.ToString(params.AttachmentId)
+	for _, networkInterface := range f.networkInterfaces {
+		attachment := networkInterface.Attachment
+		if attachment == nil || aws.ToString(attachment.AttachmentId) != attachmentID {
+			continue
+		}
+		if aws.ToInt32(attachment.DeviceIndex) == 0 {
+			return nil, apiError(awserrors.OperationNotPermitted,
+				"The network interface at device index 0 cannot be detached.")
+		}
+		networkInterface.Attachment = nil
+		networkInterface.Status = types.NetworkInterfaceStatusAvailable
+		return &ec2.DetachNetworkInterfaceOutput{}, nil
+	}
+	return nil, attachmentNotFound(attachmentID)
+}
+
+// DeleteNetworkInterface deletes a detached network interface. Interfaces managed by AWS, such as
+// those of NAT gateways and VPC endpoints, can only be deleted with their owner.
+func (f *EC2) DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	networkInterfaceID := aws.ToString(params.NetworkInterfaceId)
+	networkInterface, ok := f.networkInterfaces[networkInterfaceID]
+	if !ok {
+		return nil, networkInterfaceNotFound(networkInterfaceID)
+	}
+	if aws.ToBool(networkInterface.RequesterManaged) {
+		return nil, apiError(awserrors.OperationNotPermitted,
+			"You are not allowed to manage '%s' attachments.", string(networkInterface.InterfaceType))
+	}
+	if networkInterface.Attachment != nil {
+		return nil, apiError(awserrors.InvalidNetworkInterfaceInUse,
+			"The network interface '%s' is currently in use.", networkInterfaceID)
+	}
+	f.deleteNetworkInterface(networkInterfaceID)
+	return &ec2.DeleteNetworkInterfaceOutput{}, nil
+}
+
+func instanceFilterAttributes(instance *types.Instance) map[string][]string {
+	attributes := map[string][]string{
+		"instance-id":                            {aws.ToString(instance.InstanceId)},
+		"vpc-id":                                 optional(instance.VpcId),
+		"subnet-id":                              optional(instance.SubnetId),
+		"instance-state-name":                    {string(instance.State.Name)},
+		"instance-state-code":                    {fmt.Sprintf("%d", aws.ToInt32(instance.State.Code))},
+		"instance-type":                          {string(instance.InstanceType)},
+		"image-id":                               {aws.ToString(instance.ImageId)},
+		"key-name":                               optional(instance.KeyName),
+		"availability-zone":                      {aws.ToString(instance.Placement.AvailabilityZone)},
+		"private-ip-address":                     optional(instance.PrivateIpAddress),
+		"ip-address":                             optional(instance.PublicIpAddress),
+		"architecture":                           {string(instance.Architecture)},
+		"network-interface.network-interface-id": {},
+		"instance.group-id":                      {},
+		"instance.group-name":                    {},
+	}
+	for _, networkInterface := range instance.NetworkInterfaces {
+		attributes["network-interface.network-interface-id"] = append(attributes["network-interface.network-interface-id"],
+			aws.ToString(networkInterface.NetworkInterfaceId))
+	}
+	for _, group := range instance.SecurityGroups {
+		attributes["instance.group-id"] = append(attributes["instance.group-id"], aws.ToString(group.GroupId))
+		attributes["instance.group-name"] = append(attributes["instance.group-name"], aws.ToString(group.GroupName))
+	}
+	return attributes
+}
+
+func instanceTags(instance *types.Instance) []types.Tag {
+	return instance.Tags
+}
+
+// refreshInstance copies the network interfaces, security groups and addresses of an instance
+// from its attached network interfaces.
+func (f *EC2) refreshInstance(instance *types.Instance) {
+	instanceID := aws.ToString(instance.InstanceId)
+	instance.NetworkInterfaces = nil
+	instance.SecurityGroups = nil
+	instance.PublicIpAddress = nil
+	instance.PublicDnsName = aws.String("")
+	for _, id := range sortedIDs(f.networkInterfaces) {
+		networkInterface := f.networkInterfaces[id]
+		attachment := networkInterface.Attachment
+		if attachment == nil || aws.ToString(attachment.InstanceId) != instanceID {
+			continue
+		}
+		instanceInterface := types.InstanceNetworkInterface{
+			NetworkInterfaceId: networkInterface.NetworkInterfaceId,
+			SubnetId:           networkInterface.SubnetId,
+			VpcId:              networkInterface.VpcId,
+			MacAddress:         networkInterface.MacAddress,
+			OwnerId:            networkInterface.OwnerId,
+			PrivateIpAddress:   networkInterface.PrivateIpAddress,
+			Status:             types.NetworkInterfaceStatusInUse,
+			SourceDestCheck:    networkInterface.SourceDestCheck,
+			Attachment: &types.InstanceNetworkInterfaceAttachment{
+				AttachmentId:        attachment.AttachmentId,
+				DeviceIndex:         attachment.DeviceIndex,
+				DeleteOnTermination: attachment.DeleteOnTermination,
+				AttachTime:          attachment.AttachTime,
+				Status:              attachment.Status,
+			},
+		}
+		for _, group := range networkInterface.Groups {
+			instanceInterface.Groups = append(instanceInterface.Groups, group)
+		}
+		if association := networkInterface.Association; association != nil {
+			instanceInterface.Association = &types.InstanceNetworkInterfaceAssociation{
+				IpOwnerId:     association.IpOwnerId,
+				PublicDnsName: association.PublicDnsName,
+				PublicIp:      association.PublicIp,
+			}
+		}
+		instance.NetworkInterfaces = append(instance.NetworkInterfaces, instanceInterface)
+		if aws.ToInt32(attachment.DeviceIndex) == 0 {
+			instance.SecurityGroups = instanceInterface.Groups
+			if networkInterface.Association != nil {
+				instance.PublicIpAddress = networkInterface.Association.PublicIp
+				instance.PublicDnsName = networkInterface.Association.PublicDnsName
+			}
+		}
+	}
+}
+
+// RunInstances launches running instances into a subnet. Each instance gets a primary network
+// interface with the given security groups, or the default group of the VPC, and a root volume.
+func (f *EC2) RunInstances(ctx context.Context, params *ec2.RunInstancesInput, optFns ...func(*ec2.Options)) (*ec2.RunInstancesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	image, ok := f.images[aws.ToString(params.ImageId)]
+	if !ok {
+		return nil, imageNotFound(aws.ToString(params.ImageId))
+	}
+	subnetID := aws.ToString(params.SubnetId)
+	associatePublicIP := (*bool)(nil)
+	groupIDs := params.SecurityGroupIds
+	if len(params.NetworkInterfaces) != 0 {
+		spec := params.NetworkInterfaces[0]
+		if subnetID == "" {
+			subnetID = aws.ToString(spec.SubnetId)
+		}
+		associatePublicIP = spec.AssociatePublicIpAddress
+		if len(groupIDs) == 0 {
+			groupIDs = spec.Groups
+		}
+	}
+	if subnetID == "" {
+		return nil, missingParameter("subnetId")
+	}
+	subnet, ok := f.subnets[subnetID]
+	if !ok {
+		return nil, subnetNotFound(subnetID)
+	}
+	vpcID := aws.ToString(subnet.VpcId)
+	for _, groupID := range groupIDs {
+		group, ok := f.securityGroups[groupID]
+		if !ok {
+			return nil, securityGroupNotFound(groupID)
+		}
+		if aws.ToString(group.VpcId) != vpcID {
+			return nil, apiError(awserrors.InvalidParameterValue,
+				"Security group %s and subnet %s belong to different networks.", groupID, subnetID)
+		}
+	}
+	if len(groupIDs) == 0 {
+		groupIDs = []string{f.defaultSecurityGroupID(vpcID)}
+	}
+	if params.KeyName != nil {
+		if _, ok := f.keyPairs[*params.KeyName]; !ok {
+			return nil, keyPairNotFound(*params.KeyName)
+		}
+	}
+	count := aws.ToInt32(params.MinCount)
+	if max := aws.ToInt32(params.MaxCount); max > count {
+		count = max
+	}
+	if count < 1 {
+		return nil, apiError(awserrors.InvalidParameterValue, "Value (%d) for parameter maxCount is invalid.", count)
+	}
+	instanceType := params.InstanceType
+	if instanceType == "" {
+		instanceType = types.InstanceTypeM1Small
+	}
+	if associatePublicIP == nil {
+		associatePublicIP = subnet.MapPublicIpOnLaunch
+	}
+
+	reservationID := f.newID("r")
+	output := &ec2.RunInstancesOutput{
+		ReservationId: aws.String(reservationID),
+		OwnerId:       aws.String(f.accountID),
+	}
+	instanceTags := tagsFor(params.TagSpecifications, types.ResourceTypeInstance)
+	volumeTags := tagsFor(params.TagSpecifications, types.ResourceTypeVolume)
+	for i := int32(0); i < count; i++ {
+		instanceID := f.newID("i")
+		launchTime := f.now()
+		networkInterface := f.createNetworkInterface(subnet, types.NetworkInterfaceTypeInterface, "", groupIDs, false)
+		networkInterface.Status = types.NetworkInterfaceStatusInUse
+		networkInterface.Attachment = &types.NetworkInterfaceAttachment{
+			AttachmentId:        aws.String(f.newID("eni-attach")),
+			InstanceId:          aws.String(instanceID),
+			InstanceOwnerId:     aws.String(f.accountID),
+			DeviceIndex:         aws.Int32(0),
+			DeleteOnTermination: aws.Bool(true),
+			AttachTime:          launchTime,
+			Status:              types.AttachmentStatusAttached,
+		}
+		if aws.ToBool(associatePublicIP) {
+			publicIP := f.newPublicIP()
+			networkInterface.Association = &types.NetworkInterfaceAssociation{
+				IpOwnerId: aws.String("amazon"),
+				PublicIp:  aws.String(publicIP),
+				PublicDnsName: aws.String(fmt.Sprintf("ec2-%s.%s.compute.amazonaws.com",
+					dashed(publicIP), f.region)),
+			}
+		}
+		volumeID := f.newID("vol")
+		f.volumes[volumeID] = &types.Volume{
+			VolumeId:         aws.String(volumeID),
+			AvailabilityZone: subnet.AvailabilityZone,
+			Size:             aws.Int32(8),
+			VolumeType:       types.VolumeTypeGp2,
+			State:            types.VolumeStateInUse,
+			CreateTime:       launchTime,
+			Encrypted:        aws.Bool(false),
+			SnapshotId:       aws.String(""),
+			Attachments: []types.VolumeAttachment{{
+				VolumeId:            aws.String(volumeID),
+				InstanceId:          aws.String(instanceID),
+				Device:              aws.String(rootDeviceName),
+				State:               types.VolumeAttachmentStateAttached,
+				DeleteOnTermination: aws.Bool(true),
+				AttachTime:          launchTime,
+			}},
+			Tags: clone(volumeTags),
+		}
+		instance := &types.Instance{
+			InstanceId:         aws.String(instanceID),
+			ImageId:            image.ImageId,
+			InstanceType:       instanceType,
+			KeyName:            params.KeyName,
+			LaunchTime:         launchTime,
+			AmiLaunchIndex:     aws.Int32(i),
+			Architecture:       image.Architecture,
+			Hypervisor:         types.HypervisorTypeXen,
+			RootDeviceName:     aws.String(rootDeviceName),
+			RootDeviceType:     types.DeviceTypeEbs,
+			VirtualizationType: types.VirtualizationTypeHvm,
+			VpcId:              subnet.VpcId,
+			SubnetId:           subnet.SubnetId,
+			PrivateIpAddress:   networkInterface.PrivateIpAddress,
+			PrivateDnsName: aws.String(fmt.Sprintf("ip-%s.%s.compute.internal",
+				dashed(aws.ToString(networkInterface.PrivateIpAddress)), f.region)),
+			Placement: &types.Placement{
+				AvailabilityZone: subnet.AvailabilityZone,
+				Tenancy:          types.TenancyDefault,
+			},
+			State: &types.InstanceState{
+				Code: aws.Int32(16),
+				Name: types.InstanceStateNameRunning,
+			},
+			BlockDeviceMappings: []types.InstanceBlockDeviceMapping{{
+				DeviceName: aws.String(rootDeviceName),
+				Ebs: &types.EbsInstanceBlockDevice{
+					VolumeId:            aws.String(volumeID),
+					Status:              types.AttachmentStatusAttached,
+					DeleteOnTermination: aws.Bool(true),
+					AttachTime:          launchTime,
+				},
+			}},
+			Tags: clone(instanceTags),
+		}
+		f.instances[instanceID] = instance
+		f.reservations[instanceID] = reservationID
+		f.refreshInstance(instance)
+		output.Instances = append(output.Instances, clone(*instance))
+	}
+	return output, nil
+}
+
+// DescribeInstances returns the instances selected by IDs and filters, grouped by reservation.
+func (f *EC2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeInstancesInput{}
+	}
+	for _, instance := range f.instances {
+		if instance.State.Name != types.InstanceStateNameTerminated {
+			f.refreshInstance(instance)
+		}
+	}
+	filters := []types.Filter{}
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Name) == "reservation-id" {
+			continue
+		}
+		filters = append(filters, filter)
+	}
+	instances, err := describe(f.instances, params.InstanceIds, filters, instanceNotFound, instanceFilterAttributes, instanceTags)
+	if err != nil {
+		return nil, err
+	}
+	output := &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{}}
+	index := map[string]int{}
+	for _, instance := range instances {
+		reservationID := f.reservations[aws.ToString(instance.InstanceId)]
+		if !matchReservation(params.Filters, reservationID) {
+			continue
+		}
+		i, ok := index[reservationID]
+		if !ok {
+			i = len(output.Reservations)
+			index[reservationID] = i
+			output.Reservations = append(output.Reservations, types.Reservation{
+				ReservationId: aws.String(reservationID),
+				OwnerId:       aws.String(f.accountID),
+			})
+		}
+		output.Reservations[i].Instances = append(output.Reservations[i].Instances, instance)
+	}
+	return output, nil
+}
+
+func matchReservation(filters []types.Filter, reservationID string) bool {
+	for _, filter := range filters {
+		if aws.ToString(filter.Name) == "reservation-id" && !matchAny(filter.Values, []string{reservationID}) {
+			return false
+		}
+	}
+	return true
+}
+
+// TerminateInstances terminates instances, deleting their root volumes and the network interfaces
+// marked for deletion on termination. Elastic IP addresses stay allocated. Terminating a
+// terminated instance succeeds.
+func (f *EC2) TerminateInstances(ctx context.Context, params *ec2.TerminateInstancesInput, optFns ...func(*ec2.Options)) (*ec2.TerminateInstancesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if len(params.InstanceIds) == 0 {
+		return nil, missingParameter("InstanceId")
+	}
+	for _, instanceID := range params.InstanceIds {
+		if _, ok := f.instances[instanceID]; !ok {
+			return nil, instanceNotFound(instanceID)
+		}
+	}
+	output := &ec2.TerminateInstancesOutput{}
+	for _, instanceID := range params.InstanceIds {
+		instance := f.instances[instanceID]
+		previous := *instance.State
+		if instance.State.Name != types.InstanceStateNameTerminated {
+			for _, id := range sortedIDs(f.networkInterfaces) {
+				networkInterface := f.networkInterfaces[id]
+				attachment := networkInterface.Attachment
+				if attachment == nil || aws.ToString(attachment.InstanceId) != instanceID {
+					continue
+				}
+				if aws.ToBool(attachment.DeleteOnTermination) {
+					f.deleteNetworkInterface(id)
+				} else {
+					networkInterface.Attachment = nil
+					networkInterface.Status = types.NetworkInterfaceStatusAvailable
+				}
+			}
+			for _, id := range sortedIDs(f.volumes) {
+				volume := f.volumes[id]
+				for _, attachment := range volume.Attachments {
+					if aws.ToString(attachment.InstanceId) != instanceID {
+						continue
+					}
+					if aws.ToBool(attachment.DeleteOnTermination) {
+						delete(f.volumes, id)
+					} else {
+						volume.Attachments = nil
+						volume.State = types.VolumeStateAvailable
+					}
+				}
+			}
+			instance.State = &types.InstanceState{Code: aws.Int32(48), Name: types.InstanceStateNameTerminated}
+			instance.NetworkInterfaces = nil
+			instance.SecurityGroups = nil
+			instance.BlockDeviceMappings = nil
+			instance.VpcId = nil
+			instance.SubnetId = nil
+			instance.PrivateIpAddress = nil
+			instance.PublicIpAddress = nil
+			instance.PublicDnsName = aws.String("")
+			instance.PrivateDnsName = aws.String("")
+		}
+		output.TerminatingInstances = append(output.TerminatingInstances, types.InstanceStateChange{
+			InstanceId:    aws.String(instanceID),
+			PreviousState: cloned(&previous),
+			CurrentState:  cloned(instance.State),
+		})
+	}
+	return output, nil
+}
+
+// DescribeInstanceStatus returns the status of running instances, or of all instances when
+// IncludeAllInstances is set. Running instances always pass their status checks.
+func (f *EC2) DescribeInstanceStatus(ctx context.Context, params *ec2.DescribeInstanceStatusInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceStatusOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeInstanceStatusInput{}
+	}
+	ids := params.InstanceIds
+	if len(ids) == 0 {
+		ids = sortedIDs(f.instances)
+	}
+	output := &ec2.DescribeInstanceStatusOutput{InstanceStatuses: []types.InstanceStatus{}}
+	for _, instanceID := range ids {
+		instance, ok := f.instances[instanceID]
+		if !ok {
+			return nil, instanceNotFound(instanceID)
+		}
+		running := instance.State.Name == types.InstanceStateNameRunning
+		if !running && !aws.ToBool(params.IncludeAllInstances) {
+			continue
+		}
+		summary := &types.InstanceStatusSummary{Status: types.SummaryStatusNotApplicable}
+		if running {
+			summary = &types.InstanceStatusSummary{
+				Status: types.SummaryStatusOk,
+				Details: []types.InstanceStatusDetails{{
+					Name:   types.StatusNameReachability,
+					Status: types.StatusTypePassed,
+				}},
+			}
+		}
+		output.InstanceStatuses = append(output.InstanceStatuses, types.InstanceStatus{
+			InstanceId:       instance.InstanceId,
+			AvailabilityZone: instance.Placement.AvailabilityZone,
+			InstanceState:    cloned(instance.State),
+			InstanceStatus:   summary,
+			SystemStatus:     cloned(summary),
+		})
+	}
+	return output, nil
+}
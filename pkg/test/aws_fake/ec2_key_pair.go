@@ -0,0 +1,174 @@
+package aws_fake
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"golang.org/x/crypto/ssh"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+func keyPairFilterAttributes(keyPair *types.KeyPairInfo) map[string][]string {
+	return map[string][]string{
+		"key-name":    {aws.ToString(keyPair.KeyName)},
+		"key-pair-id": {aws.ToString(keyPair.KeyPairId)},
+		"fingerprint": {aws.ToString(keyPair.KeyFingerprint)},
+		"key-type":    {string(keyPair.KeyType)},
+	}
+}
+
+func keyPairTags(keyPair *types.KeyPairInfo) []types.Tag {
+	return keyPair.Tags
+}
+
+// colonHex formats a digest as colon separated hex bytes, like the fingerprints shown by AWS.
+func colonHex(digest []byte) string {
+	parts := make([]string, len(digest))
+	for i, b := range digest {
+		parts[i] = hex.EncodeToString([]byte{b})
+	}
+	return strings.Join(parts, ":")
+}
+
+// newKeyMaterial generates a private key of the given type. It returns the PEM encoded key and
+// its fingerprint computed the way AWS does for keys it creates.
+func newKeyMaterial(keyType types.KeyType) (string, string, error) {
+	switch keyType {
+	case types.KeyTypeEd25519:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", "", err
+		}
+		block, err := ssh.MarshalPrivateKey(private, "")
+		if err != nil {
+			return "", "", err
+		}
+		sshPublic, err := ssh.NewPublicKey(public)
+		if err != nil {
+			return "", "", err
+		}
+		digest := sha256.Sum256(sshPublic.Marshal())
+		return string(pem.EncodeToMemory(block)), base64.StdEncoding.EncodeToString(digest[:]), nil
+	default:
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return "", "", err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(private)
+		if err != nil {
+			return "", "", err
+		}
+		digest := sha1.Sum(der)
+		material := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+		return string(material), colonHex(digest[:]), nil
+	}
+}
+
+// findKeyPair returns the key pair with the given ID or, when it is empty, name.
+func (f *EC2) findKeyPair(keyPairID *string, keyName *string) (*types.KeyPairInfo, error) {
+	if keyPairID != nil {
+		for _, keyPair := range f.keyPairs {
+			if aws.ToString(keyPair.KeyPairId) == *keyPairID {
+				return keyPair, nil
+			}
+		}
+		return nil, keyPairNotFound(*keyPairID)
+	}
+	if keyName == nil {
+		return nil, missingParameter("keyName")
+	}
+	keyPair, ok := f.keyPairs[*keyName]
+	if !ok {
+		return nil, keyPairNotFound(*keyName)
+	}
+	return keyPair, nil
+}
+
+// CreateKeyPair creates a key pair with real key material. RSA keys are returned in PKCS#1 and
+// ED25519 keys in OpenSSH format, as AWS does.
+func (f *EC2) CreateKeyPair(ctx context.Context, params *ec2.CreateKeyPairInput, optFns ...func(*ec2.Options)) (*ec2.CreateKeyPairOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	keyName := aws.ToString(params.KeyName)
+	if keyName == "" {
+		return nil, missingParameter("keyName")
+	}
+	if _, ok := f.keyPairs[keyName]; ok {
+		return nil, apiError(awserrors.InvalidKeyPairDuplicate, "The keypair already exists")
+	}
+	keyType := params.KeyType
+	if keyType == "" {
+		keyType = types.KeyTypeRsa
+	}
+	material, fingerprint, err := newKeyMaterial(keyType)
+	if err != nil {
+		return nil, err
+	}
+	keyPair := &types.KeyPairInfo{
+		KeyPairId:      aws.String(f.newID("key")),
+		KeyName:        aws.String(keyName),
+		KeyType:        keyType,
+		KeyFingerprint: aws.String(fingerprint),
+		CreateTime:     f.now(),
+		Tags:           tagsFor(params.TagSpecifications, types.ResourceTypeKeyPair),
+	}
+	f.keyPairs[keyName] = keyPair
+	return &ec2.CreateKeyPairOutput{
+		KeyPairId:      keyPair.KeyPairId,
+		KeyName:        keyPair.KeyName,
+		KeyFingerprint: keyPair.KeyFingerprint,
+		KeyMaterial:    aws.String(material),
+		Tags:           clone(keyPair.Tags),
+	}, nil
+}
+
+// DescribeKeyPairs returns the key pairs selected by names, IDs and filters.
+func (f *EC2) DescribeKeyPairs(ctx context.Context, params *ec2.DescribeKeyPairsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeKeyPairsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeKeyPairsInput{}
+	}
+	names := append([]string{}, params.KeyNames...)
+	for _, keyPairID := range params.KeyPairIds {
+		keyPair, err := f.findKeyPair(aws.String(keyPairID), nil)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, aws.ToString(keyPair.KeyName))
+	}
+	keyPairs, err := describe(f.keyPairs, names, params.Filters, keyPairNotFound, keyPairFilterAttributes, keyPairTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeKeyPairsOutput{KeyPairs: keyPairs}, nil
+}
+
+// DeleteKeyPair deletes a key pair by name or ID. Deleting a missing key pair succeeds, as it does
+// on AWS.
+func (f *EC2) DeleteKeyPair(ctx context.Context, params *ec2.DeleteKeyPairInput, optFns ...func(*ec2.Options)) (*ec2.DeleteKeyPairOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.KeyName == nil && params.KeyPairId == nil {
+		return nil, missingParameter("keyName")
+	}
+	keyPair, err := f.findKeyPair(params.KeyPairId, params.KeyName)
+	if err != nil {
+		return &ec2.DeleteKeyPairOutput{Return: aws.Bool(true)}, nil
+	}
+	delete(f.keyPairs, aws.ToString(keyPair.KeyName))
+	return &ec2.DeleteKeyPairOutput{Return: aws.Bool(true), KeyPairId: keyPair.KeyPairId}, nil
+}
@@ -0,0 +1,185 @@
+package aws_fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+// defaultRegions are the regions returned by DescribeRegions besides the region of the fake.
+var defaultRegions = []string{
+	"ap-northeast-1", "ap-south-1", "ap-southeast-1", "ca-central-1", "eu-central-1", "eu-west-1",
+	"sa-east-1", "us-east-1", "us-east-2", "us-west-1", "us-west-2",
+}
+
+const optInNotRequired = "opt-in-not-required"
+
+// regionNames returns the regions known to the fake in lexical order.
+func (f *EC2) regionNames() []string {
+	regions := map[string]*string{f.region: nil}
+	for _, region := range defaultRegions {
+		regions[region] = nil
+	}
+	return sortedIDs(regions)
+}
+
+// offers reports whether instanceType is offered by the fake.
+func (f *EC2) offers(instanceType string) bool {
+	for _, offered := range f.instanceTypes {
+		if offered == instanceType {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeRegions returns the enabled regions selected by names and filters. Every region of the
+// fake is enabled and does not require opting in.
+func (f *EC2) DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeRegionsInput{}
+	}
+	known := f.regionNames()
+	for _, name := range params.RegionNames {
+		if !matchAny([]string{name}, known) {
+			return nil, apiError(awserrors.InvalidParameterValue, "Invalid region: %s", name)
+		}
+	}
+	output := &ec2.DescribeRegionsOutput{Regions: []types.Region{}}
+	for _, name := range known {
+		if len(params.RegionNames) != 0 && !matchAny(params.RegionNames, []string{name}) {
+			continue
+		}
+		region := types.Region{
+			RegionName:  aws.String(name),
+			Endpoint:    aws.String(fmt.Sprintf("ec2.%s.amazonaws.com", name)),
+			OptInStatus: aws.String(optInNotRequired),
+		}
+		match, err := matchFilters(params.Filters, map[string][]string{
+			"region-name":   {name},
+			"endpoint":      {aws.ToString(region.Endpoint)},
+			"opt-in-status": {optInNotRequired},
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			output.Regions = append(output.Regions, region)
+		}
+	}
+	return output, nil
+}
+
+// DescribeAvailabilityZones returns the availability zones of the fake selected by names, IDs and
+// filters.
+func (f *EC2) DescribeAvailabilityZones(ctx context.Context, params *ec2.DescribeAvailabilityZonesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeAvailabilityZonesInput{}
+	}
+	for _, name := range params.ZoneNames {
+		if !f.validZone(name) {
+			return nil, apiError(awserrors.InvalidParameterValue, "The zone '%s' does not exist.", name)
+		}
+	}
+	output := &ec2.DescribeAvailabilityZonesOutput{AvailabilityZones: []types.AvailabilityZone{}}
+	for _, name := range f.zones {
+		zoneID := f.zoneID(name)
+		if len(params.ZoneNames) != 0 && !matchAny(params.ZoneNames, []string{name}) {
+			continue
+		}
+		if len(params.ZoneIds) != 0 && !matchAny(params.ZoneIds, []string{zoneID}) {
+			continue
+		}
+		zone := types.AvailabilityZone{
+			ZoneName:           aws.String(name),
+			ZoneId:             aws.String(zoneID),
+			ZoneType:           aws.String("availability-zone"),
+			RegionName:         aws.String(f.region),
+			GroupName:          aws.String(f.region),
+			NetworkBorderGroup: aws.String(f.region),
+			State:              types.AvailabilityZoneStateAvailable,
+			OptInStatus:        types.AvailabilityZoneOptInStatusOptInNotRequired,
+			Messages:           []types.AvailabilityZoneMessage{},
+		}
+		match, err := matchFilters(params.Filters, map[string][]string{
+			"zone-name":     {name},
+			"zone-id":       {zoneID},
+			"zone-type":     {aws.ToString(zone.ZoneType)},
+			"region-name":   {f.region},
+			"group-name":    {f.region},
+			"state":         {string(zone.State)},
+			"opt-in-status": {string(zone.OptInStatus)},
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			output.AvailabilityZones = append(output.AvailabilityZones, zone)
+		}
+	}
+	return output, nil
+}
+
+// DescribeInstanceTypeOfferings returns the instance types offered in the region, or in each
+// availability zone when LocationType asks for zones. Every zone offers the same types.
+func (f *EC2) DescribeInstanceTypeOfferings(ctx context.Context, params *ec2.DescribeInstanceTypeOfferingsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypeOfferingsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeInstanceTypeOfferingsInput{}
+	}
+	locationType := params.LocationType
+	if locationType == "" {
+		locationType = types.LocationTypeRegion
+	}
+	var locations []string
+	switch locationType {
+	case types.LocationTypeRegion:
+		locations = []string{f.region}
+	case types.LocationTypeAvailabilityZone:
+		locations = f.zones
+	case types.LocationTypeAvailabilityZoneId:
+		for _, zone := range f.zones {
+			locations = append(locations, f.zoneID(zone))
+		}
+	default:
+		return nil, apiError(awserrors.InvalidParameterValue, "The location type '%s' is not supported.", locationType)
+	}
+	output := &ec2.DescribeInstanceTypeOfferingsOutput{InstanceTypeOfferings: []types.InstanceTypeOffering{}}
+	for _, location := range locations {
+		for _, instanceType := range f.instanceTypes {
+			match, err := matchFilters(params.Filters, map[string][]string{
+				"location":      {location},
+				"instance-type": {instanceType},
+			}, nil)
+			if err != nil {
+				return nil, err
+			}
+			if match {
+				output.InstanceTypeOfferings = append(output.InstanceTypeOfferings, types.InstanceTypeOffering{
+					InstanceType: types.InstanceType(instanceType),
+					Location:     aws.String(location),
+					LocationType: locationType,
+				})
+			}
+		}
+	}
+	return output, nil
+}
+
+// DescribeReservedInstancesOfferings returns no offerings: reserved instances cannot be bought
+// from the fake.
+func (f *EC2) DescribeReservedInstancesOfferings(ctx context.Context, params *ec2.DescribeReservedInstancesOfferingsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeReservedInstancesOfferingsOutput, error) {
+	return &ec2.DescribeReservedInstancesOfferingsOutput{
+		ReservedInstancesOfferings: []types.ReservedInstancesOffering{},
+	}, nil
+}
@@ -0,0 +1,447 @@
+package aws_fake
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+func securityGroupFilterAttributes(group *types.SecurityGroup) map[string][]string {
+	return map[string][]string{
+		"group-id":    {aws.ToString(group.GroupId)},
+		"group-name":  {aws.ToString(group.GroupName)},
+		"description": {aws.ToString(group.Description)},
+		"owner-id":    {aws.ToString(group.OwnerId)},
+		"vpc-id":      {aws.ToString(group.VpcId)},
+	}
+}
+
+func securityGroupTags(group *types.SecurityGroup) []types.Tag {
+	return group.Tags
+}
+
+func securityGroupRuleFilterAttributes(rule *types.SecurityGroupRule) map[string][]string {
+	return map[string][]string{
+		"group-id":               {aws.ToString(rule.GroupId)},
+		"security-group-rule-id": {aws.ToString(rule.SecurityGroupRuleId)},
+	}
+}
+
+func securityGroupRuleTags(rule *types.SecurityGroupRule) []types.Tag {
+	return rule.Tags
+}
+
+// createSecurityGroup creates a security group with the default rule allowing all egress traffic.
+func (f *EC2) createSecurityGroup(vpcID string, name string, description string, tags []types.Tag) *types.SecurityGroup {
+	groupID := f.newID("sg")
+	group := &types.SecurityGroup{
+		GroupId:     aws.String(groupID),
+		GroupName:   aws.String(name),
+		Description: aws.String(description),
+		VpcId:       aws.String(vpcID),
+		OwnerId:     aws.String(f.accountID),
+		Tags:        tags,
+	}
+	f.securityGroups[groupID] = group
+	f.addSecurityGroupRule(types.SecurityGroupRule{
+		GroupId:    group.GroupId,
+		IsEgress:   aws.Bool(true),
+		IpProtocol: aws.String("-1"),
+		FromPort:   aws.Int32(-1),
+		ToPort:     aws.Int32(-1),
+		CidrIpv4:   aws.String("0.0.0.0/0"),
+	})
+	return group
+}
+
+func (f *EC2) defaultSecurityGroupID(vpcID string) string {
+	for _, groupID := range sortedIDs(f.securityGroups) {
+		group := f.securityGroups[groupID]
+		if aws.ToString(group.VpcId) == vpcID && aws.ToString(group.GroupName) == "default" {
+			return groupID
+		}
+	}
+	return ""
+}
+
+// findSecurityGroup returns the security group with the given ID or, when it is empty, name.
+func (f *EC2) findSecurityGroup(groupID *string, groupName *string) (*types.SecurityGroup, error) {
+	if groupID != nil {
+		group, ok := f.securityGroups[*groupID]
+		if !ok {
+			return nil, securityGroupNotFound(*groupID)
+		}
+		return group, nil
+	}
+	if groupName == nil {
+		return nil, missingParameter("groupId")
+	}
+	for _, id := range sortedIDs(f.securityGroups) {
+		if aws.ToString(f.securityGroups[id].GroupName) == *groupName {
+			return f.securityGroups[id], nil
+		}
+	}
+	return nil, securityGroupNotFound(*groupName)
+}
+
+// addSecurityGroupRule stores rule under a new ID and refreshes the permissions of its group.
+func (f *EC2) addSecurityGroupRule(rule types.SecurityGroupRule) types.SecurityGroupRule {
+	rule.SecurityGroupRuleId = aws.String(f.newID("sgr"))
+	rule.GroupOwnerId = aws.String(f.accountID)
+	f.securityGroupRules[*rule.SecurityGroupRuleId] = &rule
+	f.syncSecurityGroup(aws.ToString(rule.GroupId))
+	return rule
+}
+
+func (f *EC2) deleteSecurityGroupRules(groupID string) {
+	for id, rule := range f.securityGroupRules {
+		if aws.ToString(rule.GroupId) == groupID {
+			delete(f.securityGroupRules, id)
+		}
+	}
+}
+
+// syncSecurityGroup rebuilds the permissions of a security group from its rules.
+func (f *EC2) syncSecurityGroup(groupID string) {
+	group, ok := f.securityGroups[groupID]
+	if !ok {
+		return
+	}
+	group.IpPermissions = []types.IpPermission{}
+	group.IpPermissionsEgress = []types.IpPermission{}
+	for _, id := range sortedIDs(f.securityGroupRules) {
+		rule := f.securityGroupRules[id]
+		if aws.ToString(rule.GroupId) != groupID {
+			continue
+		}
+		permission := types.IpPermission{IpProtocol: rule.IpProtocol}
+		if aws.ToString(rule.IpProtocol) != "-1" {
+			permission.FromPort = rule.FromPort
+			permission.ToPort = rule.ToPort
+		}
+		switch {
+		case rule.CidrIpv4 != nil:
+			permission.IpRanges = []types.IpRange{{CidrIp: rule.CidrIpv4, Description: rule.Description}}
+		case rule.CidrIpv6 != nil:
+			permission.Ipv6Ranges = []types.Ipv6Range{{CidrIpv6: rule.CidrIpv6, Description: rule.Description}}
+		case rule.PrefixListId != nil:
+			permission.PrefixListIds = []types.PrefixListId{{PrefixListId: rule.PrefixListId, Description: rule.Description}}
+		case rule.ReferencedGroupInfo != nil:
+			permission.UserIdGroupPairs = []types.UserIdGroupPair{{
+				GroupId:     rule.ReferencedGroupInfo.GroupId,
+				UserId:      rule.ReferencedGroupInfo.UserId,
+				Description: rule.Description,
+			}}
+		}
+		if aws.ToBool(rule.IsEgress) {
+			group.IpPermissionsEgress = append(group.IpPermissionsEgress, permission)
+		} else {
+			group.IpPermissions = append(group.IpPermissions, permission)
+		}
+	}
+}
+
+// rulesFromPermissions expands permissions into one rule per source, as AWS does.
+func (f *EC2) rulesFromPermissions(group *types.SecurityGroup, egress bool, permissions []types.IpPermission) ([]types.SecurityGroupRule, error) {
+	rules := []types.SecurityGroupRule{}
+	for _, permission := range permissions {
+		protocol := strings.ToLower(aws.ToString(permission.IpProtocol))
+		if protocol == "" {
+			return nil, missingParameter("ipProtocol")
+		}
+		if protocol == "all" {
+			protocol = "-1"
+		}
+		base := types.SecurityGroupRule{
+			GroupId:    group.GroupId,
+			IsEgress:   aws.Bool(egress),
+			IpProtocol: aws.String(protocol),
+			FromPort:   permission.FromPort,
+			ToPort:     permission.ToPort,
+		}
+		if protocol == "-1" {
+			base.FromPort = aws.Int32(-1)
+			base.ToPort = aws.Int32(-1)
+		}
+		for _, ipRange := range permission.IpRanges {
+			rule := base
+			rule.CidrIpv4 = ipRange.CidrIp
+			rule.Description = ipRange.Description
+			rules = append(rules, rule)
+		}
+		for _, ipRange := range permission.Ipv6Ranges {
+			rule := base
+			rule.CidrIpv6 = ipRange.CidrIpv6
+			rule.Description = ipRange.Description
+			rules = append(rules, rule)
+		}
+		for _, prefixList := range permission.PrefixListIds {
+			rule := base
+			rule.PrefixListId = prefixList.PrefixListId
+			rule.Description = prefixList.Description
+			rules = append(rules, rule)
+		}
+		for _, pair := range permission.UserIdGroupPairs {
+			referenced, err := f.findSecurityGroup(pair.GroupId, pair.GroupName)
+			if err != nil {
+				return nil, err
+			}
+			rule := base
+			rule.ReferencedGroupInfo = &types.ReferencedSecurityGroup{
+				GroupId: referenced.GroupId,
+				UserId:  aws.String(f.accountID),
+			}
+			rule.Description = pair.Description
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// sameRule reports whether two rules allow the same traffic.
+func sameRule(a *types.SecurityGroupRule, b *types.SecurityGroupRule) bool {
+	referenced := func(rule *types.SecurityGroupRule) string {
+		if rule.ReferencedGroupInfo == nil {
+			return ""
+		}
+		return aws.ToString(rule.ReferencedGroupInfo.GroupId)
+	}
+	return aws.ToString(a.GroupId) == aws.ToString(b.GroupId) &&
+		aws.ToBool(a.IsEgress) == aws.ToBool(b.IsEgress) &&
+		aws.ToString(a.IpProtocol) == aws.ToString(b.IpProtocol) &&
+		aws.ToInt32(a.FromPort) == aws.ToInt32(b.FromPort) &&
+		aws.ToInt32(a.ToPort) == aws.ToInt32(b.ToPort) &&
+		aws.ToString(a.CidrIpv4) == aws.ToString(b.CidrIpv4) &&
+		aws.ToString(a.CidrIpv6) == aws.ToString(b.CidrIpv6) &&
+		aws.ToString(a.PrefixListId) == aws.ToString(b.PrefixListId) &&
+		referenced(a) == referenced(b)
+}
+
+// revokeRules deletes the rules of a group selected by rule IDs or by permissions.
+func (f *EC2) revokeRules(group *types.SecurityGroup, egress bool, ruleIDs []string, permissions []types.IpPermission) error {
+	selected := []string{}
+	for _, ruleID := range ruleIDs {
+		rule, ok := f.securityGroupRules[ruleID]
+		if !ok || aws.ToString(rule.GroupId) != aws.ToString(group.GroupId) || aws.ToBool(rule.IsEgress) != egress {
+			return securityGroupRuleNotFound(ruleID)
+		}
+		selected = append(selected, ruleID)
+	}
+	rules, err := f.rulesFromPermissions(group, egress, permissions)
+	if err != nil {
+		return err
+	}
+	for i := range rules {
+		found := false
+		for id, existing := range f.securityGroupRules {
+			if sameRule(existing, &rules[i]) {
+				selected = append(selected, id)
+				found = true
+			}
+		}
+		if !found {
+			return apiError(awserrors.InvalidPermissionNotFound, "The specified rule does not exist in this security group.")
+		}
+	}
+	if len(selected) == 0 {
+		return missingParameter("ipPermissions")
+	}
+	for _, id := range selected {
+		delete(f.securityGroupRules, id)
+	}
+	f.syncSecurityGroup(aws.ToString(group.GroupId))
+	return nil
+}
+
+// CreateSecurityGroup creates a security group in a VPC. Names are unique in a VPC.
+func (f *EC2) CreateSecurityGroup(ctx context.Context, params *ec2.CreateSecurityGroupInput, optFns ...func(*ec2.Options)) (*ec2.CreateSecurityGroupOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.GroupName == nil {
+		return nil, missingParameter("groupName")
+	}
+	if params.Description == nil {
+		return nil, missingParameter("groupDescription")
+	}
+	if params.VpcId == nil {
+		return nil, missingParameter("vpcId")
+	}
+	if _, ok := f.vpcs[*params.VpcId]; !ok {
+		return nil, vpcNotFound(*params.VpcId)
+	}
+	for _, group := range f.securityGroups {
+		if aws.ToString(group.VpcId) == *params.VpcId && aws.ToString(group.GroupName) == *params.GroupName {
+			return nil, apiError(awserrors.InvalidGroupDuplicate, "The security group '%s' already exists for VPC '%s'",
+				*params.GroupName, *params.VpcId)
+		}
+	}
+	group := f.createSecurityGroup(*params.VpcId, *params.GroupName, *params.Description,
+		tagsFor(params.TagSpecifications, types.ResourceTypeSecurityGroup))
+	return &ec2.CreateSecurityGroupOutput{
+		GroupId: group.GroupId,
+		Tags:    clone(group.Tags),
+	}, nil
+}
+
+// DescribeSecurityGroups returns the security groups selected by IDs, names and filters.
+func (f *EC2) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeSecurityGroupsInput{}
+	}
+	filters := params.Filters
+	for _, name := range params.GroupNames {
+		if _, err := f.findSecurityGroup(nil, aws.String(name)); err != nil {
+			return nil, err
+		}
+	}
+	if len(params.GroupNames) != 0 {
+		filters = append(append([]types.Filter{}, filters...), types.Filter{Name: aws.String("group-name"), Values: params.GroupNames})
+	}
+	groups, err := describe(f.securityGroups, params.GroupIds, filters, securityGroupNotFound,
+		securityGroupFilterAttributes, securityGroupTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: groups}, nil
+}
+
+// DeleteSecurityGroup deletes a security group that is neither the default group of its VPC,
+// used by a network interface nor referenced by the rules of another group.
+func (f *EC2) DeleteSecurityGroup(ctx context.Context, params *ec2.DeleteSecurityGroupInput, optFns ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	group, err := f.findSecurityGroup(params.GroupId, params.GroupName)
+	if err != nil {
+		return nil, err
+	}
+	groupID := aws.ToString(group.GroupId)
+	if aws.ToString(group.GroupName) == "default" {
+		return nil, apiError(awserrors.CannotDelete, "the specified group: \"%s\" name: \"default\" cannot be deleted by a user", groupID)
+	}
+	for _, networkInterface := range f.networkInterfaces {
+		for _, attached := range networkInterface.Groups {
+			if aws.ToString(attached.GroupId) == groupID {
+				return nil, apiError(awserrors.DependencyViolation, "resource %s has a dependent object", groupID)
+			}
+		}
+	}
+	for _, rule := range f.securityGroupRules {
+		if rule.ReferencedGroupInfo != nil && aws.ToString(rule.ReferencedGroupInfo.GroupId) == groupID &&
+			aws.ToString(rule.GroupId) != groupID {
+			return nil, apiError(awserrors.DependencyViolation, "resource %s has a dependent object", groupID)
+		}
+	}
+	f.deleteSecurityGroupRules(groupID)
+	delete(f.securityGroups, groupID)
+	return &ec2.DeleteSecurityGroupOutput{}, nil
+}
+
+// AuthorizeSecurityGroupIngress adds ingress rules given either as permissions or with the
+// single CIDR parameters.
+func (f *EC2) AuthorizeSecurityGroupIngress(ctx context.Context, params *ec2.AuthorizeSecurityGroupIngressInput, optFns ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	group, err := f.findSecurityGroup(params.GroupId, params.GroupName)
+	if err != nil {
+		return nil, err
+	}
+	permissions := params.IpPermissions
+	if len(permissions) == 0 {
+		permission := types.IpPermission{
+			IpProtocol: params.IpProtocol,
+			FromPort:   params.FromPort,
+			ToPort:     params.ToPort,
+		}
+		if params.CidrIp != nil {
+			permission.IpRanges = []types.IpRange{{CidrIp: params.CidrIp}}
+		}
+		if params.SourceSecurityGroupName != nil {
+			permission.UserIdGroupPairs = []types.UserIdGroupPair{{GroupName: params.SourceSecurityGroupName}}
+		}
+		permissions = []types.IpPermission{permission}
+	}
+	rules, err := f.rulesFromPermissions(group, false, permissions)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, missingParameter("ipPermissions")
+	}
+	for i := range rules {
+		for _, existing := range f.securityGroupRules {
+			if sameRule(existing, &rules[i]) {
+				return nil, apiError(awserrors.InvalidPermissionDuplicate,
+					"the specified rule \"peer: %s, %s, from port: %d, to port: %d, ALLOW\" already exists",
+					aws.ToString(rules[i].CidrIpv4), aws.ToString(rules[i].IpProtocol),
+					aws.ToInt32(rules[i].FromPort), aws.ToInt32(rules[i].ToPort))
+			}
+		}
+	}
+	output := &ec2.AuthorizeSecurityGroupIngressOutput{Return: aws.Bool(true)}
+	tags := tagsFor(params.TagSpecifications, types.ResourceTypeSecurityGroupRule)
+	for _, rule := range rules {
+		rule.Tags = clone(tags)
+		output.SecurityGroupRules = append(output.SecurityGroupRules, clone(f.addSecurityGroupRule(rule)))
+	}
+	return output, nil
+}
+
+// RevokeSecurityGroupIngress deletes ingress rules selected by rule IDs, permissions or the single
+// CIDR parameters.
+func (f *EC2) RevokeSecurityGroupIngress(ctx context.Context, params *ec2.RevokeSecurityGroupIngressInput, optFns ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	group, err := f.findSecurityGroup(params.GroupId, params.GroupName)
+	if err != nil {
+		return nil, err
+	}
+	permissions := params.IpPermissions
+	if len(permissions) == 0 && params.CidrIp != nil {
+		permissions = []types.IpPermission{{
+			IpProtocol: params.IpProtocol,
+			FromPort:   params.FromPort,
+			ToPort:     params.ToPort,
+			IpRanges:   []types.IpRange{{CidrIp: params.CidrIp}},
+		}}
+	}
+	if err := f.revokeRules(group, false, params.SecurityGroupRuleIds, permissions); err != nil {
+		return nil, err
+	}
+	return &ec2.RevokeSecurityGroupIngressOutput{Return: aws.Bool(true)}, nil
+}
+
+// RevokeSecurityGroupEgress deletes egress rules selected by rule IDs or permissions.
+func (f *EC2) RevokeSecurityGroupEgress(ctx context.Context, params *ec2.RevokeSecurityGroupEgressInput, optFns ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupEgressOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	group, err := f.findSecurityGroup(params.GroupId, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.revokeRules(group, true, params.SecurityGroupRuleIds, params.IpPermissions); err != nil {
+		return nil, err
+	}
+	return &ec2.RevokeSecurityGroupEgressOutput{Return: aws.Bool(true)}, nil
+}
+
+// DescribeSecurityGroupRules returns the security group rules selected by IDs and filters.
+func (f *EC2) DescribeSecurityGroupRules(ctx context.Context, params *ec2.DescribeSecurityGroupRulesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupRulesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeSecurityGroupRulesInput{}
+	}
+	rules, err := describe(f.securityGroupRules, params.SecurityGroupRuleIds, params.Filters, securityGroupRuleNotFound,
+		securityGroupRuleFilterAttributes, securityGroupRuleTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeSecurityGroupRulesOutput{SecurityGroupRules: rules}, nil
+}
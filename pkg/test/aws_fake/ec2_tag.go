@@ -0,0 +1,200 @@
+package aws_fake
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+// tagsOf returns the tags of the resource with the given ID, using the ID prefix to find its kind.
+func (f *EC2) tagsOf(id string) (*[]types.Tag, error) {
+	separator := strings.LastIndex(id, "-")
+	if separator <= 0 {
+		return nil, apiError(awserrors.InvalidID, "The ID '%s' is not valid", id)
+	}
+	switch id[:separator] {
+	case "vpc":
+		if vpc, ok := f.vpcs[id]; ok {
+			return &vpc.Tags, nil
+		}
+		return nil, vpcNotFound(id)
+	case "subnet":
+		if subnet, ok := f.subnets[id]; ok {
+			return &subnet.Tags, nil
+		}
+		return nil, subnetNotFound(id)
+	case "rtb":
+		if routeTable, ok := f.routeTables[id]; ok {
+			return &routeTable.Tags, nil
+		}
+		return nil, routeTableNotFound(id)
+	case "igw":
+		if gateway, ok := f.internetGateways[id]; ok {
+			return &gateway.Tags, nil
+		}
+		return nil, internetGatewayNotFound(id)
+	case "eigw":
+		if gateway, ok := f.egressOnlyGateways[id]; ok {
+			return &gateway.Tags, nil
+		}
+		return nil, egressOnlyGatewayNotFound(id)
+	case "nat":
+		if gateway, ok := f.natGateways[id]; ok {
+			return &gateway.Tags, nil
+		}
+		return nil, natGatewayNotFound(id)
+	case "eipalloc":
+		if address, ok := f.addresses[id]; ok {
+			return &address.Tags, nil
+		}
+		return nil, allocationNotFound(id)
+	case "sg":
+		if group, ok := f.securityGroups[id]; ok {
+			return &group.Tags, nil
+		}
+		return nil, securityGroupNotFound(id)
+	case "sgr":
+		if rule, ok := f.securityGroupRules[id]; ok {
+			return &rule.Tags, nil
+		}
+		return nil, securityGroupRuleNotFound(id)
+	case "eni":
+		if networkInterface, ok := f.networkInterfaces[id]; ok {
+			return &networkInterface.TagSet, nil
+		}
+		return nil, networkInterfaceNotFound(id)
+	case "i":
+		if instance, ok := f.instances[id]; ok {
+			return &instance.Tags, nil
+		}
+		return nil, instanceNotFound(id)
+	case "acl":
+		if acl, ok := f.networkAcls[id]; ok {
+			return &acl.Tags, nil
+		}
+		return nil, networkAclNotFound(id)
+	case "vpce":
+		if endpoint, ok := f.vpcEndpoints[id]; ok {
+			return &endpoint.Tags, nil
+		}
+		return nil, vpcEndpointNotFound(id)
+	case "key":
+		keyPair, err := f.findKeyPair(aws.String(id), nil)
+		if err != nil {
+			return nil, err
+		}
+		return &keyPair.Tags, nil
+	case "vol":
+		if volume, ok := f.volumes[id]; ok {
+			return &volume.Tags, nil
+		}
+		return nil, volumeNotFound(id)
+	case "ami":
+		if image, ok := f.images[id]; ok {
+			return &image.Tags, nil
+		}
+		return nil, imageNotFound(id)
+	case "cr":
+		if reservation, ok := f.capacityReservations[id]; ok {
+			return &reservation.Tags, nil
+		}
+		return nil, capacityReservationNotFound(id)
+	case "pcx":
+		if connection, ok := f.peeringConnections[id]; ok {
+			return &connection.Tags, nil
+		}
+		return nil, peeringConnectionNotFound(id)
+	case "tgw":
+		if gateway, ok := f.transitGateways[id]; ok {
+			return &gateway.Tags, nil
+		}
+		return nil, transitGatewayNotFound(id)
+	case "tgw-attach":
+		if attachment, ok := f.transitAttachments[id]; ok {
+			return &attachment.Tags, nil
+		}
+		return nil, transitAttachmentNotFound(id)
+	case "tgw-rtb":
+		if routeTable, ok := f.transitRouteTables[id]; ok {
+			return &routeTable.Tags, nil
+		}
+		return nil, transitRouteTableNotFound(id)
+	}
+	return nil, apiError(awserrors.InvalidID, "The ID '%s' is not valid", id)
+}
+
+// CreateTags adds or overwrites tags on resources. Every resource is checked before any is tagged.
+func (f *EC2) CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if len(params.Resources) == 0 {
+		return nil, missingParameter("resourceIdSet")
+	}
+	targets := []*[]types.Tag{}
+	for _, id := range params.Resources {
+		tags, err := f.tagsOf(id)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, tags)
+	}
+	for _, tags := range targets {
+		for _, tag := range params.Tags {
+			replaced := false
+			for i := range *tags {
+				if aws.ToString((*tags)[i].Key) == aws.ToString(tag.Key) {
+					(*tags)[i].Value = aws.String(aws.ToString(tag.Value))
+					replaced = true
+				}
+			}
+			if !replaced {
+				*tags = append(*tags, types.Tag{Key: aws.String(aws.ToString(tag.Key)), Value: aws.String(aws.ToString(tag.Value))})
+			}
+		}
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+// DeleteTags removes tags from resources. A tag given with a value is only removed when the value
+// matches; without tags every tag is removed.
+func (f *EC2) DeleteTags(ctx context.Context, params *ec2.DeleteTagsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if len(params.Resources) == 0 {
+		return nil, missingParameter("resourceIdSet")
+	}
+	targets := []*[]types.Tag{}
+	for _, id := range params.Resources {
+		tags, err := f.tagsOf(id)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, tags)
+	}
+	for _, tags := range targets {
+		if len(params.Tags) == 0 {
+			*tags = []types.Tag{}
+			continue
+		}
+		kept := []types.Tag{}
+		for _, existing := range *tags {
+			removed := false
+			for _, tag := range params.Tags {
+				if aws.ToString(tag.Key) == aws.ToString(existing.Key) &&
+					(tag.Value == nil || aws.ToString(tag.Value) == aws.ToString(existing.Value)) {
+					removed = true
+				}
+			}
+			if !removed {
+				kept = append(kept, existing)
+			}
+		}
+		*tags = kept
+	}
+	return &ec2.DeleteTagsOutput{}, nil
+}
@@ -0,0 +1,770 @@
+package aws_fake
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+func peeringConnectionFilterAttributes(connection *types.VpcPeeringConnection) map[string][]string {
+	return map[string][]string{
+		"vpc-peering-connection-id":     {aws.ToString(connection.VpcPeeringConnectionId)},
+		"requester-vpc-info.vpc-id":     {aws.ToString(connection.RequesterVpcInfo.VpcId)},
+		"accepter-vpc-info.vpc-id":      {aws.ToString(connection.AccepterVpcInfo.VpcId)},
+		"requester-vpc-info.cidr-block": {aws.ToString(connection.RequesterVpcInfo.CidrBlock)},
+		"accepter-vpc-info.cidr-block":  {aws.ToString(connection.AccepterVpcInfo.CidrBlock)},
+		"status-code":                   {string(connection.Status.Code)},
+	}
+}
+
+func peeringConnectionTags(connection *types.VpcPeeringConnection) []types.Tag {
+	return connection.Tags
+}
+
+// peeringVpcInfo describes one side of a peering connection.
+func (f *EC2) peeringVpcInfo(vpc *types.Vpc) *types.VpcPeeringConnectionVpcInfo {
+	return &types.VpcPeeringConnectionVpcInfo{
+		VpcId:        vpc.VpcId,
+		OwnerId:      aws.String(f.accountID),
+		Region:       aws.String(f.region),
+		CidrBlock:    vpc.CidrBlock,
+		CidrBlockSet: []types.CidrBlock{{CidrBlock: vpc.CidrBlock}},
+	}
+}
+
+// CreateVpcPeeringConnection requests a peering connection between two VPCs of the fake. Like on
+// AWS the connection is returned initiating the request, and is described pending acceptance until
+// AcceptVpcPeeringConnection. When the VPCs overlap it fails right away as it does on AWS.
+func (f *EC2) CreateVpcPeeringConnection(ctx context.Context, params *ec2.CreateVpcPeeringConnectionInput, optFns ...func(*ec2.Options)) (*ec2.CreateVpcPeeringConnectionOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.VpcId == nil {
+		return nil, missingParameter("vpcId")
+	}
+	if params.PeerVpcId == nil {
+		return nil, missingParameter("peerVpcId")
+	}
+	requester, ok := f.vpcs[*params.VpcId]
+	if !ok {
+		return nil, vpcNotFound(*params.VpcId)
+	}
+	accepter, ok := f.vpcs[*params.PeerVpcId]
+	if !ok {
+		return nil, vpcNotFound(*params.PeerVpcId)
+	}
+	connection := &types.VpcPeeringConnection{
+		VpcPeeringConnectionId: aws.String(f.newID("pcx")),
+		RequesterVpcInfo:       f.peeringVpcInfo(requester),
+		AccepterVpcInfo:        f.peeringVpcInfo(accepter),
+		Status: &types.VpcPeeringConnectionStateReason{
+			Code:    types.VpcPeeringConnectionStateReasonCodePendingAcceptance,
+			Message: aws.String(fmt.Sprintf("Pending Acceptance by %s", f.accountID)),
+		},
+		Tags: tagsFor(params.TagSpecifications, types.ResourceTypeVpcPeeringConnection),
+	}
+	_, requesterNetwork, _ := net.ParseCIDR(aws.ToString(requester.CidrBlock))
+	_, accepterNetwork, _ := net.ParseCIDR(aws.ToString(accepter.CidrBlock))
+	if overlaps(requesterNetwork, accepterNetwork) {
+		connection.Status = &types.VpcPeeringConnectionStateReason{
+			Code:    types.VpcPeeringConnectionStateReasonCodeFailed,
+			Message: aws.String("Overlapping CIDRs"),
+		}
+	}
+	f.peeringConnections[aws.ToString(connection.VpcPeeringConnectionId)] = connection
+	created := cloned(connection)
+	if created.Status.Code == types.VpcPeeringConnectionStateReasonCodePendingAcceptance {
+		created.Status = &types.VpcPeeringConnectionStateReason{
+			Code:    types.VpcPeeringConnectionStateReasonCodeInitiatingRequest,
+			Message: aws.String(fmt.Sprintf("Initiating Request to %s", f.accountID)),
+		}
+	}
+	return &ec2.CreateVpcPeeringConnectionOutput{VpcPeeringConnection: created}, nil
+}
+
+// AcceptVpcPeeringConnection activates a peering connection pending acceptance.
+func (f *EC2) AcceptVpcPeeringConnection(ctx context.Context, params *ec2.AcceptVpcPeeringConnectionInput, optFns ...func(*ec2.Options)) (*ec2.AcceptVpcPeeringConnectionOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	connectionID := aws.ToString(params.VpcPeeringConnectionId)
+	connection, ok := f.peeringConnections[connectionID]
+	if !ok {
+		return nil, peeringConnectionNotFound(connectionID)
+	}
+	if connection.Status.Code != types.VpcPeeringConnectionStateReasonCodePendingAcceptance {
+		return nil, apiError(awserrors.OperationNotPermitted,
+			"The vpcPeeringConnection %s is not in the correct state (%s) for this operation", connectionID, connection.Status.Code)
+	}
+	connection.Status = &types.VpcPeeringConnectionStateReason{
+		Code:    types.VpcPeeringConnectionStateReasonCodeActive,
+		Message: aws.String("Active"),
+	}
+	return &ec2.AcceptVpcPeeringConnectionOutput{VpcPeeringConnection: cloned(connection)}, nil
+}
+
+// DescribeVpcPeeringConnections returns the peering connections selected by IDs and filters.
+func (f *EC2) DescribeVpcPeeringConnections(ctx context.Context, params *ec2.DescribeVpcPeeringConnectionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcPeeringConnectionsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeVpcPeeringConnectionsInput{}
+	}
+	connections, err := describe(f.peeringConnections, params.VpcPeeringConnectionIds, params.Filters,
+		peeringConnectionNotFound, peeringConnectionFilterAttributes, peeringConnectionTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeVpcPeeringConnectionsOutput{VpcPeeringConnections: connections}, nil
+}
+
+// DeleteVpcPeeringConnection deletes a peering connection. Like on AWS, deleted connections stay
+// visible in the deleted state.
+func (f *EC2) DeleteVpcPeeringConnection(ctx context.Context, params *ec2.DeleteVpcPeeringConnectionInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVpcPeeringConnectionOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	connectionID := aws.ToString(params.VpcPeeringConnectionId)
+	connection, ok := f.peeringConnections[connectionID]
+	if !ok || connection.Status.Code == types.VpcPeeringConnectionStateReasonCodeDeleted {
+		return nil, peeringConnectionNotFound(connectionID)
+	}
+	connection.Status = &types.VpcPeeringConnectionStateReason{
+		Code:    types.VpcPeeringConnectionStateReasonCodeDeleted,
+		Message: aws.String(fmt.Sprintf("Deleted by %s", f.accountID)),
+	}
+	for _, routeTable := range f.routeTables {
+		routes := []types.Route{}
+		for _, route := range routeTable.Routes {
+			if aws.ToString(route.VpcPeeringConnectionId) != connectionID {
+				routes = append(routes, route)
+			}
+		}
+		routeTable.Routes = routes
+	}
+	return &ec2.DeleteVpcPeeringConnectionOutput{Return: aws.Bool(true)}, nil
+}
+
+func transitGatewayFilterAttributes(gateway *types.TransitGateway) map[string][]string {
+	return map[string][]string{
+		"transit-gateway-id": {aws.ToString(gateway.TransitGatewayId)},
+		"owner-id":           {aws.ToString(gateway.OwnerId)},
+		"state":              {string(gateway.State)},
+	}
+}
+
+func transitGatewayTags(gateway *types.TransitGateway) []types.Tag {
+	return gateway.Tags
+}
+
+// CreateTransitGateway creates an available transit gateway. Like on AWS, default route table
+// association and propagation are enabled unless disabled in the options, and then the gateway
+// gets a default route table the new attachments are associated with and propagate to.
+func (f *EC2) CreateTransitGateway(ctx context.Context, params *ec2.CreateTransitGatewayInput, optFns ...func(*ec2.Options)) (*ec2.CreateTransitGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	gatewayID := f.newID("tgw")
+	options := &types.TransitGatewayOptions{
+		AmazonSideAsn:                aws.Int64(64512),
+		DefaultRouteTableAssociation: types.DefaultRouteTableAssociationValueEnable,
+		DefaultRouteTablePropagation: types.DefaultRouteTablePropagationValueEnable,
+	}
+	if params.Options != nil {
+		if params.Options.AmazonSideAsn != nil {
+			options.AmazonSideAsn = params.Options.AmazonSideAsn
+		}
+		if params.Options.DefaultRouteTableAssociation != "" {
+			options.DefaultRouteTableAssociation = params.Options.DefaultRouteTableAssociation
+		}
+		if params.Options.DefaultRouteTablePropagation != "" {
+			options.DefaultRouteTablePropagation = params.Options.DefaultRouteTablePropagation
+		}
+	}
+	associate := options.DefaultRouteTableAssociation == types.DefaultRouteTableAssociationValueEnable
+	propagate := options.DefaultRouteTablePropagation == types.DefaultRouteTablePropagationValueEnable
+	if associate || propagate {
+		routeTable := f.createTransitRouteTable(gatewayID, nil)
+		routeTable.DefaultAssociationRouteTable = aws.Bool(associate)
+		routeTable.DefaultPropagationRouteTable = aws.Bool(propagate)
+		if associate {
+			options.AssociationDefaultRouteTableId = routeTable.TransitGatewayRouteTableId
+		}
+		if propagate {
+			options.PropagationDefaultRouteTableId = routeTable.TransitGatewayRouteTableId
+		}
+	}
+	gateway := &types.TransitGateway{
+		TransitGatewayId:  aws.String(gatewayID),
+		TransitGatewayArn: aws.String(f.arn("transit-gateway", gatewayID)),
+		Description:       params.Description,
+		OwnerId:           aws.String(f.accountID),
+		State:             types.TransitGatewayStateAvailable,
+		CreationTime:      f.now(),
+		Options:           options,
+		Tags:              tagsFor(params.TagSpecifications, types.ResourceTypeTransitGateway),
+	}
+	f.transitGateways[gatewayID] = gateway
+	return &ec2.CreateTransitGatewayOutput{TransitGateway: cloned(gateway)}, nil
+}
+
+// DescribeTransitGateways returns the transit gateways selected by IDs and filters.
+func (f *EC2) DescribeTransitGateways(ctx context.Context, params *ec2.DescribeTransitGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewaysOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeTransitGatewaysInput{}
+	}
+	gateways, err := describe(f.transitGateways, params.TransitGatewayIds, params.Filters,
+		transitGatewayNotFound, transitGatewayFilterAttributes, transitGatewayTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeTransitGatewaysOutput{TransitGateways: gateways}, nil
+}
+
+// DeleteTransitGateway deletes a transit gateway without attachments. Deleted gateways stay
+// visible in the deleted state.
+func (f *EC2) DeleteTransitGateway(ctx context.Context, params *ec2.DeleteTransitGatewayInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTransitGatewayOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	gatewayID := aws.ToString(params.TransitGatewayId)
+	gateway, ok := f.transitGateways[gatewayID]
+	if !ok || gateway.State == types.TransitGatewayStateDeleted {
+		return nil, transitGatewayNotFound(gatewayID)
+	}
+	for _, attachment := range f.transitAttachments {
+		if aws.ToString(attachment.TransitGatewayId) == gatewayID && attachment.State != types.TransitGatewayAttachmentStateDeleted {
+			return nil, apiError(awserrors.IncorrectState,
+				"%s has non-deleted Transit Gateway Attachments: %s.", gatewayID, aws.ToString(attachment.TransitGatewayAttachmentId))
+		}
+	}
+	for _, routeTable := range f.transitRouteTables {
+		if aws.ToString(routeTable.TransitGatewayId) == gatewayID {
+			routeTable.State = types.TransitGatewayRouteTableStateDeleted
+		}
+	}
+	gateway.State = types.TransitGatewayStateDeleted
+	return &ec2.DeleteTransitGatewayOutput{TransitGateway: cloned(gateway)}, nil
+}
+
+func transitAttachmentFilterAttributes(attachment *types.TransitGatewayVpcAttachment) map[string][]string {
+	return map[string][]string{
+		"transit-gateway-attachment-id": {aws.ToString(attachment.TransitGatewayAttachmentId)},
+		"transit-gateway-id":            {aws.ToString(attachment.TransitGatewayId)},
+		"vpc-id":                        {aws.ToString(attachment.VpcId)},
+		"state":                         {string(attachment.State)},
+	}
+}
+
+func transitAttachmentTags(attachment *types.TransitGatewayVpcAttachment) []types.Tag {
+	return attachment.Tags
+}
+
+// CreateTransitGatewayVpcAttachment attaches a VPC to a transit gateway. The attachment gets a
+// requester managed network interface in each of its subnets, which must be in distinct zones.
+func (f *EC2) CreateTransitGatewayVpcAttachment(ctx context.Context, params *ec2.CreateTransitGatewayVpcAttachmentInput, optFns ...func(*ec2.Options)) (*ec2.CreateTransitGatewayVpcAttachmentOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	gatewayID := aws.ToString(params.TransitGatewayId)
+	gateway, ok := f.transitGateways[gatewayID]
+	if !ok || gateway.State == types.TransitGatewayStateDeleted {
+		return nil, transitGatewayNotFound(gatewayID)
+	}
+	vpcID := aws.ToString(params.VpcId)
+	if _, ok := f.vpcs[vpcID]; !ok {
+		return nil, vpcNotFound(vpcID)
+	}
+	if len(params.SubnetIds) == 0 {
+		return nil, missingParameter("SubnetIds")
+	}
+	for _, attachment := range f.transitAttachments {
+		if aws.ToString(attachment.TransitGatewayId) == gatewayID && aws.ToString(attachment.VpcId) == vpcID &&
+			attachment.State != types.TransitGatewayAttachmentStateDeleted {
+			return nil, apiError(awserrors.DuplicateTransitGatewayAttachment,
+				"%s has non-deleted Transit Gateway Attachments with same VPC ID.", gatewayID)
+		}
+	}
+	zones := map[string]bool{}
+	for _, subnetID := range params.SubnetIds {
+		subnet, ok := f.subnets[subnetID]
+		if !ok || aws.ToString(subnet.VpcId) != vpcID {
+			return nil, subnetNotFound(subnetID)
+		}
+		zone := aws.ToString(subnet.AvailabilityZone)
+		if zones[zone] {
+			return nil, apiError(awserrors.InvalidParameterValue, "Only one subnet per availability zone is allowed: %s", zone)
+		}
+		zones[zone] = true
+	}
+	attachmentID := f.newID("tgw-attach")
+	for _, subnetID := range params.SubnetIds {
+		f.createNetworkInterface(f.subnets[subnetID], types.NetworkInterfaceTypeTransitGateway,
+			fmt.Sprintf("Network Interface for Transit Gateway Attachment %s", attachmentID), nil, true)
+	}
+	attachment := &types.TransitGatewayVpcAttachment{
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+		TransitGatewayId:           aws.String(gatewayID),
+		VpcId:                      aws.String(vpcID),
+		VpcOwnerId:                 aws.String(f.accountID),
+		State:                      types.TransitGatewayAttachmentStateAvailable,
+		SubnetIds:                  append([]string{}, params.SubnetIds...),
+		CreationTime:               f.now(),
+		Tags:                       tagsFor(params.TagSpecifications, types.ResourceTypeTransitGatewayAttachment),
+	}
+	f.transitAttachments[attachmentID] = attachment
+	if routeTableID := aws.ToString(gateway.Options.AssociationDefaultRouteTableId); routeTableID != "" {
+		f.transitAssociations[attachmentID] = routeTableID
+	}
+	if routeTableID := aws.ToString(gateway.Options.PropagationDefaultRouteTableId); routeTableID != "" {
+		f.transitPropagations[routeTableID][attachmentID] = true
+	}
+	return &ec2.CreateTransitGatewayVpcAttachmentOutput{TransitGatewayVpcAttachment: cloned(attachment)}, nil
+}
+
+// transitGatewayAttached reports whether the VPC has a live attachment to the transit gateway.
+func (f *EC2) transitGatewayAttached(gatewayID string, vpcID string) bool {
+	for _, attachment := range f.transitAttachments {
+		if aws.ToString(attachment.TransitGatewayId) == gatewayID && aws.ToString(attachment.VpcId) == vpcID &&
+			attachment.State != types.TransitGatewayAttachmentStateDeleted {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeTransitGatewayVpcAttachments returns the VPC attachments selected by IDs and filters.
+func (f *EC2) DescribeTransitGatewayVpcAttachments(ctx context.Context, params *ec2.DescribeTransitGatewayVpcAttachmentsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayVpcAttachmentsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeTransitGatewayVpcAttachmentsInput{}
+	}
+	attachments, err := describe(f.transitAttachments, params.TransitGatewayAttachmentIds, params.Filters,
+		transitAttachmentNotFound, transitAttachmentFilterAttributes, transitAttachmentTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeTransitGatewayVpcAttachmentsOutput{TransitGatewayVpcAttachments: attachments}, nil
+}
+
+// DeleteTransitGatewayVpcAttachment deletes a VPC attachment and its network interfaces. Deleted
+// attachments stay visible in the deleted state.
+func (f *EC2) DeleteTransitGatewayVpcAttachment(ctx context.Context, params *ec2.DeleteTransitGatewayVpcAttachmentInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTransitGatewayVpcAttachmentOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	attachmentID := aws.ToString(params.TransitGatewayAttachmentId)
+	attachment, ok := f.transitAttachments[attachmentID]
+	if !ok || attachment.State == types.TransitGatewayAttachmentStateDeleted {
+		return nil, transitAttachmentNotFound(attachmentID)
+	}
+	description := fmt.Sprintf("Network Interface for Transit Gateway Attachment %s", attachmentID)
+	for _, networkInterfaceID := range sortedIDs(f.networkInterfaces) {
+		if aws.ToString(f.networkInterfaces[networkInterfaceID].Description) == description {
+			f.deleteNetworkInterface(networkInterfaceID)
+		}
+	}
+	delete(f.transitAssociations, attachmentID)
+	for _, propagations := range f.transitPropagations {
+		delete(propagations, attachmentID)
+	}
+	attachment.State = types.TransitGatewayAttachmentStateDeleted
+	return &ec2.DeleteTransitGatewayVpcAttachmentOutput{TransitGatewayVpcAttachment: cloned(attachment)}, nil
+}
+
+func transitRouteTableFilterAttributes(routeTable *types.TransitGatewayRouteTable) map[string][]string {
+	return map[string][]string{
+		"transit-gateway-route-table-id":  {aws.ToString(routeTable.TransitGatewayRouteTableId)},
+		"transit-gateway-id":              {aws.ToString(routeTable.TransitGatewayId)},
+		"state":                           {string(routeTable.State)},
+		"default-association-route-table": boolValue(aws.ToBool(routeTable.DefaultAssociationRouteTable)),
+		"default-propagation-route-table": boolValue(aws.ToBool(routeTable.DefaultPropagationRouteTable)),
+	}
+}
+
+func transitRouteTableTags(routeTable *types.TransitGatewayRouteTable) []types.Tag {
+	return routeTable.Tags
+}
+
+func (f *EC2) createTransitRouteTable(gatewayID string, tags []types.Tag) *types.TransitGatewayRouteTable {
+	routeTableID := f.newID("tgw-rtb")
+	routeTable := &types.TransitGatewayRouteTable{
+		TransitGatewayRouteTableId:   aws.String(routeTableID),
+		TransitGatewayId:             aws.String(gatewayID),
+		State:                        types.TransitGatewayRouteTableStateAvailable,
+		DefaultAssociationRouteTable: aws.Bool(false),
+		DefaultPropagationRouteTable: aws.Bool(false),
+		CreationTime:                 f.now(),
+		Tags:                         tags,
+	}
+	f.transitRouteTables[routeTableID] = routeTable
+	f.transitPropagations[routeTableID] = map[string]bool{}
+	return routeTable
+}
+
+// transitRouteTable returns the route table unless it is missing or deleted.
+func (f *EC2) transitRouteTable(routeTableID string) (*types.TransitGatewayRouteTable, error) {
+	routeTable, ok := f.transitRouteTables[routeTableID]
+	if !ok || routeTable.State == types.TransitGatewayRouteTableStateDeleted {
+		return nil, transitRouteTableNotFound(routeTableID)
+	}
+	return routeTable, nil
+}
+
+// transitAttachment returns the attachment unless it is missing or deleted, checking it belongs
+// to the transit gateway of the route table.
+func (f *EC2) transitAttachment(attachmentID string, routeTable *types.TransitGatewayRouteTable) (*types.TransitGatewayVpcAttachment, error) {
+	attachment, ok := f.transitAttachments[attachmentID]
+	if !ok || attachment.State == types.TransitGatewayAttachmentStateDeleted {
+		return nil, transitAttachmentNotFound(attachmentID)
+	}
+	if aws.ToString(attachment.TransitGatewayId) != aws.ToString(routeTable.TransitGatewayId) {
+		return nil, apiError(awserrors.InvalidParameterValue, "Transit Gateway Attachment %s and route table %s belong to different transit gateways.",
+			attachmentID, aws.ToString(routeTable.TransitGatewayRouteTableId))
+	}
+	return attachment, nil
+}
+
+// CreateTransitGatewayRouteTable creates a route table of a transit gateway.
+func (f *EC2) CreateTransitGatewayRouteTable(ctx context.Context, params *ec2.CreateTransitGatewayRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.CreateTransitGatewayRouteTableOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	gatewayID := aws.ToString(params.TransitGatewayId)
+	gateway, ok := f.transitGateways[gatewayID]
+	if !ok || gateway.State == types.TransitGatewayStateDeleted {
+		return nil, transitGatewayNotFound(gatewayID)
+	}
+	routeTable := f.createTransitRouteTable(gatewayID, tagsFor(params.TagSpecifications, types.ResourceTypeTransitGatewayRouteTable))
+	return &ec2.CreateTransitGatewayRouteTableOutput{TransitGatewayRouteTable: cloned(routeTable)}, nil
+}
+
+// DescribeTransitGatewayRouteTables returns the transit gateway route tables selected by IDs and
+// filters.
+func (f *EC2) DescribeTransitGatewayRouteTables(ctx context.Context, params *ec2.DescribeTransitGatewayRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayRouteTablesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeTransitGatewayRouteTablesInput{}
+	}
+	routeTables, err := describe(f.transitRouteTables, params.TransitGatewayRouteTableIds, params.Filters,
+		transitRouteTableNotFound, transitRouteTableFilterAttributes, transitRouteTableTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeTransitGatewayRouteTablesOutput{TransitGatewayRouteTables: routeTables}, nil
+}
+
+// DeleteTransitGatewayRouteTable deletes a transit gateway route table without associations.
+// Deleted route tables stay visible in the deleted state.
+func (f *EC2) DeleteTransitGatewayRouteTable(ctx context.Context, params *ec2.DeleteTransitGatewayRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTransitGatewayRouteTableOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.TransitGatewayRouteTableId)
+	routeTable, err := f.transitRouteTable(routeTableID)
+	if err != nil {
+		return nil, err
+	}
+	for _, attachmentID := range sortedIDs(f.transitAssociations) {
+		if f.transitAssociations[attachmentID] == routeTableID {
+			return nil, apiError(awserrors.IncorrectState,
+				"%s has associations: %s.", routeTableID, attachmentID)
+		}
+	}
+	routeTable.State = types.TransitGatewayRouteTableStateDeleted
+	delete(f.transitPropagations, routeTableID)
+	delete(f.transitRoutes, routeTableID)
+	return &ec2.DeleteTransitGatewayRouteTableOutput{TransitGatewayRouteTable: cloned(routeTable)}, nil
+}
+
+// AssociateTransitGatewayRouteTable associates an attachment with a route table. An attachment
+// is associated with one route table at most.
+func (f *EC2) AssociateTransitGatewayRouteTable(ctx context.Context, params *ec2.AssociateTransitGatewayRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.AssociateTransitGatewayRouteTableOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.TransitGatewayRouteTableId)
+	routeTable, err := f.transitRouteTable(routeTableID)
+	if err != nil {
+		return nil, err
+	}
+	attachmentID := aws.ToString(params.TransitGatewayAttachmentId)
+	attachment, err := f.transitAttachment(attachmentID, routeTable)
+	if err != nil {
+		return nil, err
+	}
+	if associated, ok := f.transitAssociations[attachmentID]; ok {
+		return nil, apiError(awserrors.ResourceAlreadyAssociated,
+			"Transit Gateway Attachment %s is already associated to a route table %s.", attachmentID, associated)
+	}
+	f.transitAssociations[attachmentID] = routeTableID
+	return &ec2.AssociateTransitGatewayRouteTableOutput{Association: &types.TransitGatewayAssociation{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+		ResourceId:                 attachment.VpcId,
+		ResourceType:               types.TransitGatewayAttachmentResourceTypeVpc,
+		State:                      types.TransitGatewayAssociationStateAssociated,
+	}}, nil
+}
+
+// DisassociateTransitGatewayRouteTable removes the association of an attachment with a route
+// table.
+func (f *EC2) DisassociateTransitGatewayRouteTable(ctx context.Context, params *ec2.DisassociateTransitGatewayRouteTableInput, optFns ...func(*ec2.Options)) (*ec2.DisassociateTransitGatewayRouteTableOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.TransitGatewayRouteTableId)
+	routeTable, err := f.transitRouteTable(routeTableID)
+	if err != nil {
+		return nil, err
+	}
+	attachmentID := aws.ToString(params.TransitGatewayAttachmentId)
+	attachment, err := f.transitAttachment(attachmentID, routeTable)
+	if err != nil {
+		return nil, err
+	}
+	if f.transitAssociations[attachmentID] != routeTableID {
+		return nil, associationNotFound(attachmentID)
+	}
+	delete(f.transitAssociations, attachmentID)
+	return &ec2.DisassociateTransitGatewayRouteTableOutput{Association: &types.TransitGatewayAssociation{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+		ResourceId:                 attachment.VpcId,
+		ResourceType:               types.TransitGatewayAttachmentResourceTypeVpc,
+		State:                      types.TransitGatewayAssociationStateDisassociated,
+	}}, nil
+}
+
+// EnableTransitGatewayRouteTablePropagation makes the route table learn the CIDR blocks of the
+// VPC of the attachment.
+func (f *EC2) EnableTransitGatewayRouteTablePropagation(ctx context.Context, params *ec2.EnableTransitGatewayRouteTablePropagationInput, optFns ...func(*ec2.Options)) (*ec2.EnableTransitGatewayRouteTablePropagationOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.TransitGatewayRouteTableId)
+	routeTable, err := f.transitRouteTable(routeTableID)
+	if err != nil {
+		return nil, err
+	}
+	attachmentID := aws.ToString(params.TransitGatewayAttachmentId)
+	attachment, err := f.transitAttachment(attachmentID, routeTable)
+	if err != nil {
+		return nil, err
+	}
+	if f.transitPropagations[routeTableID][attachmentID] {
+		return nil, apiError(awserrors.TransitGatewayPropagationExists,
+			"Propagation from %s to %s already exists.", attachmentID, routeTableID)
+	}
+	f.transitPropagations[routeTableID][attachmentID] = true
+	return &ec2.EnableTransitGatewayRouteTablePropagationOutput{Propagation: &types.TransitGatewayPropagation{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+		ResourceId:                 attachment.VpcId,
+		ResourceType:               types.TransitGatewayAttachmentResourceTypeVpc,
+		State:                      types.TransitGatewayPropagationStateEnabled,
+	}}, nil
+}
+
+// DisableTransitGatewayRouteTablePropagation stops the propagation of the attachment to the route
+// table, removing the routes it learnt.
+func (f *EC2) DisableTransitGatewayRouteTablePropagation(ctx context.Context, params *ec2.DisableTransitGatewayRouteTablePropagationInput, optFns ...func(*ec2.Options)) (*ec2.DisableTransitGatewayRouteTablePropagationOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.TransitGatewayRouteTableId)
+	routeTable, err := f.transitRouteTable(routeTableID)
+	if err != nil {
+		return nil, err
+	}
+	attachmentID := aws.ToString(params.TransitGatewayAttachmentId)
+	attachment, err := f.transitAttachment(attachmentID, routeTable)
+	if err != nil {
+		return nil, err
+	}
+	if !f.transitPropagations[routeTableID][attachmentID] {
+		return nil, apiError(awserrors.TransitGatewayPropagationNotFound,
+			"Propagation from %s to %s does not exist.", attachmentID, routeTableID)
+	}
+	delete(f.transitPropagations[routeTableID], attachmentID)
+	return &ec2.DisableTransitGatewayRouteTablePropagationOutput{Propagation: &types.TransitGatewayPropagation{
+		TransitGatewayRouteTableId: aws.String(routeTableID),
+		TransitGatewayAttachmentId: aws.String(attachmentID),
+		ResourceId:                 attachment.VpcId,
+		ResourceType:               types.TransitGatewayAttachmentResourceTypeVpc,
+		State:                      types.TransitGatewayPropagationStateDisabled,
+	}}, nil
+}
+
+func transitPropagationFilterAttributes(propagation *types.TransitGatewayRouteTablePropagation) map[string][]string {
+	return map[string][]string{
+		"transit-gateway-attachment-id": {aws.ToString(propagation.TransitGatewayAttachmentId)},
+		"resource-id":                   {aws.ToString(propagation.ResourceId)},
+		"resource-type":                 {string(propagation.ResourceType)},
+		"state":                         {string(propagation.State)},
+	}
+}
+
+// GetTransitGatewayRouteTablePropagations returns the attachments propagating to a route table.
+func (f *EC2) GetTransitGatewayRouteTablePropagations(ctx context.Context, params *ec2.GetTransitGatewayRouteTablePropagationsInput, optFns ...func(*ec2.Options)) (*ec2.GetTransitGatewayRouteTablePropagationsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.TransitGatewayRouteTableId)
+	if _, err := f.transitRouteTable(routeTableID); err != nil {
+		return nil, err
+	}
+	propagations := []types.TransitGatewayRouteTablePropagation{}
+	for _, attachmentID := range sortedIDs(f.transitPropagations[routeTableID]) {
+		propagation := types.TransitGatewayRouteTablePropagation{
+			TransitGatewayAttachmentId: aws.String(attachmentID),
+			ResourceId:                 f.transitAttachments[attachmentID].VpcId,
+			ResourceType:               types.TransitGatewayAttachmentResourceTypeVpc,
+			State:                      types.TransitGatewayPropagationStateEnabled,
+		}
+		match, err := matchFilters(params.Filters, transitPropagationFilterAttributes(&propagation), nil)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			propagations = append(propagations, propagation)
+		}
+	}
+	return &ec2.GetTransitGatewayRouteTablePropagationsOutput{TransitGatewayRouteTablePropagations: propagations}, nil
+}
+
+// CreateTransitGatewayRoute adds a static route to a transit gateway route table, either to an
+// attachment or, with Blackhole, dropping the traffic.
+func (f *EC2) CreateTransitGatewayRoute(ctx context.Context, params *ec2.CreateTransitGatewayRouteInput, optFns ...func(*ec2.Options)) (*ec2.CreateTransitGatewayRouteOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.TransitGatewayRouteTableId)
+	routeTable, err := f.transitRouteTable(routeTableID)
+	if err != nil {
+		return nil, err
+	}
+	destination := aws.ToString(params.DestinationCidrBlock)
+	if destination == "" {
+		return nil, missingParameter("DestinationCidrBlock")
+	}
+	if _, _, err := net.ParseCIDR(destination); err != nil {
+		return nil, apiError(awserrors.InvalidParameterValue, "Invalid destination CIDR block %s", destination)
+	}
+	for _, route := range f.transitRoutes[routeTableID] {
+		if aws.ToString(route.DestinationCidrBlock) == destination {
+			return nil, apiError(awserrors.RouteAlreadyExists, "Route %s already exists in Transit Gateway Route Table %s.",
+				destination, routeTableID)
+		}
+	}
+	route := types.TransitGatewayRoute{
+		DestinationCidrBlock: aws.String(destination),
+		Type:                 types.TransitGatewayRouteTypeStatic,
+		State:                types.TransitGatewayRouteStateBlackhole,
+	}
+	if !aws.ToBool(params.Blackhole) {
+		attachmentID := aws.ToString(params.TransitGatewayAttachmentId)
+		if attachmentID == "" {
+			return nil, missingParameter("TransitGatewayAttachmentId")
+		}
+		attachment, err := f.transitAttachment(attachmentID, routeTable)
+		if err != nil {
+			return nil, err
+		}
+		route.State = types.TransitGatewayRouteStateActive
+		route.TransitGatewayAttachments = []types.TransitGatewayRouteAttachment{{
+			TransitGatewayAttachmentId: aws.String(attachmentID),
+			ResourceId:                 attachment.VpcId,
+			ResourceType:               types.TransitGatewayAttachmentResourceTypeVpc,
+		}}
+	}
+	f.transitRoutes[routeTableID] = append(f.transitRoutes[routeTableID], route)
+	return &ec2.CreateTransitGatewayRouteOutput{Route: cloned(&route)}, nil
+}
+
+// DeleteTransitGatewayRoute removes a static route from a transit gateway route table.
+func (f *EC2) DeleteTransitGatewayRoute(ctx context.Context, params *ec2.DeleteTransitGatewayRouteInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTransitGatewayRouteOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.TransitGatewayRouteTableId)
+	if _, err := f.transitRouteTable(routeTableID); err != nil {
+		return nil, err
+	}
+	destination := aws.ToString(params.DestinationCidrBlock)
+	routes := f.transitRoutes[routeTableID]
+	for i, route := range routes {
+		if aws.ToString(route.DestinationCidrBlock) == destination {
+			f.transitRoutes[routeTableID] = append(routes[:i:i], routes[i+1:]...)
+			route.State = types.TransitGatewayRouteStateDeleted
+			return &ec2.DeleteTransitGatewayRouteOutput{Route: cloned(&route)}, nil
+		}
+	}
+	return nil, apiError(awserrors.InvalidRouteNotFound, "Route %s does not exist in Transit Gateway Route Table %s.",
+		destination, routeTableID)
+}
+
+func transitRouteFilterAttributes(route *types.TransitGatewayRoute) map[string][]string {
+	attachmentIDs := []string{}
+	resourceIDs := []string{}
+	for _, attachment := range route.TransitGatewayAttachments {
+		attachmentIDs = append(attachmentIDs, aws.ToString(attachment.TransitGatewayAttachmentId))
+		resourceIDs = append(resourceIDs, aws.ToString(attachment.ResourceId))
+	}
+	return map[string][]string{
+		"route-search.exact-match": {aws.ToString(route.DestinationCidrBlock)},
+		"type":                     {string(route.Type)},
+		"state":                    {string(route.State)},
+		"attachment.transit-gateway-attachment-id": attachmentIDs,
+		"attachment.resource-id":                   resourceIDs,
+	}
+}
+
+// SearchTransitGatewayRoutes returns the routes of a transit gateway route table matching the
+// filters: the static routes, then the CIDR blocks of the VPCs propagating to the route table.
+// Static routes to a deleted attachment are blackholes.
+func (f *EC2) SearchTransitGatewayRoutes(ctx context.Context, params *ec2.SearchTransitGatewayRoutesInput, optFns ...func(*ec2.Options)) (*ec2.SearchTransitGatewayRoutesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	routeTableID := aws.ToString(params.TransitGatewayRouteTableId)
+	if _, err := f.transitRouteTable(routeTableID); err != nil {
+		return nil, err
+	}
+	if len(params.Filters) == 0 {
+		return nil, missingParameter("Filters")
+	}
+	routes := []types.TransitGatewayRoute{}
+	for _, route := range f.transitRoutes[routeTableID] {
+		route = clone(route)
+		for _, attachment := range route.TransitGatewayAttachments {
+			if f.transitAttachments[aws.ToString(attachment.TransitGatewayAttachmentId)].State == types.TransitGatewayAttachmentStateDeleted {
+				route.State = types.TransitGatewayRouteStateBlackhole
+			}
+		}
+		routes = append(routes, route)
+	}
+	for _, attachmentID := range sortedIDs(f.transitPropagations[routeTableID]) {
+		attachment := f.transitAttachments[attachmentID]
+		vpc, ok := f.vpcs[aws.ToString(attachment.VpcId)]
+		if !ok {
+			continue
+		}
+		for _, association := range vpc.CidrBlockAssociationSet {
+			routes = append(routes, types.TransitGatewayRoute{
+				DestinationCidrBlock: association.CidrBlock,
+				Type:                 types.TransitGatewayRouteTypePropagated,
+				State:                types.TransitGatewayRouteStateActive,
+				TransitGatewayAttachments: []types.TransitGatewayRouteAttachment{{
+					TransitGatewayAttachmentId: aws.String(attachmentID),
+					ResourceId:                 attachment.VpcId,
+					ResourceType:               types.TransitGatewayAttachmentResourceTypeVpc,
+				}},
+			})
+		}
+	}
+	matched := []types.TransitGatewayRoute{}
+	for _, route := range routes {
+		match, err := matchFilters(params.Filters, transitRouteFilterAttributes(&route), nil)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			matched = append(matched, route)
+		}
+	}
+	return &ec2.SearchTransitGatewayRoutesOutput{Routes: matched, AdditionalRoutesAvailable: aws.Bool(false)}, nil
+}
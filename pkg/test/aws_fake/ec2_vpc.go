@@ -0,0 +1,815 @@
+package aws_fake
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+)
+
+func vpcFilterAttributes(vpc *types.Vpc) map[string][]string {
+	cidrs := []string{}
+	for _, association := range vpc.CidrBlockAssociationSet {
+		cidrs = append(cidrs, aws.ToString(association.CidrBlock))
+	}
+	ipv6Cidrs := []string{}
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		ipv6Cidrs = append(ipv6Cidrs, aws.ToString(association.Ipv6CidrBlock))
+	}
+	return map[string][]string{
+		"vpc-id":                            {aws.ToString(vpc.VpcId)},
+		"cidr":                              {aws.ToString(vpc.CidrBlock)},
+		"cidr-block-association.cidr-block": cidrs,
+		"dhcp-options-id":                   {aws.ToString(vpc.DhcpOptionsId)},
+		"is-default":                        boolValue(aws.ToBool(vpc.IsDefault)),
+		"owner-id":                          {aws.ToString(vpc.OwnerId)},
+		"state":                             {string(vpc.State)},
+		"ipv6-cidr-block-association.ipv6-cidr-block": ipv6Cidrs,
+	}
+}
+
+func vpcTags(vpc *types.Vpc) []types.Tag {
+	return vpc.Tags
+}
+
+// CreateVpc creates an available VPC together with its main route table, default security group
+// and default network ACL. An Amazon provided /56 IPv6 block is associated when requested.
+func (f *EC2) CreateVpc(ctx context.Context, params *ec2.CreateVpcInput, optFns ...func(*ec2.Options)) (*ec2.CreateVpcOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.CidrBlock == nil {
+		return nil, missingParameter("cidrBlock")
+	}
+	_, network, err := net.ParseCIDR(*params.CidrBlock)
+	if err != nil || network.IP.To4() == nil {
+		return nil, apiError(awserrors.InvalidParameterValue, "Value (%s) for parameter cidrBlock is invalid. This is not a valid CIDR block.", *params.CidrBlock)
+	}
+	if ones, _ := network.Mask.Size(); ones < 16 || ones > 28 {
+		return nil, apiError(awserrors.InvalidVpcRange, "The CIDR '%s' is invalid.", *params.CidrBlock)
+	}
+	tenancy := params.InstanceTenancy
+	if tenancy == "" {
+		tenancy = types.TenancyDefault
+	}
+	vpc := &types.Vpc{
+		VpcId:     aws.String(f.newID("vpc")),
+		CidrBlock: aws.String(network.String()),
+		CidrBlockAssociationSet: []types.VpcCidrBlockAssociation{{
+			AssociationId:  aws.String(f.newID("vpc-cidr-assoc")),
+			CidrBlock:      aws.String(network.String()),
+			CidrBlockState: &types.VpcCidrBlockState{State: types.VpcCidrBlockStateCodeAssociated},
+		}},
+		DhcpOptionsId:   aws.String(f.newID("dopt")),
+		InstanceTenancy: tenancy,
+		IsDefault:       aws.Bool(false),
+		OwnerId:         aws.String(f.accountID),
+		State:           types.VpcStateAvailable,
+		Tags:            tagsFor(params.TagSpecifications, types.ResourceTypeVpc),
+	}
+	if aws.ToBool(params.AmazonProvidedIpv6CidrBlock) {
+		vpc.Ipv6CidrBlockAssociationSet = []types.VpcIpv6CidrBlockAssociation{{
+			AssociationId:      aws.String(f.newID("vpc-cidr-assoc")),
+			Ipv6CidrBlock:      aws.String(f.newIPv6Block()),
+			Ipv6CidrBlockState: &types.VpcCidrBlockState{State: types.VpcCidrBlockStateCodeAssociating},
+			Ipv6Pool:           aws.String("Amazon"),
+			NetworkBorderGroup: aws.String(f.region),
+		}}
+	}
+	vpcID := aws.ToString(vpc.VpcId)
+	f.vpcs[vpcID] = vpc
+	f.vpcAttributes[vpcID] = &vpcAttributes{enableDnsSupport: true}
+	f.createRouteTable(vpc, true, nil)
+	f.createSecurityGroup(vpcID, "default", "default VPC security group", nil)
+	f.createDefaultNetworkAcl(vpcID)
+	return &ec2.CreateVpcOutput{Vpc: cloned(vpc)}, nil
+}
+
+// DescribeVpcs returns the VPCs selected by IDs and filters.
+func (f *EC2) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeVpcsInput{}
+	}
+	vpcs, err := describe(f.vpcs, params.VpcIds, params.Filters, vpcNotFound, vpcFilterAttributes, vpcTags)
+	if err != nil {
+		return nil, err
+	}
+	// IPv6 blocks seen associating get associated, so that callers have to describe the VPC again.
+	for _, vpc := range vpcs {
+		for _, association := range f.vpcs[aws.ToString(vpc.VpcId)].Ipv6CidrBlockAssociationSet {
+			if association.Ipv6CidrBlockState.State == types.VpcCidrBlockStateCodeAssociating {
+				association.Ipv6CidrBlockState.State = types.VpcCidrBlockStateCodeAssociated
+			}
+		}
+	}
+	return &ec2.DescribeVpcsOutput{Vpcs: vpcs}, nil
+}
+
+// DeleteVpc deletes a VPC once everything but its default resources has been deleted.
+func (f *EC2) DeleteVpc(ctx context.Context, params *ec2.DeleteVpcInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVpcOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	vpcID := aws.ToString(params.VpcId)
+	if _, ok := f.vpcs[vpcID]; !ok {
+		return nil, vpcNotFound(vpcID)
+	}
+	for _, subnet := range f.subnets {
+		if aws.ToString(subnet.VpcId) == vpcID {
+			return nil, dependencyViolation("vpc", vpcID)
+		}
+	}
+	for _, gateway := range f.internetGateways {
+		for _, attachment := range gateway.Attachments {
+			if aws.ToString(attachment.VpcId) == vpcID {
+				return nil, dependencyViolation("vpc", vpcID)
+			}
+		}
+	}
+	for _, routeTable := range f.routeTables {
+		if aws.ToString(routeTable.VpcId) == vpcID && !isMainRouteTable(routeTable) {
+			return nil, dependencyViolation("vpc", vpcID)
+		}
+	}
+	for _, group := range f.securityGroups {
+		if aws.ToString(group.VpcId) == vpcID && aws.ToString(group.GroupName) != "default" {
+			return nil, dependencyViolation("vpc", vpcID)
+		}
+	}
+	for _, acl := range f.networkAcls {
+		if aws.ToString(acl.VpcId) == vpcID && !aws.ToBool(acl.IsDefault) {
+			return nil, dependencyViolation("vpc", vpcID)
+		}
+	}
+	for _, endpoint := range f.vpcEndpoints {
+		if aws.ToString(endpoint.VpcId) == vpcID {
+			return nil, dependencyViolation("vpc", vpcID)
+		}
+	}
+	for _, gateway := range f.egressOnlyGateways {
+		if egressOnlyAttachedTo(gateway, vpcID) {
+			return nil, dependencyViolation("vpc", vpcID)
+		}
+	}
+	for _, attachment := range f.transitAttachments {
+		if aws.ToString(attachment.VpcId) == vpcID && attachment.State != types.TransitGatewayAttachmentStateDeleted {
+			return nil, dependencyViolation("vpc", vpcID)
+		}
+	}
+
+	for id, routeTable := range f.routeTables {
+		if aws.ToString(routeTable.VpcId) == vpcID {
+			delete(f.routeTables, id)
+		}
+	}
+	for id, group := range f.securityGroups {
+		if aws.ToString(group.VpcId) == vpcID {
+			f.deleteSecurityGroupRules(id)
+			delete(f.securityGroups, id)
+		}
+	}
+	for id, acl := range f.networkAcls {
+		if aws.ToString(acl.VpcId) == vpcID {
+			delete(f.networkAcls, id)
+		}
+	}
+	delete(f.vpcAttributes, vpcID)
+	delete(f.vpcs, vpcID)
+	return &ec2.DeleteVpcOutput{}, nil
+}
+
+// maxVpcCidrBlocks is the default quota of IPv4 CIDR blocks per VPC.
+const maxVpcCidrBlocks = 5
+
+// AssociateVpcCidrBlock associates a secondary IPv4 CIDR block to a VPC and adds its local route to
+// every route table of the VPC.
+func (f *EC2) AssociateVpcCidrBlock(ctx context.Context, params *ec2.AssociateVpcCidrBlockInput, optFns ...func(*ec2.Options)) (*ec2.AssociateVpcCidrBlockOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.VpcId == nil {
+		return nil, missingParameter("vpcId")
+	}
+	vpc, ok := f.vpcs[*params.VpcId]
+	if !ok {
+		return nil, vpcNotFound(*params.VpcId)
+	}
+	if params.CidrBlock == nil {
+		return nil, missingParameter("cidrBlock")
+	}
+	_, network, err := net.ParseCIDR(*params.CidrBlock)
+	if err != nil || network.IP.To4() == nil {
+		return nil, apiError(awserrors.InvalidParameterValue, "Value (%s) for parameter cidrBlock is invalid. This is not a valid CIDR block.", *params.CidrBlock)
+	}
+	if ones, _ := network.Mask.Size(); ones < 16 || ones > 28 {
+		return nil, apiError(awserrors.InvalidVpcRange, "The CIDR '%s' is invalid.", *params.CidrBlock)
+	}
+	if len(vpc.CidrBlockAssociationSet) >= maxVpcCidrBlocks {
+		return nil, apiError(awserrors.CidrLimitExceeded, "This network '%s' has met its maximum number of allowed CIDRs: %d", *params.VpcId, maxVpcCidrBlocks)
+	}
+	for _, association := range vpc.CidrBlockAssociationSet {
+		_, associated, _ := net.ParseCIDR(aws.ToString(association.CidrBlock))
+		if overlaps(network, associated) {
+			return nil, apiError(awserrors.CidrConflict, "The CIDR '%s' conflicts with another CIDR block of the network", *params.CidrBlock)
+		}
+	}
+	association := types.VpcCidrBlockAssociation{
+		AssociationId:  aws.String(f.newID("vpc-cidr-assoc")),
+		CidrBlock:      aws.String(network.String()),
+		CidrBlockState: &types.VpcCidrBlockState{State: types.VpcCidrBlockStateCodeAssociated},
+	}
+	vpc.CidrBlockAssociationSet = append(vpc.CidrBlockAssociationSet, association)
+	for _, routeTable := range f.routeTables {
+		if aws.ToString(routeTable.VpcId) == *params.VpcId {
+			routeTable.Routes = append(routeTable.Routes, types.Route{
+				DestinationCidrBlock: association.CidrBlock,
+				GatewayId:            aws.String("local"),
+				Origin:               types.RouteOriginCreateRouteTable,
+				State:                types.RouteStateActive,
+			})
+		}
+	}
+	return &ec2.AssociateVpcCidrBlockOutput{
+		VpcId:                params.VpcId,
+		CidrBlockAssociation: cloned(&association),
+	}, nil
+}
+
+// DescribeVpcAttribute returns the DNS attributes of a VPC.
+func (f *EC2) DescribeVpcAttribute(ctx context.Context, params *ec2.DescribeVpcAttributeInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcAttributeOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	vpcID := aws.ToString(params.VpcId)
+	attributes, ok := f.vpcAttributes[vpcID]
+	if !ok {
+		return nil, vpcNotFound(vpcID)
+	}
+	output := &ec2.DescribeVpcAttributeOutput{VpcId: aws.String(vpcID)}
+	switch params.Attribute {
+	case types.VpcAttributeNameEnableDnsHostnames:
+		output.EnableDnsHostnames = &types.AttributeBooleanValue{Value: aws.Bool(attributes.enableDnsHostnames)}
+	case types.VpcAttributeNameEnableDnsSupport:
+		output.EnableDnsSupport = &types.AttributeBooleanValue{Value: aws.Bool(attributes.enableDnsSupport)}
+	default:
+		return nil, apiError(awserrors.InvalidParameterValue, "Value (%s) for parameter attribute is invalid.", params.Attribute)
+	}
+	return output, nil
+}
+
+// ModifyVpcAttribute updates the DNS attributes of a VPC.
+func (f *EC2) ModifyVpcAttribute(ctx context.Context, params *ec2.ModifyVpcAttributeInput, optFns ...func(*ec2.Options)) (*ec2.ModifyVpcAttributeOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	vpcID := aws.ToString(params.VpcId)
+	attributes, ok := f.vpcAttributes[vpcID]
+	if !ok {
+		return nil, vpcNotFound(vpcID)
+	}
+	if params.EnableDnsHostnames == nil && params.EnableDnsSupport == nil {
+		return nil, missingParameter("attribute")
+	}
+	if params.EnableDnsHostnames != nil {
+		attributes.enableDnsHostnames = aws.ToBool(params.EnableDnsHostnames.Value)
+	}
+	if params.EnableDnsSupport != nil {
+		attributes.enableDnsSupport = aws.ToBool(params.EnableDnsSupport.Value)
+	}
+	return &ec2.ModifyVpcAttributeOutput{}, nil
+}
+
+func subnetFilterAttributes(subnet *types.Subnet) map[string][]string {
+	ipv6Cidrs := []string{}
+	for _, association := range subnet.Ipv6CidrBlockAssociationSet {
+		ipv6Cidrs = append(ipv6Cidrs, aws.ToString(association.Ipv6CidrBlock))
+	}
+	return map[string][]string{
+		"subnet-id":                  {aws.ToString(subnet.SubnetId)},
+		"subnet-arn":                 {aws.ToString(subnet.SubnetArn)},
+		"vpc-id":                     {aws.ToString(subnet.VpcId)},
+		"cidr":                       {aws.ToString(subnet.CidrBlock)},
+		"cidr-block":                 {aws.ToString(subnet.CidrBlock)},
+		"cidrBlock":                  {aws.ToString(subnet.CidrBlock)},
+		"availability-zone":          {aws.ToString(subnet.AvailabilityZone)},
+		"availabilityZone":           {aws.ToString(subnet.AvailabilityZone)},
+		"availability-zone-id":       {aws.ToString(subnet.AvailabilityZoneId)},
+		"available-ip-address-count": {fmt.Sprintf("%d", aws.ToInt32(subnet.AvailableIpAddressCount))},
+		"default-for-az":             boolValue(aws.ToBool(subnet.DefaultForAz)),
+		"defaultForAz":               boolValue(aws.ToBool(subnet.DefaultForAz)),
+		"map-public-ip-on-launch":    boolValue(aws.ToBool(subnet.MapPublicIpOnLaunch)),
+		"owner-id":                   {aws.ToString(subnet.OwnerId)},
+		"state":                      {string(subnet.State)},
+		"ipv6-cidr-block-association.ipv6-cidr-block": ipv6Cidrs,
+	}
+}
+
+func subnetTags(subnet *types.Subnet) []types.Tag {
+	return subnet.Tags
+}
+
+// CreateSubnet creates a subnet after checking that its CIDR lies in the VPC and does not overlap
+// the other subnets of the VPC. The same goes for the optional /64 IPv6 CIDR and the IPv6 block of
+// the VPC. The subnet is associated with the default network ACL.
+func (f *EC2) CreateSubnet(ctx context.Context, params *ec2.CreateSubnetInput, optFns ...func(*ec2.Options)) (*ec2.CreateSubnetOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.VpcId == nil {
+		return nil, missingParameter("vpcId")
+	}
+	vpc, ok := f.vpcs[*params.VpcId]
+	if !ok {
+		return nil, vpcNotFound(*params.VpcId)
+	}
+	zone := aws.ToString(params.AvailabilityZone)
+	if zone == "" && params.AvailabilityZoneId != nil {
+		for _, known := range f.zones {
+			if f.zoneID(known) == *params.AvailabilityZoneId {
+				zone = known
+			}
+		}
+	}
+	if zone == "" {
+		zone = f.zones[0]
+	}
+	if !f.validZone(zone) {
+		return nil, apiError(awserrors.InvalidParameterValue,
+			"Value (%s) for parameter availabilityZone is invalid. Subnets can currently only be created in the following availability zones: %s.",
+			zone, strings.Join(f.zones, ", "))
+	}
+	if params.CidrBlock == nil {
+		return nil, missingParameter("cidrBlock")
+	}
+	_, network, err := net.ParseCIDR(*params.CidrBlock)
+	if err != nil || network.IP.To4() == nil {
+		return nil, apiError(awserrors.InvalidParameterValue, "Value (%s) for parameter cidrBlock is invalid. This is not a valid CIDR block.", *params.CidrBlock)
+	}
+	ones, _ := network.Mask.Size()
+	if ones < 16 || ones > 28 || !vpcContains(vpc, network) {
+		return nil, apiError(awserrors.InvalidSubnetRange, "The CIDR '%s' is invalid.", *params.CidrBlock)
+	}
+	for _, existing := range f.subnets {
+		if aws.ToString(existing.VpcId) != *params.VpcId {
+			continue
+		}
+		_, existingNetwork, _ := net.ParseCIDR(aws.ToString(existing.CidrBlock))
+		if overlaps(network, existingNetwork) {
+			return nil, apiError(awserrors.InvalidSubnetConflict, "The CIDR '%s' conflicts with another subnet", *params.CidrBlock)
+		}
+	}
+	var ipv6Associations []types.SubnetIpv6CidrBlockAssociation
+	if params.Ipv6CidrBlock != nil {
+		ipv6Network, err := f.checkSubnetIPv6Cidr(vpc, *params.Ipv6CidrBlock)
+		if err != nil {
+			return nil, err
+		}
+		ipv6Associations = []types.SubnetIpv6CidrBlockAssociation{{
+			AssociationId:      aws.String(f.newID("subnet-cidr-assoc")),
+			Ipv6CidrBlock:      aws.String(ipv6Network.String()),
+			Ipv6CidrBlockState: &types.SubnetCidrBlockState{State: types.SubnetCidrBlockStateCodeAssociated},
+		}}
+	}
+	subnetID := f.newID("subnet")
+	subnet := &types.Subnet{
+		SubnetId:                    aws.String(subnetID),
+		SubnetArn:                   aws.String(f.arn("subnet", subnetID)),
+		VpcId:                       params.VpcId,
+		CidrBlock:                   aws.String(network.String()),
+		AvailabilityZone:            aws.String(zone),
+		AvailabilityZoneId:          aws.String(f.zoneID(zone)),
+		AvailableIpAddressCount:     aws.Int32(int32(1<<(32-ones)) - 5),
+		DefaultForAz:                aws.Bool(false),
+		MapPublicIpOnLaunch:         aws.Bool(false),
+		OwnerId:                     aws.String(f.accountID),
+		State:                       types.SubnetStateAvailable,
+		Tags:                        tagsFor(params.TagSpecifications, types.ResourceTypeSubnet),
+		AssignIpv6AddressOnCreation: aws.Bool(false),
+		Ipv6CidrBlockAssociationSet: ipv6Associations,
+	}
+	f.subnets[subnetID] = subnet
+	f.associateDefaultNetworkAcl(*params.VpcId, subnetID)
+	return &ec2.CreateSubnetOutput{Subnet: cloned(subnet)}, nil
+}
+
+// checkSubnetIPv6Cidr checks that cidr is a /64 of the IPv6 block of vpc not used by another subnet.
+func (f *EC2) checkSubnetIPv6Cidr(vpc *types.Vpc, cidr string) (*net.IPNet, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil || network.IP.To4() != nil {
+		return nil, apiError(awserrors.InvalidParameterValue, "Value (%s) for parameter ipv6CidrBlock is invalid. This is not a valid IPv6 CIDR block.", cidr)
+	}
+	if ones, _ := network.Mask.Size(); ones != 64 {
+		return nil, apiError(awserrors.InvalidSubnetRange, "The IPv6 CIDR '%s' is invalid, subnets must use a /64.", cidr)
+	}
+	inVpc := false
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		_, vpcNetwork, err := net.ParseCIDR(aws.ToString(association.Ipv6CidrBlock))
+		if err == nil && vpcNetwork.Contains(network.IP) {
+			inVpc = true
+		}
+	}
+	if !inVpc {
+		return nil, apiError(awserrors.InvalidSubnetRange, "The IPv6 CIDR '%s' is not within the IPv6 CIDR blocks of %s.",
+			cidr, aws.ToString(vpc.VpcId))
+	}
+	for _, existing := range f.subnets {
+		if aws.ToString(existing.VpcId) != aws.ToString(vpc.VpcId) {
+			continue
+		}
+		for _, association := range existing.Ipv6CidrBlockAssociationSet {
+			if aws.ToString(association.Ipv6CidrBlock) == network.String() {
+				return nil, apiError(awserrors.InvalidSubnetConflict, "The IPv6 CIDR '%s' conflicts with another subnet", cidr)
+			}
+		}
+	}
+	return network, nil
+}
+
+// vpcContains reports whether network lies in one of the CIDR blocks of vpc.
+func vpcContains(vpc *types.Vpc, network *net.IPNet) bool {
+	ones, _ := network.Mask.Size()
+	for _, association := range vpc.CidrBlockAssociationSet {
+		_, vpcNetwork, err := net.ParseCIDR(aws.ToString(association.CidrBlock))
+		if err != nil {
+			continue
+		}
+		vpcOnes, _ := vpcNetwork.Mask.Size()
+		if vpcNetwork.Contains(network.IP) && ones >= vpcOnes {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeSubnets returns the subnets selected by IDs and filters.
+func (f *EC2) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeSubnetsInput{}
+	}
+	subnets, err := describe(f.subnets, params.SubnetIds, params.Filters, subnetNotFound, subnetFilterAttributes, subnetTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeSubnetsOutput{Subnets: subnets}, nil
+}
+
+// DeleteSubnet deletes a subnet without network interfaces, dropping its route table and
+// network ACL associations.
+func (f *EC2) DeleteSubnet(ctx context.Context, params *ec2.DeleteSubnetInput, optFns ...func(*ec2.Options)) (*ec2.DeleteSubnetOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	subnetID := aws.ToString(params.SubnetId)
+	if _, ok := f.subnets[subnetID]; !ok {
+		return nil, subnetNotFound(subnetID)
+	}
+	for _, networkInterface := range f.networkInterfaces {
+		if aws.ToString(networkInterface.SubnetId) == subnetID {
+			return nil, dependencyViolation("subnet", subnetID)
+		}
+	}
+	for _, routeTable := range f.routeTables {
+		associations := []types.RouteTableAssociation{}
+		for _, association := range routeTable.Associations {
+			if aws.ToString(association.SubnetId) != subnetID {
+				associations = append(associations, association)
+			}
+		}
+		routeTable.Associations = associations
+	}
+	for _, acl := range f.networkAcls {
+		associations := []types.NetworkAclAssociation{}
+		for _, association := range acl.Associations {
+			if aws.ToString(association.SubnetId) != subnetID {
+				associations = append(associations, association)
+			}
+		}
+		acl.Associations = associations
+	}
+	delete(f.privateIPs, subnetID)
+	delete(f.subnets, subnetID)
+	return &ec2.DeleteSubnetOutput{}, nil
+}
+
+func networkAclFilterAttributes(acl *types.NetworkAcl) map[string][]string {
+	subnetIDs := []string{}
+	associationIDs := []string{}
+	for _, association := range acl.Associations {
+		subnetIDs = append(subnetIDs, aws.ToString(association.SubnetId))
+		associationIDs = append(associationIDs, aws.ToString(association.NetworkAclAssociationId))
+	}
+	return map[string][]string{
+		"network-acl-id":             {aws.ToString(acl.NetworkAclId)},
+		"vpc-id":                     {aws.ToString(acl.VpcId)},
+		"default":                    boolValue(aws.ToBool(acl.IsDefault)),
+		"owner-id":                   {aws.ToString(acl.OwnerId)},
+		"association.subnet-id":      subnetIDs,
+		"association.association-id": associationIDs,
+		"association.network-acl-id": {aws.ToString(acl.NetworkAclId)},
+	}
+}
+
+func networkAclTags(acl *types.NetworkAcl) []types.Tag {
+	return acl.Tags
+}
+
+// createDefaultNetworkAcl creates the default network ACL of a VPC, which allows all traffic.
+func (f *EC2) createDefaultNetworkAcl(vpcID string) {
+	entry := func(ruleNumber int32, egress bool, action types.RuleAction) types.NetworkAclEntry {
+		return types.NetworkAclEntry{
+			CidrBlock:  aws.String("0.0.0.0/0"),
+			Egress:     aws.Bool(egress),
+			Protocol:   aws.String("-1"),
+			RuleAction: action,
+			RuleNumber: aws.Int32(ruleNumber),
+		}
+	}
+	aclID := f.newID("acl")
+	f.networkAcls[aclID] = &types.NetworkAcl{
+		NetworkAclId: aws.String(aclID),
+		VpcId:        aws.String(vpcID),
+		IsDefault:    aws.Bool(true),
+		OwnerId:      aws.String(f.accountID),
+		Associations: []types.NetworkAclAssociation{},
+		Entries: []types.NetworkAclEntry{
+			entry(100, true, types.RuleActionAllow),
+			entry(32767, true, types.RuleActionDeny),
+			entry(100, false, types.RuleActionAllow),
+			entry(32767, false, types.RuleActionDeny),
+		},
+	}
+}
+
+func (f *EC2) associateDefaultNetworkAcl(vpcID string, subnetID string) {
+	for _, acl := range f.networkAcls {
+		if aws.ToString(acl.VpcId) == vpcID && aws.ToBool(acl.IsDefault) {
+			acl.Associations = append(acl.Associations, types.NetworkAclAssociation{
+				NetworkAclAssociationId: aws.String(f.newID("aclassoc")),
+				NetworkAclId:            acl.NetworkAclId,
+				SubnetId:                aws.String(subnetID),
+			})
+		}
+	}
+}
+
+// DescribeNetworkAcls returns the network ACLs selected by IDs and filters.
+func (f *EC2) DescribeNetworkAcls(ctx context.Context, params *ec2.DescribeNetworkAclsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkAclsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeNetworkAclsInput{}
+	}
+	acls, err := describe(f.networkAcls, params.NetworkAclIds, params.Filters, networkAclNotFound, networkAclFilterAttributes, networkAclTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeNetworkAclsOutput{NetworkAcls: acls}, nil
+}
+
+// CreateNetworkAclEntry adds a rule to a network ACL.
+func (f *EC2) CreateNetworkAclEntry(ctx context.Context, params *ec2.CreateNetworkAclEntryInput, optFns ...func(*ec2.Options)) (*ec2.CreateNetworkAclEntryOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	aclID := aws.ToString(params.NetworkAclId)
+	acl, ok := f.networkAcls[aclID]
+	if !ok {
+		return nil, networkAclNotFound(aclID)
+	}
+	if params.RuleNumber == nil {
+		return nil, missingParameter("ruleNumber")
+	}
+	if params.Protocol == nil {
+		return nil, missingParameter("protocol")
+	}
+	ruleNumber := *params.RuleNumber
+	if ruleNumber < 1 || ruleNumber > 32766 {
+		return nil, apiError(awserrors.InvalidParameterValue, "Value (%d) for parameter ruleNumber is invalid.", ruleNumber)
+	}
+	egress := aws.ToBool(params.Egress)
+	for _, entry := range acl.Entries {
+		if aws.ToInt32(entry.RuleNumber) == ruleNumber && aws.ToBool(entry.Egress) == egress {
+			return nil, apiError(awserrors.NetworkAclEntryAlreadyExists, "The network acl entry identified by %d already exists.", ruleNumber)
+		}
+	}
+	acl.Entries = append(acl.Entries, types.NetworkAclEntry{
+		CidrBlock:     params.CidrBlock,
+		Egress:        aws.Bool(egress),
+		IcmpTypeCode:  params.IcmpTypeCode,
+		Ipv6CidrBlock: params.Ipv6CidrBlock,
+		PortRange:     params.PortRange,
+		Protocol:      params.Protocol,
+		RuleAction:    params.RuleAction,
+		RuleNumber:    aws.Int32(ruleNumber),
+	})
+	return &ec2.CreateNetworkAclEntryOutput{}, nil
+}
+
+// DeleteNetworkAclEntry removes a rule from a network ACL.
+func (f *EC2) DeleteNetworkAclEntry(ctx context.Context, params *ec2.DeleteNetworkAclEntryInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkAclEntryOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	aclID := aws.ToString(params.NetworkAclId)
+	acl, ok := f.networkAcls[aclID]
+	if !ok {
+		return nil, networkAclNotFound(aclID)
+	}
+	ruleNumber := aws.ToInt32(params.RuleNumber)
+	if ruleNumber == 32767 {
+		return nil, apiError(awserrors.InvalidParameterValue, "Value (%d) for parameter ruleNumber is invalid.", ruleNumber)
+	}
+	for i, entry := range acl.Entries {
+		if aws.ToInt32(entry.RuleNumber) == ruleNumber && aws.ToBool(entry.Egress) == aws.ToBool(params.Egress) {
+			acl.Entries = append(acl.Entries[:i], acl.Entries[i+1:]...)
+			return &ec2.DeleteNetworkAclEntryOutput{}, nil
+		}
+	}
+	return nil, apiError(awserrors.InvalidNetworkAclEntry, "The network acl entry identified by %d does not exist.", ruleNumber)
+}
+
+func vpcEndpointFilterAttributes(endpoint *types.VpcEndpoint) map[string][]string {
+	return map[string][]string{
+		"vpc-endpoint-id":    {aws.ToString(endpoint.VpcEndpointId)},
+		"vpc-id":             {aws.ToString(endpoint.VpcId)},
+		"service-name":       {aws.ToString(endpoint.ServiceName)},
+		"vpc-endpoint-state": {string(endpoint.State)},
+		"vpc-endpoint-type":  {string(endpoint.VpcEndpointType)},
+	}
+}
+
+func vpcEndpointTags(endpoint *types.VpcEndpoint) []types.Tag {
+	return endpoint.Tags
+}
+
+// CreateVpcEndpoint creates an available endpoint. Interface endpoints get a requester managed
+// network interface in each of their subnets.
+func (f *EC2) CreateVpcEndpoint(ctx context.Context, params *ec2.CreateVpcEndpointInput, optFns ...func(*ec2.Options)) (*ec2.CreateVpcEndpointOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.VpcId == nil {
+		return nil, missingParameter("vpcId")
+	}
+	if params.ServiceName == nil {
+		return nil, missingParameter("serviceName")
+	}
+	vpcID := *params.VpcId
+	if _, ok := f.vpcs[vpcID]; !ok {
+		return nil, vpcNotFound(vpcID)
+	}
+	for _, routeTableID := range params.RouteTableIds {
+		if routeTable, ok := f.routeTables[routeTableID]; !ok || aws.ToString(routeTable.VpcId) != vpcID {
+			return nil, routeTableNotFound(routeTableID)
+		}
+	}
+	for _, subnetID := range params.SubnetIds {
+		if subnet, ok := f.subnets[subnetID]; !ok || aws.ToString(subnet.VpcId) != vpcID {
+			return nil, subnetNotFound(subnetID)
+		}
+	}
+	groupIDs := params.SecurityGroupIds
+	for _, groupID := range groupIDs {
+		if group, ok := f.securityGroups[groupID]; !ok || aws.ToString(group.VpcId) != vpcID {
+			return nil, securityGroupNotFound(groupID)
+		}
+	}
+	endpointType := params.VpcEndpointType
+	if endpointType == "" {
+		endpointType = types.VpcEndpointTypeGateway
+	}
+	endpointID := f.newID("vpce")
+	endpoint := &types.VpcEndpoint{
+		VpcEndpointId:     aws.String(endpointID),
+		VpcEndpointType:   endpointType,
+		VpcId:             aws.String(vpcID),
+		ServiceName:       params.ServiceName,
+		State:             types.StateAvailable,
+		OwnerId:           aws.String(f.accountID),
+		CreationTimestamp: f.now(),
+		PrivateDnsEnabled: params.PrivateDnsEnabled,
+		PolicyDocument:    params.PolicyDocument,
+		RouteTableIds:     append([]string{}, params.RouteTableIds...),
+		SubnetIds:         append([]string{}, params.SubnetIds...),
+		Tags:              tagsFor(params.TagSpecifications, types.ResourceTypeVpcEndpoint),
+	}
+	if endpointType == types.VpcEndpointTypeInterface {
+		if len(groupIDs) == 0 {
+			groupIDs = []string{f.defaultSecurityGroupID(vpcID)}
+		}
+		for _, groupID := range groupIDs {
+			endpoint.Groups = append(endpoint.Groups, types.SecurityGroupIdentifier{
+				GroupId:   aws.String(groupID),
+				GroupName: f.securityGroups[groupID].GroupName,
+			})
+		}
+		for _, subnetID := range params.SubnetIds {
+			networkInterface := f.createNetworkInterface(f.subnets[subnetID], types.NetworkInterfaceTypeVpcEndpoint,
+				fmt.Sprintf("VPC Endpoint Interface %s", endpointID), groupIDs, true)
+			endpoint.NetworkInterfaceIds = append(endpoint.NetworkInterfaceIds, aws.ToString(networkInterface.NetworkInterfaceId))
+		}
+	}
+	f.vpcEndpoints[endpointID] = endpoint
+	return &ec2.CreateVpcEndpointOutput{VpcEndpoint: cloned(endpoint)}, nil
+}
+
+// DescribeVpcEndpoints returns the endpoints selected by IDs and filters.
+func (f *EC2) DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &ec2.DescribeVpcEndpointsInput{}
+	}
+	endpoints, err := describe(f.vpcEndpoints, params.VpcEndpointIds, params.Filters, vpcEndpointNotFound,
+		vpcEndpointFilterAttributes, vpcEndpointTags)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeVpcEndpointsOutput{VpcEndpoints: endpoints}, nil
+}
+
+// ModifyVpcEndpoint adds route tables and subnets to an endpoint or removes them from it. Subnets
+// added to an interface endpoint get a network interface, removed ones lose theirs.
+func (f *EC2) ModifyVpcEndpoint(ctx context.Context, params *ec2.ModifyVpcEndpointInput, optFns ...func(*ec2.Options)) (*ec2.ModifyVpcEndpointOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params.VpcEndpointId == nil {
+		return nil, missingParameter("VpcEndpointId")
+	}
+	endpoint, ok := f.vpcEndpoints[*params.VpcEndpointId]
+	if !ok {
+		return nil, vpcEndpointNotFound(*params.VpcEndpointId)
+	}
+	vpcID := aws.ToString(endpoint.VpcId)
+	for _, routeTableID := range params.AddRouteTableIds {
+		if routeTable, ok := f.routeTables[routeTableID]; !ok || aws.ToString(routeTable.VpcId) != vpcID {
+			return nil, routeTableNotFound(routeTableID)
+		}
+	}
+	for _, subnetID := range params.AddSubnetIds {
+		if subnet, ok := f.subnets[subnetID]; !ok || aws.ToString(subnet.VpcId) != vpcID {
+			return nil, subnetNotFound(subnetID)
+		}
+	}
+	for _, routeTableID := range params.AddRouteTableIds {
+		if !containsID(endpoint.RouteTableIds, routeTableID) {
+			endpoint.RouteTableIds = append(endpoint.RouteTableIds, routeTableID)
+		}
+	}
+	endpoint.RouteTableIds = withoutIDs(endpoint.RouteTableIds, params.RemoveRouteTableIds...)
+	groupIDs := []string{}
+	for _, group := range endpoint.Groups {
+		groupIDs = append(groupIDs, aws.ToString(group.GroupId))
+	}
+	for _, subnetID := range params.AddSubnetIds {
+		if containsID(endpoint.SubnetIds, subnetID) {
+			continue
+		}
+		endpoint.SubnetIds = append(endpoint.SubnetIds, subnetID)
+		if endpoint.VpcEndpointType == types.VpcEndpointTypeInterface {
+			networkInterface := f.createNetworkInterface(f.subnets[subnetID], types.NetworkInterfaceTypeVpcEndpoint,
+				fmt.Sprintf("VPC Endpoint Interface %s", aws.ToString(endpoint.VpcEndpointId)), groupIDs, true)
+			endpoint.NetworkInterfaceIds = append(endpoint.NetworkInterfaceIds, aws.ToString(networkInterface.NetworkInterfaceId))
+		}
+	}
+	for _, subnetID := range params.RemoveSubnetIds {
+		for _, networkInterfaceID := range endpoint.NetworkInterfaceIds {
+			if networkInterface, ok := f.networkInterfaces[networkInterfaceID]; ok && aws.ToString(networkInterface.SubnetId) == subnetID {
+				f.deleteNetworkInterface(networkInterfaceID)
+				endpoint.NetworkInterfaceIds = withoutIDs(endpoint.NetworkInterfaceIds, networkInterfaceID)
+				break
+			}
+		}
+	}
+	endpoint.SubnetIds = withoutIDs(endpoint.SubnetIds, params.RemoveSubnetIds...)
+	return &ec2.ModifyVpcEndpointOutput{Return: aws.Bool(true)}, nil
+}
+
+// DeleteVpcEndpoints deletes endpoints and their network interfaces. Like AWS, endpoints that do
+// not exist are reported as unsuccessful items instead of failing the call.
+func (f *EC2) DeleteVpcEndpoints(ctx context.Context, params *ec2.DeleteVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVpcEndpointsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if len(params.VpcEndpointIds) == 0 {
+		return nil, missingParameter("VpcEndpointId")
+	}
+	output := &ec2.DeleteVpcEndpointsOutput{Unsuccessful: []types.UnsuccessfulItem{}}
+	for _, endpointID := range params.VpcEndpointIds {
+		endpoint, ok := f.vpcEndpoints[endpointID]
+		if !ok {
+			output.Unsuccessful = append(output.Unsuccessful, types.UnsuccessfulItem{
+				ResourceId: aws.String(endpointID),
+				Error: &types.UnsuccessfulItemError{
+					Code:    aws.String(awserrors.InvalidVpcEndpointID),
+					Message: aws.String(fmt.Sprintf("The Vpc Endpoint Id '%s' does not exist", endpointID)),
+				},
+			})
+			continue
+		}
+		for _, networkInterfaceID := range endpoint.NetworkInterfaceIds {
+			f.deleteNetworkInterface(networkInterfaceID)
+		}
+		delete(f.vpcEndpoints, endpointID)
+	}
+	return output, nil
+}
@@ -0,0 +1,280 @@
+package aws_fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+)
+
+const (
+	// DefaultPoliciesPerRole is the number of managed policies that can be attached to a role of a
+	// fake unless WithPoliciesPerRole is given.
+	DefaultPoliciesPerRole = 10
+	// TagsPerResource is the number of tags an IAM resource can carry.
+	TagsPerResource = 50
+
+	defaultIAMPageSize = 100
+)
+
+var _ aws_client.IAMClientAPI = &IAM{}
+
+// IAMOption configures a fake built by NewIAM.
+type IAMOption func(*IAM)
+
+// WithIAMAccountID sets the account owning the roles, policies and providers of the fake.
+func WithIAMAccountID(accountID string) IAMOption {
+	return func(f *IAM) {
+		f.accountID = accountID
+	}
+}
+
+// WithPoliciesPerRole sets the number of managed policies that can be attached to one role.
+func WithPoliciesPerRole(limit int) IAMOption {
+	return func(f *IAM) {
+		f.policiesPerRole = limit
+	}
+}
+
+// IAM is an in-memory implementation of aws_client.IAMClientAPI. It keeps roles with their trust
+// policies, customer and AWS managed policies with their versions, role attachments, tags,
+// instance profiles and OpenID Connect providers, and enforces the rules of IAM: a role with
+// attached or inline policies cannot be deleted, a policy keeps at most five versions and so on. Failures
+// are the typed exceptions of the IAM SDK, so callers can check them with the helpers of
+// pkg/aws/errors as they would against AWS.
+//
+// Documents are validated with pkg/aws/iampolicy and returned percent-encoded, as IAM returns
+// them. Changes are visible immediately. The fake is safe for concurrent use.
+type IAM struct {
+	mutex           sync.Mutex
+	accountID       string
+	policiesPerRole int
+	counter         uint64
+	lastTimestamp   time.Time
+
+	roles            map[string]*iamRole
+	policies         map[string]*iamPolicy
+	instanceProfiles map[string]*iamInstanceProfile
+	oidcProviders    map[string]*iamOIDCProvider
+}
+
+// iamRole is a role with the ARNs of its attached managed policies in attachment order and the
+// documents of its inline policies by name.
+type iamRole struct {
+	role     types.Role
+	policies []string
+	inline   map[string]string
+}
+
+// iamPolicy is a managed policy with its versions in creation order.
+type iamPolicy struct {
+	policy      types.Policy
+	versions    []types.PolicyVersion
+	nextVersion int
+}
+
+// iamInstanceProfile is an instance profile with the names of its roles.
+type iamInstanceProfile struct {
+	profile types.InstanceProfile
+	roles   []string
+}
+
+type iamOIDCProvider struct {
+	url         string
+	clientIDs   []string
+	thumbprints []string
+	tags        []types.Tag
+	createDate  time.Time
+}
+
+// NewIAM returns an empty fake.
+func NewIAM(opts ...IAMOption) *IAM {
+	f := &IAM{
+		accountID:        DefaultAccountID,
+		policiesPerRole:  DefaultPoliciesPerRole,
+		roles:            map[string]*iamRole{},
+		policies:         map[string]*iamPolicy{},
+		instanceProfiles: map[string]*iamInstanceProfile{},
+		oidcProviders:    map[string]*iamOIDCProvider{},
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// AccountID returns the account owning the resources of the fake.
+func (f *IAM) AccountID() string {
+	return f.accountID
+}
+
+// newUniqueID returns a new unique ID with the given prefix, formatted like the IDs of IAM, for
+// example AROA for roles and ANPA for policies.
+func (f *IAM) newUniqueID(prefix string) string {
+	f.counter++
+	return fmt.Sprintf("%s%017X", prefix, f.counter)
+}
+
+// arn returns the ARN of an IAM resource of the fake.
+func (f *IAM) arn(resource string, path string, name string) string {
+	return fmt.Sprintf("arn:aws:iam::%s:%s%s%s", f.accountID, resource, path, name)
+}
+
+// timestamp returns the current time, later than every timestamp returned before so resources
+// created in a row keep their order.
+func (f *IAM) timestamp() *time.Time {
+	stamp := time.Now().UTC()
+	if !stamp.After(f.lastTimestamp) {
+		stamp = f.lastTimestamp.Add(time.Millisecond)
+	}
+	f.lastTimestamp = stamp
+	return aws.Time(stamp)
+}
+
+// normalizedPath returns path, defaulting to / as IAM does. Paths must start and end with a slash.
+func normalizedPath(path *string) (string, error) {
+	if path == nil || *path == "" {
+		return "/", nil
+	}
+	if !strings.HasPrefix(*path, "/") || !strings.HasSuffix(*path, "/") {
+		return "", invalidInput("The specified value for path is invalid. It must begin and end with / and contain only alphanumeric characters and/or / characters.")
+	}
+	return *path, nil
+}
+
+// checkDocument validates a policy document, which must be plain JSON rather than the
+// percent-encoded form IAM returns. Trust policies must name a principal in every
+// statement and must not name resources; permission policies must not name principals.
+func checkDocument(document *string, limit int, trust bool) error {
+	if !json.Valid([]byte(aws.ToString(document))) {
+		return malformedPolicyDocument("Syntax errors in policy.")
+	}
+	parsed, err := iampolicy.ParseDocument(*document)
+	if err != nil {
+		return malformedPolicyDocument("Syntax errors in policy.")
+	}
+	if err := parsed.Validate(); err != nil {
+		return malformedPolicyDocument("%v", err)
+	}
+	for i, statement := range parsed.Statement {
+		switch {
+		case trust && statement.Principal == nil && statement.NotPrincipal == nil:
+			return malformedPolicyDocument("Statement %d: Missing required field Principal", i)
+		case trust && (len(statement.Resource) != 0 || len(statement.NotResource) != 0):
+			return malformedPolicyDocument("Statement %d: Has prohibited field Resource", i)
+		case !trust && (statement.Principal != nil || statement.NotPrincipal != nil):
+			return malformedPolicyDocument("Statement %d: Policy document should not specify a principal.", i)
+		}
+	}
+	if err := iampolicy.CheckPolicySize(*document, limit); err != nil {
+		return limitExceeded("Cannot exceed quota for policy document size: %d", limit)
+	}
+	return nil
+}
+
+// encodeDocument percent-encodes a policy document, as IAM returns documents.
+func encodeDocument(document string) *string {
+	return aws.String(url.PathEscape(document))
+}
+
+// mergeTags adds or overwrites tags, failing when the result would exceed TagsPerResource.
+func mergeTags(existing []types.Tag, added []types.Tag) ([]types.Tag, error) {
+	merged := clone(existing)
+	for _, tag := range added {
+		replaced := false
+		for i := range merged {
+			if aws.ToString(merged[i].Key) == aws.ToString(tag.Key) {
+				merged[i].Value = aws.String(aws.ToString(tag.Value))
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, types.Tag{Key: aws.String(aws.ToString(tag.Key)), Value: aws.String(aws.ToString(tag.Value))})
+		}
+	}
+	if len(merged) > TagsPerResource {
+		return nil, limitExceeded("The number of tags has reached the maximum limit.")
+	}
+	return merged, nil
+}
+
+// removeTags returns existing without the tags with the given keys.
+func removeTags(existing []types.Tag, keys []string) []types.Tag {
+	kept := []types.Tag{}
+	for _, tag := range existing {
+		removed := false
+		for _, key := range keys {
+			if aws.ToString(tag.Key) == key {
+				removed = true
+			}
+		}
+		if !removed {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// page returns the items of one page selected by marker and maxItems, with the marker of the next
+// page when the result is truncated. Markers are the offset of the first item of the page.
+func page[T any](items []T, marker *string, maxItems *int32) ([]T, *string, bool, error) {
+	start := 0
+	if marker != nil {
+		offset, err := strconv.Atoi(*marker)
+		if err != nil || offset < 0 || offset > len(items) {
+			return nil, nil, false, invalidInput("Invalid Marker.")
+		}
+		start = offset
+	}
+	size := defaultIAMPageSize
+	if maxItems != nil {
+		if *maxItems < 1 || *maxItems > 1000 {
+			return nil, nil, false, invalidInput("1 validation error detected: Value '%d' at 'maxItems' failed to satisfy constraint", *maxItems)
+		}
+		size = int(*maxItems)
+	}
+	end := start + size
+	if end >= len(items) {
+		return items[start:], nil, false, nil
+	}
+	return items[start:end], aws.String(strconv.Itoa(end)), true, nil
+}
+
+func noSuchEntity(format string, args ...interface{}) error {
+	return &types.NoSuchEntityException{Message: aws.String(fmt.Sprintf(format, args...))}
+}
+
+func entityAlreadyExists(format string, args ...interface{}) error {
+	return &types.EntityAlreadyExistsException{Message: aws.String(fmt.Sprintf(format, args...))}
+}
+
+func deleteConflict(format string, args ...interface{}) error {
+	return &types.DeleteConflictException{Message: aws.String(fmt.Sprintf(format, args...))}
+}
+
+func limitExceeded(format string, args ...interface{}) error {
+	return &types.LimitExceededException{Message: aws.String(fmt.Sprintf(format, args...))}
+}
+
+func malformedPolicyDocument(format string, args ...interface{}) error {
+	return &types.MalformedPolicyDocumentException{Message: aws.String(fmt.Sprintf(format, args...))}
+}
+
+func invalidInput(format string, args ...interface{}) error {
+	return &types.InvalidInputException{Message: aws.String(fmt.Sprintf(format, args...))}
+}
+
+// accessDenied is returned for changes to AWS managed policies, which belong to another account.
+func accessDenied(action string, resource string) error {
+	return apiError(awserrors.AccessDenied, "User is not authorized to perform: iam:%s on resource: %s", action, resource)
+}
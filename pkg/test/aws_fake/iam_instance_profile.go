@@ -0,0 +1,255 @@
+package aws_fake
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// findInstanceProfile returns the instance profile named name. Names are case insensitive.
+func (f *IAM) findInstanceProfile(name *string) (*iamInstanceProfile, error) {
+	profile, ok := f.instanceProfiles[strings.ToLower(aws.ToString(name))]
+	if !ok {
+		return nil, noSuchEntity("Instance Profile %s cannot be found.", aws.ToString(name))
+	}
+	return profile, nil
+}
+
+// profilesOf returns the keys of the instance profiles holding the role named roleName.
+func (f *IAM) profilesOf(roleName string) []string {
+	keys := []string{}
+	for _, key := range sortedIDs(f.instanceProfiles) {
+		for _, name := range f.instanceProfiles[key].roles {
+			if strings.EqualFold(name, roleName) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// instanceProfileOutput returns a copy of profile listing its current roles.
+func (f *IAM) instanceProfileOutput(profile *iamInstanceProfile) *types.InstanceProfile {
+	output := cloned(&profile.profile)
+	output.Roles = []types.Role{}
+	for _, name := range profile.roles {
+		role := clone(f.roles[strings.ToLower(name)].role)
+		role.Tags = nil
+		output.Roles = append(output.Roles, role)
+	}
+	return output
+}
+
+// CreateInstanceProfile creates an empty instance profile.
+func (f *IAM) CreateInstanceProfile(ctx context.Context, params *iam.CreateInstanceProfileInput, optFns ...func(*iam.Options)) (*iam.CreateInstanceProfileOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	name := aws.ToString(params.InstanceProfileName)
+	if name == "" {
+		return nil, invalidInput("The specified value for instanceProfileName is invalid.")
+	}
+	if _, ok := f.instanceProfiles[strings.ToLower(name)]; ok {
+		return nil, entityAlreadyExists("Instance Profile %s already exists.", name)
+	}
+	path, err := normalizedPath(params.Path)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := mergeTags(nil, params.Tags)
+	if err != nil {
+		return nil, err
+	}
+	profile := &iamInstanceProfile{profile: types.InstanceProfile{
+		InstanceProfileName: aws.String(name),
+		InstanceProfileId:   aws.String(f.newUniqueID("AIPA")),
+		Arn:                 aws.String(f.arn("instance-profile", path, name)),
+		Path:                aws.String(path),
+		CreateDate:          f.timestamp(),
+		Tags:                tags,
+	}}
+	f.instanceProfiles[strings.ToLower(name)] = profile
+	return &iam.CreateInstanceProfileOutput{InstanceProfile: f.instanceProfileOutput(profile)}, nil
+}
+
+// GetInstanceProfile returns an instance profile with its roles.
+func (f *IAM) GetInstanceProfile(ctx context.Context, params *iam.GetInstanceProfileInput, optFns ...func(*iam.Options)) (*iam.GetInstanceProfileOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	profile, err := f.findInstanceProfile(params.InstanceProfileName)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.GetInstanceProfileOutput{InstanceProfile: f.instanceProfileOutput(profile)}, nil
+}
+
+// DeleteInstanceProfile deletes an instance profile. Profiles holding a role cannot be deleted.
+func (f *IAM) DeleteInstanceProfile(ctx context.Context, params *iam.DeleteInstanceProfileInput, optFns ...func(*iam.Options)) (*iam.DeleteInstanceProfileOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	profile, err := f.findInstanceProfile(params.InstanceProfileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(profile.roles) != 0 {
+		return nil, deleteConflict("Cannot delete entity, must remove roles from instance profile first.")
+	}
+	delete(f.instanceProfiles, strings.ToLower(aws.ToString(params.InstanceProfileName)))
+	return &iam.DeleteInstanceProfileOutput{}, nil
+}
+
+// AddRoleToInstanceProfile adds a role to an instance profile. Like IAM, a profile holds at most
+// one role.
+func (f *IAM) AddRoleToInstanceProfile(ctx context.Context, params *iam.AddRoleToInstanceProfileInput, optFns ...func(*iam.Options)) (*iam.AddRoleToInstanceProfileOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	profile, err := f.findInstanceProfile(params.InstanceProfileName)
+	if err != nil {
+		return nil, err
+	}
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	if len(profile.roles) != 0 {
+		return nil, limitExceeded("Cannot exceed quota for InstanceSessionsPerInstanceProfile: 1")
+	}
+	profile.roles = append(profile.roles, aws.ToString(role.role.RoleName))
+	return &iam.AddRoleToInstanceProfileOutput{}, nil
+}
+
+// RemoveRoleFromInstanceProfile removes a role from an instance profile.
+func (f *IAM) RemoveRoleFromInstanceProfile(ctx context.Context, params *iam.RemoveRoleFromInstanceProfileInput, optFns ...func(*iam.Options)) (*iam.RemoveRoleFromInstanceProfileOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	profile, err := f.findInstanceProfile(params.InstanceProfileName)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range profile.roles {
+		if strings.EqualFold(name, aws.ToString(params.RoleName)) {
+			profile.roles = append(profile.roles[:i], profile.roles[i+1:]...)
+			return &iam.RemoveRoleFromInstanceProfileOutput{}, nil
+		}
+	}
+	return nil, noSuchEntity("The role with name %s cannot be found.", aws.ToString(params.RoleName))
+}
+
+// ListInstanceProfilesForRole returns the instance profiles holding a role.
+func (f *IAM) ListInstanceProfilesForRole(ctx context.Context, params *iam.ListInstanceProfilesForRoleInput, optFns ...func(*iam.Options)) (*iam.ListInstanceProfilesForRoleOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	profiles := []types.InstanceProfile{}
+	for _, key := range f.profilesOf(aws.ToString(role.role.RoleName)) {
+		profile := f.instanceProfileOutput(f.instanceProfiles[key])
+		profile.Tags = nil
+		profiles = append(profiles, *profile)
+	}
+	selected, marker, truncated, err := page(profiles, params.Marker, params.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.ListInstanceProfilesForRoleOutput{InstanceProfiles: selected, Marker: marker, IsTruncated: truncated}, nil
+}
+
+// ListInstanceProfileTags returns the tags of an instance profile.
+func (f *IAM) ListInstanceProfileTags(ctx context.Context, params *iam.ListInstanceProfileTagsInput, optFns ...func(*iam.Options)) (*iam.ListInstanceProfileTagsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	profile, err := f.findInstanceProfile(params.InstanceProfileName)
+	if err != nil {
+		return nil, err
+	}
+	selected, marker, truncated, err := page(clone(profile.profile.Tags), params.Marker, params.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.ListInstanceProfileTagsOutput{Tags: selected, Marker: marker, IsTruncated: truncated}, nil
+}
+
+// oidcProviderArn returns the ARN of the OpenID Connect provider issuing tokens at issuerURL.
+func (f *IAM) oidcProviderArn(issuerURL string) string {
+	return fmt.Sprintf("arn:aws:iam::%s:oidc-provider/%s", f.accountID, strings.TrimPrefix(issuerURL, "https://"))
+}
+
+// findOIDCProvider returns the OpenID Connect provider with the given ARN.
+func (f *IAM) findOIDCProvider(arn *string) (*iamOIDCProvider, error) {
+	provider, ok := f.oidcProviders[aws.ToString(arn)]
+	if !ok {
+		return nil, noSuchEntity("OpenIDConnect Provider not found for arn %s", aws.ToString(arn))
+	}
+	return provider, nil
+}
+
+// CreateOpenIDConnectProvider registers the OpenID Connect provider issuing tokens at an https URL.
+func (f *IAM) CreateOpenIDConnectProvider(ctx context.Context, params *iam.CreateOpenIDConnectProviderInput, optFns ...func(*iam.Options)) (*iam.CreateOpenIDConnectProviderOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	issuer, err := url.Parse(aws.ToString(params.Url))
+	if err != nil || issuer.Scheme != "https" || issuer.Host == "" {
+		return nil, invalidInput("Invalid URL %s. The URL must begin with https://", aws.ToString(params.Url))
+	}
+	arn := f.oidcProviderArn(aws.ToString(params.Url))
+	if _, ok := f.oidcProviders[arn]; ok {
+		return nil, entityAlreadyExists("Provider with url %s already exists.", aws.ToString(params.Url))
+	}
+	tags, err := mergeTags(nil, params.Tags)
+	if err != nil {
+		return nil, err
+	}
+	f.oidcProviders[arn] = &iamOIDCProvider{
+		url:         strings.TrimPrefix(aws.ToString(params.Url), "https://"),
+		clientIDs:   append([]string{}, params.ClientIDList...),
+		thumbprints: append([]string{}, params.ThumbprintList...),
+		tags:        tags,
+		createDate:  *f.timestamp(),
+	}
+	return &iam.CreateOpenIDConnectProviderOutput{OpenIDConnectProviderArn: aws.String(arn), Tags: clone(tags)}, nil
+}
+
+// GetOpenIDConnectProvider returns an OpenID Connect provider. Like IAM, the URL has no scheme.
+func (f *IAM) GetOpenIDConnectProvider(ctx context.Context, params *iam.GetOpenIDConnectProviderInput, optFns ...func(*iam.Options)) (*iam.GetOpenIDConnectProviderOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	provider, err := f.findOIDCProvider(params.OpenIDConnectProviderArn)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.GetOpenIDConnectProviderOutput{
+		Url:            aws.String(provider.url),
+		ClientIDList:   append([]string{}, provider.clientIDs...),
+		ThumbprintList: append([]string{}, provider.thumbprints...),
+		Tags:           clone(provider.tags),
+		CreateDate:     aws.Time(provider.createDate),
+	}, nil
+}
+
+// ListOpenIDConnectProviders returns the ARNs of the OpenID Connect providers of the account.
+func (f *IAM) ListOpenIDConnectProviders(ctx context.Context, params *iam.ListOpenIDConnectProvidersInput, optFns ...func(*iam.Options)) (*iam.ListOpenIDConnectProvidersOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	output := &iam.ListOpenIDConnectProvidersOutput{OpenIDConnectProviderList: []types.OpenIDConnectProviderListEntry{}}
+	for _, arn := range sortedIDs(f.oidcProviders) {
+		output.OpenIDConnectProviderList = append(output.OpenIDConnectProviderList, types.OpenIDConnectProviderListEntry{Arn: aws.String(arn)})
+	}
+	return output, nil
+}
+
+// DeleteOpenIDConnectProvider deletes an OpenID Connect provider.
+func (f *IAM) DeleteOpenIDConnectProvider(ctx context.Context, params *iam.DeleteOpenIDConnectProviderInput, optFns ...func(*iam.Options)) (*iam.DeleteOpenIDConnectProviderOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.findOIDCProvider(params.OpenIDConnectProviderArn); err != nil {
+		return nil, err
+	}
+	delete(f.oidcProviders, aws.ToString(params.OpenIDConnectProviderArn))
+	return &iam.DeleteOpenIDConnectProviderOutput{}, nil
+}
@@ -0,0 +1,340 @@
+package aws_fake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+)
+
+// awsManagedPolicyPrefix starts the ARNs of the policies AWS manages for every account.
+const awsManagedPolicyPrefix = "arn:aws:iam::aws:policy"
+
+// findPolicy returns the managed policy with the given ARN.
+func (f *IAM) findPolicy(arn *string) (*iamPolicy, error) {
+	policy, ok := f.policies[aws.ToString(arn)]
+	if !ok {
+		return nil, noSuchEntity("Policy %s does not exist or is not attachable.", aws.ToString(arn))
+	}
+	return policy, nil
+}
+
+// findCustomerPolicy returns the customer managed policy with the given ARN. AWS managed policies
+// cannot be changed.
+func (f *IAM) findCustomerPolicy(arn *string, action string) (*iamPolicy, error) {
+	policy, err := f.findPolicy(arn)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(aws.ToString(arn), awsManagedPolicyPrefix) {
+		return nil, accessDenied(action, aws.ToString(arn))
+	}
+	return policy, nil
+}
+
+// findPolicyVersion returns the version of policy with the given ID and its index.
+func findPolicyVersion(policy *iamPolicy, versionID *string) (int, error) {
+	for i, version := range policy.versions {
+		if aws.ToString(version.VersionId) == aws.ToString(versionID) {
+			return i, nil
+		}
+	}
+	return 0, noSuchEntity("Policy %s version %s does not exist or is not attachable.",
+		aws.ToString(policy.policy.Arn), aws.ToString(versionID))
+}
+
+// policyOutput returns a copy of policy with its attachment counts.
+func (f *IAM) policyOutput(policy *iamPolicy) *types.Policy {
+	output := cloned(&policy.policy)
+	attachments := int32(0)
+	boundaries := int32(0)
+	for _, role := range f.roles {
+		for _, arn := range role.policies {
+			if arn == aws.ToString(policy.policy.Arn) {
+				attachments++
+			}
+		}
+		if role.role.PermissionsBoundary != nil &&
+			aws.ToString(role.role.PermissionsBoundary.PermissionsBoundaryArn) == aws.ToString(policy.policy.Arn) {
+			boundaries++
+		}
+	}
+	output.AttachmentCount = aws.Int32(attachments)
+	output.PermissionsBoundaryUsageCount = aws.Int32(boundaries)
+	output.IsAttachable = true
+	return output
+}
+
+// addVersion appends a version with document to policy, making it the default when asked to.
+func (f *IAM) addVersion(policy *iamPolicy, document string, setAsDefault bool) types.PolicyVersion {
+	policy.nextVersion++
+	version := types.PolicyVersion{
+		VersionId:  aws.String(fmt.Sprintf("v%d", policy.nextVersion)),
+		Document:   encodeDocument(document),
+		CreateDate: f.timestamp(),
+	}
+	policy.versions = append(policy.versions, version)
+	policy.policy.UpdateDate = version.CreateDate
+	if setAsDefault {
+		setDefaultVersion(policy, version.VersionId)
+		version.IsDefaultVersion = true
+	}
+	return version
+}
+
+func setDefaultVersion(policy *iamPolicy, versionID *string) {
+	for i := range policy.versions {
+		policy.versions[i].IsDefaultVersion = aws.ToString(policy.versions[i].VersionId) == aws.ToString(versionID)
+	}
+	policy.policy.DefaultVersionId = aws.String(aws.ToString(versionID))
+}
+
+// AddAWSManagedPolicy adds an AWS managed policy with the given name and document and returns its
+// ARN. AWS managed policies can be attached to roles but not changed or deleted.
+func (f *IAM) AddAWSManagedPolicy(name string, document string) string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	arn := fmt.Sprintf("%s/%s", awsManagedPolicyPrefix, name)
+	policy := &iamPolicy{policy: types.Policy{
+		Arn:        aws.String(arn),
+		PolicyName: aws.String(name),
+		PolicyId:   aws.String(f.newUniqueID("ANPA")),
+		Path:       aws.String("/"),
+		CreateDate: f.timestamp(),
+	}}
+	f.addVersion(policy, document, true)
+	f.policies[arn] = policy
+	return arn
+}
+
+// CreatePolicy creates a customer managed policy whose default version v1 holds the document.
+func (f *IAM) CreatePolicy(ctx context.Context, params *iam.CreatePolicyInput, optFns ...func(*iam.Options)) (*iam.CreatePolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	name := aws.ToString(params.PolicyName)
+	if name == "" {
+		return nil, invalidInput("The specified value for policyName is invalid.")
+	}
+	for _, existing := range f.policies {
+		if strings.EqualFold(aws.ToString(existing.policy.PolicyName), name) &&
+			!strings.HasPrefix(aws.ToString(existing.policy.Arn), awsManagedPolicyPrefix) {
+			return nil, entityAlreadyExists("A policy called %s already exists. Duplicate names are not allowed.", name)
+		}
+	}
+	path, err := normalizedPath(params.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDocument(params.PolicyDocument, iampolicy.ManagedPolicySizeLimit, false); err != nil {
+		return nil, err
+	}
+	tags, err := mergeTags(nil, params.Tags)
+	if err != nil {
+		return nil, err
+	}
+	arn := f.arn("policy", path, name)
+	policy := &iamPolicy{policy: types.Policy{
+		Arn:         aws.String(arn),
+		PolicyName:  aws.String(name),
+		PolicyId:    aws.String(f.newUniqueID("ANPA")),
+		Path:        aws.String(path),
+		Description: params.Description,
+		CreateDate:  f.timestamp(),
+		Tags:        tags,
+	}}
+	f.addVersion(policy, *params.PolicyDocument, true)
+	f.policies[arn] = policy
+	return &iam.CreatePolicyOutput{Policy: f.policyOutput(policy)}, nil
+}
+
+// GetPolicy returns a managed policy with its default version ID and attachment count.
+func (f *IAM) GetPolicy(ctx context.Context, params *iam.GetPolicyInput, optFns ...func(*iam.Options)) (*iam.GetPolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	policy, err := f.findPolicy(params.PolicyArn)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.GetPolicyOutput{Policy: f.policyOutput(policy)}, nil
+}
+
+// DeletePolicy deletes a customer managed policy. Like IAM it refuses policies that are attached
+// to roles, used as permissions boundaries or that have versions besides the default one.
+func (f *IAM) DeletePolicy(ctx context.Context, params *iam.DeletePolicyInput, optFns ...func(*iam.Options)) (*iam.DeletePolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	policy, err := f.findCustomerPolicy(params.PolicyArn, "DeletePolicy")
+	if err != nil {
+		return nil, err
+	}
+	output := f.policyOutput(policy)
+	if aws.ToInt32(output.AttachmentCount) != 0 {
+		return nil, deleteConflict("Cannot delete a policy attached to entities.")
+	}
+	if aws.ToInt32(output.PermissionsBoundaryUsageCount) != 0 {
+		return nil, deleteConflict("Cannot delete a policy used as a permissions boundary.")
+	}
+	if len(policy.versions) > 1 {
+		return nil, deleteConflict("This policy has more than one version. Before you delete a policy, you must delete the policy's versions. The default version is deleted with the policy.")
+	}
+	delete(f.policies, aws.ToString(params.PolicyArn))
+	return &iam.DeletePolicyOutput{}, nil
+}
+
+// ListPolicies returns the managed policies selected by Scope, OnlyAttached and PathPrefix, ordered
+// by ARN. Like IAM it leaves out tags, which only GetPolicy returns.
+func (f *IAM) ListPolicies(ctx context.Context, params *iam.ListPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListPoliciesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &iam.ListPoliciesInput{}
+	}
+	policies := []types.Policy{}
+	for _, arn := range sortedIDs(f.policies) {
+		awsManaged := strings.HasPrefix(arn, awsManagedPolicyPrefix)
+		if (params.Scope == types.PolicyScopeTypeLocal && awsManaged) || (params.Scope == types.PolicyScopeTypeAws && !awsManaged) {
+			continue
+		}
+		policy := f.policyOutput(f.policies[arn])
+		if params.OnlyAttached && aws.ToInt32(policy.AttachmentCount) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(aws.ToString(policy.Path), aws.ToString(params.PathPrefix)) {
+			continue
+		}
+		policy.Tags = nil
+		policies = append(policies, *policy)
+	}
+	selected, marker, truncated, err := page(policies, params.Marker, params.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.ListPoliciesOutput{Policies: selected, Marker: marker, IsTruncated: truncated}, nil
+}
+
+// TagPolicy adds or overwrites tags of a customer managed policy.
+func (f *IAM) TagPolicy(ctx context.Context, params *iam.TagPolicyInput, optFns ...func(*iam.Options)) (*iam.TagPolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	policy, err := f.findCustomerPolicy(params.PolicyArn, "TagPolicy")
+	if err != nil {
+		return nil, err
+	}
+	tags, err := mergeTags(policy.policy.Tags, params.Tags)
+	if err != nil {
+		return nil, err
+	}
+	policy.policy.Tags = tags
+	return &iam.TagPolicyOutput{}, nil
+}
+
+// UntagPolicy removes tags from a customer managed policy.
+func (f *IAM) UntagPolicy(ctx context.Context, params *iam.UntagPolicyInput, optFns ...func(*iam.Options)) (*iam.UntagPolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	policy, err := f.findCustomerPolicy(params.PolicyArn, "UntagPolicy")
+	if err != nil {
+		return nil, err
+	}
+	policy.policy.Tags = removeTags(policy.policy.Tags, params.TagKeys)
+	return &iam.UntagPolicyOutput{}, nil
+}
+
+// CreatePolicyVersion adds a version to a customer managed policy. A policy keeps at most
+// aws_client.MaxManagedPolicyVersions versions.
+func (f *IAM) CreatePolicyVersion(ctx context.Context, params *iam.CreatePolicyVersionInput, optFns ...func(*iam.Options)) (*iam.CreatePolicyVersionOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	policy, err := f.findCustomerPolicy(params.PolicyArn, "CreatePolicyVersion")
+	if err != nil {
+		return nil, err
+	}
+	if len(policy.versions) >= aws_client.MaxManagedPolicyVersions {
+		return nil, limitExceeded("A managed policy can have up to %d versions. Before you create a new version, you must delete an existing version.",
+			aws_client.MaxManagedPolicyVersions)
+	}
+	if err := checkDocument(params.PolicyDocument, iampolicy.ManagedPolicySizeLimit, false); err != nil {
+		return nil, err
+	}
+	version := f.addVersion(policy, *params.PolicyDocument, params.SetAsDefault)
+	version.Document = nil
+	return &iam.CreatePolicyVersionOutput{PolicyVersion: &version}, nil
+}
+
+// GetPolicyVersion returns a version of a managed policy with its percent-encoded document.
+func (f *IAM) GetPolicyVersion(ctx context.Context, params *iam.GetPolicyVersionInput, optFns ...func(*iam.Options)) (*iam.GetPolicyVersionOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	policy, err := f.findPolicy(params.PolicyArn)
+	if err != nil {
+		return nil, err
+	}
+	i, err := findPolicyVersion(policy, params.VersionId)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.GetPolicyVersionOutput{PolicyVersion: cloned(&policy.versions[i])}, nil
+}
+
+// ListPolicyVersions returns the versions of a managed policy, newest first, without documents.
+func (f *IAM) ListPolicyVersions(ctx context.Context, params *iam.ListPolicyVersionsInput, optFns ...func(*iam.Options)) (*iam.ListPolicyVersionsOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	policy, err := f.findPolicy(params.PolicyArn)
+	if err != nil {
+		return nil, err
+	}
+	versions := []types.PolicyVersion{}
+	for i := len(policy.versions) - 1; i >= 0; i-- {
+		version := clone(policy.versions[i])
+		version.Document = nil
+		versions = append(versions, version)
+	}
+	selected, marker, truncated, err := page(versions, params.Marker, params.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.ListPolicyVersionsOutput{Versions: selected, Marker: marker, IsTruncated: truncated}, nil
+}
+
+// DeletePolicyVersion deletes a version of a customer managed policy. The default version cannot
+// be deleted.
+func (f *IAM) DeletePolicyVersion(ctx context.Context, params *iam.DeletePolicyVersionInput, optFns ...func(*iam.Options)) (*iam.DeletePolicyVersionOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	policy, err := f.findCustomerPolicy(params.PolicyArn, "DeletePolicyVersion")
+	if err != nil {
+		return nil, err
+	}
+	i, err := findPolicyVersion(policy, params.VersionId)
+	if err != nil {
+		return nil, err
+	}
+	if policy.versions[i].IsDefaultVersion {
+		return nil, deleteConflict("Cannot delete the default version of a policy.")
+	}
+	policy.versions = append(policy.versions[:i], policy.versions[i+1:]...)
+	return &iam.DeletePolicyVersionOutput{}, nil
+}
+
+// SetDefaultPolicyVersion makes a version the default version of a customer managed policy.
+func (f *IAM) SetDefaultPolicyVersion(ctx context.Context, params *iam.SetDefaultPolicyVersionInput, optFns ...func(*iam.Options)) (*iam.SetDefaultPolicyVersionOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	policy, err := f.findCustomerPolicy(params.PolicyArn, "SetDefaultPolicyVersion")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findPolicyVersion(policy, params.VersionId); err != nil {
+		return nil, err
+	}
+	setDefaultVersion(policy, params.VersionId)
+	policy.policy.UpdateDate = f.timestamp()
+	return &iam.SetDefaultPolicyVersionOutput{}, nil
+}
@@ -0,0 +1,296 @@
+package aws_fake
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+)
+
+const (
+	defaultMaxSessionDuration = 3600
+	// inlinePolicySizeLimit is the maximum size of the inline policies of a role.
+	inlinePolicySizeLimit = 10240
+)
+
+// findRole returns the role named name. Role names are case insensitive.
+func (f *IAM) findRole(name *string) (*iamRole, error) {
+	role, ok := f.roles[strings.ToLower(aws.ToString(name))]
+	if !ok {
+		return nil, noSuchEntity("The role with name %s cannot be found.", aws.ToString(name))
+	}
+	return role, nil
+}
+
+// CreateRole creates a role trusting the principals of its assume role policy document.
+func (f *IAM) CreateRole(ctx context.Context, params *iam.CreateRoleInput, optFns ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	name := aws.ToString(params.RoleName)
+	if name == "" {
+		return nil, invalidInput("The specified value for roleName is invalid.")
+	}
+	if _, ok := f.roles[strings.ToLower(name)]; ok {
+		return nil, entityAlreadyExists("Role with name %s already exists.", name)
+	}
+	path, err := normalizedPath(params.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDocument(params.AssumeRolePolicyDocument, iampolicy.TrustPolicySizeLimit, true); err != nil {
+		return nil, err
+	}
+	tags, err := mergeTags(nil, params.Tags)
+	if err != nil {
+		return nil, err
+	}
+	role := types.Role{
+		Path:                     aws.String(path),
+		RoleName:                 aws.String(name),
+		RoleId:                   aws.String(f.newUniqueID("AROA")),
+		Arn:                      aws.String(f.arn("role", path, name)),
+		CreateDate:               f.timestamp(),
+		AssumeRolePolicyDocument: encodeDocument(*params.AssumeRolePolicyDocument),
+		Description:              params.Description,
+		MaxSessionDuration:       aws.Int32(defaultMaxSessionDuration),
+		Tags:                     tags,
+	}
+	if params.MaxSessionDuration != nil {
+		role.MaxSessionDuration = aws.Int32(*params.MaxSessionDuration)
+	}
+	if params.PermissionsBoundary != nil {
+		if _, err := f.findPolicy(params.PermissionsBoundary); err != nil {
+			return nil, err
+		}
+		role.PermissionsBoundary = &types.AttachedPermissionsBoundary{
+			PermissionsBoundaryArn:  aws.String(*params.PermissionsBoundary),
+			PermissionsBoundaryType: types.PermissionsBoundaryAttachmentTypePolicy,
+		}
+	}
+	f.roles[strings.ToLower(name)] = &iamRole{role: role}
+	return &iam.CreateRoleOutput{Role: cloned(&role)}, nil
+}
+
+// GetRole returns a role with its tags and percent-encoded trust policy.
+func (f *IAM) GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.GetRoleOutput{Role: cloned(&role.role)}, nil
+}
+
+// DeleteRole deletes a role. Roles with attached or inline policies or in instance profiles cannot
+// be deleted.
+func (f *IAM) DeleteRole(ctx context.Context, params *iam.DeleteRoleInput, optFns ...func(*iam.Options)) (*iam.DeleteRoleOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	if len(role.policies) != 0 {
+		return nil, deleteConflict("Cannot delete entity, must detach all policies first.")
+	}
+	if len(role.inline) != 0 {
+		return nil, deleteConflict("Cannot delete entity, must delete policies first.")
+	}
+	if len(f.profilesOf(aws.ToString(role.role.RoleName))) != 0 {
+		return nil, deleteConflict("Cannot delete entity, must remove roles from instance profile first.")
+	}
+	delete(f.roles, strings.ToLower(aws.ToString(role.role.RoleName)))
+	return &iam.DeleteRoleOutput{}, nil
+}
+
+// ListRoles returns the roles under PathPrefix ordered by name. Like IAM it leaves out tags and
+// permissions boundaries, which only GetRole returns.
+func (f *IAM) ListRoles(ctx context.Context, params *iam.ListRolesInput, optFns ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if params == nil {
+		params = &iam.ListRolesInput{}
+	}
+	roles := []types.Role{}
+	for _, key := range sortedIDs(f.roles) {
+		role := clone(f.roles[key].role)
+		if !strings.HasPrefix(aws.ToString(role.Path), aws.ToString(params.PathPrefix)) {
+			continue
+		}
+		role.Tags = nil
+		role.PermissionsBoundary = nil
+		roles = append(roles, role)
+	}
+	selected, marker, truncated, err := page(roles, params.Marker, params.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.ListRolesOutput{Roles: selected, Marker: marker, IsTruncated: truncated}, nil
+}
+
+// UpdateAssumeRolePolicy replaces the trust policy of a role.
+func (f *IAM) UpdateAssumeRolePolicy(ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDocument(params.PolicyDocument, iampolicy.TrustPolicySizeLimit, true); err != nil {
+		return nil, err
+	}
+	role.role.AssumeRolePolicyDocument = encodeDocument(*params.PolicyDocument)
+	return &iam.UpdateAssumeRolePolicyOutput{}, nil
+}
+
+// TagRole adds or overwrites tags of a role.
+func (f *IAM) TagRole(ctx context.Context, params *iam.TagRoleInput, optFns ...func(*iam.Options)) (*iam.TagRoleOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := mergeTags(role.role.Tags, params.Tags)
+	if err != nil {
+		return nil, err
+	}
+	role.role.Tags = tags
+	return &iam.TagRoleOutput{}, nil
+}
+
+// UntagRole removes tags from a role. Keys the role is not tagged with are ignored.
+func (f *IAM) UntagRole(ctx context.Context, params *iam.UntagRoleInput, optFns ...func(*iam.Options)) (*iam.UntagRoleOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	role.role.Tags = removeTags(role.role.Tags, params.TagKeys)
+	return &iam.UntagRoleOutput{}, nil
+}
+
+// AttachRolePolicy attaches a managed policy to a role. Attaching a policy twice has no effect.
+func (f *IAM) AttachRolePolicy(ctx context.Context, params *iam.AttachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.AttachRolePolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := f.findPolicy(params.PolicyArn)
+	if err != nil {
+		return nil, err
+	}
+	arn := aws.ToString(policy.policy.Arn)
+	for _, attached := range role.policies {
+		if attached == arn {
+			return &iam.AttachRolePolicyOutput{}, nil
+		}
+	}
+	if len(role.policies) >= f.policiesPerRole {
+		return nil, limitExceeded("Cannot exceed quota for PoliciesPerRole: %d", f.policiesPerRole)
+	}
+	role.policies = append(role.policies, arn)
+	return &iam.AttachRolePolicyOutput{}, nil
+}
+
+// DetachRolePolicy detaches a managed policy from a role.
+func (f *IAM) DetachRolePolicy(ctx context.Context, params *iam.DetachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	for i, attached := range role.policies {
+		if attached == aws.ToString(params.PolicyArn) {
+			role.policies = append(role.policies[:i], role.policies[i+1:]...)
+			return &iam.DetachRolePolicyOutput{}, nil
+		}
+	}
+	return nil, noSuchEntity("Policy %s was not found.", aws.ToString(params.PolicyArn))
+}
+
+// ListAttachedRolePolicies returns the managed policies attached to a role in attachment order.
+func (f *IAM) ListAttachedRolePolicies(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	attached := []types.AttachedPolicy{}
+	for _, arn := range role.policies {
+		policy := f.policies[arn].policy
+		if !strings.HasPrefix(aws.ToString(policy.Path), aws.ToString(params.PathPrefix)) {
+			continue
+		}
+		attached = append(attached, types.AttachedPolicy{
+			PolicyArn:  aws.String(arn),
+			PolicyName: aws.String(aws.ToString(policy.PolicyName)),
+		})
+	}
+	selected, marker, truncated, err := page(attached, params.Marker, params.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.ListAttachedRolePoliciesOutput{AttachedPolicies: selected, Marker: marker, IsTruncated: truncated}, nil
+}
+
+// PutRolePolicy adds or replaces an inline policy of a role.
+func (f *IAM) PutRolePolicy(ctx context.Context, params *iam.PutRolePolicyInput, optFns ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	if aws.ToString(params.PolicyName) == "" {
+		return nil, invalidInput("The specified value for policyName is invalid.")
+	}
+	if err := checkDocument(params.PolicyDocument, inlinePolicySizeLimit, false); err != nil {
+		return nil, err
+	}
+	if role.inline == nil {
+		role.inline = map[string]string{}
+	}
+	role.inline[aws.ToString(params.PolicyName)] = *params.PolicyDocument
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+// DeleteRolePolicy deletes an inline policy of a role.
+func (f *IAM) DeleteRolePolicy(ctx context.Context, params *iam.DeleteRolePolicyInput, optFns ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := role.inline[aws.ToString(params.PolicyName)]; !ok {
+		return nil, noSuchEntity("The role policy with name %s cannot be found.", aws.ToString(params.PolicyName))
+	}
+	delete(role.inline, aws.ToString(params.PolicyName))
+	return &iam.DeleteRolePolicyOutput{}, nil
+}
+
+// ListRolePolicies returns the names of the inline policies of a role ordered by name.
+func (f *IAM) ListRolePolicies(ctx context.Context, params *iam.ListRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	role, err := f.findRole(params.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	selected, marker, truncated, err := page(sortedIDs(role.inline), params.Marker, params.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	return &iam.ListRolePoliciesOutput{PolicyNames: selected, Marker: marker, IsTruncated: truncated}, nil
+}
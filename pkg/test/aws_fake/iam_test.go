@@ -0,0 +1,265 @@
+package aws_fake_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+	"github.com/openshift-online/ocm-common/pkg/rosa/accountroles"
+	. "github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+)
+
+const readOnlyPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"ec2:Describe*","Resource":"*"}]}`
+
+var _ = Describe("IAM", func() {
+	var (
+		ctx    context.Context
+		fake   *IAM
+		client *aws_client.AWSClient
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = NewIAM()
+		client = &aws_client.AWSClient{IamClient: fake, AccountID: fake.AccountID()}
+	})
+
+	installerArn := func(name string) string {
+		return fmt.Sprintf("arn:aws:iam::%s:role/%s", DefaultAccountID, name)
+	}
+
+	createPolicy := func(name string) string {
+		policyArn, err := client.CreatePolicyFromDocument(name, iampolicy.NewDocument().AddStatement(
+			iampolicy.NewStatement().AddAction("ec2:DescribeVpcs").AddResource("*")))
+		Expect(err).ToNot(HaveOccurred())
+		return policyArn
+	}
+
+	trustPolicy := func() string {
+		document, err := iampolicy.NewDocument().AddStatement(iampolicy.NewStatement().
+			AddAWSPrincipal(installerArn("installer")).AddAction("sts:AssumeRole")).JSON()
+		Expect(err).ToNot(HaveOccurred())
+		return document
+	}
+
+	It("creates a role with an attached policy and deletes both", func() {
+		policyArn := createPolicy("ocm-policy")
+		role, err := client.CreateRoleAndAttachPolicy("ocm-role", trustPolicy(), "", map[string]string{"owner": "ocm"}, "/ocm/", policyArn)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.ToString(role.Arn)).To(Equal(installerArn("ocm/ocm-role")))
+		Expect(aws.ToString(role.RoleId)).To(HavePrefix("AROA"))
+
+		attached, err := client.IsPolicyAttachedToRole("ocm-role", policyArn)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(attached).To(BeTrue())
+		policy, err := client.GetIAMPolicy(policyArn)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.ToInt32(policy.AttachmentCount)).To(Equal(int32(1)))
+		Expect(aws.ToString(policy.DefaultVersionId)).To(Equal("v1"))
+
+		fetched, err := client.GetRole("OCM-ROLE")
+		Expect(err).ToNot(HaveOccurred())
+		document, err := url.PathUnescape(aws.ToString(fetched.AssumeRolePolicyDocument))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(document).To(Equal(trustPolicy()))
+		Expect(fetched.Tags).To(HaveLen(1))
+
+		_, err = client.CreatePolicyVersion(policyArn, readOnlyPolicy, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.DeleteRoleAndPolicy("ocm-role", false)).To(Succeed())
+
+		_, err = client.GetRole("ocm-role")
+		Expect(awserrors.IsNoSuchEntityException(err)).To(BeTrue())
+		_, err = fake.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: aws.String(policyArn)})
+		Expect(awserrors.IsErrorCode(err, awserrors.NoSuchEntity)).To(BeTrue())
+	})
+
+	It("refuses duplicate roles and policies", func() {
+		_, err := client.CreateRole("ocm-role", trustPolicy(), "", nil, "")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.CreateRole("OCM-Role", trustPolicy(), "", nil, "")
+		Expect(awserrors.IsEntityAlreadyExistsException(err)).To(BeTrue())
+
+		createPolicy("ocm-policy")
+		_, err = client.CreatePolicyFromDocument("ocm-policy", iampolicy.NewDocument().AddStatement(
+			iampolicy.NewStatement().AddAction("ec2:DescribeVpcs").AddResource("*")))
+		Expect(awserrors.IsErrorCode(err, awserrors.EntityAlreadyExists)).To(BeTrue())
+	})
+
+	It("reports delete conflicts", func() {
+		policyArn := createPolicy("ocm-policy")
+		_, err := client.CreateRoleAndAttachPolicy("ocm-role", trustPolicy(), "", nil, "", policyArn)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = client.DeleteRole("ocm-role")
+		Expect(awserrors.IsDeleteConfictException(err)).To(BeTrue())
+		_, err = fake.DeletePolicy(ctx, &iam.DeletePolicyInput{PolicyArn: aws.String(policyArn)})
+		Expect(awserrors.IsErrorCode(err, awserrors.DeleteConflict)).To(BeTrue())
+		_, err = fake.DeletePolicyVersion(ctx, &iam.DeletePolicyVersionInput{
+			PolicyArn: aws.String(policyArn),
+			VersionId: aws.String("v1"),
+		})
+		Expect(awserrors.IsDeleteConfictException(err)).To(BeTrue())
+
+		Expect(client.DetachRolePolicies("ocm-role")).To(Succeed())
+		_, err = fake.CreateInstanceProfile(ctx, &iam.CreateInstanceProfileInput{InstanceProfileName: aws.String("ocm-profile")})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fake.AddRoleToInstanceProfile(ctx, &iam.AddRoleToInstanceProfileInput{
+			InstanceProfileName: aws.String("ocm-profile"),
+			RoleName:            aws.String("ocm-role"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		err = client.DeleteRole("ocm-role")
+		Expect(awserrors.IsDeleteConfictException(err)).To(BeTrue())
+
+		Expect(client.DeleteRoleInstanceProfiles("ocm-role")).To(Succeed())
+		document, err := iampolicy.NewDocument().AddStatement(
+			iampolicy.NewStatement().AddAction("ec2:DescribeVpcs").AddResource("*")).JSON()
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fake.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+			RoleName:       aws.String("ocm-role"),
+			PolicyName:     aws.String("ocm-inline"),
+			PolicyDocument: aws.String(document),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		err = client.DeleteRole("ocm-role")
+		Expect(awserrors.IsDeleteConfictException(err)).To(BeTrue())
+
+		Expect(client.DeleteRoleInlinePolicies("ocm-role")).To(Succeed())
+		inline, err := fake.ListRolePolicies(ctx, &iam.ListRolePoliciesInput{RoleName: aws.String("ocm-role")})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inline.PolicyNames).To(BeEmpty())
+		Expect(client.DeleteRole("ocm-role")).To(Succeed())
+		Expect(client.DeleteIAMPolicy(policyArn)).To(Succeed())
+	})
+
+	It("limits policy versions and attachments", func() {
+		policyArn := createPolicy("ocm-policy")
+		for i := 0; i < aws_client.MaxManagedPolicyVersions-1; i++ {
+			_, err := fake.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{
+				PolicyArn:      aws.String(policyArn),
+				PolicyDocument: aws.String(readOnlyPolicy),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		}
+		_, err := fake.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{
+			PolicyArn:      aws.String(policyArn),
+			PolicyDocument: aws.String(readOnlyPolicy),
+		})
+		Expect(awserrors.IsLimitExceededException(err)).To(BeTrue())
+
+		version, err := client.CreatePolicyVersion(policyArn, readOnlyPolicy, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.ToString(version.VersionId)).To(Equal("v6"))
+		versions, err := client.ListPolicyVersionsWithDocuments(policyArn)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(versions).To(HaveLen(aws_client.MaxManagedPolicyVersions))
+		Expect(versions[0].IsDefault).To(BeTrue())
+		Expect(versions[len(versions)-1].VersionID).To(Equal("v1"))
+
+		fake = NewIAM(WithPoliciesPerRole(1))
+		client.IamClient = fake
+		_, err = client.CreateRoleAndAttachPolicy("ocm-role", trustPolicy(), "", nil, "", createPolicy("first"))
+		Expect(err).ToNot(HaveOccurred())
+		err = client.AttachPolicy("ocm-role", createPolicy("second"), 0, 0)
+		Expect(awserrors.IsLimitExceededException(err)).To(BeTrue())
+	})
+
+	It("validates documents", func() {
+		_, err := client.CreateRole("ocm-role", readOnlyPolicy, "", nil, "")
+		Expect(awserrors.IsErrorCode(err, awserrors.MalformedPolicyDocument)).To(BeTrue())
+		_, err = client.CreateRole("ocm-role", url.PathEscape(trustPolicy()), "", nil, "")
+		Expect(awserrors.IsErrorCode(err, awserrors.MalformedPolicyDocument)).To(BeTrue())
+		_, err = fake.CreatePolicy(ctx, &iam.CreatePolicyInput{
+			PolicyName:     aws.String("ocm-policy"),
+			PolicyDocument: aws.String(trustPolicy()),
+		})
+		Expect(awserrors.IsErrorCode(err, awserrors.MalformedPolicyDocument)).To(BeTrue())
+	})
+
+	It("updates the trust policy of shared VPC roles", func() {
+		_, err := client.CreateRoleForSharedVPC("shared-vpc-role", installerArn("installer"), installerArn("ingress"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.UpdateAssumeRolePolicyForSharedVPCRole("shared-vpc-role",
+			installerArn("installer"), installerArn("ingress"), installerArn("control-plane"))).To(Succeed())
+
+		role, err := client.GetRole("shared-vpc-role")
+		Expect(err).ToNot(HaveOccurred())
+		document, err := iampolicy.ParseDocument(aws.ToString(role.AssumeRolePolicyDocument))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(document.Statement[0].Principal.AWS).To(ConsistOf(
+			installerArn("installer"), installerArn("ingress"), installerArn("control-plane")))
+
+		err = client.UpdateAssumeRolePolicyForSharedVPCRole("missing-role", installerArn("installer"))
+		Expect(awserrors.IsNoSuchEntityException(err)).To(BeTrue())
+	})
+
+	It("applies STS external IDs to account role trust policies", func() {
+		for _, name := range []string{"ocm-Installer-Role", "ocm-Support-Role"} {
+			_, err := client.CreateRole(name, trustPolicy(), "", nil, "")
+			Expect(err).ToNot(HaveOccurred())
+		}
+		cluster, err := cmv1.NewCluster().AWS(cmv1.NewAWS().STS(cmv1.NewSTS().
+			RoleARN(installerArn("ocm-Installer-Role")).
+			SupportRoleARN(installerArn("ocm-Support-Role")))).Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		err = accountroles.ValidateExternalIDForAccountRoles(client, cluster, "ocm-external-id")
+		var mismatch *ststrust.ExternalIDMismatchError
+		Expect(errors.As(err, &mismatch)).To(BeTrue())
+
+		for _, name := range []string{"ocm-Installer-Role", "ocm-Support-Role"} {
+			role, err := client.GetRole(name)
+			Expect(err).ToNot(HaveOccurred())
+			document, err := url.PathUnescape(aws.ToString(role.AssumeRolePolicyDocument))
+			Expect(err).ToNot(HaveOccurred())
+			updated, err := ststrust.ApplySTSExternalIDToTrustPolicy(document, "ocm-external-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.UpdateAssumeRolePolicy(name, updated)).To(Succeed())
+		}
+		Expect(accountroles.ValidateExternalIDForAccountRoles(client, cluster, "ocm-external-id")).To(Succeed())
+	})
+
+	It("deletes OIDC providers", func() {
+		output, err := fake.CreateOpenIDConnectProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
+			Url:            aws.String("https://oidc.example.com/cluster"),
+			ClientIDList:   []string{"openshift", "sts.amazonaws.com"},
+			ThumbprintList: []string{"0123456789abcdef0123456789abcdef01234567"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		providerArn := aws.ToString(output.OpenIDConnectProviderArn)
+		Expect(providerArn).To(Equal(fmt.Sprintf("arn:aws:iam::%s:oidc-provider/oidc.example.com/cluster", DefaultAccountID)))
+		_, err = fake.CreateOpenIDConnectProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
+			Url: aws.String("https://oidc.example.com/cluster"),
+		})
+		Expect(awserrors.IsEntityAlreadyExistsException(err)).To(BeTrue())
+
+		Expect(client.DeleteOIDCProvider(providerArn)).To(Succeed())
+		err = client.DeleteOIDCProvider(providerArn)
+		Expect(awserrors.IsNoSuchEntityException(err)).To(BeTrue())
+	})
+
+	It("protects AWS managed policies", func() {
+		policyArn := fake.AddAWSManagedPolicy("ReadOnlyAccess", readOnlyPolicy)
+		_, err := client.CreateRoleAndAttachPolicy("ocm-role", trustPolicy(), "", nil, "", policyArn)
+		Expect(err).ToNot(HaveOccurred())
+		local, err := client.GetCustomerIAMPolicies()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(local).To(BeEmpty())
+
+		Expect(client.DeleteRoleAndPolicy("ocm-role", true)).To(Succeed())
+		_, err = fake.DeletePolicy(ctx, &iam.DeletePolicyInput{PolicyArn: aws.String(policyArn)})
+		Expect(awserrors.IsAccessDeniedException(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,50 @@
+package aws_fake
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/ststrust"
+)
+
+var _ ststrust.RoleGetter = &RoleGetter{}
+
+// RoleGetter is an in-memory ststrust.RoleGetter for code that only reads trust policies. It
+// serves the trust policy documents of Policies by role name, percent-encoded as IAM returns
+// them, and records the names it is asked for. Unknown roles fail with NoSuchEntity. The fake is
+// safe for concurrent use.
+type RoleGetter struct {
+	Policies map[string]string
+
+	mutex   sync.Mutex
+	fetched []string
+}
+
+// NewRoleGetter returns a fake serving the given trust policies by role name.
+func NewRoleGetter(policies map[string]string) *RoleGetter {
+	return &RoleGetter{Policies: policies}
+}
+
+// GetRole returns the role named roleName with its percent-encoded trust policy.
+func (f *RoleGetter) GetRole(roleName string) (*types.Role, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.fetched = append(f.fetched, roleName)
+	policy, ok := f.Policies[roleName]
+	if !ok {
+		return nil, noSuchEntity("The role with name %s cannot be found.", roleName)
+	}
+	return &types.Role{
+		RoleName:                 aws.String(roleName),
+		AssumeRolePolicyDocument: encodeDocument(policy),
+	}, nil
+}
+
+// Fetched returns the role names requested so far, in request order.
+func (f *RoleGetter) Fetched() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]string{}, f.fetched...)
+}
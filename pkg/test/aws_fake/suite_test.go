@@ -0,0 +1,13 @@
+package aws_fake_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAWSFake(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AWS Fake Suite")
+}
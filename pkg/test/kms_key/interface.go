@@ -1,21 +1,15 @@
 package kms_key
 
-type KMSKeyPolicy struct {
-	Version   string      `json:"Version,omitempty"`
-	ID        string      `json:"Id,omitempty"`
-	Statement []Statement `json:"Statement,omitempty"`
-}
-type Principal struct {
-	Aws interface{} `json:"AWS,omitempty"`
-}
-type Statement struct {
-	Sid       string      `json:"Sid,omitempty"`
-	Effect    string      `json:"Effect,omitempty"`
-	Principal Principal   `json:"Principal,omitempty"`
-	Action    interface{} `json:"Action,omitempty"`
-	Resource  string      `json:"Resource,omitempty"`
-	Condition Condition   `json:"Condition,omitempty"`
-}
-type Condition struct {
-	Bool interface{} `json:"Bool,omitempty"`
-}
+import "github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
+
+// KMSKeyPolicy is a KMS key policy document.
+type KMSKeyPolicy = iampolicy.Document
+
+// Statement is a KMS key policy statement.
+type Statement = iampolicy.Statement
+
+// Principal is the Principal element of a KMS key policy statement.
+type Principal = iampolicy.Principal
+
+// Condition is the Condition element of a KMS key policy statement.
+type Condition = iampolicy.Condition
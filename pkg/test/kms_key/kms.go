@@ -1,10 +1,10 @@
 package kms_key
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	"github.com/openshift-online/ocm-common/pkg/aws/iampolicy"
 	"github.com/openshift-online/ocm-common/pkg/log"
 )
 
@@ -15,25 +15,18 @@ func CreateOCMTestKMSKey(region string, multiRegion bool, testClient string) (st
 		return "", err
 	}
 	accountRoleArns := []string{fmt.Sprintf("arn:%s:iam::%s:root", client.GetAWSPartition(), client.AccountID)}
-	testKMSKeyPolicy := KMSKeyPolicy{
-		Statement: []Statement{
-			{
-				Principal: Principal{
-					Aws: accountRoleArns,
-				},
-				Sid:      "Enable IAM User Permissions",
-				Effect:   "Allow",
-				Action:   "kms:*",
-				Resource: "*",
-			},
-		},
-	}
+	testKMSKeyPolicy := iampolicy.NewDocument().AddStatement(
+		iampolicy.NewStatement().
+			SetSid("Enable IAM User Permissions").
+			AddAWSPrincipal(accountRoleArns...).
+			AddAction("kms:*").
+			AddResource("*"),
+	)
 
-	keyJson, err := json.Marshal(testKMSKeyPolicy)
+	keyString, err := testKMSKeyPolicy.JSON()
 	if err != nil {
 		return "", err
 	}
-	keyString := string(keyJson)
 	tagKey, tagValue, keyDescription := "Purpose", fmt.Sprintf("%s automation test", testClient), fmt.Sprintf("BYOK Test Key for client %s automation", testClient)
 
 	_, kmsKeyArn, err := client.CreateKMSKeys(tagKey, tagValue, keyDescription, keyString, multiRegion)
@@ -68,106 +61,70 @@ func ConfigKMSKeyPolicyForSTS(key string, region string, HCP bool, accountRoles
 	if err != nil {
 		return err
 	}
-	var KMSPolicy KMSKeyPolicy
-	err = json.Unmarshal([]byte(*KMSPolicyResponse.Policy), &KMSPolicy)
+	KMSPolicy, err := iampolicy.ParseDocument(*KMSPolicyResponse.Policy)
 	if err != nil {
 		return err
 	}
-	var additionalStatements []Statement = []Statement{}
 	if !HCP {
 		roles := append(accountRoles, operatorRoleArn["ebs-cloud-credentials"])
-		s1 := Statement{
-			Principal: Principal{
-				Aws: roles,
-			},
-			Sid:    "Allow ROSA use of the key",
-			Effect: "Allow",
-			Action: []string{
+		s1 := iampolicy.NewStatement().
+			SetSid("Allow ROSA use of the key").
+			AddAWSPrincipal(roles...).
+			AddAction(
 				"kms:Encrypt",
 				"kms:Decrypt",
 				"kms:ReEncrypt*",
 				"kms:GenerateDataKey*",
-				"kms:DescribeKey"},
-			Resource: "*",
-		}
+				"kms:DescribeKey").
+			AddResource("*")
 
-		s2 := Statement{
-			Principal: Principal{
-				Aws: roles,
-			},
-			Sid:    "Allow attachment of persistent resources",
-			Effect: "Allow",
-			Action: []string{
+		s2 := iampolicy.NewStatement().
+			SetSid("Allow attachment of persistent resources").
+			AddAWSPrincipal(roles...).
+			AddAction(
 				"kms:CreateGrant",
 				"kms:ListGrants",
-				"kms:RevokeGrant"},
-			Resource: "*",
-			Condition: Condition{
-				Bool: map[string]interface{}{
-					"kms:GrantIsForAWSResource": "true",
-				},
-			},
-		}
-		additionalStatements = append(additionalStatements, s1, s2)
+				"kms:RevokeGrant").
+			AddResource("*").
+			AddCondition("Bool", "kms:GrantIsForAWSResource", "true")
+		KMSPolicy.AddStatement(s1, s2)
 	} else {
-		s1 := Statement{
-			Principal: Principal{
-				Aws: accountRoles,
-			},
-			Sid:    "Installer Permissions",
-			Effect: "Allow",
-			Action: []string{
+		s1 := iampolicy.NewStatement().
+			SetSid("Installer Permissions").
+			AddAWSPrincipal(accountRoles...).
+			AddAction(
 				"kms:CreateGrant",
 				"kms:DescribeKey",
-				"kms:GenerateDataKeyWithoutPlaintext"},
-			Resource: "*",
-		}
+				"kms:GenerateDataKeyWithoutPlaintext").
+			AddResource("*")
 
-		s2 := Statement{
-			Principal: Principal{
-				Aws: operatorRoleArn["kube-controller-manager"],
-			},
-			Sid:    "ROSA KubeControllerManager Permissions",
-			Effect: "Allow",
-			Action: []string{
-				"kms:DescribeKey",
-			},
-			Resource: "*",
-		}
-		s3 := Statement{
-			Principal: Principal{
-				Aws: operatorRoleArn["kms-provider"],
-			},
-			Sid:    "ROSA KMS Provider Permissions",
-			Effect: "Allow",
-			Action: []string{
+		s2 := iampolicy.NewStatement().
+			SetSid("ROSA KubeControllerManager Permissions").
+			AddAWSPrincipal(operatorRoleArn["kube-controller-manager"]).
+			AddAction("kms:DescribeKey").
+			AddResource("*")
+		s3 := iampolicy.NewStatement().
+			SetSid("ROSA KMS Provider Permissions").
+			AddAWSPrincipal(operatorRoleArn["kms-provider"]).
+			AddAction(
 				"kms:Encrypt",
 				"kms:Decrypt",
-				"kms:DescribeKey",
-			},
-			Resource: "*",
-		}
-		s4 := Statement{
-			Principal: Principal{
-				Aws: operatorRoleArn["capa-controller-manager"],
-			},
-			Sid:    "ROSA NodeManager Permissions",
-			Effect: "Allow",
-			Action: []string{
+				"kms:DescribeKey").
+			AddResource("*")
+		s4 := iampolicy.NewStatement().
+			SetSid("ROSA NodeManager Permissions").
+			AddAWSPrincipal(operatorRoleArn["capa-controller-manager"]).
+			AddAction(
 				"kms:DescribeKey",
 				"kms:GenerateDataKeyWithoutPlaintext",
-				"kms:CreateGrant",
-			},
-			Resource: "*",
-		}
-		additionalStatements = append(additionalStatements, s1, s2, s3, s4)
+				"kms:CreateGrant").
+			AddResource("*")
+		KMSPolicy.AddStatement(s1, s2, s3, s4)
 	}
-	KMSPolicy.Statement = append(KMSPolicy.Statement, additionalStatements...)
-	keyJson, err := json.Marshal(KMSPolicy)
+	keyString, err := KMSPolicy.JSON()
 	if err != nil {
 		return err
 	}
-	keyString := string(keyJson)
 
 	_, err = client.PutKMSPolicy(key, "", keyString)
 	return err
@@ -52,7 +52,8 @@ func (vpc *VPC) LaunchBastion(imageID string, zone string, userData string, keyp
 		return inst, err
 	}
 	tags := map[string]string{
-		"Name": CON.BastionName,
+		"Name":  CON.BastionName,
+		"VpcId": vpc.VpcID,
 	}
 	_, err = vpc.AWSClient.TagResource(*key.KeyPairId, tags)
 	if err != nil {
@@ -1,29 +1,175 @@
 package vpc_client
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"net"
+	"os"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/apparentlymart/go-cidr/cidr"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
 	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/cluster/validations"
 )
 
-func NewCIDRPool(vpcCIDR string) *VPCCIDRPool {
+// NewCIDRPool returns a pool of the vpcCIDR split in subnets of prefix, CON.DefaultCIDRPrefix when
+// not set. AllocatePrefix hands out blocks of any other prefix from the same pool.
+func NewCIDRPool(vpcCIDR string, prefix ...int) *VPCCIDRPool {
 	v := &VPCCIDRPool{
-		CIDR: vpcCIDR,
+		CIDR:   vpcCIDR,
+		Prefix: CON.DefaultCIDRPrefix,
 	}
-	prefix := CON.DefaultCIDRPrefix
-	if v.Prefix != 0 {
-		prefix = v.Prefix
+	if len(prefix) == 1 && prefix[0] != 0 {
+		v.Prefix = prefix[0]
 	}
-	v.GenerateSubnetPool(prefix)
+	v.GenerateSubnetPool(v.Prefix)
 	return v
 }
 
+// NewCIDRPoolFromVPC returns the pool of an existing AWS vpc, covering its primary and secondary
+// CIDR blocks, with the blocks of its subnets reserved.
+func NewCIDRPoolFromVPC(vpc types.Vpc, subnets []types.Subnet, prefix ...int) (*VPCCIDRPool, error) {
+	cidrs := aws_client.VpcCidrBlocks(vpc)
+	v := NewCIDRPool(cidrs[0], prefix...)
+	for _, secondary := range cidrs[1:] {
+		if err := v.AddCIDR(secondary); err != nil {
+			return nil, err
+		}
+	}
+	for _, subnet := range subnets {
+		if err := v.Reserve(aws.ToString(subnet.CidrBlock)); err != nil {
+			return nil, err
+		}
+	}
+	ipv6CIDR := aws_client.VpcIPv6CidrBlock(vpc)
+	if ipv6CIDR == "" {
+		return v, nil
+	}
+	if err := v.GenerateIPv6SubnetPool(ipv6CIDR); err != nil {
+		return nil, err
+	}
+	for _, subnet := range subnets {
+		if subnetIPv6CIDR := subnetIPv6CidrBlock(subnet); subnetIPv6CIDR != "" {
+			if err := v.ReserveIPv6(subnetIPv6CIDR); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+// LoadCIDRPoolFile loads a pool saved with SaveToFile.
+func LoadCIDRPoolFile(path string) (*VPCCIDRPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	v := &VPCCIDRPool{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("load CIDR pool from %s: %w", path, err)
+	}
+	return v, nil
+}
+
+// SaveToFile writes the pool as JSON so a later test run can keep allocating from it.
+func (v *VPCCIDRPool) SaveToFile(path string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// UnmarshalJSON restores IPNet, which is not serialized, from CIDR.
+func (s *SubnetCIDR) UnmarshalJSON(data []byte) error {
+	type plain SubnetCIDR
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	_, ipnet, err := net.ParseCIDR(s.CIDR)
+	if err != nil {
+		return fmt.Errorf("wrong CIDR:%s in the pool. %s", s.CIDR, err)
+	}
+	s.IPNet = ipnet
+	return nil
+}
+
+// CIDRs returns the IPv4 CIDR blocks of the pool, the primary one first.
+func (v *VPCCIDRPool) CIDRs() []string {
+	return append([]string{v.CIDR}, v.SecondaryCIDRs...)
+}
+
+// GenerateSubnetPool splits the CIDR blocks of the pool in subnets of prefix. The subnets
+// overlapping an allocation are reserved.
 func (v *VPCCIDRPool) GenerateSubnetPool(prefix int) {
+	v.Prefix = prefix
+	v.SubNetPool = []*SubnetCIDR{}
+	for _, poolCIDR := range v.CIDRs() {
+		v.SubNetPool = append(v.SubNetPool, v.carvePoolCIDR(poolCIDR, prefix)...)
+	}
+}
+
+// AddCIDR adds a secondary IPv4 CIDR block associated to the VPC to the pool.
+func (v *VPCCIDRPool) AddCIDR(secondaryCIDR string) error {
+	_, network, err := net.ParseCIDR(secondaryCIDR)
+	if err != nil || network.IP.To4() == nil {
+		return fmt.Errorf("you passed a wrong IPv4 CIDR:%s", secondaryCIDR)
+	}
+	for _, poolCIDR := range v.CIDRs() {
+		_, poolNetwork, err := net.ParseCIDR(poolCIDR)
+		if err == nil && intersect(poolNetwork, network) {
+			return fmt.Errorf("CIDR %s overlaps %s of the pool", secondaryCIDR, poolCIDR)
+		}
+	}
+	prefix := v.Prefix
+	if prefix == 0 {
+		prefix = CON.DefaultCIDRPrefix
+	}
+	v.SecondaryCIDRs = append(v.SecondaryCIDRs, network.String())
+	v.SubNetPool = append(v.SubNetPool, v.carvePoolCIDR(network.String(), prefix)...)
+	return nil
+}
+
+func (v *VPCCIDRPool) carvePoolCIDR(poolCIDR string, prefix int) []*SubnetCIDR {
+	_, network, err := net.ParseCIDR(poolCIDR)
+	if err != nil {
+		return nil
+	}
+	subnets := carveSubnets(network, prefix)
+	for _, subnet := range subnets {
+		subnet.Reserved = v.allocated(subnet.IPNet)
+	}
+	return subnets
+}
+
+// GenerateIPv6SubnetPool carves the IPv6 CIDR block of a dual-stack VPC into the /64 subnets AWS
+// accepts for subnets.
+func (v *VPCCIDRPool) GenerateIPv6SubnetPool(ipv6CIDR string) error {
+	_, vpcSubnet, err := net.ParseCIDR(ipv6CIDR)
+	if err != nil {
+		return fmt.Errorf("you passed a wrong IPv6 CIDR:%s. %s", ipv6CIDR, err)
+	}
+	if vpcSubnet.IP.To4() != nil {
+		return fmt.Errorf("CIDR %s is not an IPv6 CIDR", ipv6CIDR)
+	}
+	ones, _ := vpcSubnet.Mask.Size()
+	if ones > CON.DefaultIPv6CIDRPrefix {
+		return fmt.Errorf("IPv6 CIDR %s is smaller than a /%d", ipv6CIDR, CON.DefaultIPv6CIDRPrefix)
+	}
+	v.IPv6CIDR = vpcSubnet.String()
+	v.IPv6SubNetPool = carveSubnets(vpcSubnet, CON.DefaultIPv6CIDRPrefix)
+	return nil
+}
+
+func carveSubnets(vpcSubnet *net.IPNet, prefix int) []*SubnetCIDR {
 	subnetcidrs := []*SubnetCIDR{}
-	_, vpcSubnet, _ := net.ParseCIDR(v.CIDR)
 	currentSubnet, _ := cidr.PreviousSubnet(vpcSubnet, prefix)
 	var loopFinished bool
 	for {
@@ -38,11 +184,184 @@ func (v *VPCCIDRPool) GenerateSubnetPool(prefix int) {
 			break
 		}
 	}
-	v.SubNetPool = subnetcidrs
+	return subnetcidrs
 }
 
 func (v *VPCCIDRPool) Allocate() *SubnetCIDR {
-	for _, subnetCIDR := range v.SubNetPool {
+	subnetCIDR := allocate(v.SubNetPool)
+	if subnetCIDR != nil {
+		v.Allocations = append(v.Allocations, subnetCIDR.CIDR)
+	}
+	return subnetCIDR
+}
+
+// AllocatePrefix returns a free block of prefix bits. It is carved from the smallest free block
+// that can hold it, so that big blocks stay available for later allocations.
+func (v *VPCCIDRPool) AllocatePrefix(prefix int) (*SubnetCIDR, error) {
+	if prefix < 16 || prefix > MaxSubnetPrefix {
+		return nil, fmt.Errorf("subnet prefix /%d is out of the /16-/%d range", prefix, MaxSubnetPrefix)
+	}
+	var best *net.IPNet
+	bestOnes := -1
+	for _, block := range v.freeBlocks() {
+		if ones, _ := block.Mask.Size(); ones <= prefix && ones > bestOnes {
+			best, bestOnes = block, ones
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no free /%d block left in %s", prefix, strings.Join(v.CIDRs(), ","))
+	}
+	allocated := &net.IPNet{IP: best.IP, Mask: net.CIDRMask(prefix, 32)}
+	v.Allocations = append(v.Allocations, allocated.String())
+	for _, subnet := range v.SubNetPool {
+		if intersect(subnet.IPNet, allocated) {
+			subnet.Reserved = true
+		}
+	}
+	return &SubnetCIDR{Reserved: true, CIDR: allocated.String(), IPNet: allocated}, nil
+}
+
+// Free gives a block returned by Allocate, AllocatePrefix or taken with Reserve back to the pool.
+func (v *VPCCIDRPool) Free(allocatedCIDR string) error {
+	_, network, err := net.ParseCIDR(allocatedCIDR)
+	if err != nil {
+		return fmt.Errorf("you passed a wrong CIDR:%s to free. %s", allocatedCIDR, err)
+	}
+	index := slices.Index(v.Allocations, network.String())
+	if index < 0 {
+		return fmt.Errorf("CIDR %s is not allocated from the pool", allocatedCIDR)
+	}
+	v.Allocations = slices.Delete(v.Allocations, index, index+1)
+	for _, subnet := range v.SubNetPool {
+		if intersect(subnet.IPNet, network) {
+			subnet.Reserved = v.allocated(subnet.IPNet)
+		}
+	}
+	return nil
+}
+
+// Usage reports how much of the IPv4 space of the pool is allocated and how fragmented the free
+// space is.
+func (v *VPCCIDRPool) Usage() CIDRPoolUsage {
+	usage := CIDRPoolUsage{}
+	for _, poolCIDR := range v.CIDRs() {
+		if _, network, err := net.ParseCIDR(poolCIDR); err == nil {
+			usage.TotalAddresses += ipv4Range(network).size()
+		}
+	}
+	var largest uint64
+	for _, block := range v.freeBlocks() {
+		size := ipv4Range(block).size()
+		usage.FreeAddresses += size
+		usage.FreeBlocks++
+		if size > largest {
+			largest = size
+			usage.LargestFreeBlock = block.String()
+		}
+	}
+	usage.AllocatedAddresses = usage.TotalAddresses - usage.FreeAddresses
+	if usage.FreeAddresses != 0 {
+		usage.Fragmentation = 1 - float64(largest)/float64(usage.FreeAddresses)
+	}
+	return usage
+}
+
+// allocated reports whether network overlaps an allocation.
+func (v *VPCCIDRPool) allocated(network *net.IPNet) bool {
+	for _, allocation := range v.Allocations {
+		_, allocated, err := net.ParseCIDR(allocation)
+		if err == nil && intersect(allocated, network) {
+			return true
+		}
+	}
+	return false
+}
+
+// freeBlocks returns the free IPv4 space of the pool as the largest aligned blocks, in address
+// order of each pool CIDR.
+func (v *VPCCIDRPool) freeBlocks() []*net.IPNet {
+	taken := []addressRange{}
+	for _, allocation := range v.Allocations {
+		if _, network, err := net.ParseCIDR(allocation); err == nil && network.IP.To4() != nil {
+			taken = append(taken, ipv4Range(network))
+		}
+	}
+	// Subnets overlapping an allocation are only partly used; the others were reserved by hand.
+	for _, subnet := range v.SubNetPool {
+		if subnet.Reserved && subnet.IPNet != nil && subnet.IPNet.IP.To4() != nil && !v.allocated(subnet.IPNet) {
+			taken = append(taken, ipv4Range(subnet.IPNet))
+		}
+	}
+	sort.Slice(taken, func(i, j int) bool { return taken[i].start < taken[j].start })
+
+	blocks := []*net.IPNet{}
+	for _, poolCIDR := range v.CIDRs() {
+		_, network, err := net.ParseCIDR(poolCIDR)
+		if err != nil || network.IP.To4() == nil {
+			continue
+		}
+		pool := ipv4Range(network)
+		cursor := pool.start
+		for _, used := range taken {
+			if used.end <= cursor || used.start >= pool.end {
+				continue
+			}
+			if used.start > cursor {
+				blocks = append(blocks, alignedBlocks(cursor, used.start)...)
+			}
+			cursor = used.end
+		}
+		if cursor < pool.end {
+			blocks = append(blocks, alignedBlocks(cursor, pool.end)...)
+		}
+	}
+	return blocks
+}
+
+// addressRange is the IPv4 address range [start, end).
+type addressRange struct {
+	start uint64
+	end   uint64
+}
+
+func (r addressRange) size() uint64 {
+	return r.end - r.start
+}
+
+func ipv4Range(network *net.IPNet) addressRange {
+	ones, bits := network.Mask.Size()
+	start := uint64(binary.BigEndian.Uint32(network.IP.To4()))
+	return addressRange{start: start, end: start + 1<<uint(bits-ones)}
+}
+
+// alignedBlocks splits [start, end) in the fewest CIDR blocks.
+func alignedBlocks(start uint64, end uint64) []*net.IPNet {
+	blocks := []*net.IPNet{}
+	for start < end {
+		ones := 32
+		for ones > 0 {
+			size := uint64(1) << uint(33-ones)
+			if start%size != 0 || start+size > end {
+				break
+			}
+			ones--
+		}
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, uint32(start))
+		blocks = append(blocks, &net.IPNet{IP: ip, Mask: net.CIDRMask(ones, 32)})
+		start += uint64(1) << uint(32-ones)
+	}
+	return blocks
+}
+
+// AllocateIPv6 returns a free /64 of the IPv6 pool, or nil once the pool is exhausted or the VPC is
+// not dual-stack.
+func (v *VPCCIDRPool) AllocateIPv6() *SubnetCIDR {
+	return allocate(v.IPv6SubNetPool)
+}
+
+func allocate(pool []*SubnetCIDR) *SubnetCIDR {
+	for _, subnetCIDR := range pool {
 		if !subnetCIDR.Reserved {
 			subnetCIDR.Reserved = true
 			return subnetCIDR
@@ -53,12 +372,30 @@ func (v *VPCCIDRPool) Allocate() *SubnetCIDR {
 
 // Reserve will reserve the ones you passed as parameter so you won't allocate them again from the pool
 func (v *VPCCIDRPool) Reserve(reservedCIDRs ...string) error {
+	if err := reserve(v.SubNetPool, reservedCIDRs...); err != nil {
+		return err
+	}
+	for _, reservedCIDR := range reservedCIDRs {
+		_, ipnet, _ := net.ParseCIDR(reservedCIDR)
+		if !slices.Contains(v.Allocations, ipnet.String()) {
+			v.Allocations = append(v.Allocations, ipnet.String())
+		}
+	}
+	return nil
+}
+
+// ReserveIPv6 is Reserve for the IPv6 pool.
+func (v *VPCCIDRPool) ReserveIPv6(reservedCIDRs ...string) error {
+	return reserve(v.IPv6SubNetPool, reservedCIDRs...)
+}
+
+func reserve(pool []*SubnetCIDR, reservedCIDRs ...string) error {
 	for _, reservedCIDR := range reservedCIDRs {
 		_, ipnet, err := net.ParseCIDR(reservedCIDR)
 		if err != nil {
 			return fmt.Errorf("you passed a wrong CIDR:%s for reserve. %s", reservedCIDR, err)
 		}
-		for _, freeCidr := range v.SubNetPool {
+		for _, freeCidr := range pool {
 			if intersect(freeCidr.IPNet, ipnet) {
 				freeCidr.Reserved = true
 			}
@@ -67,6 +404,19 @@ func (v *VPCCIDRPool) Reserve(reservedCIDRs ...string) error {
 	return nil
 }
 
+// ValidateClusterNetworks checks the cluster networks against the subnets of the vpc, see
+// validations.ValidateClusterNetworks. An empty MachineCIDR defaults to the CIDR of the vpc.
+func (vpc *VPC) ValidateClusterNetworks(networks validations.ClusterNetworks) *validations.NetworkReport {
+	if networks.MachineCIDR == "" {
+		networks.MachineCIDR = vpc.CIDRValue
+	}
+	networks.Subnets = append([]validations.NetworkSubnet{}, networks.Subnets...)
+	for _, subnet := range vpc.SubnetList {
+		networks.Subnets = append(networks.Subnets, validations.NetworkSubnet{ID: subnet.ID, CIDR: subnet.Cidr})
+	}
+	return validations.ValidateClusterNetworks(networks)
+}
+
 func intersect(n1, n2 *net.IPNet) bool {
 	return n2.Contains(n1.IP) || n1.Contains(n2.IP)
 }
@@ -0,0 +1,145 @@
+package vpc_client_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	"github.com/openshift-online/ocm-common/pkg/cluster/validations"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+	. "github.com/openshift-online/ocm-common/pkg/test/vpc_client"
+)
+
+var _ = Describe("CIDR pool", func() {
+	allocate := func(pool *VPCCIDRPool, prefix int) string {
+		allocated, err := pool.AllocatePrefix(prefix)
+		Expect(err).ToNot(HaveOccurred())
+		return allocated.CIDR
+	}
+
+	It("should split the pool with the requested prefix", func() {
+		pool := NewCIDRPool("10.0.0.0/16", 20)
+		Expect(pool.Prefix).To(Equal(20))
+		Expect(pool.SubNetPool).To(HaveLen(16))
+		Expect(NewCIDRPool("10.0.0.0/16").SubNetPool).To(HaveLen(256))
+	})
+
+	It("should pack blocks of any prefix in the smallest free block", func() {
+		pool := NewCIDRPool("10.0.0.0/22")
+		Expect(allocate(pool, 26)).To(Equal("10.0.0.0/26"))
+		Expect(allocate(pool, 24)).To(Equal("10.0.1.0/24"))
+		Expect(allocate(pool, 27)).To(Equal("10.0.0.64/27"))
+		Expect(allocate(pool, 25)).To(Equal("10.0.0.128/25"))
+		Expect(pool.Allocate().CIDR).To(Equal("10.0.2.0/24"))
+		_, err := pool.AllocatePrefix(23)
+		Expect(err).To(HaveOccurred())
+		Expect(allocate(pool, 24)).To(Equal("10.0.3.0/24"))
+	})
+
+	It("should free allocations and report fragmentation", func() {
+		pool := NewCIDRPool("10.0.0.0/24", 26)
+		first := allocate(pool, 26)
+		second := allocate(pool, 26)
+		Expect(allocate(pool, 26)).To(Equal("10.0.0.128/26"))
+		Expect(pool.Free(second)).To(Succeed())
+
+		usage := pool.Usage()
+		Expect(usage.TotalAddresses).To(BeEquivalentTo(256))
+		Expect(usage.FreeAddresses).To(BeEquivalentTo(128))
+		Expect(usage.AllocatedAddresses).To(BeEquivalentTo(128))
+		Expect(usage.FreeBlocks).To(Equal(2))
+		Expect(usage.LargestFreeBlock).To(Equal("10.0.0.64/26"))
+		Expect(usage.Fragmentation).To(BeNumerically("~", 0.5))
+
+		_, err := pool.AllocatePrefix(25)
+		Expect(err).To(MatchError(ContainSubstring("no free /25 block")))
+		Expect(pool.Free(first)).To(Succeed())
+		Expect(pool.Usage().Fragmentation).To(BeNumerically("~", 1.0/3, 0.001))
+		Expect(allocate(pool, 25)).To(Equal("10.0.0.0/25"))
+		Expect(pool.Free(first)).To(MatchError(ContainSubstring("not allocated")))
+		_, err = pool.AllocatePrefix(29)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should keep the equal subnets and the allocations in sync", func() {
+		pool := NewCIDRPool("10.0.0.0/23", 25)
+		Expect(pool.Reserve("10.0.0.0/25")).To(Succeed())
+		Expect(allocate(pool, 24)).To(Equal("10.0.1.0/24"))
+		Expect(pool.Allocate().CIDR).To(Equal("10.0.0.128/25"))
+		Expect(pool.Allocate()).To(BeNil())
+		Expect(pool.Free("10.0.1.0/24")).To(Succeed())
+		Expect(pool.Allocate().CIDR).To(Equal("10.0.1.0/25"))
+	})
+
+	It("should allocate from secondary CIDR blocks", func() {
+		pool := NewCIDRPool("10.0.0.0/24")
+		Expect(pool.AddCIDR("10.0.0.128/25")).ToNot(Succeed())
+		Expect(pool.AddCIDR("100.64.0.0/22")).To(Succeed())
+		Expect(pool.CIDRs()).To(Equal([]string{"10.0.0.0/24", "100.64.0.0/22"}))
+		Expect(pool.SubNetPool).To(HaveLen(5))
+		Expect(allocate(pool, 23)).To(Equal("100.64.0.0/23"))
+		Expect(pool.Usage().TotalAddresses).To(BeEquivalentTo(1280))
+	})
+
+	It("should save and load its state", func() {
+		pool := NewCIDRPool("10.0.0.0/16")
+		Expect(pool.AddCIDR("10.1.0.0/20")).To(Succeed())
+		allocate(pool, 20)
+		pool.Allocate()
+		path := filepath.Join(GinkgoT().TempDir(), "pool.json")
+		Expect(pool.SaveToFile(path)).To(Succeed())
+
+		loaded, err := LoadCIDRPoolFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(loaded.CIDRs()).To(Equal(pool.CIDRs()))
+		Expect(loaded.Allocations).To(Equal(pool.Allocations))
+		Expect(loaded.Usage()).To(Equal(pool.Usage()))
+		Expect(loaded.SubNetPool[0].IPNet).ToNot(BeNil())
+		Expect(allocate(loaded, 24)).To(Equal(allocate(pool, 24)))
+		_, err = LoadCIDRPoolFile(filepath.Join(GinkgoT().TempDir(), "missing.json"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should seed itself from the CIDR blocks and subnets of a VPC", func() {
+		fake := aws_fake.NewEC2(aws_fake.WithRegion("us-east-2"))
+		client := &aws_client.AWSClient{Ec2Client: fake}
+		created, err := client.CreateDualStackVpc("10.0.0.0/24")
+		Expect(err).ToNot(HaveOccurred())
+		vpcID := *created.Vpc.VpcId
+		_, err = client.AssociateVpcCidrBlock(vpcID, "10.0.0.0/25")
+		Expect(err).To(HaveOccurred())
+		_, err = client.AssociateVpcCidrBlock(vpcID, "10.1.0.0/24")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.CreateSubnet(vpcID, "us-east-2a", "10.1.0.0/26")
+		Expect(err).ToNot(HaveOccurred())
+
+		vpc := NewVPC().AWSclient(client).ID(vpcID)
+		pool, err := vpc.LoadCIDRPool(26)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpc.CIDRPool).To(BeIdenticalTo(pool))
+		Expect(vpc.IPv6Enabled).To(BeTrue())
+		Expect(pool.CIDRs()).To(Equal([]string{"10.0.0.0/24", "10.1.0.0/24"}))
+		Expect(pool.Allocations).To(Equal([]string{"10.1.0.0/26"}))
+		Expect(pool.IPv6SubNetPool).To(HaveLen(256))
+
+		cidr := allocate(pool, 24)
+		Expect(cidr).To(Equal("10.0.0.0/24"))
+		_, err = client.CreateSubnet(vpcID, "us-east-2a", cidr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allocate(pool, 26)).To(Equal("10.1.0.64/26"))
+	})
+
+	It("should validate the cluster networks against the subnets of the VPC", func() {
+		vpc := NewVPC().CIDR("10.0.0.0/16").Subnets(
+			NewSubnet().SetID("subnet-a").SetCidr("10.0.0.0/24"),
+			NewSubnet().SetID("subnet-b").SetCidr("10.1.0.0/24"),
+		)
+		report := vpc.ValidateClusterNetworks(validations.ClusterNetworks{})
+		Expect(report.Issues).To(HaveLen(1))
+		Expect(report.Issues[0].Kind).To(Equal(validations.NetworkSubnetOutsideMachineCIDR))
+		Expect(report.Issues[0].Message).To(ContainSubstring("subnet-b"))
+		Expect(vpc.ValidateClusterNetworks(validations.ClusterNetworks{MachineCIDR: "10.0.0.0/15"}).Valid()).To(BeTrue())
+	})
+})
@@ -48,3 +48,34 @@ func (vpc *VPC) DeleteVPCInternetGateWays() error {
 	}
 	return nil
 }
+
+// PrepareEgressOnlyInternetGateway will return the existing egress-only internet gateway of the vpc
+// Otherwise, it will create one. Private subnets of a dual-stack vpc route "::/0" through it.
+func (vpc *VPC) PrepareEgressOnlyInternetGateway() (eigwID string, err error) {
+	eigws, err := vpc.AWSClient.ListEgressOnlyInternetGateways(vpc.VpcID)
+	if err != nil {
+		return "", err
+	}
+	if len(eigws) != 0 {
+		return *eigws[0].EgressOnlyInternetGatewayId, nil
+	}
+	eigw, err := vpc.AWSClient.CreateEgressOnlyInternetGateway(vpc.VpcID)
+	if err != nil {
+		return "", err
+	}
+	return *eigw.EgressOnlyInternetGatewayId, nil
+}
+
+func (vpc *VPC) DeleteVPCEgressOnlyInternetGateways() error {
+	eigws, err := vpc.AWSClient.ListEgressOnlyInternetGateways(vpc.VpcID)
+	if err != nil {
+		return err
+	}
+	for _, eigw := range eigws {
+		err = vpc.AWSClient.DeleteEgressOnlyInternetGateway(*eigw.EgressOnlyInternetGatewayId)
+		if err != nil && !awserrors.IsErrorCode(err, awserrors.InvalidGatewayID) {
+			return err
+		}
+	}
+	return nil
+}
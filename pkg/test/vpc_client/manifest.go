@@ -0,0 +1,312 @@
+package vpc_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/log"
+)
+
+// VPCManifestVersion is the version of the manifest written by SaveManifest. LoadVPCManifest
+// refuses manifests of a newer version.
+const VPCManifestVersion = 1
+
+// VPCManifest records a VPC environment so that a later CI step can rebuild the VPC struct with
+// LoadVPCFromManifest instead of discovering the resources again.
+type VPCManifest struct {
+	Version                      int                  `json:"version"`
+	VpcID                        string               `json:"vpcId"`
+	Name                         string               `json:"name,omitempty"`
+	Region                       string               `json:"region"`
+	CIDR                         string               `json:"cidr"`
+	IPv6CIDR                     string               `json:"ipv6Cidr,omitempty"`
+	Zones                        []string             `json:"zones,omitempty"`
+	Subnets                      []SubnetManifest     `json:"subnets,omitempty"`
+	InternetGatewayIDs           []string             `json:"internetGatewayIds,omitempty"`
+	EgressOnlyInternetGatewayIDs []string             `json:"egressOnlyInternetGatewayIds,omitempty"`
+	NatGateways                  []NatGatewayManifest `json:"natGateways,omitempty"`
+	Instances                    []InstanceManifest   `json:"instances,omitempty"`
+	KeyPairNames                 []string             `json:"keyPairNames,omitempty"`
+	SecurityGroupIDs             []string             `json:"securityGroupIds,omitempty"`
+	CIDRPool                     *VPCCIDRPool         `json:"cidrPool,omitempty"`
+}
+
+type SubnetManifest struct {
+	ID           string `json:"id"`
+	Name         string `json:"name,omitempty"`
+	Zone         string `json:"zone"`
+	CIDR         string `json:"cidr"`
+	IPv6CIDR     string `json:"ipv6Cidr,omitempty"`
+	Private      bool   `json:"private"`
+	RouteTableID string `json:"routeTableId,omitempty"`
+}
+
+type NatGatewayManifest struct {
+	ID       string `json:"id"`
+	SubnetID string `json:"subnetId"`
+	// AllocationIDs are the elastic IPs of the gateway.
+	AllocationIDs []string `json:"allocationIds,omitempty"`
+}
+
+// InstanceManifest records an instance of the VPC, such as the proxy or the bastion, told apart
+// by their Name.
+type InstanceManifest struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Zone      string `json:"zone,omitempty"`
+	SubnetID  string `json:"subnetId,omitempty"`
+	PrivateIP string `json:"privateIp,omitempty"`
+	PublicIP  string `json:"publicIp,omitempty"`
+	KeyName   string `json:"keyName,omitempty"`
+}
+
+// Manifest returns the manifest of the VPC: the fields of the struct, the gateways, instances and
+// security groups living in the VPC on AWS and the key pairs created for it.
+func (vpc *VPC) Manifest() (*VPCManifest, error) {
+	if vpc.VpcID == "" {
+		return nil, fmt.Errorf("got empty vpc ID to export. Make sure you loaded it from AWS")
+	}
+	client := vpc.AWSClient
+	manifest := &VPCManifest{
+		Version:  VPCManifestVersion,
+		VpcID:    vpc.VpcID,
+		Name:     vpc.VPCName,
+		Region:   vpc.Region,
+		CIDR:     vpc.CIDRValue,
+		CIDRPool: vpc.CIDRPool,
+	}
+	if manifest.Region == "" {
+		manifest.Region = client.Region
+	}
+	if vpc.CIDRPool != nil {
+		manifest.IPv6CIDR = vpc.CIDRPool.IPv6CIDR
+	}
+
+	subnets := vpc.SubnetList
+	if len(subnets) == 0 {
+		var err error
+		subnets, err = vpc.ListSubnets()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, subnet := range subnets {
+		if !slices.Contains(manifest.Zones, subnet.Zone) {
+			manifest.Zones = append(manifest.Zones, subnet.Zone)
+		}
+		subnetManifest := SubnetManifest{
+			ID:       subnet.ID,
+			Name:     subnet.Name,
+			Zone:     subnet.Zone,
+			CIDR:     subnet.Cidr,
+			IPv6CIDR: subnet.IPv6Cidr,
+			Private:  subnet.Private,
+		}
+		if subnet.RTable != nil {
+			subnetManifest.RouteTableID = aws.ToString(subnet.RTable.RouteTableId)
+		}
+		manifest.Subnets = append(manifest.Subnets, subnetManifest)
+	}
+
+	internetGateways, err := client.ListInternetGateWay(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, internetGateway := range internetGateways {
+		manifest.InternetGatewayIDs = append(manifest.InternetGatewayIDs, aws.ToString(internetGateway.InternetGatewayId))
+	}
+	egressOnlyGateways, err := client.ListEgressOnlyInternetGateways(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, egressOnlyGateway := range egressOnlyGateways {
+		manifest.EgressOnlyInternetGatewayIDs = append(manifest.EgressOnlyInternetGatewayIDs,
+			aws.ToString(egressOnlyGateway.EgressOnlyInternetGatewayId))
+	}
+
+	natGateways, err := client.ListNatGateways(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, natGateway := range natGateways {
+		if natGateway.State == types.NatGatewayStateDeleted || natGateway.State == types.NatGatewayStateFailed {
+			continue
+		}
+		natGatewayManifest := NatGatewayManifest{
+			ID:       aws.ToString(natGateway.NatGatewayId),
+			SubnetID: aws.ToString(natGateway.SubnetId),
+		}
+		for _, address := range natGateway.NatGatewayAddresses {
+			if address.AllocationId != nil {
+				natGatewayManifest.AllocationIDs = append(natGatewayManifest.AllocationIDs, *address.AllocationId)
+			}
+		}
+		manifest.NatGateways = append(manifest.NatGateways, natGatewayManifest)
+	}
+
+	instances, err := client.ListInstances([]string{}, map[string][]string{"vpc-id": {vpc.VpcID}})
+	if err != nil {
+		return nil, err
+	}
+	keyUsers := map[string][]string{}
+	for _, instance := range instances {
+		if instance.State != nil && instance.State.Name == types.InstanceStateNameTerminated {
+			continue
+		}
+		instanceManifest := InstanceManifest{
+			ID:        aws.ToString(instance.InstanceId),
+			Name:      aws_client.GetInstanceName(&instance),
+			SubnetID:  aws.ToString(instance.SubnetId),
+			PrivateIP: aws.ToString(instance.PrivateIpAddress),
+			PublicIP:  aws.ToString(instance.PublicIpAddress),
+			KeyName:   aws.ToString(instance.KeyName),
+		}
+		if instance.Placement != nil {
+			instanceManifest.Zone = aws.ToString(instance.Placement.AvailabilityZone)
+		}
+		manifest.Instances = append(manifest.Instances, instanceManifest)
+		if instanceManifest.KeyName != "" {
+			keyUsers[instanceManifest.KeyName] = append(keyUsers[instanceManifest.KeyName], instanceManifest.ID)
+		}
+	}
+	// Key pairs are account-wide: only those created for the VPC are recorded, not the ones its
+	// instances share with other VPCs.
+	manifest.KeyPairNames, err = vpc.ownedKeyPairNames(keyUsers)
+	if err != nil {
+		return nil, err
+	}
+	keyPairNames, err := vpc.getKeyPairNamesByVpcId()
+	if err != nil {
+		return nil, err
+	}
+	for _, keyPairName := range keyPairNames {
+		if !slices.Contains(manifest.KeyPairNames, keyPairName) {
+			manifest.KeyPairNames = append(manifest.KeyPairNames, keyPairName)
+		}
+	}
+
+	securityGroups, err := client.ListSecurityGroups(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, securityGroup := range securityGroups {
+		if aws.ToString(securityGroup.GroupName) != "default" {
+			manifest.SecurityGroupIDs = append(manifest.SecurityGroupIDs, aws.ToString(securityGroup.GroupId))
+		}
+	}
+	return manifest, nil
+}
+
+// SaveManifest writes the manifest of the VPC to CON.NetworkResourceFileName in dir and returns
+// the path of the file.
+func (vpc *VPC) SaveManifest(dir string) (string, error) {
+	manifest, err := vpc.Manifest()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, CON.NetworkResourceFileName)
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return "", err
+	}
+	log.LogInfo("Saved the manifest of vpc %s to %s", vpc.VpcID, path)
+	return path, nil
+}
+
+// LoadVPCManifest reads the manifest written by SaveManifest in dir.
+func LoadVPCManifest(dir string) (*VPCManifest, error) {
+	path := filepath.Join(dir, CON.NetworkResourceFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &VPCManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("load vpc manifest from %s: %w", path, err)
+	}
+	switch {
+	case manifest.Version == 0:
+		return nil, fmt.Errorf("vpc manifest %s has no version", path)
+	case manifest.Version > VPCManifestVersion:
+		return nil, fmt.Errorf("vpc manifest %s has version %d, only versions up to %d are supported",
+			path, manifest.Version, VPCManifestVersion)
+	case manifest.VpcID == "":
+		return nil, fmt.Errorf("vpc manifest %s has no vpc ID", path)
+	}
+	return manifest, nil
+}
+
+// LoadVPCFromManifest rebuilds the VPC saved with SaveManifest in dir, with an AWS client of the
+// region of the manifest.
+func LoadVPCFromManifest(dir string, awsSharedCredentialFile ...string) (*VPC, error) {
+	manifest, err := LoadVPCManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	awsClient, err := aws_client.CreateAWSClient("", manifest.Region, awsSharedCredentialFile...)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.VPC(awsClient)
+}
+
+// VPC rebuilds the VPC struct recorded in the manifest and keeps the manifest as its
+// SourceManifest. Without a recorded CIDR pool, a pool is generated with the subnet blocks
+// reserved.
+func (manifest *VPCManifest) VPC(awsClient *aws_client.AWSClient) (*VPC, error) {
+	vpc := NewVPC().
+		AWSclient(awsClient).
+		ID(manifest.VpcID).
+		Name(manifest.Name).
+		SetRegion(manifest.Region).
+		CIDR(manifest.CIDR)
+	vpc.IPv6Enabled = manifest.IPv6CIDR != ""
+	vpc.CIDRPool = manifest.CIDRPool
+	if vpc.CIDRPool == nil {
+		vpc.CIDRPool = NewCIDRPool(manifest.CIDR)
+		if vpc.IPv6Enabled {
+			if err := vpc.CIDRPool.GenerateIPv6SubnetPool(manifest.IPv6CIDR); err != nil {
+				return nil, fmt.Errorf("generate the IPv6 pool of vpc %s: %w", manifest.VpcID, err)
+			}
+		}
+	}
+	for _, subnetManifest := range manifest.Subnets {
+		subnet := NewSubnet().
+			SetID(subnetManifest.ID).
+			SetName(subnetManifest.Name).
+			SetZone(subnetManifest.Zone).
+			SetCidr(subnetManifest.CIDR).
+			SetIPv6Cidr(subnetManifest.IPv6CIDR).
+			SetPrivate(subnetManifest.Private).
+			SetVpcID(manifest.VpcID).
+			SetRegion(manifest.Region)
+		if subnetManifest.RouteTableID != "" {
+			subnet.RTable = &types.RouteTable{RouteTableId: aws.String(subnetManifest.RouteTableID)}
+		}
+		if manifest.CIDRPool == nil {
+			if err := vpc.CIDRPool.Reserve(subnet.Cidr); err != nil {
+				return nil, fmt.Errorf("reserve subnet %s of vpc %s: %w", subnet.ID, manifest.VpcID, err)
+			}
+			if subnet.IPv6Cidr != "" {
+				if err := vpc.CIDRPool.ReserveIPv6(subnet.IPv6Cidr); err != nil {
+					return nil, fmt.Errorf("reserve subnet %s of vpc %s: %w", subnet.ID, manifest.VpcID, err)
+				}
+			}
+		}
+		vpc.SubnetList = append(vpc.SubnetList, subnet)
+	}
+	vpc.SourceManifest = manifest
+	return vpc, nil
+}
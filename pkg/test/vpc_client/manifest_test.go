@@ -0,0 +1,159 @@
+package vpc_client_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+	. "github.com/openshift-online/ocm-common/pkg/test/vpc_client"
+)
+
+var _ = Describe("VPC manifest", func() {
+	var (
+		ctx    context.Context
+		fake   *aws_fake.EC2
+		client *aws_client.AWSClient
+		vpc    *VPC
+		dir    string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = aws_fake.NewEC2(aws_fake.WithRegion("us-east-2"))
+		client = &aws_client.AWSClient{Ec2Client: fake, Region: fake.Region()}
+		dir = GinkgoT().TempDir()
+
+		var err error
+		vpc, err = NewVPC().
+			AWSclient(client).
+			Name("manifest-vpc").
+			CIDR(CON.DefaultVPCCIDR).
+			SetRegion(fake.Region()).
+			NewCIDRPool().
+			DualStack(true).
+			CreateVPCChain(fake.Zones()[0])
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = fake.CreateKeyPair(ctx, &ec2.CreateKeyPairInput{
+			KeyName:           aws.String("manifest-vpc-proxy"),
+			TagSpecifications: vpcKeyPairTags(vpc.VpcID),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fake.RunInstances(ctx, &ec2.RunInstancesInput{
+			ImageId:  aws.String(fake.AddImage(types.Image{})),
+			SubnetId: aws.String(vpc.AllPublicSubnetIDs()[0]),
+			KeyName:  aws.String("manifest-vpc-proxy"),
+			MinCount: aws.Int32(1),
+			MaxCount: aws.Int32(1),
+			TagSpecifications: []types.TagSpecification{{
+				ResourceType: types.ResourceTypeInstance,
+				Tags:         []types.Tag{{Key: aws.String("Name"), Value: aws.String(CON.ProxyName)}},
+			}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should export the resources of the VPC", func() {
+		_, err := fake.CreateKeyPair(ctx, &ec2.CreateKeyPairInput{KeyName: aws.String("shared-key")})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fake.RunInstances(ctx, &ec2.RunInstancesInput{
+			ImageId:  aws.String(fake.AddImage(types.Image{})),
+			SubnetId: aws.String(vpc.AllPrivateSubnetIDs()[0]),
+			KeyName:  aws.String("shared-key"),
+			MinCount: aws.Int32(1),
+			MaxCount: aws.Int32(1),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		manifest, err := vpc.Manifest()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Version).To(Equal(VPCManifestVersion))
+		Expect(manifest.VpcID).To(Equal(vpc.VpcID))
+		Expect(manifest.Region).To(Equal("us-east-2"))
+		Expect(manifest.IPv6CIDR).ToNot(BeEmpty())
+		Expect(manifest.Zones).To(Equal([]string{fake.Zones()[0]}))
+		Expect(manifest.Subnets).To(HaveLen(2))
+		for _, subnet := range manifest.Subnets {
+			Expect(subnet.RouteTableID).ToNot(BeEmpty())
+			Expect(subnet.IPv6CIDR).ToNot(BeEmpty())
+		}
+		Expect(manifest.InternetGatewayIDs).To(HaveLen(1))
+		Expect(manifest.EgressOnlyInternetGatewayIDs).To(HaveLen(1))
+		Expect(manifest.NatGateways).To(HaveLen(1))
+		Expect(manifest.NatGateways[0].AllocationIDs).To(HaveLen(1))
+		Expect(manifest.Instances).To(HaveLen(2))
+		Expect(manifest.Instances).To(ContainElement(And(
+			HaveField("Name", CON.ProxyName),
+			HaveField("Zone", fake.Zones()[0]),
+		)))
+		Expect(manifest.KeyPairNames).To(Equal([]string{"manifest-vpc-proxy"}))
+	})
+
+	It("should rebuild the VPC from the saved manifest", func() {
+		path, err := vpc.SaveManifest(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(path).To(Equal(filepath.Join(dir, CON.NetworkResourceFileName)))
+
+		manifest, err := LoadVPCManifest(dir)
+		Expect(err).ToNot(HaveOccurred())
+		loaded, err := manifest.VPC(client)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(loaded.VpcID).To(Equal(vpc.VpcID))
+		Expect(loaded.VPCName).To(Equal("manifest-vpc"))
+		Expect(loaded.CIDRValue).To(Equal(vpc.CIDRValue))
+		Expect(loaded.IPv6Enabled).To(BeTrue())
+		Expect(loaded.AllPublicSubnetIDs()).To(Equal(vpc.AllPublicSubnetIDs()))
+		Expect(loaded.AllPrivateSubnetIDs()).To(Equal(vpc.AllPrivateSubnetIDs()))
+		Expect(loaded.CIDRPool.Allocations).To(Equal(vpc.CIDRPool.Allocations))
+		Expect(loaded.SourceManifest).To(Equal(manifest))
+
+		subnet, err := loaded.CreateSubnet(fake.Zones()[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subnet.Cidr).ToNot(BeElementOf(vpc.CIDRPool.Allocations))
+	})
+
+	It("should reserve the subnets without a recorded CIDR pool", func() {
+		manifest, err := vpc.Manifest()
+		Expect(err).ToNot(HaveOccurred())
+		manifest.CIDRPool = nil
+		loaded, err := manifest.VPC(client)
+		Expect(err).ToNot(HaveOccurred())
+		for _, subnet := range vpc.SubnetList {
+			Expect(loaded.CIDRPool.Allocations).To(ContainElement(subnet.Cidr))
+		}
+	})
+
+	It("should refuse manifests it cannot rebuild the CIDR pool from", func() {
+		manifest, err := vpc.Manifest()
+		Expect(err).ToNot(HaveOccurred())
+		manifest.CIDRPool = nil
+		manifest.Subnets[0].CIDR = "10.0.0.0"
+		_, err = manifest.VPC(client)
+		Expect(err).To(MatchError(ContainSubstring("reserve subnet " + manifest.Subnets[0].ID)))
+
+		manifest.IPv6CIDR = "not-a-cidr"
+		_, err = manifest.VPC(client)
+		Expect(err).To(MatchError(ContainSubstring("generate the IPv6 pool")))
+	})
+
+	It("should refuse manifests it cannot read", func() {
+		_, err := LoadVPCManifest(dir)
+		Expect(err).To(HaveOccurred())
+		path := filepath.Join(dir, CON.NetworkResourceFileName)
+		Expect(os.WriteFile(path, []byte(`{"version": 2, "vpcId": "vpc-1"}`), 0600)).To(Succeed())
+		_, err = LoadVPCManifest(dir)
+		Expect(err).To(MatchError(ContainSubstring("version 2")))
+		Expect(os.WriteFile(path, []byte(`{"vpcId": "vpc-1"}`), 0600)).To(Succeed())
+		_, err = LoadVPCManifest(dir)
+		Expect(err).To(MatchError(ContainSubstring("no version")))
+	})
+})
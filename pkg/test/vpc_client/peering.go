@@ -0,0 +1,113 @@
+package vpc_client
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	"github.com/openshift-online/ocm-common/pkg/log"
+)
+
+// PeerVPC connects the VPC to the peer VPC with a peering connection, accepted with the AWS client
+// of the peer once it is pending acceptance, and routes the CIDR blocks of each VPC to the other one in the custom route tables
+// of both sides. The VPCs must be in the same region and must not overlap. It returns the ID of
+// the connection.
+func (vpc *VPC) PeerVPC(peer *VPC) (string, error) {
+	connection, err := vpc.AWSClient.CreateVpcPeeringConnection(vpc.VpcID, peer.VpcID)
+	if err != nil {
+		return "", err
+	}
+	connectionID := aws.ToString(connection.VpcPeeringConnectionId)
+	err = peer.AWSClient.WaitForVpcPeeringConnectionPendingAcceptance(connectionID)
+	if err != nil {
+		return connectionID, err
+	}
+	_, err = peer.AWSClient.AcceptVpcPeeringConnection(connectionID)
+	if err != nil {
+		return connectionID, err
+	}
+	peerCIDRs, err := peer.cidrBlocks()
+	if err != nil {
+		return connectionID, err
+	}
+	err = vpc.RouteCIDRs(connectionID, peerCIDRs...)
+	if err != nil {
+		return connectionID, err
+	}
+	cidrs, err := vpc.cidrBlocks()
+	if err != nil {
+		return connectionID, err
+	}
+	err = peer.RouteCIDRs(connectionID, cidrs...)
+	if err != nil {
+		return connectionID, err
+	}
+	log.LogInfo("Peered vpc %s with vpc %s: %s", vpc.VpcID, peer.VpcID, connectionID)
+	return connectionID, nil
+}
+
+// DeletePeering removes the routes through the peering connection from both VPCs, then deletes
+// the connection.
+func (vpc *VPC) DeletePeering(peer *VPC, connectionID string) error {
+	for _, side := range []*VPC{vpc, peer} {
+		err := side.DeleteRoutesTo(connectionID)
+		if err != nil {
+			return err
+		}
+	}
+	return vpc.AWSClient.DeleteVpcPeeringConnection(connectionID)
+}
+
+// RouteCIDRs routes each CIDR block to the target, a peering connection or a transit gateway for
+// example, in every custom route table of the VPC.
+func (vpc *VPC) RouteCIDRs(targetID string, cidrs ...string) error {
+	routeTables, err := vpc.AWSClient.ListCustomerRouteTables(vpc.VpcID)
+	if err != nil {
+		return err
+	}
+	for _, routeTable := range routeTables {
+		for _, cidr := range cidrs {
+			_, err = vpc.AWSClient.CreateRouteWithDestination(aws.ToString(routeTable.RouteTableId), cidr, targetID)
+			if err != nil {
+				return fmt.Errorf("route %s to %s in route table %s failed: %w",
+					cidr, targetID, aws.ToString(routeTable.RouteTableId), err)
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteRoutesTo removes the routes to the peering connection or transit gateway from the custom
+// route tables of the VPC.
+func (vpc *VPC) DeleteRoutesTo(targetID string) error {
+	routeTables, err := vpc.AWSClient.ListCustomerRouteTables(vpc.VpcID)
+	if err != nil {
+		return err
+	}
+	for _, routeTable := range routeTables {
+		for _, route := range routeTable.Routes {
+			if aws.ToString(route.VpcPeeringConnectionId) != targetID && aws.ToString(route.TransitGatewayId) != targetID {
+				continue
+			}
+			destination := aws.ToString(route.DestinationCidrBlock)
+			if destination == "" {
+				destination = aws.ToString(route.DestinationIpv6CidrBlock)
+			}
+			err = vpc.AWSClient.DeleteRoute(aws.ToString(routeTable.RouteTableId), destination)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cidrBlocks returns the primary and secondary IPv4 CIDR blocks of the VPC.
+func (vpc *VPC) cidrBlocks() ([]string, error) {
+	described, err := vpc.AWSClient.DescribeVPC(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	return aws_client.VpcCidrBlocks(described), nil
+}
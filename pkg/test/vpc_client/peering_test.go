@@ -0,0 +1,137 @@
+package vpc_client_test
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+	. "github.com/openshift-online/ocm-common/pkg/test/vpc_client"
+)
+
+// routesTo returns the destinations routed to the target in the custom route tables of the VPC.
+func routesTo(vpc *VPC, targetID string) []string {
+	routeTables, err := vpc.AWSClient.ListCustomerRouteTables(vpc.VpcID)
+	Expect(err).ToNot(HaveOccurred())
+	destinations := []string{}
+	for _, routeTable := range routeTables {
+		for _, route := range routeTable.Routes {
+			if aws.ToString(route.VpcPeeringConnectionId) == targetID || aws.ToString(route.TransitGatewayId) == targetID {
+				destinations = append(destinations, aws.ToString(route.DestinationCidrBlock))
+			}
+		}
+	}
+	return destinations
+}
+
+// initiatingPeeringEC2 reports peering connections initiating the request for the next describes.
+type initiatingPeeringEC2 struct {
+	*aws_fake.EC2
+	mutex     sync.Mutex
+	describes int
+}
+
+func (f *initiatingPeeringEC2) DescribeVpcPeeringConnections(ctx context.Context, params *ec2.DescribeVpcPeeringConnectionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcPeeringConnectionsOutput, error) {
+	output, err := f.EC2.DescribeVpcPeeringConnections(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.describes > 0 {
+		f.describes--
+		for _, connection := range output.VpcPeeringConnections {
+			connection.Status.Code = types.VpcPeeringConnectionStateReasonCodeInitiatingRequest
+		}
+	}
+	return output, nil
+}
+
+var _ = Describe("VPC peering", func() {
+	var (
+		fake    *aws_fake.EC2
+		client  *aws_client.AWSClient
+		cluster *VPC
+		bastion *VPC
+	)
+
+	createVPC := func(name string, cidr string) *VPC {
+		vpc, err := NewVPC().
+			AWSclient(client).
+			Name(name).
+			CIDR(cidr).
+			SetRegion(fake.Region()).
+			NewCIDRPool().
+			CreateVPCChain(fake.Zones()[0])
+		Expect(err).ToNot(HaveOccurred())
+		return vpc
+	}
+
+	BeforeEach(func() {
+		fake = aws_fake.NewEC2(aws_fake.WithRegion("us-east-2"))
+		client = &aws_client.AWSClient{Ec2Client: fake}
+		cluster = createVPC("cluster-vpc", "10.0.0.0/16")
+		bastion = createVPC("bastion-vpc", "172.16.0.0/16")
+	})
+
+	It("should route both VPCs through the peering connection", func() {
+		connectionID, err := cluster.PeerVPC(bastion)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routesTo(cluster, connectionID)).To(Equal([]string{"172.16.0.0/16", "172.16.0.0/16"}))
+		Expect(routesTo(bastion, connectionID)).To(Equal([]string{"10.0.0.0/16", "10.0.0.0/16"}))
+
+		Expect(cluster.DeletePeering(bastion, connectionID)).To(Succeed())
+		Expect(routesTo(cluster, connectionID)).To(BeEmpty())
+		Expect(routesTo(bastion, connectionID)).To(BeEmpty())
+		connections, err := client.ListVpcPeeringConnections(cluster.VpcID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(connections).To(BeEmpty())
+	})
+
+	It("should refuse to peer overlapping VPCs", func() {
+		overlapping := createVPC("overlapping-vpc", "10.0.0.0/20")
+		_, err := cluster.PeerVPC(overlapping)
+		Expect(err).To(MatchError(ContainSubstring("Overlapping CIDRs")))
+	})
+
+	It("should not route to a peering connection pending acceptance", func() {
+		connection, err := client.CreateVpcPeeringConnection(cluster.VpcID, bastion.VpcID)
+		Expect(err).ToNot(HaveOccurred())
+		connectionID := aws.ToString(connection.VpcPeeringConnectionId)
+		Expect(connection.Status.Code).To(Equal(types.VpcPeeringConnectionStateReasonCodeInitiatingRequest))
+		Expect(cluster.RouteCIDRs(connectionID, bastion.CIDRValue)).ToNot(Succeed())
+
+		_, err = client.AcceptVpcPeeringConnection(connectionID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.RouteCIDRs(connectionID, bastion.CIDRValue)).To(Succeed())
+		described, err := fake.DescribeVpcPeeringConnections(context.Background(), &ec2.DescribeVpcPeeringConnectionsInput{
+			VpcPeeringConnectionIds: []string{connectionID},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(described.VpcPeeringConnections[0].Status.Code).To(Equal(types.VpcPeeringConnectionStateReasonCodeActive))
+	})
+
+	It("should wait for the peering connection to be pending acceptance", func() {
+		initiating := &initiatingPeeringEC2{EC2: fake, describes: 1}
+		bastion.AWSClient = &aws_client.AWSClient{Ec2Client: initiating}
+		connectionID, err := cluster.PeerVPC(bastion)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(initiating.describes).To(BeZero())
+		Expect(routesTo(cluster, connectionID)).To(HaveLen(2))
+	})
+
+	It("should not accept a failed peering connection", func() {
+		connection, err := client.CreateVpcPeeringConnection(cluster.VpcID, bastion.VpcID)
+		Expect(err).ToNot(HaveOccurred())
+		connectionID := aws.ToString(connection.VpcPeeringConnectionId)
+		Expect(client.DeleteVpcPeeringConnection(connectionID)).To(Succeed())
+		err = client.WaitForVpcPeeringConnectionPendingAcceptance(connectionID)
+		Expect(err).To(MatchError(ContainSubstring("is deleted")))
+	})
+})
@@ -1,6 +1,7 @@
 package vpc_client
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -129,16 +130,13 @@ func (vpc *VPC) WaitforSecrityGroupDeleted(sgID string, waitTime int) (result bo
 		return false, err
 	}
 
-	timeout := time.Duration(waitTime) * time.Minute
-	start := time.Now()
-	result = false
-	for time.Since(start) < timeout {
-		output, err := vpc.AWSClient.GetSecurityGroupWithID(sgID)
-		if err != nil && output == nil {
-			result = true
-			return result, nil
-		}
-		time.Sleep(time.Second)
-	}
-	return result, err
+	err = vpc.AWSClient.WaitFor("security group deleted: "+sgID, time.Duration(waitTime)*time.Minute,
+		func(context.Context) (bool, string, error) {
+			output, err := vpc.AWSClient.GetSecurityGroupWithID(sgID)
+			if err != nil && output == nil {
+				return true, "", nil
+			}
+			return false, "still existing", nil
+		})
+	return err == nil, err
 }
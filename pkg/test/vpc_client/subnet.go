@@ -143,6 +143,18 @@ func (vpc *VPC) CreatePrivateSubnet(zone string, natEnabled bool) (*Subnet, erro
 			return subnet, fmt.Errorf("error happens when create route NAT gateway route to subnet: %s, %s", subnet.ID, err.Error())
 		}
 		subnet.RTable.Routes = append(subnet.RTable.Routes, *route)
+		if subnet.IPv6Cidr != "" {
+			eigwID, err := vpc.PrepareEgressOnlyInternetGateway()
+			if err != nil {
+				return subnet, fmt.Errorf("prepare egress-only internet gateway failed for vpc: %s", err)
+			}
+			route, err = vpc.AWSClient.CreateRouteWithDestination(*respRouteTable.RouteTable.RouteTableId,
+				CON.RouteDestinationIpv6CidrBlock, eigwID)
+			if err != nil {
+				return subnet, fmt.Errorf("error happens when create egress-only gateway route to subnet: %s, %s", subnet.ID, err.Error())
+			}
+			subnet.RTable.Routes = append(subnet.RTable.Routes, *route)
+		}
 	}
 	_, err = vpc.AWSClient.TagResource(subnet.ID, tags)
 	if err != nil {
@@ -201,6 +213,14 @@ func (vpc *VPC) CreatePublicSubnet(zone string) (*Subnet, error) {
 		return nil, fmt.Errorf("create route failed for rt %s: %s", *respRouteTable.RouteTable.RouteTableId, err)
 	}
 	subnet.RTable.Routes = append(subnet.RTable.Routes, *route)
+	if subnet.IPv6Cidr != "" {
+		route, err = vpc.AWSClient.CreateRouteWithDestination(*respRouteTable.RouteTable.RouteTableId,
+			CON.RouteDestinationIpv6CidrBlock, igwid)
+		if err != nil {
+			return nil, fmt.Errorf("create IPv6 route failed for rt %s: %s", *respRouteTable.RouteTable.RouteTableId, err)
+		}
+		subnet.RTable.Routes = append(subnet.RTable.Routes, *route)
+	}
 	subnet.Private = false
 	_, err = vpc.AWSClient.TagResource(subnet.ID, tags)
 	if err != nil {
@@ -318,7 +338,15 @@ func (vpc *VPC) CreateSubnet(zone string) (*Subnet, error) {
 	}
 
 	subnetcidr := vpc.CIDRPool.Allocate().CIDR
-	respCreateSubnet, err := vpc.AWSClient.CreateSubnet(vpc.VpcID, zone, subnetcidr)
+	ipv6cidr := ""
+	if vpc.CIDRPool.IPv6CIDR != "" {
+		ipv6Subnet := vpc.CIDRPool.AllocateIPv6()
+		if ipv6Subnet == nil {
+			return nil, fmt.Errorf("no free IPv6 /64 left in %s", vpc.CIDRPool.IPv6CIDR)
+		}
+		ipv6cidr = ipv6Subnet.CIDR
+	}
+	respCreateSubnet, err := vpc.AWSClient.CreateDualStackSubnet(vpc.VpcID, zone, subnetcidr, ipv6cidr)
 	if err != nil {
 		log.LogError("create subnet error %s", err.Error())
 		return nil, err
@@ -332,12 +360,13 @@ func (vpc *VPC) CreateSubnet(zone string) (*Subnet, error) {
 
 	log.LogInfo("Created subnet with ID %s", *respCreateSubnet.SubnetId)
 	subnet := &Subnet{
-		ID:      *respCreateSubnet.SubnetId,
-		Private: true,
-		Zone:    zone,
-		Cidr:    subnetcidr,
-		Region:  vpc.Region,
-		VpcID:   vpc.VpcID,
+		ID:       *respCreateSubnet.SubnetId,
+		Private:  true,
+		Zone:     zone,
+		Cidr:     subnetcidr,
+		IPv6Cidr: ipv6cidr,
+		Region:   vpc.Region,
+		VpcID:    vpc.VpcID,
 	}
 	vpc.SubnetList = append(vpc.SubnetList, subnet)
 	return subnet, err
@@ -410,6 +439,9 @@ func (vpc *VPC) ListSubnets() ([]*Subnet, error) {
 			SetVpcID(*sub.VpcId).
 			SetName(subnetName).
 			SetRegion(vpc.Region)
+		if ipv6Cidr := subnetIPv6CidrBlock(sub); ipv6Cidr != "" {
+			subnet.SetIPv6Cidr(ipv6Cidr)
+		}
 
 		subnets = append(subnets, subnet)
 		log.LogInfo("%s\t%s\t%s\t", *sub.SubnetId, *sub.CidrBlock, *sub.AvailabilityZone)
@@ -423,6 +455,18 @@ func (vpc *VPC) ListSubnets() ([]*Subnet, error) {
 	return subnets, err
 }
 
+// subnetIPv6CidrBlock returns the associated IPv6 CIDR block of the subnet, or an empty string for
+// an IPv4 only subnet.
+func subnetIPv6CidrBlock(subnet types.Subnet) string {
+	for _, association := range subnet.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState != nil &&
+			association.Ipv6CidrBlockState.State == types.SubnetCidrBlockStateCodeAssociated {
+			return aws.ToString(association.Ipv6CidrBlock)
+		}
+	}
+	return ""
+}
+
 // UniqueSubnet will return a unique subnet by the subnetID
 // It contains more values including the CIDR values
 func (vpc *VPC) UniqueSubnet(subnetID string) *Subnet {
@@ -0,0 +1,773 @@
+package vpc_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+	"github.com/openshift-online/ocm-common/pkg/log"
+)
+
+// TeardownKind is the kind of resource a teardown step deletes.
+type TeardownKind string
+
+const (
+	TeardownVpcPeeringConnection      TeardownKind = "vpc-peering-connection"
+	TeardownTransitGatewayAttachment  TeardownKind = "transit-gateway-attachment"
+	TeardownLoadBalancer              TeardownKind = "load-balancer"
+	TeardownVpcEndpoint               TeardownKind = "vpc-endpoint"
+	TeardownInstance                  TeardownKind = "instance"
+	TeardownKeyPair                   TeardownKind = "key-pair"
+	TeardownNatGateway                TeardownKind = "nat-gateway"
+	TeardownElasticIP                 TeardownKind = "elastic-ip"
+	TeardownNetworkInterface          TeardownKind = "network-interface"
+	TeardownSecurityGroupRules        TeardownKind = "security-group-rules"
+	TeardownSecurityGroup             TeardownKind = "security-group"
+	TeardownRouteTable                TeardownKind = "route-table"
+	TeardownInternetGateway           TeardownKind = "internet-gateway"
+	TeardownEgressOnlyInternetGateway TeardownKind = "egress-only-internet-gateway"
+	TeardownSubnet                    TeardownKind = "subnet"
+	TeardownVpc                       TeardownKind = "vpc"
+)
+
+// Defaults of the teardown execution.
+const (
+	DefaultTeardownParallelism  = 4
+	DefaultTeardownRetryTimeout = 5 * time.Minute
+)
+
+// errTeardownPending tells that a step cannot run yet because AWS still holds the resource, for
+// example a network interface managed by a service that is being deleted.
+var errTeardownPending = errors.New("resource is still in use")
+
+// TeardownStep deletes one resource once the steps it depends on are done.
+type TeardownStep struct {
+	// ID identifies the step in the plan as <kind>/<resource ID>.
+	ID         string       `json:"id"`
+	Kind       TeardownKind `json:"kind"`
+	ResourceID string       `json:"resourceId"`
+	Name       string       `json:"name,omitempty"`
+	// Managed marks network interfaces owned by an AWS service. They are not deleted, the step
+	// waits for the service to release them.
+	Managed   bool     `json:"managed,omitempty"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Level is the length of the longest dependency chain leading to the step. Steps of the same
+	// level do not depend on each other and run in parallel.
+	Level int `json:"level"`
+}
+
+// TeardownPlan lists the steps deleting a VPC and everything attached to it, ordered by level.
+// It serializes to JSON, so it can be reviewed before ExecuteTeardown runs it.
+type TeardownPlan struct {
+	VpcID string          `json:"vpcId"`
+	Steps []*TeardownStep `json:"steps"`
+}
+
+// Levels groups the steps of the plan by level.
+func (plan *TeardownPlan) Levels() [][]*TeardownStep {
+	levels := [][]*TeardownStep{}
+	for _, step := range plan.Steps {
+		for len(levels) <= step.Level {
+			levels = append(levels, []*TeardownStep{})
+		}
+		levels[step.Level] = append(levels[step.Level], step)
+	}
+	return levels
+}
+
+// Step returns the step with the given ID, or nil.
+func (plan *TeardownPlan) Step(id string) *TeardownStep {
+	for _, step := range plan.Steps {
+		if step.ID == id {
+			return step
+		}
+	}
+	return nil
+}
+
+func (plan *TeardownPlan) String() string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "Teardown plan of %s:\n", plan.VpcID)
+	for level, steps := range plan.Levels() {
+		fmt.Fprintf(builder, "level %d:\n", level)
+		for _, step := range steps {
+			fmt.Fprintf(builder, "  %s", step.ID)
+			if step.Name != "" {
+				fmt.Fprintf(builder, " (%s)", step.Name)
+			}
+			if len(step.DependsOn) != 0 {
+				fmt.Fprintf(builder, " after %s", strings.Join(step.DependsOn, ", "))
+			}
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
+
+// TeardownOption configures ExecuteTeardown.
+type TeardownOption func(*teardownOptions)
+
+type teardownOptions struct {
+	parallelism int
+	waiter      *aws_client.Waiter
+}
+
+// WithTeardownParallelism bounds the number of steps of a level running at the same time.
+func WithTeardownParallelism(parallelism int) TeardownOption {
+	return func(options *teardownOptions) {
+		options.parallelism = parallelism
+	}
+}
+
+// WithTeardownWaiter sets how a step is retried while AWS reports a DependencyViolation, which
+// happens when resources deleted by earlier steps are not fully gone yet.
+func WithTeardownWaiter(waiter *aws_client.Waiter) TeardownOption {
+	return func(options *teardownOptions) {
+		options.waiter = waiter
+	}
+}
+
+// teardownGraph collects the steps of a plan while discovering the VPC resources.
+type teardownGraph struct {
+	steps map[string]*TeardownStep
+}
+
+func teardownStepID(kind TeardownKind, resourceID string) string {
+	return string(kind) + "/" + resourceID
+}
+
+func (graph *teardownGraph) add(kind TeardownKind, resourceID string, name string) *TeardownStep {
+	id := teardownStepID(kind, resourceID)
+	if step, ok := graph.steps[id]; ok {
+		return step
+	}
+	step := &TeardownStep{ID: id, Kind: kind, ResourceID: resourceID, Name: name}
+	graph.steps[id] = step
+	return step
+}
+
+// dependOn makes step run after the given steps. IDs without a step in the graph are ignored.
+func (graph *teardownGraph) dependOn(step *TeardownStep, ids ...string) {
+	for _, id := range ids {
+		if _, ok := graph.steps[id]; !ok || id == step.ID {
+			continue
+		}
+		if !containsString(step.DependsOn, id) {
+			step.DependsOn = append(step.DependsOn, id)
+		}
+	}
+}
+
+// plan computes the step levels and returns them sorted by level and ID.
+func (graph *teardownGraph) plan(vpcID string) (*TeardownPlan, error) {
+	levels := map[string]int{}
+	visiting := map[string]bool{}
+	var level func(step *TeardownStep) (int, error)
+	level = func(step *TeardownStep) (int, error) {
+		if value, ok := levels[step.ID]; ok {
+			return value, nil
+		}
+		if visiting[step.ID] {
+			return 0, fmt.Errorf("teardown step %s depends on itself", step.ID)
+		}
+		visiting[step.ID] = true
+		value := 0
+		for _, dependency := range step.DependsOn {
+			dependencyLevel, err := level(graph.steps[dependency])
+			if err != nil {
+				return 0, err
+			}
+			if dependencyLevel+1 > value {
+				value = dependencyLevel + 1
+			}
+		}
+		levels[step.ID] = value
+		return value, nil
+	}
+	plan := &TeardownPlan{VpcID: vpcID, Steps: []*TeardownStep{}}
+	for _, step := range graph.steps {
+		value, err := level(step)
+		if err != nil {
+			return nil, err
+		}
+		step.Level = value
+		sort.Strings(step.DependsOn)
+		plan.Steps = append(plan.Steps, step)
+	}
+	sort.Slice(plan.Steps, func(i, j int) bool {
+		if plan.Steps[i].Level != plan.Steps[j].Level {
+			return plan.Steps[i].Level < plan.Steps[j].Level
+		}
+		return plan.Steps[i].ID < plan.Steps[j].ID
+	})
+	return plan, nil
+}
+
+func (graph *teardownGraph) ids(kind TeardownKind) []string {
+	ids := []string{}
+	for id, step := range graph.steps {
+		if step.Kind == kind {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanTeardown discovers everything attached to the VPC and returns the steps deleting it. The
+// default security group, network ACL and main route table go away with the VPC and have no step.
+// For a VPC rebuilt from a manifest, the key pairs, NAT gateway addresses and internet gateways
+// recorded in it are deleted too, as they outlive their attachment to the VPC.
+func (vpc *VPC) PlanTeardown() (*TeardownPlan, error) {
+	if vpc.VpcID == "" {
+		return nil, fmt.Errorf("got empty vpc ID to plan the teardown. Make sure you loaded it from AWS")
+	}
+	client := vpc.AWSClient
+	graph := &teardownGraph{steps: map[string]*TeardownStep{}}
+	// subnetSteps lists the steps of the resources living in each subnet.
+	subnetSteps := map[string][]string{}
+	// ownerSteps maps network interfaces to the step of the resource owning them.
+	ownerSteps := map[string]string{}
+
+	peerings, err := client.ListVpcPeeringConnections(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, peering := range peerings {
+		graph.add(TeardownVpcPeeringConnection, aws.ToString(peering.VpcPeeringConnectionId), "")
+	}
+
+	attachments, err := client.ListTransitGatewayVpcAttachments(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	attachmentSteps := map[string]string{}
+	for _, attachment := range attachments {
+		step := graph.add(TeardownTransitGatewayAttachment, aws.ToString(attachment.TransitGatewayAttachmentId),
+			aws.ToString(attachment.TransitGatewayId))
+		attachmentSteps[aws.ToString(attachment.TransitGatewayAttachmentId)] = step.ID
+		for _, subnetID := range attachment.SubnetIds {
+			subnetSteps[subnetID] = append(subnetSteps[subnetID], step.ID)
+		}
+	}
+
+	loadBalancers, err := client.DescribeLoadBalancers(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	loadBalancerSteps := map[string]string{}
+	for _, loadBalancer := range loadBalancers {
+		step := graph.add(TeardownLoadBalancer, aws.ToString(loadBalancer.LoadBalancerArn), aws.ToString(loadBalancer.LoadBalancerName))
+		loadBalancerSteps[loadBalancerInterfaceDescription(loadBalancer)] = step.ID
+		for _, zone := range loadBalancer.AvailabilityZones {
+			subnetSteps[aws.ToString(zone.SubnetId)] = append(subnetSteps[aws.ToString(zone.SubnetId)], step.ID)
+		}
+	}
+
+	endpoints, err := client.ListEndpointAssociation(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	endpointSteps := []string{}
+	groupUsers := map[string][]string{}
+	for _, endpoint := range endpoints {
+		if endpoint.State == types.StateDeleted {
+			continue
+		}
+		step := graph.add(TeardownVpcEndpoint, aws.ToString(endpoint.VpcEndpointId), aws.ToString(endpoint.ServiceName))
+		endpointSteps = append(endpointSteps, step.ID)
+		for _, networkInterfaceID := range endpoint.NetworkInterfaceIds {
+			ownerSteps[networkInterfaceID] = step.ID
+		}
+		for _, subnetID := range endpoint.SubnetIds {
+			subnetSteps[subnetID] = append(subnetSteps[subnetID], step.ID)
+		}
+		for _, group := range endpoint.Groups {
+			groupUsers[aws.ToString(group.GroupId)] = append(groupUsers[aws.ToString(group.GroupId)], step.ID)
+		}
+	}
+
+	keyUsers := map[string][]string{}
+	instances, err := client.ListInstances([]string{}, map[string][]string{"vpc-id": {vpc.VpcID}})
+	if err != nil {
+		return nil, err
+	}
+	for _, instance := range instances {
+		if instance.State != nil && instance.State.Name == types.InstanceStateNameTerminated {
+			continue
+		}
+		step := graph.add(TeardownInstance, aws.ToString(instance.InstanceId), aws_client.GetInstanceName(&instance))
+		subnetSteps[aws.ToString(instance.SubnetId)] = append(subnetSteps[aws.ToString(instance.SubnetId)], step.ID)
+		for _, group := range instance.SecurityGroups {
+			groupUsers[aws.ToString(group.GroupId)] = append(groupUsers[aws.ToString(group.GroupId)], step.ID)
+		}
+		if instance.KeyName != nil {
+			keyUsers[aws.ToString(instance.KeyName)] = append(keyUsers[aws.ToString(instance.KeyName)], step.ID)
+		}
+	}
+	keyNames, err := vpc.ownedKeyPairNames(keyUsers)
+	if err != nil {
+		return nil, err
+	}
+	if vpc.SourceManifest != nil {
+		// The key pairs recorded in the manifest were created for the VPC and are deleted even
+		// once the instances using them are gone.
+		keyNames = append(keyNames, vpc.SourceManifest.KeyPairNames...)
+	}
+	for _, keyName := range keyNames {
+		keyPair := graph.add(TeardownKeyPair, keyName, "")
+		graph.dependOn(keyPair, keyUsers[keyName]...)
+	}
+
+	natGateways, err := client.ListNatGateways(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, natGateway := range natGateways {
+		if natGateway.State == types.NatGatewayStateDeleted || natGateway.State == types.NatGatewayStateFailed {
+			continue
+		}
+		step := graph.add(TeardownNatGateway, aws.ToString(natGateway.NatGatewayId), "")
+		subnetSteps[aws.ToString(natGateway.SubnetId)] = append(subnetSteps[aws.ToString(natGateway.SubnetId)], step.ID)
+		for _, address := range natGateway.NatGatewayAddresses {
+			ownerSteps[aws.ToString(address.NetworkInterfaceId)] = step.ID
+		}
+	}
+
+	networkInterfaces, err := client.DescribeNetWorkInterface(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, networkInterface := range networkInterfaces {
+		networkInterfaceID := aws.ToString(networkInterface.NetworkInterfaceId)
+		step := graph.add(TeardownNetworkInterface, networkInterfaceID, aws.ToString(networkInterface.Description))
+		step.Managed = aws.ToBool(networkInterface.RequesterManaged)
+		owner := ownerSteps[networkInterfaceID]
+		if owner == "" && networkInterface.Attachment != nil && networkInterface.Attachment.InstanceId != nil {
+			owner = teardownStepID(TeardownInstance, aws.ToString(networkInterface.Attachment.InstanceId))
+		}
+		if owner == "" {
+			owner = loadBalancerSteps[aws.ToString(networkInterface.Description)]
+		}
+		if owner == "" {
+			for attachmentID, attachmentStep := range attachmentSteps {
+				if strings.HasSuffix(aws.ToString(networkInterface.Description), attachmentID) {
+					owner = attachmentStep
+				}
+			}
+		}
+		graph.dependOn(step, owner)
+		subnetSteps[aws.ToString(networkInterface.SubnetId)] = append(subnetSteps[aws.ToString(networkInterface.SubnetId)], step.ID)
+		for _, group := range networkInterface.Groups {
+			groupUsers[aws.ToString(group.GroupId)] = append(groupUsers[aws.ToString(group.GroupId)], step.ID, owner)
+		}
+
+		if networkInterface.Association != nil && networkInterface.Association.AllocationId != nil {
+			address := graph.add(TeardownElasticIP, aws.ToString(networkInterface.Association.AllocationId),
+				aws.ToString(networkInterface.Association.PublicIp))
+			if owner != "" {
+				graph.dependOn(address, owner)
+			} else {
+				graph.dependOn(address, step.ID)
+			}
+		}
+	}
+
+	if vpc.SourceManifest != nil {
+		// The addresses of a NAT gateway deleted by an interrupted teardown are no longer found
+		// through the VPC, those recorded in the manifest are released anyway.
+		for _, natGateway := range vpc.SourceManifest.NatGateways {
+			for _, allocationID := range natGateway.AllocationIDs {
+				address := graph.add(TeardownElasticIP, allocationID, "")
+				graph.dependOn(address, teardownStepID(TeardownNatGateway, natGateway.ID))
+			}
+		}
+	}
+
+	output, err := client.Ec2Client.DescribeSecurityGroups(vpc.requestContext(), &ec2.DescribeSecurityGroupsInput{
+		Filters: []types.Filter{{Name: aws.String("vpc-id"), Values: []string{vpc.VpcID}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range output.SecurityGroups {
+		if aws.ToString(group.GroupName) == "default" {
+			continue
+		}
+		step := graph.add(TeardownSecurityGroup, aws.ToString(group.GroupId), aws.ToString(group.GroupName))
+		graph.dependOn(step, groupUsers[aws.ToString(group.GroupId)]...)
+	}
+	// A group referenced by the rules of another group can only be deleted once the rules are
+	// revoked, including rules of the default group.
+	for _, group := range output.SecurityGroups {
+		groupID := aws.ToString(group.GroupId)
+		for _, referenced := range referencedSecurityGroups(group) {
+			referencedStep, ok := graph.steps[teardownStepID(TeardownSecurityGroup, referenced)]
+			if !ok || referenced == groupID {
+				continue
+			}
+			rules := graph.add(TeardownSecurityGroupRules, groupID, aws.ToString(group.GroupName))
+			graph.dependOn(referencedStep, rules.ID)
+		}
+	}
+
+	routeTables, err := client.ListCustomerRouteTables(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, routeTable := range routeTables {
+		step := graph.add(TeardownRouteTable, aws.ToString(routeTable.RouteTableId), "")
+		graph.dependOn(step, endpointSteps...)
+	}
+
+	internetGateways, err := client.ListInternetGateWay(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	internetGatewayIDs := []string{}
+	for _, internetGateway := range internetGateways {
+		internetGatewayIDs = append(internetGatewayIDs, aws.ToString(internetGateway.InternetGatewayId))
+	}
+	if vpc.SourceManifest != nil {
+		// A gateway detached by an interrupted teardown is only known from the manifest.
+		internetGatewayIDs = append(internetGatewayIDs, vpc.SourceManifest.InternetGatewayIDs...)
+	}
+	for _, internetGatewayID := range internetGatewayIDs {
+		step := graph.add(TeardownInternetGateway, internetGatewayID, "")
+		for _, kind := range []TeardownKind{TeardownNatGateway, TeardownElasticIP, TeardownInstance, TeardownLoadBalancer} {
+			graph.dependOn(step, graph.ids(kind)...)
+		}
+	}
+
+	egressOnlyGateways, err := client.ListEgressOnlyInternetGateways(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, egressOnlyGateway := range egressOnlyGateways {
+		graph.add(TeardownEgressOnlyInternetGateway, aws.ToString(egressOnlyGateway.EgressOnlyInternetGatewayId), "")
+	}
+
+	subnets, err := client.ListSubnetByVpcID(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, subnet := range subnets {
+		step := graph.add(TeardownSubnet, aws.ToString(subnet.SubnetId), subnetName(subnet))
+		graph.dependOn(step, subnetSteps[aws.ToString(subnet.SubnetId)]...)
+	}
+
+	vpcStep := graph.add(TeardownVpc, vpc.VpcID, vpc.VPCName)
+	for id, step := range graph.steps {
+		if step.Kind != TeardownKeyPair {
+			graph.dependOn(vpcStep, id)
+		}
+	}
+	return graph.plan(vpc.VpcID)
+}
+
+// ownedKeyPairNames returns the key pairs among keyUsers that the VPC created, as told by
+// ownsKeyPair. Key pairs are account-wide and may be shared with instances of other VPCs, so the
+// others are left alone.
+func (vpc *VPC) ownedKeyPairNames(keyUsers map[string][]string) ([]string, error) {
+	if len(keyUsers) == 0 {
+		return nil, nil
+	}
+	keyNames := make([]string, 0, len(keyUsers))
+	for keyName := range keyUsers {
+		keyNames = append(keyNames, keyName)
+	}
+	output, err := vpc.AWSClient.Ec2Client.DescribeKeyPairs(vpc.requestContext(), &ec2.DescribeKeyPairsInput{
+		Filters: []types.Filter{{Name: aws.String("key-name"), Values: keyNames}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe key pairs failed: %w", err)
+	}
+	owned := []string{}
+	for _, keyPair := range output.KeyPairs {
+		if vpc.ownsKeyPair(keyPair.Tags) {
+			owned = append(owned, aws.ToString(keyPair.KeyName))
+		}
+	}
+	sort.Strings(owned)
+	return owned, nil
+}
+
+// ownsKeyPair tells whether a key pair was created for the VPC, which tags it with its ID. Names
+// are not trusted: the key of a VPC named my-vpc-2 would pass for a key of my-vpc.
+func (vpc *VPC) ownsKeyPair(tags []types.Tag) bool {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == "VpcId" && aws.ToString(tag.Value) == vpc.VpcID {
+			return true
+		}
+	}
+	return false
+}
+
+// loadBalancerInterfaceDescription returns the description AWS gives to the network interfaces
+// of a load balancer, for example "ELB app/my-lb/50dc6c495c0c9188".
+func loadBalancerInterfaceDescription(loadBalancer elbtypes.LoadBalancer) string {
+	arn := aws.ToString(loadBalancer.LoadBalancerArn)
+	index := strings.Index(arn, ":loadbalancer/")
+	if index < 0 {
+		return ""
+	}
+	return "ELB " + arn[index+len(":loadbalancer/"):]
+}
+
+// referencedSecurityGroups returns the groups the rules of group refer to.
+func referencedSecurityGroups(group types.SecurityGroup) []string {
+	referenced := []string{}
+	for _, permissions := range [][]types.IpPermission{group.IpPermissions, group.IpPermissionsEgress} {
+		for _, permission := range permissions {
+			for _, pair := range permission.UserIdGroupPairs {
+				if pair.GroupId != nil && !containsString(referenced, *pair.GroupId) {
+					referenced = append(referenced, *pair.GroupId)
+				}
+			}
+		}
+	}
+	return referenced
+}
+
+func subnetName(subnet types.Subnet) string {
+	for _, tag := range subnet.Tags {
+		if aws.ToString(tag.Key) == "Name" {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
+
+// requestContext returns the context of the AWS client of the VPC.
+func (vpc *VPC) requestContext() context.Context {
+	if vpc.AWSClient.ClientContext == nil {
+		return context.Background()
+	}
+	return vpc.AWSClient.ClientContext
+}
+
+// ExecuteTeardown runs the steps of the plan level by level, running the steps of a level in
+// parallel. A step failing with DependencyViolation is retried until the waiter gives up, and a
+// resource already gone counts as deleted. Steps depending on a failed step are skipped. All the
+// failures are returned joined.
+func (vpc *VPC) ExecuteTeardown(plan *TeardownPlan, opts ...TeardownOption) error {
+	options := &teardownOptions{
+		parallelism: DefaultTeardownParallelism,
+		waiter:      aws_client.NewWaiter(DefaultTeardownRetryTimeout),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.parallelism < 1 {
+		options.parallelism = 1
+	}
+	log.LogInfo("Going to tear down the vpc %s in %d steps", plan.VpcID, len(plan.Steps))
+
+	var (
+		mutex  sync.Mutex
+		failed = map[string]bool{}
+		errs   []error
+	)
+	for _, steps := range plan.Levels() {
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, options.parallelism)
+		for _, step := range steps {
+			mutex.Lock()
+			blocked := ""
+			for _, dependency := range step.DependsOn {
+				if failed[dependency] {
+					blocked = dependency
+					break
+				}
+			}
+			if blocked != "" {
+				failed[step.ID] = true
+				errs = append(errs, fmt.Errorf("%s: skipped as %s failed", step.ID, blocked))
+				mutex.Unlock()
+				continue
+			}
+			mutex.Unlock()
+
+			wg.Add(1)
+			slots <- struct{}{}
+			go func(step *TeardownStep) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				err := vpc.runTeardownStep(options.waiter, step)
+				if err == nil {
+					return
+				}
+				log.LogError("Teardown step %s failed: %s", step.ID, err.Error())
+				mutex.Lock()
+				defer mutex.Unlock()
+				failed[step.ID] = true
+				errs = append(errs, fmt.Errorf("%s: %w", step.ID, err))
+			}(step)
+		}
+		wg.Wait()
+	}
+	return errors.Join(errs...)
+}
+
+// Teardown plans and executes the deletion of the VPC and everything attached to it.
+func (vpc *VPC) Teardown(opts ...TeardownOption) error {
+	plan, err := vpc.PlanTeardown()
+	if err != nil {
+		return err
+	}
+	log.LogInfo("%s", plan.String())
+	return vpc.ExecuteTeardown(plan, opts...)
+}
+
+func (vpc *VPC) runTeardownStep(waiter *aws_client.Waiter, step *TeardownStep) error {
+	return waiter.Wait(vpc.requestContext(), "teardown "+step.ID, func(ctx context.Context) (bool, string, error) {
+		err := vpc.deleteTeardownResource(step)
+		switch {
+		case err == nil || awserrors.IsNotFoundError(err):
+			return true, "", nil
+		case awserrors.IsErrorCode(err, awserrors.DependencyViolation) || errors.Is(err, errTeardownPending):
+			return false, err.Error(), nil
+		}
+		return false, "", err
+	})
+}
+
+func (vpc *VPC) deleteTeardownResource(step *TeardownStep) error {
+	client := vpc.AWSClient
+	switch step.Kind {
+	case TeardownVpcPeeringConnection:
+		return client.DeleteVpcPeeringConnection(step.ResourceID)
+	case TeardownTransitGatewayAttachment:
+		return client.DeleteTransitGatewayVpcAttachment(step.ResourceID)
+	case TeardownLoadBalancer:
+		return client.DeleteELB(elbtypes.LoadBalancer{
+			LoadBalancerArn:  aws.String(step.ResourceID),
+			LoadBalancerName: aws.String(step.Name),
+		})
+	case TeardownVpcEndpoint:
+		return vpc.deleteTeardownVpcEndpoint(step.ResourceID)
+	case TeardownInstance:
+		return client.TerminateInstances([]string{step.ResourceID}, true, 20)
+	case TeardownKeyPair:
+		_, err := client.DeleteKeyPair(step.ResourceID)
+		return err
+	case TeardownNatGateway:
+		_, err := client.DeleteNatGateway(step.ResourceID, 600)
+		return err
+	case TeardownElasticIP:
+		return vpc.releaseTeardownAddress(step.ResourceID)
+	case TeardownNetworkInterface:
+		return vpc.deleteTeardownNetworkInterface(step.ResourceID, step.Managed)
+	case TeardownSecurityGroupRules:
+		return client.ReleaseInboundOutboundRules(step.ResourceID)
+	case TeardownSecurityGroup:
+		_, err := client.DeleteSecurityGroup(step.ResourceID)
+		return err
+	case TeardownRouteTable:
+		return client.DeleteRouteTableChain(step.ResourceID)
+	case TeardownInternetGateway:
+		_, err := client.DetachInternetGateway(step.ResourceID, vpc.VpcID)
+		if err != nil && !awserrors.IsErrorCode(err, awserrors.GatewayNotAttached) {
+			return err
+		}
+		_, err = client.DeleteInternetGateway(step.ResourceID)
+		return err
+	case TeardownEgressOnlyInternetGateway:
+		return client.DeleteEgressOnlyInternetGateway(step.ResourceID)
+	case TeardownSubnet:
+		_, err := client.DeleteSubnet(step.ResourceID)
+		return err
+	case TeardownVpc:
+		_, err := client.DeleteVpc(step.ResourceID)
+		return err
+	}
+	return fmt.Errorf("unknown teardown step kind %s", step.Kind)
+}
+
+// deleteTeardownVpcEndpoint deletes the endpoint and waits for it to be gone, as its network
+// interfaces are released only then.
+func (vpc *VPC) deleteTeardownVpcEndpoint(endpointID string) error {
+	output, err := vpc.AWSClient.Ec2Client.DeleteVpcEndpoints(vpc.requestContext(), &ec2.DeleteVpcEndpointsInput{
+		VpcEndpointIds: []string{endpointID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, item := range output.Unsuccessful {
+		if item.Error != nil && !strings.HasSuffix(aws.ToString(item.Error.Code), ".NotFound") {
+			return fmt.Errorf("delete vpc endpoint %s: %s: %s", endpointID,
+				aws.ToString(item.Error.Code), aws.ToString(item.Error.Message))
+		}
+	}
+	return vpc.AWSClient.WaitForResourceDeleted(endpointID, 600)
+}
+
+// releaseTeardownAddress releases the address, disassociating it first when its owner left it
+// associated.
+func (vpc *VPC) releaseTeardownAddress(allocationID string) error {
+	output, err := vpc.AWSClient.Ec2Client.DescribeAddresses(vpc.requestContext(), &ec2.DescribeAddressesInput{
+		AllocationIds: []string{allocationID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, address := range output.Addresses {
+		if address.AssociationId != nil {
+			if _, err := vpc.AWSClient.DisassociateAddress(*address.AssociationId); err != nil &&
+				!awserrors.IsNotFoundError(err) {
+				return err
+			}
+		}
+	}
+	return vpc.AWSClient.ReleaseAddressWithAllocationID(allocationID)
+}
+
+// deleteTeardownNetworkInterface deletes a network interface, detaching it first. Interfaces
+// managed by AWS services are only waited for.
+func (vpc *VPC) deleteTeardownNetworkInterface(networkInterfaceID string, managed bool) error {
+	output, err := vpc.AWSClient.Ec2Client.DescribeNetworkInterfaces(vpc.requestContext(), &ec2.DescribeNetworkInterfacesInput{
+		NetworkInterfaceIds: []string{networkInterfaceID},
+	})
+	if err != nil {
+		return err
+	}
+	if len(output.NetworkInterfaces) == 0 {
+		return nil
+	}
+	if managed {
+		return fmt.Errorf("network interface %s: %w", networkInterfaceID, errTeardownPending)
+	}
+	networkInterface := output.NetworkInterfaces[0]
+	if networkInterface.Attachment != nil && networkInterface.Attachment.AttachmentId != nil {
+		err = vpc.AWSClient.DetachNetworkInterface(*networkInterface.Attachment.AttachmentId, true)
+		if err != nil && !awserrors.IsNotFoundError(err) {
+			return err
+		}
+	}
+	_, err = vpc.AWSClient.Ec2Client.DeleteNetworkInterface(vpc.requestContext(), &ec2.DeleteNetworkInterfaceInput{
+		NetworkInterfaceId: aws.String(networkInterfaceID),
+	})
+	if awserrors.IsErrorCode(err, awserrors.InvalidNetworkInterfaceInUse) {
+		return fmt.Errorf("network interface %s: %w", networkInterfaceID, errTeardownPending)
+	}
+	return err
+}
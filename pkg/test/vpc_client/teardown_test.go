@@ -0,0 +1,333 @@
+package vpc_client_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	smithy "github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	awserrors "github.com/openshift-online/ocm-common/pkg/aws/errors"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+	. "github.com/openshift-online/ocm-common/pkg/test/vpc_client"
+)
+
+// flakyEC2 fails the first deletions of subnets with a dependency violation, and every internet
+// gateway deletion when denyGateways is set.
+type flakyEC2 struct {
+	*aws_fake.EC2
+	mutex            sync.Mutex
+	subnetViolations int
+	denyGateways     bool
+}
+
+func (f *flakyEC2) DeleteSubnet(ctx context.Context, params *ec2.DeleteSubnetInput, optFns ...func(*ec2.Options)) (*ec2.DeleteSubnetOutput, error) {
+	f.mutex.Lock()
+	violation := f.subnetViolations > 0
+	if violation {
+		f.subnetViolations--
+	}
+	f.mutex.Unlock()
+	if violation {
+		return nil, &smithy.GenericAPIError{Code: awserrors.DependencyViolation, Message: "has dependencies"}
+	}
+	return f.EC2.DeleteSubnet(ctx, params, optFns...)
+}
+
+func (f *flakyEC2) DeleteInternetGateway(ctx context.Context, params *ec2.DeleteInternetGatewayInput, optFns ...func(*ec2.Options)) (*ec2.DeleteInternetGatewayOutput, error) {
+	if f.denyGateways {
+		return nil, &smithy.GenericAPIError{Code: awserrors.AccessDenied, Message: "denied"}
+	}
+	return f.EC2.DeleteInternetGateway(ctx, params, optFns...)
+}
+
+// vpcKeyPairTags tags a key pair with the VPC it is created for, as proxies and bastions do.
+func vpcKeyPairTags(vpcID string) []types.TagSpecification {
+	return []types.TagSpecification{{
+		ResourceType: types.ResourceTypeKeyPair,
+		Tags:         []types.Tag{{Key: aws.String("VpcId"), Value: aws.String(vpcID)}},
+	}}
+}
+
+var _ = Describe("Teardown", func() {
+	var (
+		ctx          context.Context
+		mockCtrl     *gomock.Controller
+		fake         *aws_fake.EC2
+		flaky        *flakyEC2
+		vpc          *VPC
+		peerVpcID    string
+		peeringID    string
+		attachmentID string
+		groupA       string
+		groupB       string
+		waiter       = &aws_client.Waiter{Timeout: 5 * time.Second, MinDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockELB := aws_client.NewMockELBClientAPI(mockCtrl)
+		mockELB.EXPECT().DescribeLoadBalancers(gomock.Any(), gomock.Any()).
+			Return(&elb.DescribeLoadBalancersOutput{}, nil).AnyTimes()
+		fake = aws_fake.NewEC2(aws_fake.WithRegion("us-east-2"))
+		flaky = &flakyEC2{EC2: fake}
+		client := &aws_client.AWSClient{Ec2Client: flaky, ElbClient: mockELB}
+
+		var err error
+		vpc, err = NewVPC().
+			AWSclient(client).
+			Name("teardown-vpc").
+			CIDR(CON.DefaultVPCCIDR).
+			SetRegion(fake.Region()).
+			NewCIDRPool().
+			CreateVPCChain(fake.Zones()[0])
+		Expect(err).ToNot(HaveOccurred())
+
+		peer, err := fake.CreateVpc(ctx, &ec2.CreateVpcInput{CidrBlock: aws.String("172.16.0.0/16")})
+		Expect(err).ToNot(HaveOccurred())
+		peerVpcID = aws.ToString(peer.Vpc.VpcId)
+		peering, err := fake.CreateVpcPeeringConnection(ctx, &ec2.CreateVpcPeeringConnectionInput{
+			VpcId: aws.String(vpc.VpcID), PeerVpcId: aws.String(peerVpcID),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		peeringID = aws.ToString(peering.VpcPeeringConnection.VpcPeeringConnectionId)
+		_, err = fake.AcceptVpcPeeringConnection(ctx, &ec2.AcceptVpcPeeringConnectionInput{
+			VpcPeeringConnectionId: aws.String(peeringID),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		gateway, err := fake.CreateTransitGateway(ctx, &ec2.CreateTransitGatewayInput{})
+		Expect(err).ToNot(HaveOccurred())
+		attachment, err := fake.CreateTransitGatewayVpcAttachment(ctx, &ec2.CreateTransitGatewayVpcAttachmentInput{
+			TransitGatewayId: gateway.TransitGateway.TransitGatewayId,
+			VpcId:            aws.String(vpc.VpcID),
+			SubnetIds:        []string{vpc.AllPrivateSubnetIDs()[0]},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		attachmentID = aws.ToString(attachment.TransitGatewayVpcAttachment.TransitGatewayAttachmentId)
+
+		outputA, err := client.CreateSecurityGroup(vpc.VpcID, "group-a", "references group b")
+		Expect(err).ToNot(HaveOccurred())
+		groupA = aws.ToString(outputA.GroupId)
+		outputB, err := client.CreateSecurityGroup(vpc.VpcID, "group-b", "referenced by group a")
+		Expect(err).ToNot(HaveOccurred())
+		groupB = aws.ToString(outputB.GroupId)
+		_, err = fake.AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+			GroupId: aws.String(groupA),
+			IpPermissions: []types.IpPermission{{
+				IpProtocol:       aws.String("tcp"),
+				FromPort:         aws.Int32(443),
+				ToPort:           aws.Int32(443),
+				UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String(groupB)}},
+			}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should plan the deletion in dependency order", func() {
+		plan, err := vpc.PlanTeardown()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.VpcID).To(Equal(vpc.VpcID))
+
+		levels := plan.Levels()
+		Expect(levels[len(levels)-1]).To(HaveLen(1))
+		Expect(levels[len(levels)-1][0].ID).To(Equal("vpc/" + vpc.VpcID))
+		Expect(plan.Step("vpc-peering-connection/" + peeringID).Level).To(Equal(0))
+
+		level := func(id string) int {
+			step := plan.Step(id)
+			Expect(step).ToNot(BeNil(), id)
+			return step.Level
+		}
+		Expect(level("security-group/" + groupB)).To(BeNumerically(">", level("security-group-rules/"+groupA)))
+		Expect(plan.Step("security-group-rules/" + groupB)).To(BeNil())
+		Expect(level("subnet/" + vpc.AllPrivateSubnetIDs()[0])).To(
+			BeNumerically(">", level("transit-gateway-attachment/"+attachmentID)))
+
+		natSteps := 0
+		for _, step := range plan.Steps {
+			switch step.Kind {
+			case TeardownNatGateway:
+				natSteps++
+			case TeardownElasticIP:
+				Expect(step.DependsOn).To(ConsistOf(HavePrefix("nat-gateway/")))
+			case TeardownNetworkInterface:
+				Expect(step.Managed).To(BeTrue())
+				Expect(step.DependsOn).To(HaveLen(1))
+			case TeardownInternetGateway:
+				Expect(step.DependsOn).To(ContainElement(HavePrefix("elastic-ip/")))
+			}
+			for _, dependency := range step.DependsOn {
+				Expect(plan.Step(dependency).Level).To(BeNumerically("<", step.Level))
+			}
+		}
+		Expect(natSteps).To(Equal(1))
+
+		data, err := json.Marshal(plan)
+		Expect(err).ToNot(HaveOccurred())
+		loaded := &TeardownPlan{}
+		Expect(json.Unmarshal(data, loaded)).To(Succeed())
+		Expect(loaded).To(Equal(plan))
+		Expect(plan.String()).To(ContainSubstring("transit-gateway-attachment/" + attachmentID))
+	})
+
+	It("should delete the VPC and everything attached to it", func() {
+		flaky.subnetViolations = 2
+		Expect(vpc.Teardown(WithTeardownWaiter(waiter), WithTeardownParallelism(2))).To(Succeed())
+		Expect(flaky.subnetViolations).To(BeZero())
+
+		vpcs, err := fake.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpcs.Vpcs).To(HaveLen(1))
+		Expect(aws.ToString(vpcs.Vpcs[0].VpcId)).To(Equal(peerVpcID))
+		addresses, err := fake.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(addresses.Addresses).To(BeEmpty())
+		peerings, err := fake.DescribeVpcPeeringConnections(ctx, &ec2.DescribeVpcPeeringConnectionsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(peerings.VpcPeeringConnections[0].Status.Code).To(Equal(types.VpcPeeringConnectionStateReasonCodeDeleted))
+	})
+
+	It("should delete the egress-only internet gateway of a dual-stack VPC", func() {
+		dualStack, err := NewVPC().
+			AWSclient(vpc.AWSClient).
+			Name("dual-stack-vpc").
+			CIDR("10.1.0.0/16").
+			SetRegion(fake.Region()).
+			NewCIDRPool().
+			DualStack(true).
+			CreateVPCChain(fake.Zones()[0])
+		Expect(err).ToNot(HaveOccurred())
+
+		plan, err := dualStack.PlanTeardown()
+		Expect(err).ToNot(HaveOccurred())
+		gateways := 0
+		for _, step := range plan.Steps {
+			if step.Kind == TeardownEgressOnlyInternetGateway {
+				gateways++
+				Expect(plan.Step("vpc/" + dualStack.VpcID).DependsOn).To(ContainElement(step.ID))
+			}
+		}
+		Expect(gateways).To(Equal(1))
+
+		Expect(dualStack.Teardown(WithTeardownWaiter(waiter))).To(Succeed())
+		output, err := fake.DescribeEgressOnlyInternetGateways(ctx, &ec2.DescribeEgressOnlyInternetGatewaysInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output.EgressOnlyInternetGateways).To(BeEmpty())
+	})
+
+	It("should skip the steps depending on a failed step", func() {
+		flaky.denyGateways = true
+		err := vpc.Teardown(WithTeardownWaiter(waiter))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("internet-gateway/"))
+		Expect(err.Error()).To(ContainSubstring("vpc/" + vpc.VpcID + ": skipped"))
+
+		subnets, err := fake.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subnets.Subnets).To(BeEmpty())
+		vpcs, err := fake.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpcs.Vpcs).To(HaveLen(2))
+	})
+
+	It("should only delete the key pairs created for the VPC", func() {
+		launch := func(keyName string, tags []types.TagSpecification) {
+			_, err := fake.CreateKeyPair(ctx, &ec2.CreateKeyPairInput{KeyName: aws.String(keyName), TagSpecifications: tags})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = fake.RunInstances(ctx, &ec2.RunInstancesInput{
+				ImageId:  aws.String(fake.AddImage(types.Image{})),
+				SubnetId: aws.String(vpc.AllPrivateSubnetIDs()[0]),
+				KeyName:  aws.String(keyName),
+				MinCount: aws.Int32(1),
+				MaxCount: aws.Int32(1),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		}
+		launch("teardown-vpc-key", vpcKeyPairTags(vpc.VpcID))
+		launch("teardown-vpc-2-key", vpcKeyPairTags(peerVpcID))
+		launch("shared-key", nil)
+
+		plan, err := vpc.PlanTeardown()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Step("key-pair/teardown-vpc-key")).ToNot(BeNil())
+		Expect(plan.Step("key-pair/teardown-vpc-2-key")).To(BeNil())
+		Expect(plan.Step("key-pair/shared-key")).To(BeNil())
+
+		Expect(vpc.Teardown(WithTeardownWaiter(waiter))).To(Succeed())
+		keyPairs, err := fake.DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		keyNames := []string{}
+		for _, keyPair := range keyPairs.KeyPairs {
+			keyNames = append(keyNames, aws.ToString(keyPair.KeyName))
+		}
+		Expect(keyNames).To(ConsistOf("teardown-vpc-2-key", "shared-key"))
+	})
+
+	It("should delete the recorded resources left behind by an interrupted teardown", func() {
+		_, err := fake.CreateKeyPair(ctx, &ec2.CreateKeyPairInput{
+			KeyName:           aws.String("teardown-vpc-proxy"),
+			TagSpecifications: vpcKeyPairTags(vpc.VpcID),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fake.RunInstances(ctx, &ec2.RunInstancesInput{
+			ImageId:  aws.String(fake.AddImage(types.Image{})),
+			SubnetId: aws.String(vpc.AllPublicSubnetIDs()[0]),
+			KeyName:  aws.String("teardown-vpc-proxy"),
+			MinCount: aws.Int32(1),
+			MaxCount: aws.Int32(1),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		manifest, err := vpc.Manifest()
+		Expect(err).ToNot(HaveOccurred())
+		loaded, err := manifest.VPC(vpc.AWSClient)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = fake.TerminateInstances(ctx, &ec2.TerminateInstancesInput{InstanceIds: []string{manifest.Instances[0].ID}})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fake.DeleteNatGateway(ctx, &ec2.DeleteNatGatewayInput{NatGatewayId: aws.String(manifest.NatGateways[0].ID)})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fake.DetachInternetGateway(ctx, &ec2.DetachInternetGatewayInput{
+			InternetGatewayId: aws.String(manifest.InternetGatewayIDs[0]),
+			VpcId:             aws.String(vpc.VpcID),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		discovered, err := vpc.PlanTeardown()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(discovered.Step("key-pair/teardown-vpc-proxy")).To(BeNil())
+		Expect(discovered.Step("internet-gateway/" + manifest.InternetGatewayIDs[0])).To(BeNil())
+
+		plan, err := loaded.PlanTeardown()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Step("key-pair/teardown-vpc-proxy")).ToNot(BeNil())
+		Expect(plan.Step("elastic-ip/" + manifest.NatGateways[0].AllocationIDs[0])).ToNot(BeNil())
+		Expect(plan.Step("internet-gateway/" + manifest.InternetGatewayIDs[0])).ToNot(BeNil())
+
+		Expect(loaded.ExecuteTeardown(plan, WithTeardownWaiter(waiter))).To(Succeed())
+		keyPairs, err := fake.DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keyPairs.KeyPairs).To(BeEmpty())
+		addresses, err := fake.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(addresses.Addresses).To(BeEmpty())
+		internetGateways, err := fake.DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(internetGateways.InternetGateways).To(BeEmpty())
+	})
+})
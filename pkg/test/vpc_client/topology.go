@@ -0,0 +1,1114 @@
+package vpc_client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"gopkg.in/yaml.v3"
+
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/log"
+)
+
+// NATMode selects how the private subnets of a topology reach the internet.
+type NATMode string
+
+const (
+	// NATNone leaves the private subnets without default route, e.g. for private link layouts.
+	NATNone NATMode = "none"
+	// NATSingle routes the private subnets of every zone through one NAT gateway.
+	NATSingle NATMode = "single"
+	// NATPerZone routes the private subnets of each zone through a NAT gateway of the same zone.
+	NATPerZone NATMode = "per-zone"
+)
+
+// SubnetTier is the role of a subnet in a topology.
+type SubnetTier string
+
+const (
+	// SubnetTierPublic subnets route to the internet gateway and are tagged for public load balancers.
+	SubnetTierPublic SubnetTier = "public"
+	// SubnetTierPrivate subnets route to the NAT gateway, if any, and are tagged for internal load balancers.
+	SubnetTierPrivate SubnetTier = "private"
+	// SubnetTierIsolated subnets have no default route.
+	SubnetTierIsolated SubnetTier = "isolated"
+)
+
+// MaxSubnetPrefix is the smallest subnet AWS allows.
+const MaxSubnetPrefix = 28
+
+// TopologySpec describes a VPC layout. ReconcileTopology converges AWS toward it and
+// DetectTopologyDrift reports how AWS differs from it. It can be loaded from YAML or JSON with
+// LoadTopologySpec.
+type TopologySpec struct {
+	// Name is the Name tag of the VPC, and the prefix of the default subnet names.
+	Name string `json:"name" yaml:"name"`
+	// CIDR of the VPC, CON.DefaultVPCCIDR when empty.
+	CIDR  string     `json:"cidr,omitempty" yaml:"cidr,omitempty"`
+	Zones []ZoneSpec `json:"zones" yaml:"zones"`
+	// NAT defaults to NATSingle when the topology has both public and private subnets, and to
+	// NATNone otherwise.
+	NAT       NATMode        `json:"nat,omitempty" yaml:"nat,omitempty"`
+	Endpoints []EndpointSpec `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	// Tags are added to the VPC and to every subnet.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// DNSHostnames and DNSSupport default to enabled.
+	DNSHostnames *bool `json:"dnsHostnames,omitempty" yaml:"dnsHostnames,omitempty"`
+	DNSSupport   *bool `json:"dnsSupport,omitempty" yaml:"dnsSupport,omitempty"`
+}
+
+// ZoneSpec lists the subnets of an availability zone.
+type ZoneSpec struct {
+	Name    string       `json:"name" yaml:"name"`
+	Subnets []SubnetSpec `json:"subnets" yaml:"subnets"`
+}
+
+// SubnetSpec describes a subnet. Without CIDR, a free block of Prefix bits is allocated from the
+// VPC CIDR.
+type SubnetSpec struct {
+	Tier SubnetTier `json:"tier" yaml:"tier"`
+	// Prefix defaults to CON.DefaultCIDRPrefix, or to the prefix of CIDR.
+	Prefix int    `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	CIDR   string `json:"cidr,omitempty" yaml:"cidr,omitempty"`
+	// Name defaults to <vpc name>-<tier>-<zone>, followed by the index of the subnet in the tier
+	// when the zone has several subnets of the same tier.
+	Name string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// EndpointSpec describes a VPC endpoint. Gateway endpoints are added to the route tables of the
+// subnets of Tiers, interface endpoints to the first subnet of Tiers in each zone.
+type EndpointSpec struct {
+	// Service is the service name. A short name like "s3" stands for com.amazonaws.<region>.s3.
+	Service string `json:"service" yaml:"service"`
+	// Type defaults to Gateway.
+	Type types.VpcEndpointType `json:"type,omitempty" yaml:"type,omitempty"`
+	// Tiers defaults to the private and isolated subnets.
+	Tiers []SubnetTier `json:"tiers,omitempty" yaml:"tiers,omitempty"`
+}
+
+// SingleNATTopology returns a topology with a public and a private subnet in each zone, the
+// private subnets sharing one NAT gateway. This is the layout built by CreateVPCChain.
+func SingleNATTopology(name string, zones ...string) *TopologySpec {
+	spec := &TopologySpec{Name: name, NAT: NATSingle}
+	for _, zone := range zones {
+		spec.Zones = append(spec.Zones, ZoneSpec{
+			Name:    zone,
+			Subnets: []SubnetSpec{{Tier: SubnetTierPublic}, {Tier: SubnetTierPrivate}},
+		})
+	}
+	return spec
+}
+
+// PrivateLinkTopology returns a topology with a private subnet in each zone and neither internet
+// gateway nor NAT gateway. The subnets reach the AWS services a cluster needs through endpoints.
+func PrivateLinkTopology(name string, zones ...string) *TopologySpec {
+	spec := &TopologySpec{
+		Name: name,
+		NAT:  NATNone,
+		Endpoints: []EndpointSpec{
+			{Service: "s3", Type: types.VpcEndpointTypeGateway},
+			{Service: "sts", Type: types.VpcEndpointTypeInterface},
+			{Service: "ec2", Type: types.VpcEndpointTypeInterface},
+			{Service: "elasticloadbalancing", Type: types.VpcEndpointTypeInterface},
+		},
+	}
+	for _, zone := range zones {
+		spec.Zones = append(spec.Zones, ZoneSpec{
+			Name:    zone,
+			Subnets: []SubnetSpec{{Tier: SubnetTierPrivate}},
+		})
+	}
+	return spec
+}
+
+// LoadTopologySpec parses and validates a topology written in YAML or JSON. Unknown fields are
+// rejected so that typos don't silently fall back to defaults.
+func LoadTopologySpec(data []byte) (*TopologySpec, error) {
+	spec := &TopologySpec{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(spec); err != nil {
+			return nil, fmt.Errorf("parse topology: %w", err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(spec); err != nil {
+			return nil, fmt.Errorf("parse topology: %w", err)
+		}
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// LoadTopologySpecFile is LoadTopologySpec reading the topology from a file.
+func LoadTopologySpecFile(path string) (*TopologySpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadTopologySpec(data)
+}
+
+// Validate checks the topology is consistent: subnets fit in the VPC CIDR without overlapping
+// each other, names are unique and the NAT mode has the public subnets it needs.
+func (spec *TopologySpec) Validate() error {
+	_, err := spec.normalize()
+	return err
+}
+
+// normalize returns a validated copy of the topology with the defaults filled in.
+func (spec *TopologySpec) normalize() (*TopologySpec, error) {
+	if spec.Name == "" {
+		return nil, errors.New("topology has no name")
+	}
+	normalized := *spec
+	if normalized.CIDR == "" {
+		normalized.CIDR = CON.DefaultVPCCIDR
+	}
+	_, vpcNet, err := net.ParseCIDR(normalized.CIDR)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vpc cidr %s: %s", normalized.CIDR, err)
+	}
+	if vpcNet.String() != normalized.CIDR {
+		return nil, fmt.Errorf("vpc cidr %s is not a network address, use %s", normalized.CIDR, vpcNet)
+	}
+	vpcPrefix, _ := vpcNet.Mask.Size()
+	if normalized.DNSHostnames == nil {
+		normalized.DNSHostnames = aws.Bool(true)
+	}
+	if normalized.DNSSupport == nil {
+		normalized.DNSSupport = aws.Bool(true)
+	}
+	if len(spec.Zones) == 0 {
+		return nil, fmt.Errorf("topology %s has no zones", spec.Name)
+	}
+
+	zoneNames := map[string]bool{}
+	subnetNames := map[string]bool{}
+	tiers := map[SubnetTier]bool{}
+	var explicit []*net.IPNet
+	normalized.Zones = make([]ZoneSpec, len(spec.Zones))
+	for i, zone := range spec.Zones {
+		if zone.Name == "" {
+			return nil, fmt.Errorf("zone %d of topology %s has no name", i, spec.Name)
+		}
+		if zoneNames[zone.Name] {
+			return nil, fmt.Errorf("zone %s is listed twice", zone.Name)
+		}
+		zoneNames[zone.Name] = true
+		normalized.Zones[i] = ZoneSpec{Name: zone.Name, Subnets: make([]SubnetSpec, len(zone.Subnets))}
+		count := map[SubnetTier]int{}
+		for j, subnet := range zone.Subnets {
+			switch subnet.Tier {
+			case SubnetTierPublic, SubnetTierPrivate, SubnetTierIsolated:
+			default:
+				return nil, fmt.Errorf("subnet %d of zone %s has invalid tier '%s'", j, zone.Name, subnet.Tier)
+			}
+			tiers[subnet.Tier] = true
+			count[subnet.Tier]++
+			if subnet.Name == "" {
+				subnet.Name = strings.Join([]string{spec.Name, string(subnet.Tier), zone.Name}, "-")
+				if count[subnet.Tier] > 1 {
+					subnet.Name = fmt.Sprintf("%s-%d", subnet.Name, count[subnet.Tier])
+				}
+			}
+			if subnetNames[subnet.Name] {
+				return nil, fmt.Errorf("subnet name %s is used twice", subnet.Name)
+			}
+			subnetNames[subnet.Name] = true
+			if subnet.CIDR != "" {
+				_, subnetNet, err := net.ParseCIDR(subnet.CIDR)
+				if err != nil {
+					return nil, fmt.Errorf("invalid cidr %s of subnet %s: %s", subnet.CIDR, subnet.Name, err)
+				}
+				if subnetNet.String() != subnet.CIDR {
+					return nil, fmt.Errorf("cidr %s of subnet %s is not a network address, use %s",
+						subnet.CIDR, subnet.Name, subnetNet)
+				}
+				prefix, _ := subnetNet.Mask.Size()
+				if prefix < vpcPrefix || !vpcNet.Contains(subnetNet.IP) {
+					return nil, fmt.Errorf("cidr %s of subnet %s is outside of the vpc cidr %s",
+						subnet.CIDR, subnet.Name, normalized.CIDR)
+				}
+				if subnet.Prefix != 0 && subnet.Prefix != prefix {
+					return nil, fmt.Errorf("subnet %s has prefix /%d but cidr %s", subnet.Name, subnet.Prefix, subnet.CIDR)
+				}
+				for _, other := range explicit {
+					if intersect(other, subnetNet) {
+						return nil, fmt.Errorf("cidr %s of subnet %s overlaps %s", subnet.CIDR, subnet.Name, other)
+					}
+				}
+				explicit = append(explicit, subnetNet)
+				subnet.Prefix = prefix
+			}
+			if subnet.Prefix == 0 {
+				subnet.Prefix = CON.DefaultCIDRPrefix
+			}
+			if subnet.Prefix < vpcPrefix || subnet.Prefix > MaxSubnetPrefix {
+				return nil, fmt.Errorf("prefix /%d of subnet %s must be between /%d and /%d",
+					subnet.Prefix, subnet.Name, vpcPrefix, MaxSubnetPrefix)
+			}
+			normalized.Zones[i].Subnets[j] = subnet
+		}
+	}
+
+	if normalized.NAT == "" {
+		normalized.NAT = NATNone
+		if tiers[SubnetTierPublic] && tiers[SubnetTierPrivate] {
+			normalized.NAT = NATSingle
+		}
+	}
+	switch normalized.NAT {
+	case NATNone:
+	case NATSingle:
+		if !tiers[SubnetTierPublic] {
+			return nil, fmt.Errorf("nat mode %s needs a public subnet", normalized.NAT)
+		}
+	case NATPerZone:
+		for _, zone := range normalized.Zones {
+			if zone.has(SubnetTierPrivate) && !zone.has(SubnetTierPublic) {
+				return nil, fmt.Errorf("nat mode %s needs a public subnet in zone %s", normalized.NAT, zone.Name)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid nat mode '%s'", normalized.NAT)
+	}
+
+	services := map[string]bool{}
+	normalized.Endpoints = make([]EndpointSpec, len(spec.Endpoints))
+	for i, endpoint := range spec.Endpoints {
+		if endpoint.Service == "" {
+			return nil, fmt.Errorf("endpoint %d of topology %s has no service", i, spec.Name)
+		}
+		if services[endpoint.Service] {
+			return nil, fmt.Errorf("endpoint of service %s is listed twice", endpoint.Service)
+		}
+		services[endpoint.Service] = true
+		switch {
+		case endpoint.Type == "" || strings.EqualFold(string(endpoint.Type), string(types.VpcEndpointTypeGateway)):
+			endpoint.Type = types.VpcEndpointTypeGateway
+		case strings.EqualFold(string(endpoint.Type), string(types.VpcEndpointTypeInterface)):
+			endpoint.Type = types.VpcEndpointTypeInterface
+		default:
+			return nil, fmt.Errorf("endpoint of service %s has unsupported type '%s'", endpoint.Service, endpoint.Type)
+		}
+		if len(endpoint.Tiers) == 0 {
+			endpoint.Tiers = []SubnetTier{SubnetTierPrivate, SubnetTierIsolated}
+		}
+		for _, tier := range endpoint.Tiers {
+			switch tier {
+			case SubnetTierPublic, SubnetTierPrivate, SubnetTierIsolated:
+			default:
+				return nil, fmt.Errorf("endpoint of service %s has invalid tier '%s'", endpoint.Service, tier)
+			}
+		}
+		endpoint.Tiers = append([]SubnetTier{}, endpoint.Tiers...)
+		normalized.Endpoints[i] = endpoint
+	}
+	return &normalized, nil
+}
+
+func (zone ZoneSpec) has(tier SubnetTier) bool {
+	for _, subnet := range zone.Subnets {
+		if subnet.Tier == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// TopologyDrift is a difference between a topology and AWS.
+type TopologyDrift struct {
+	// Resource is the kind and the name or ID of the resource, e.g. subnet/my-vpc-public-us-east-2a.
+	Resource string `json:"resource"`
+	// Field is what differs, e.g. exists, cidr, tag:Name or default-route.
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	// Fixed tells whether the reconcile converged the difference. Unexpected resources and
+	// immutable fields, like CIDRs, are only reported.
+	Fixed bool `json:"fixed"`
+}
+
+func (drift TopologyDrift) String() string {
+	status := "unresolved"
+	if drift.Fixed {
+		status = "fixed"
+	}
+	return fmt.Sprintf("%s %s: expected %s, got %s (%s)", drift.Resource, drift.Field, drift.Expected, drift.Actual, status)
+}
+
+// TopologyReport lists the differences found between a topology and AWS.
+type TopologyReport struct {
+	VpcID  string          `json:"vpcId"`
+	Drifts []TopologyDrift `json:"drifts,omitempty"`
+}
+
+// InSync tells whether AWS matched the topology before the reconcile.
+func (report *TopologyReport) InSync() bool {
+	return len(report.Drifts) == 0
+}
+
+// Unresolved returns the differences left after the reconcile.
+func (report *TopologyReport) Unresolved() []TopologyDrift {
+	var unresolved []TopologyDrift
+	for _, drift := range report.Drifts {
+		if !drift.Fixed {
+			unresolved = append(unresolved, drift)
+		}
+	}
+	return unresolved
+}
+
+func (report *TopologyReport) String() string {
+	if report.InSync() {
+		return fmt.Sprintf("vpc %s is in sync", report.VpcID)
+	}
+	lines := []string{fmt.Sprintf("vpc %s has %d drifts:", report.VpcID, len(report.Drifts))}
+	for _, drift := range report.Drifts {
+		lines = append(lines, "  "+drift.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ReconcileTopology converges AWS toward the topology and reports the differences it found. The
+// VPC is looked up by its ID when set, by the topology name otherwise, and created when missing.
+// Missing resources are created and mutable attributes, like tags, DNS attributes and default
+// routes, are fixed. Resources the topology doesn't describe are reported but never deleted.
+// Reconciling twice in a row gives an in sync report.
+// On success, the VPC is loaded with its ID, CIDR pool and subnets.
+func (vpc *VPC) ReconcileTopology(spec *TopologySpec) (*TopologyReport, error) {
+	return vpc.reconcileTopology(spec, true)
+}
+
+// DetectTopologyDrift reports how AWS differs from the topology without changing anything.
+func (vpc *VPC) DetectTopologyDrift(spec *TopologySpec) (*TopologyReport, error) {
+	return vpc.reconcileTopology(spec, false)
+}
+
+type topologyReconciler struct {
+	vpc    *VPC
+	spec   *TopologySpec
+	fix    bool
+	region string
+	report *TopologyReport
+	// subnets holds the subnets by name, routeTables the route table IDs by subnet name and
+	// natGateways the NAT gateway IDs by zone. Resources missing in detection are absent.
+	subnets         map[string]*types.Subnet
+	routeTables     map[string]string
+	natGateways     map[string]string
+	internetGateway string
+}
+
+func (vpc *VPC) reconcileTopology(spec *TopologySpec, fix bool) (*TopologyReport, error) {
+	normalized, err := spec.normalize()
+	if err != nil {
+		return nil, err
+	}
+	r := &topologyReconciler{
+		vpc:         vpc,
+		spec:        normalized,
+		fix:         fix,
+		region:      vpc.Region,
+		report:      &TopologyReport{},
+		subnets:     map[string]*types.Subnet{},
+		routeTables: map[string]string{},
+		natGateways: map[string]string{},
+	}
+	if r.region == "" {
+		r.region = vpc.AWSClient.Region
+	}
+	found, err := r.reconcileVPC()
+	if err != nil || !found {
+		return r.report, err
+	}
+	steps := []func() error{
+		r.reconcileDNS,
+		r.reconcileSubnets,
+		r.reconcileInternetGateway,
+		r.reconcileNatGateways,
+		r.reconcileRouteTables,
+		r.reconcileEndpoints,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return r.report, err
+		}
+	}
+	if fix {
+		vpc.VpcID = r.report.VpcID
+		vpc.VPCName = normalized.Name
+		vpc.CIDRValue = normalized.CIDR
+		if _, err := vpc.ListSubnets(); err != nil {
+			return r.report, err
+		}
+		if _, err := vpc.LoadCIDRPool(); err != nil {
+			return r.report, err
+		}
+	}
+	log.LogInfo("Topology %s: %s", normalized.Name, r.report)
+	return r.report, nil
+}
+
+// observe records a drift and, when reconciling, fixes it. A nil fix marks a drift that can only
+// be reported.
+func (r *topologyReconciler) observe(resource string, field string, expected string, actual string, fix func() error) error {
+	drift := TopologyDrift{Resource: resource, Field: field, Expected: expected, Actual: actual}
+	if r.fix && fix != nil {
+		if err := fix(); err != nil {
+			r.report.Drifts = append(r.report.Drifts, drift)
+			return fmt.Errorf("fix %s %s: %w", resource, field, err)
+		}
+		drift.Fixed = true
+	}
+	r.report.Drifts = append(r.report.Drifts, drift)
+	return nil
+}
+
+func (r *topologyReconciler) reconcileTags(resource string, resourceID string, tags []types.Tag, desired map[string]string) error {
+	current := map[string]string{}
+	for _, tag := range tags {
+		current[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	keys := make([]string, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := current[key]
+		if ok && value == desired[key] {
+			continue
+		}
+		if !ok {
+			value = "<unset>"
+		}
+		err := r.observe(resource, "tag:"+key, strconv.Quote(desired[key]), value, func() error {
+			_, err := r.vpc.AWSClient.TagResource(resourceID, map[string]string{key: desired[key]})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *topologyReconciler) reconcileVPC() (bool, error) {
+	var current *types.Vpc
+	if r.vpc.VpcID != "" {
+		described, err := r.vpc.AWSClient.DescribeVPC(r.vpc.VpcID)
+		if err != nil {
+			return false, err
+		}
+		current = &described
+	} else {
+		vpcs, err := r.vpc.AWSClient.ListVPCByName(r.spec.Name)
+		if err != nil {
+			return false, err
+		}
+		if len(vpcs) > 1 {
+			return false, fmt.Errorf("found %d vpcs named %s, set the vpc ID to pick one", len(vpcs), r.spec.Name)
+		}
+		if len(vpcs) == 1 {
+			current = &vpcs[0]
+		}
+	}
+	if current == nil {
+		err := r.observe("vpc/"+r.spec.Name, "exists", "present", "missing", func() error {
+			output, err := r.vpc.AWSClient.CreateVpc(r.spec.CIDR, r.spec.Name)
+			if err != nil {
+				return err
+			}
+			created, err := r.vpc.AWSClient.DescribeVPC(aws.ToString(output.Vpc.VpcId))
+			if err != nil {
+				return err
+			}
+			current = &created
+			return nil
+		})
+		if err != nil || current == nil {
+			return false, err
+		}
+	}
+	vpcID := aws.ToString(current.VpcId)
+	r.report.VpcID = vpcID
+	resource := "vpc/" + vpcID
+	if cidr := aws.ToString(current.CidrBlock); cidr != r.spec.CIDR {
+		if err := r.observe(resource, "cidr", r.spec.CIDR, cidr, nil); err != nil {
+			return false, err
+		}
+		return false, fmt.Errorf("vpc %s has cidr %s instead of %s, which can't be changed", vpcID, cidr, r.spec.CIDR)
+	}
+	desired := map[string]string{"Name": r.spec.Name}
+	for key, value := range r.spec.Tags {
+		desired[key] = value
+	}
+	return true, r.reconcileTags(resource, vpcID, current.Tags, desired)
+}
+
+func (r *topologyReconciler) reconcileDNS() error {
+	attributes := []struct {
+		name    string
+		field   string
+		enabled bool
+	}{
+		{CON.VpcDnsHostnamesAttribute, "dns-hostnames", *r.spec.DNSHostnames},
+		{CON.VpcDnsSupportAttribute, "dns-support", *r.spec.DNSSupport},
+	}
+	for _, attribute := range attributes {
+		enabled, err := r.vpc.AWSClient.DescribeVpcDnsAttribute(r.report.VpcID, attribute.name)
+		if err != nil {
+			return err
+		}
+		if enabled == attribute.enabled {
+			continue
+		}
+		err = r.observe("vpc/"+r.report.VpcID, attribute.field, strconv.FormatBool(attribute.enabled), strconv.FormatBool(enabled),
+			func() error {
+				_, err := r.vpc.AWSClient.ModifyVpcDnsAttribute(r.report.VpcID, attribute.name, attribute.enabled)
+				return err
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subnetTags returns the tags of a subnet, following the tags of CreatePublicSubnet and
+// CreatePrivateSubnet.
+func (r *topologyReconciler) subnetTags(subnet SubnetSpec) map[string]string {
+	tags := map[string]string{"Name": subnet.Name}
+	switch subnet.Tier {
+	case SubnetTierPublic:
+		tags[CON.PublicSubNetTagKey] = CON.PublicSubNetTagValue
+		tags[CON.PublicLBTag] = CON.LBTagValue
+	case SubnetTierPrivate:
+		tags[CON.PrivateLBTag] = CON.LBTagValue
+	}
+	for key, value := range r.spec.Tags {
+		tags[key] = value
+	}
+	for key, value := range subnet.Tags {
+		tags[key] = value
+	}
+	return tags
+}
+
+func (r *topologyReconciler) reconcileSubnets() error {
+	vpcID := r.report.VpcID
+	existing, err := r.vpc.AWSClient.ListSubnetByVpcID(vpcID)
+	if err != nil {
+		return err
+	}
+	byName := map[string]types.Subnet{}
+	var reserved []string
+	for _, subnet := range existing {
+		byName[getTagName(subnet.Tags)] = subnet
+		reserved = append(reserved, aws.ToString(subnet.CidrBlock))
+	}
+	for _, zone := range r.spec.Zones {
+		for _, subnet := range zone.Subnets {
+			if subnet.CIDR != "" {
+				reserved = append(reserved, subnet.CIDR)
+			}
+		}
+	}
+	pool := NewCIDRPool(r.spec.CIDR)
+	if err := pool.Reserve(reserved...); err != nil {
+		return err
+	}
+	allocate := func(prefix int) (string, error) {
+		allocated, err := pool.AllocatePrefix(prefix)
+		if err != nil {
+			return "", err
+		}
+		return allocated.CIDR, nil
+	}
+
+	expected := map[string]bool{}
+	for _, zone := range r.spec.Zones {
+		for _, spec := range zone.Subnets {
+			expected[spec.Name] = true
+			resource := "subnet/" + spec.Name
+			tags := r.subnetTags(spec)
+			current, ok := byName[spec.Name]
+			if !ok {
+				err := r.observe(resource, "exists", "present", "missing", func() error {
+					cidr := spec.CIDR
+					if cidr == "" {
+						if cidr, err = allocate(spec.Prefix); err != nil {
+							return err
+						}
+					}
+					created, err := r.vpc.AWSClient.CreateSubnet(vpcID, zone.Name, cidr)
+					if err != nil {
+						return err
+					}
+					if _, err = r.vpc.AWSClient.TagResource(aws.ToString(created.SubnetId), tags); err != nil {
+						return err
+					}
+					r.subnets[spec.Name] = created
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			r.subnets[spec.Name] = &current
+			subnetID := aws.ToString(current.SubnetId)
+			if zoneName := aws.ToString(current.AvailabilityZone); zoneName != zone.Name {
+				if err := r.observe(resource, "zone", zone.Name, zoneName, nil); err != nil {
+					return err
+				}
+			}
+			cidr := aws.ToString(current.CidrBlock)
+			_, subnetNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return fmt.Errorf("subnet %s has invalid cidr %s: %s", subnetID, cidr, err)
+			}
+			prefix, _ := subnetNet.Mask.Size()
+			switch {
+			case spec.CIDR != "" && spec.CIDR != cidr:
+				err = r.observe(resource, "cidr", spec.CIDR, cidr, nil)
+			case prefix != spec.Prefix:
+				err = r.observe(resource, "prefix", fmt.Sprintf("/%d", spec.Prefix), cidr, nil)
+			}
+			if err != nil {
+				return err
+			}
+			if err := r.reconcileTags(resource, subnetID, current.Tags, tags); err != nil {
+				return err
+			}
+		}
+	}
+
+	var unexpected []string
+	for name := range byName {
+		if !expected[name] {
+			unexpected = append(unexpected, name)
+		}
+	}
+	sort.Strings(unexpected)
+	for _, name := range unexpected {
+		subnetID := aws.ToString(byName[name].SubnetId)
+		if err := r.observe("subnet/"+subnetID, "exists", "absent", name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasTier tells whether a zone of the topology has a subnet of the tier.
+func (r *topologyReconciler) hasTier(tier SubnetTier) bool {
+	for _, zone := range r.spec.Zones {
+		if zone.has(tier) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *topologyReconciler) reconcileInternetGateway() error {
+	vpcID := r.report.VpcID
+	needed := r.hasTier(SubnetTierPublic)
+	gateways, err := r.vpc.AWSClient.ListInternetGateWay(vpcID)
+	if err != nil {
+		return err
+	}
+	if len(gateways) != 0 {
+		r.internetGateway = aws.ToString(gateways[0].InternetGatewayId)
+		if !needed {
+			return r.observe("internet-gateway/"+r.internetGateway, "exists", "absent", r.internetGateway, nil)
+		}
+		return nil
+	}
+	if !needed {
+		return nil
+	}
+	return r.observe("internet-gateway", "exists", "present", "missing", func() error {
+		gateway, err := r.vpc.AWSClient.CreateInternetGateway()
+		if err != nil {
+			return err
+		}
+		gatewayID := aws.ToString(gateway.InternetGateway.InternetGatewayId)
+		if _, err = r.vpc.AWSClient.AttachInternetGateway(gatewayID, vpcID); err != nil {
+			return err
+		}
+		r.internetGateway = gatewayID
+		return nil
+	})
+}
+
+// createNatGateway creates a NAT gateway with a new elastic IP in the subnet.
+func (r *topologyReconciler) createNatGateway(subnetName string) (string, error) {
+	subnet := r.subnets[subnetName]
+	if subnet == nil {
+		return "", fmt.Errorf("subnet %s of the nat gateway is missing", subnetName)
+	}
+	allocation, err := r.vpc.AWSClient.AllocateEIPAddress()
+	if err != nil {
+		return "", fmt.Errorf("error happened when allocate EIP Address for NAT gateway: %s", err)
+	}
+	output, err := r.vpc.AWSClient.CreateNatGateway(aws.ToString(subnet.SubnetId), aws.ToString(allocation.AllocationId),
+		r.report.VpcID)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.NatGateway.NatGatewayId), nil
+}
+
+func (r *topologyReconciler) reconcileNatGateways() error {
+	gateways, err := r.vpc.AWSClient.ListNatGateways(r.report.VpcID)
+	if err != nil {
+		return err
+	}
+	live := map[string]string{}
+	var liveIDs []string
+	for _, gateway := range gateways {
+		if gateway.State == types.NatGatewayStatePending || gateway.State == types.NatGatewayStateAvailable {
+			live[aws.ToString(gateway.SubnetId)] = aws.ToString(gateway.NatGatewayId)
+			liveIDs = append(liveIDs, aws.ToString(gateway.NatGatewayId))
+		}
+	}
+	// find returns the live NAT gateway of the first public subnet of the zones which has one.
+	find := func(zones ...ZoneSpec) string {
+		for _, zone := range zones {
+			for _, spec := range zone.Subnets {
+				if subnet := r.subnets[spec.Name]; spec.Tier == SubnetTierPublic && subnet != nil {
+					if gatewayID, ok := live[aws.ToString(subnet.SubnetId)]; ok {
+						return gatewayID
+					}
+				}
+			}
+		}
+		return ""
+	}
+	// prepare returns the NAT gateway found in the zones, or creates one in their first public subnet.
+	prepare := func(zones ...ZoneSpec) (string, error) {
+		gatewayID := find(zones...)
+		if gatewayID != "" {
+			return gatewayID, nil
+		}
+		var subnetName string
+		for _, zone := range zones {
+			for _, spec := range zone.Subnets {
+				if spec.Tier == SubnetTierPublic && subnetName == "" {
+					subnetName = spec.Name
+				}
+			}
+		}
+		err := r.observe("nat-gateway/"+subnetName, "exists", "present", "missing", func() error {
+			gatewayID, err = r.createNatGateway(subnetName)
+			return err
+		})
+		return gatewayID, err
+	}
+
+	used := map[string]bool{}
+	switch r.spec.NAT {
+	case NATSingle:
+		gatewayID, err := prepare(r.spec.Zones...)
+		if err != nil {
+			return err
+		}
+		for _, zone := range r.spec.Zones {
+			r.natGateways[zone.Name] = gatewayID
+		}
+		used[gatewayID] = true
+	case NATPerZone:
+		for _, zone := range r.spec.Zones {
+			if !zone.has(SubnetTierPrivate) {
+				continue
+			}
+			gatewayID, err := prepare(zone)
+			if err != nil {
+				return err
+			}
+			r.natGateways[zone.Name] = gatewayID
+			used[gatewayID] = true
+		}
+	}
+	for _, gatewayID := range liveIDs {
+		if !used[gatewayID] {
+			if err := r.observe("nat-gateway/"+gatewayID, "exists", "absent", gatewayID, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// defaultRoute returns the target of the default route of a route table, empty without one.
+func defaultRoute(routeTable types.RouteTable) string {
+	for _, route := range routeTable.Routes {
+		if aws.ToString(route.DestinationCidrBlock) != CON.RouteDestinationCidrBlock {
+			continue
+		}
+		for _, target := range []*string{route.GatewayId, route.NatGatewayId, route.TransitGatewayId,
+			route.VpcPeeringConnectionId, route.NetworkInterfaceId, route.InstanceId, route.EgressOnlyInternetGatewayId} {
+			if aws.ToString(target) != "" {
+				return aws.ToString(target)
+			}
+		}
+	}
+	return ""
+}
+
+// expectedRoute returns the target of the default route of a subnet, and the kind of gateway to
+// create first when the target is missing.
+func (r *topologyReconciler) expectedRoute(zone string, tier SubnetTier) (target string, missing string) {
+	switch {
+	case tier == SubnetTierPublic:
+		if r.internetGateway == "" {
+			return "", "internet-gateway"
+		}
+		return r.internetGateway, ""
+	case tier == SubnetTierPrivate && r.spec.NAT != NATNone:
+		if r.natGateways[zone] == "" {
+			return "", "nat-gateway"
+		}
+		return r.natGateways[zone], ""
+	}
+	return "", ""
+}
+
+func (r *topologyReconciler) reconcileRouteTables() error {
+	vpcID := r.report.VpcID
+	routeTables, err := r.vpc.AWSClient.ListCustomerRouteTables(vpcID)
+	if err != nil {
+		return err
+	}
+	bySubnet := map[string]types.RouteTable{}
+	for _, routeTable := range routeTables {
+		for _, association := range routeTable.Associations {
+			if association.SubnetId != nil {
+				bySubnet[*association.SubnetId] = routeTable
+			}
+		}
+	}
+	none := func(target string) string {
+		if target == "" {
+			return "none"
+		}
+		return target
+	}
+	for _, zone := range r.spec.Zones {
+		for _, spec := range zone.Subnets {
+			subnet := r.subnets[spec.Name]
+			if subnet == nil {
+				continue
+			}
+			subnetID := aws.ToString(subnet.SubnetId)
+			resource := "route-table/" + spec.Name
+			routeTable, ok := bySubnet[subnetID]
+			if !ok {
+				err := r.observe(resource, "exists", "present", "missing", func() error {
+					output, err := r.vpc.AWSClient.CreateRouteTable(vpcID)
+					if err != nil {
+						return err
+					}
+					_, err = r.vpc.AWSClient.AssociateRouteTable(aws.ToString(output.RouteTable.RouteTableId), subnetID, vpcID)
+					if err != nil {
+						return err
+					}
+					routeTable, ok = *output.RouteTable, true
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+			}
+			routeTableID := aws.ToString(routeTable.RouteTableId)
+			r.routeTables[spec.Name] = routeTableID
+
+			current := defaultRoute(routeTable)
+			target, missing := r.expectedRoute(zone.Name, spec.Tier)
+			if missing != "" {
+				err = r.observe(resource, "default-route", missing, none(current), nil)
+			} else if current != target {
+				err = r.observe(resource, "default-route", none(target), none(current), func() error {
+					if current != "" {
+						if err := r.vpc.AWSClient.DeleteRoute(routeTableID, CON.RouteDestinationCidrBlock); err != nil {
+							return err
+						}
+					}
+					if target != "" {
+						_, err := r.vpc.AWSClient.CreateRoute(routeTableID, target)
+						return err
+					}
+					return nil
+				})
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// endpointTargets returns the route tables of the subnets of the endpoint tiers for a gateway
+// endpoint, the first subnet of the tiers in each zone for an interface endpoint.
+func (r *topologyReconciler) endpointTargets(endpoint EndpointSpec) []string {
+	var targets []string
+	for _, zone := range r.spec.Zones {
+		for _, spec := range zone.Subnets {
+			if !containsTier(endpoint.Tiers, spec.Tier) {
+				continue
+			}
+			if endpoint.Type == types.VpcEndpointTypeGateway {
+				if routeTableID := r.routeTables[spec.Name]; routeTableID != "" {
+					targets = append(targets, routeTableID)
+				}
+			} else if subnet := r.subnets[spec.Name]; subnet != nil {
+				targets = append(targets, aws.ToString(subnet.SubnetId))
+				break
+			}
+		}
+	}
+	return targets
+}
+
+func containsTier(tiers []SubnetTier, tier SubnetTier) bool {
+	for _, candidate := range tiers {
+		if candidate == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointServiceName expands the short name of an AWS service to the endpoint service name of
+// the region.
+func endpointServiceName(service string, region string) (string, error) {
+	if strings.Contains(service, ".") {
+		return service, nil
+	}
+	if region == "" {
+		return "", fmt.Errorf("the region is needed to expand the endpoint service %s", service)
+	}
+	return fmt.Sprintf("com.amazonaws.%s.%s", region, service), nil
+}
+
+func (r *topologyReconciler) reconcileEndpoints() error {
+	vpcID := r.report.VpcID
+	endpoints, err := r.vpc.AWSClient.ListEndpointAssociation(vpcID)
+	if err != nil {
+		return err
+	}
+	byService := map[string]types.VpcEndpoint{}
+	for _, endpoint := range endpoints {
+		switch endpoint.State {
+		case types.StateDeleted, types.StateDeleting, types.StateFailed, types.StateRejected, types.StateExpired:
+			continue
+		}
+		byService[aws.ToString(endpoint.ServiceName)] = endpoint
+	}
+	expected := map[string]bool{}
+	for _, spec := range r.spec.Endpoints {
+		service, err := endpointServiceName(spec.Service, r.region)
+		if err != nil {
+			return err
+		}
+		expected[service] = true
+		resource := "vpc-endpoint/" + service
+		targets := r.endpointTargets(spec)
+		current, ok := byService[service]
+		if !ok {
+			err := r.observe(resource, "exists", "present", "missing", func() error {
+				input := &ec2.CreateVpcEndpointInput{
+					VpcId:           aws.String(vpcID),
+					ServiceName:     aws.String(service),
+					VpcEndpointType: spec.Type,
+				}
+				if spec.Type == types.VpcEndpointTypeGateway {
+					input.RouteTableIds = targets
+				} else {
+					input.SubnetIds = targets
+				}
+				output, err := r.vpc.AWSClient.Ec2Client.CreateVpcEndpoint(r.vpc.requestContext(), input)
+				if err != nil {
+					return err
+				}
+				log.LogInfo("Create vpc endpoint %s for service %s", aws.ToString(output.VpcEndpoint.VpcEndpointId), service)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if current.VpcEndpointType != spec.Type {
+			if err := r.observe(resource, "type", string(spec.Type), string(current.VpcEndpointType), nil); err != nil {
+				return err
+			}
+			continue
+		}
+		field, attached := "route-tables", current.RouteTableIds
+		if spec.Type == types.VpcEndpointTypeInterface {
+			field, attached = "subnets", current.SubnetIds
+		}
+		var missing []string
+		for _, target := range targets {
+			if !containsString(attached, target) {
+				missing = append(missing, target)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		endpointID := aws.ToString(current.VpcEndpointId)
+		err = r.observe(resource, field, strings.Join(targets, ","), strings.Join(attached, ","), func() error {
+			input := &ec2.ModifyVpcEndpointInput{VpcEndpointId: aws.String(endpointID)}
+			if spec.Type == types.VpcEndpointTypeGateway {
+				input.AddRouteTableIds = missing
+			} else {
+				input.AddSubnetIds = missing
+			}
+			if _, err := r.vpc.AWSClient.Ec2Client.ModifyVpcEndpoint(r.vpc.requestContext(), input); err != nil {
+				return err
+			}
+			log.LogInfo("Add %s %s to vpc endpoint %s", field, strings.Join(missing, ","), endpointID)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var unexpected []string
+	for service := range byService {
+		if !expected[service] {
+			unexpected = append(unexpected, service)
+		}
+	}
+	sort.Strings(unexpected)
+	for _, service := range unexpected {
+		endpointID := aws.ToString(byService[service].VpcEndpointId)
+		if err := r.observe("vpc-endpoint/"+endpointID, "exists", "absent", service, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
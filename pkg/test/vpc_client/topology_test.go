@@ -0,0 +1,313 @@
+package vpc_client_test
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+	. "github.com/openshift-online/ocm-common/pkg/test/vpc_client"
+)
+
+const singleNATTopology = `
+name: single-nat
+tags:
+  team: qe
+zones:
+  - name: us-east-2a
+    subnets:
+      - tier: public
+      - tier: private
+  - name: us-east-2b
+    subnets:
+      - tier: public
+      - tier: private
+`
+
+const privateLinkTopology = `{
+  "name": "private-link",
+  "nat": "none",
+  "zones": [
+    {"name": "us-east-2a", "subnets": [{"tier": "private"}]},
+    {"name": "us-east-2b", "subnets": [{"tier": "private"}]}
+  ],
+  "endpoints": [
+    {"service": "s3", "type": "gateway"},
+    {"service": "sts", "type": "Interface"}
+  ]
+}`
+
+const byoCIDRTopology = `
+name: byo-cidr
+cidr: 192.168.0.0/20
+nat: per-zone
+zones:
+  - name: us-east-2a
+    subnets:
+      - tier: public
+        cidr: 192.168.0.0/24
+      - tier: private
+        prefix: 22
+      - tier: isolated
+        prefix: 26
+        name: database
+        tags:
+          role: db
+`
+
+var _ = Describe("Topology", func() {
+	var (
+		ctx    context.Context
+		fake   *aws_fake.EC2
+		client *aws_client.AWSClient
+		vpc    *VPC
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = aws_fake.NewEC2(aws_fake.WithRegion("us-east-2"))
+		client = &aws_client.AWSClient{Ec2Client: fake}
+		vpc = NewVPC().AWSclient(client).SetRegion(fake.Region())
+	})
+
+	routeTableOf := func(subnetID string) types.RouteTable {
+		output, err := fake.DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
+			Filters: []types.Filter{{Name: aws.String("association.subnet-id"), Values: []string{subnetID}}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output.RouteTables).To(HaveLen(1))
+		return output.RouteTables[0]
+	}
+	defaultRoute := func(subnetID string) *types.Route {
+		for _, route := range routeTableOf(subnetID).Routes {
+			if aws.ToString(route.DestinationCidrBlock) == CON.RouteDestinationCidrBlock {
+				return &route
+			}
+		}
+		return nil
+	}
+	natGateways := func() []types.NatGateway {
+		output, err := fake.DescribeNatGateways(ctx, &ec2.DescribeNatGatewaysInput{})
+		Expect(err).ToNot(HaveOccurred())
+		return output.NatGateways
+	}
+	expectInSync := func(spec *TopologySpec) {
+		report, err := NewVPC().AWSclient(client).SetRegion(fake.Region()).DetectTopologyDrift(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.InSync()).To(BeTrue(), report.String())
+		report, err = NewVPC().AWSclient(client).SetRegion(fake.Region()).ReconcileTopology(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.InSync()).To(BeTrue(), report.String())
+	}
+
+	It("should build a single NAT layout", func() {
+		spec, err := LoadTopologySpec([]byte(singleNATTopology))
+		Expect(err).ToNot(HaveOccurred())
+		report, err := vpc.ReconcileTopology(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.InSync()).To(BeFalse())
+		Expect(report.Unresolved()).To(BeEmpty())
+		Expect(report.VpcID).To(Equal(vpc.VpcID))
+		Expect(vpc.CIDRValue).To(Equal(CON.DefaultVPCCIDR))
+
+		Expect(vpc.SubnetList).To(HaveLen(4))
+		Expect(vpc.AllPublicSubnetIDs()).To(HaveLen(2))
+		Expect(natGateways()).To(HaveLen(1))
+		natID := aws.ToString(natGateways()[0].NatGatewayId)
+		for _, subnet := range vpc.SubnetList {
+			Expect(subnet.Name).To(HavePrefix("single-nat-"))
+			route := defaultRoute(subnet.ID)
+			Expect(route).ToNot(BeNil())
+			if subnet.Private {
+				Expect(aws.ToString(route.NatGatewayId)).To(Equal(natID))
+			} else {
+				Expect(aws.ToString(route.GatewayId)).To(HavePrefix("igw-"))
+			}
+		}
+		hostnames, err := client.DescribeVpcDnsAttribute(vpc.VpcID, CON.VpcDnsHostnamesAttribute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hostnames).To(BeTrue())
+		tagged, err := client.ListSubnetsByFilter([]types.Filter{{Name: aws.String("tag:team"), Values: []string{"qe"}}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tagged).To(HaveLen(4))
+
+		expectInSync(spec)
+	})
+
+	It("should build a private link layout without NAT", func() {
+		spec, err := LoadTopologySpec([]byte(privateLinkTopology))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = vpc.ReconcileTopology(spec)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(vpc.AllPrivateSubnetIDs()).To(HaveLen(2))
+		Expect(natGateways()).To(BeEmpty())
+		gateways, err := client.ListInternetGateWay(vpc.VpcID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gateways).To(BeEmpty())
+		for _, subnetID := range vpc.AllPrivateSubnetIDs() {
+			Expect(defaultRoute(subnetID)).To(BeNil())
+		}
+
+		endpoints, err := client.ListEndpointAssociation(vpc.VpcID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(endpoints).To(HaveLen(2))
+		for _, endpoint := range endpoints {
+			switch aws.ToString(endpoint.ServiceName) {
+			case "com.amazonaws.us-east-2.s3":
+				Expect(endpoint.VpcEndpointType).To(Equal(types.VpcEndpointTypeGateway))
+				Expect(endpoint.RouteTableIds).To(HaveLen(2))
+			case "com.amazonaws.us-east-2.sts":
+				Expect(endpoint.VpcEndpointType).To(Equal(types.VpcEndpointTypeInterface))
+				Expect(endpoint.SubnetIds).To(ConsistOf(vpc.AllPrivateSubnetIDs()))
+			default:
+				Fail("unexpected endpoint " + aws.ToString(endpoint.ServiceName))
+			}
+		}
+
+		expectInSync(spec)
+	})
+
+	It("should attach the missing route tables and subnets to endpoints", func() {
+		spec, err := LoadTopologySpec([]byte(privateLinkTopology))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = vpc.ReconcileTopology(spec)
+		Expect(err).ToNot(HaveOccurred())
+
+		endpoints, err := client.ListEndpointAssociation(vpc.VpcID)
+		Expect(err).ToNot(HaveOccurred())
+		for _, endpoint := range endpoints {
+			input := &ec2.ModifyVpcEndpointInput{VpcEndpointId: endpoint.VpcEndpointId}
+			if endpoint.VpcEndpointType == types.VpcEndpointTypeGateway {
+				input.RemoveRouteTableIds = endpoint.RouteTableIds[:1]
+			} else {
+				input.RemoveSubnetIds = endpoint.SubnetIds[:1]
+			}
+			_, err = fake.ModifyVpcEndpoint(ctx, input)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		report, err := vpc.ReconcileTopology(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Unresolved()).To(BeEmpty())
+		Expect(report.String()).To(ContainSubstring("vpc-endpoint/com.amazonaws.us-east-2.s3 route-tables"))
+		Expect(report.String()).To(ContainSubstring("vpc-endpoint/com.amazonaws.us-east-2.sts subnets"))
+		endpoints, err = client.ListEndpointAssociation(vpc.VpcID)
+		Expect(err).ToNot(HaveOccurred())
+		for _, endpoint := range endpoints {
+			if endpoint.VpcEndpointType == types.VpcEndpointTypeGateway {
+				Expect(endpoint.RouteTableIds).To(HaveLen(2))
+			} else {
+				Expect(endpoint.SubnetIds).To(ConsistOf(vpc.AllPrivateSubnetIDs()))
+				Expect(endpoint.NetworkInterfaceIds).To(HaveLen(2))
+			}
+		}
+
+		expectInSync(spec)
+	})
+
+	It("should build a layout in a custom CIDR", func() {
+		spec, err := LoadTopologySpec([]byte(byoCIDRTopology))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = vpc.ReconcileTopology(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpc.CIDRValue).To(Equal("192.168.0.0/20"))
+
+		cidrs := map[string]string{}
+		for _, subnet := range vpc.SubnetList {
+			cidrs[subnet.Name] = subnet.Cidr
+		}
+		Expect(cidrs).To(HaveKeyWithValue("byo-cidr-public-us-east-2a", "192.168.0.0/24"))
+		Expect(cidrs).To(HaveKeyWithValue("byo-cidr-private-us-east-2a", "192.168.4.0/22"))
+		Expect(cidrs).To(HaveKeyWithValue("database", "192.168.1.0/26"))
+
+		database := vpc.SubnetList[0]
+		for _, subnet := range vpc.SubnetList {
+			if subnet.Name == "database" {
+				database = subnet
+			}
+		}
+		Expect(defaultRoute(database.ID)).To(BeNil())
+		tagged, err := client.ListSubnetsByFilter([]types.Filter{{Name: aws.String("tag:role"), Values: []string{"db"}}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tagged).To(HaveLen(1))
+		Expect(natGateways()).To(HaveLen(1))
+
+		expectInSync(spec)
+	})
+
+	It("should adopt a VPC built by CreateVPCChain", func() {
+		_, err := vpc.Name("chain").CIDR(CON.DefaultVPCCIDR).NewCIDRPool().CreateVPCChain("us-east-2a", "us-east-2b")
+		Expect(err).ToNot(HaveOccurred())
+
+		report, err := NewVPC().AWSclient(client).SetRegion(fake.Region()).
+			DetectTopologyDrift(SingleNATTopology("chain", "us-east-2a", "us-east-2b"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.InSync()).To(BeTrue(), report.String())
+	})
+
+	It("should report and fix drift", func() {
+		spec := SingleNATTopology("drift", "us-east-2a")
+		_, err := vpc.ReconcileTopology(spec)
+		Expect(err).ToNot(HaveOccurred())
+
+		privateID := vpc.AllPrivateSubnetIDs()[0]
+		routeTableID := aws.ToString(routeTableOf(privateID).RouteTableId)
+		Expect(client.DeleteRoute(routeTableID, CON.RouteDestinationCidrBlock)).To(Succeed())
+		_, err = client.ModifyVpcDnsAttribute(vpc.VpcID, CON.VpcDnsHostnamesAttribute, false)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.TagResource(privateID, map[string]string{"Name": "renamed"})
+		Expect(err).ToNot(HaveOccurred())
+
+		report, err := NewVPC().ID(vpc.VpcID).AWSclient(client).SetRegion(fake.Region()).DetectTopologyDrift(spec)
+		Expect(err).ToNot(HaveOccurred())
+		fields := []string{}
+		for _, drift := range report.Drifts {
+			Expect(drift.Fixed).To(BeFalse())
+			fields = append(fields, drift.Resource+" "+drift.Field)
+		}
+		Expect(fields).To(ConsistOf(
+			"vpc/"+vpc.VpcID+" dns-hostnames",
+			"subnet/drift-private-us-east-2a exists",
+			"subnet/"+privateID+" exists",
+		))
+		Expect(defaultRoute(privateID)).To(BeNil())
+
+		_, err = client.TagResource(privateID, map[string]string{"Name": "drift-private-us-east-2a"})
+		Expect(err).ToNot(HaveOccurred())
+		report, err = vpc.ReconcileTopology(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Unresolved()).To(BeEmpty())
+		Expect(report.String()).To(ContainSubstring("route-table/drift-private-us-east-2a default-route"))
+		Expect(defaultRoute(privateID).NatGatewayId).ToNot(BeNil())
+
+		subnet, err := client.CreateSubnet(vpc.VpcID, "us-east-2a", "10.0.200.0/24")
+		Expect(err).ToNot(HaveOccurred())
+		report, err = vpc.ReconcileTopology(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Unresolved()).To(ConsistOf(HaveField("Resource", "subnet/"+aws.ToString(subnet.SubnetId))))
+	})
+
+	It("should reject inconsistent topologies", func() {
+		invalid := map[string]string{
+			"name: x\nzones: []": "has no zones",
+			"name: x\nzones:\n- name: a\n  subnets:\n  - tier: dmz":                                                                     "invalid tier",
+			"name: x\nnat: single\nzones:\n- name: a\n  subnets:\n  - tier: private":                                                    "needs a public subnet",
+			"name: x\ncidr: 10.0.0.0/16\nzones:\n- name: a\n  subnets:\n  - tier: public\n    cidr: 10.1.0.0/24":                        "outside of the vpc cidr",
+			"name: x\nzones:\n- name: a\n  subnets:\n  - tier: public\n    cidr: 10.0.0.0/24\n  - tier: private\n    cidr: 10.0.0.0/25": "overlaps",
+			"name: x\nzones:\n- name: a\n  subnets:\n  - tier: public\n    prefix: 30":                                                  "must be between",
+			"name: x\nzone: []": "field zone not found",
+		}
+		for data, message := range invalid {
+			_, err := LoadTopologySpec([]byte(data))
+			Expect(err).To(HaveOccurred(), data)
+			Expect(err.Error()).To(ContainSubstring(message), data)
+		}
+	})
+})
@@ -0,0 +1,149 @@
+package vpc_client
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/log"
+)
+
+// AttachTransitGateway attaches the VPC to the transit gateway through one subnet per zone,
+// preferring the private subnets, and routes the CIDR blocks to the gateway in the custom route
+// tables of the VPC. It returns the ID of the attachment.
+func (vpc *VPC) AttachTransitGateway(gatewayID string, cidrs ...string) (string, error) {
+	subnets := vpc.SubnetList
+	if len(subnets) == 0 {
+		var err error
+		subnets, err = vpc.ListSubnets()
+		if err != nil {
+			return "", err
+		}
+	}
+	subnetByZone := map[string]*Subnet{}
+	zones := []string{}
+	for _, subnet := range subnets {
+		selected, ok := subnetByZone[subnet.Zone]
+		if !ok {
+			zones = append(zones, subnet.Zone)
+		}
+		if !ok || (subnet.Private && !selected.Private) {
+			subnetByZone[subnet.Zone] = subnet
+		}
+	}
+	if len(zones) == 0 {
+		return "", fmt.Errorf("vpc %s has no subnet to attach transit gateway %s to", vpc.VpcID, gatewayID)
+	}
+	subnetIDs := []string{}
+	for _, zone := range zones {
+		subnetIDs = append(subnetIDs, subnetByZone[zone].ID)
+	}
+	attachment, err := vpc.AWSClient.CreateTransitGatewayVpcAttachment(gatewayID, vpc.VpcID, subnetIDs...)
+	if err != nil {
+		return "", err
+	}
+	attachmentID := aws.ToString(attachment.TransitGatewayAttachmentId)
+	return attachmentID, vpc.RouteCIDRs(gatewayID, cidrs...)
+}
+
+// DetachTransitGateway removes the routes to the transit gateway from the VPC and deletes the
+// attachment of the VPC to the gateway.
+func (vpc *VPC) DetachTransitGateway(gatewayID string) error {
+	err := vpc.DeleteRoutesTo(gatewayID)
+	if err != nil {
+		return err
+	}
+	attachments, err := vpc.AWSClient.ListTransitGatewayVpcAttachments(vpc.VpcID)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		if aws.ToString(attachment.TransitGatewayId) != gatewayID {
+			continue
+		}
+		err = vpc.AWSClient.DeleteTransitGatewayVpcAttachment(aws.ToString(attachment.TransitGatewayAttachmentId))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConnectVPCsByTransitGateway attaches the VPCs to the transit gateway and routes the CIDR blocks
+// of every VPC to the gateway in the others, so that they all reach each other. The gateway must
+// associate and propagate the attachments to its default route table, as the gateways created by
+// AWSClient.CreateTransitGateway do. It returns the IDs of the attachments in the order of the
+// VPCs.
+func ConnectVPCsByTransitGateway(gatewayID string, vpcs ...*VPC) ([]string, error) {
+	cidrs := map[string][]string{}
+	for _, vpc := range vpcs {
+		vpcCIDRs, err := vpc.cidrBlocks()
+		if err != nil {
+			return nil, err
+		}
+		cidrs[vpc.VpcID] = vpcCIDRs
+	}
+	attachmentIDs := []string{}
+	for _, vpc := range vpcs {
+		destinations := []string{}
+		for _, other := range vpcs {
+			if other.VpcID != vpc.VpcID {
+				destinations = append(destinations, cidrs[other.VpcID]...)
+			}
+		}
+		attachmentID, err := vpc.AttachTransitGateway(gatewayID, destinations...)
+		if err != nil {
+			return attachmentIDs, err
+		}
+		attachmentIDs = append(attachmentIDs, attachmentID)
+	}
+	return attachmentIDs, nil
+}
+
+// ConnectHubAndSpokes sets up a hub-and-spoke egress through the transit gateway: the spokes send
+// all their traffic to the gateway, whose default route table forwards the internet traffic to
+// the hub, and the hub routes the CIDR blocks of the spokes back through the gateway. The hub must
+// have its own egress, such as the NAT gateways of CreateVPCChain, and the custom route tables of
+// the spokes must not have a default route yet, like private subnets created without NAT. It
+// returns the IDs of the attachments, hub first.
+func ConnectHubAndSpokes(gatewayID string, hub *VPC, spokes ...*VPC) ([]string, error) {
+	gateway, err := hub.AWSClient.DescribeTransitGateway(gatewayID)
+	if err != nil {
+		return nil, err
+	}
+	routeTableID := ""
+	if gateway.Options != nil {
+		routeTableID = aws.ToString(gateway.Options.AssociationDefaultRouteTableId)
+	}
+	if routeTableID == "" {
+		return nil, fmt.Errorf("transit gateway %s has no default association route table", gatewayID)
+	}
+
+	spokeCIDRs := []string{}
+	for _, spoke := range spokes {
+		cidrs, err := spoke.cidrBlocks()
+		if err != nil {
+			return nil, err
+		}
+		spokeCIDRs = append(spokeCIDRs, cidrs...)
+	}
+	hubAttachmentID, err := hub.AttachTransitGateway(gatewayID, spokeCIDRs...)
+	if err != nil {
+		return nil, err
+	}
+	attachmentIDs := []string{hubAttachmentID}
+	for _, spoke := range spokes {
+		attachmentID, err := spoke.AttachTransitGateway(gatewayID, CON.RouteDestinationCidrBlock)
+		if err != nil {
+			return attachmentIDs, err
+		}
+		attachmentIDs = append(attachmentIDs, attachmentID)
+	}
+	err = hub.AWSClient.CreateTransitGatewayRoute(routeTableID, CON.RouteDestinationCidrBlock, hubAttachmentID)
+	if err != nil {
+		return attachmentIDs, err
+	}
+	log.LogInfo("Connected vpc %s as the egress hub of transit gateway %s", hub.VpcID, gatewayID)
+	return attachmentIDs, nil
+}
@@ -0,0 +1,106 @@
+package vpc_client_test
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+	. "github.com/openshift-online/ocm-common/pkg/test/vpc_client"
+)
+
+var _ = Describe("Transit gateway", func() {
+	var (
+		fake      *aws_fake.EC2
+		client    *aws_client.AWSClient
+		gatewayID string
+	)
+
+	createVPC := func(name string, cidr string, zones ...string) *VPC {
+		vpc, err := NewVPC().
+			AWSclient(client).
+			Name(name).
+			CIDR(cidr).
+			SetRegion(fake.Region()).
+			NewCIDRPool().
+			CreateVPCChain(zones...)
+		Expect(err).ToNot(HaveOccurred())
+		return vpc
+	}
+
+	defaultRouteTableID := func() string {
+		gateway, err := client.DescribeTransitGateway(gatewayID)
+		Expect(err).ToNot(HaveOccurred())
+		return aws.ToString(gateway.Options.AssociationDefaultRouteTableId)
+	}
+
+	BeforeEach(func() {
+		fake = aws_fake.NewEC2(aws_fake.WithRegion("us-east-2"))
+		client = &aws_client.AWSClient{Ec2Client: fake}
+		gateway, err := client.CreateTransitGateway("test-tgw")
+		Expect(err).ToNot(HaveOccurred())
+		gatewayID = aws.ToString(gateway.TransitGatewayId)
+	})
+
+	It("should connect every VPC to the others", func() {
+		first := createVPC("first-vpc", "10.0.0.0/16", fake.Zones()[:2]...)
+		second := createVPC("second-vpc", "10.1.0.0/16", fake.Zones()[0])
+		third := createVPC("third-vpc", "10.2.0.0/16", fake.Zones()[0])
+
+		attachmentIDs, err := ConnectVPCsByTransitGateway(gatewayID, first, second, third)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(attachmentIDs).To(HaveLen(3))
+		attachments, err := client.ListTransitGatewayVpcAttachments(first.VpcID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(attachments[0].SubnetIds).To(ConsistOf(first.AllPrivateSubnetIDs()))
+		Expect(routesTo(first, gatewayID)).To(HaveLen(8))
+		Expect(routesTo(second, gatewayID)).To(ConsistOf("10.0.0.0/16", "10.2.0.0/16", "10.0.0.0/16", "10.2.0.0/16"))
+
+		propagations, err := client.ListTransitGatewayRouteTablePropagations(defaultRouteTableID())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(propagations).To(HaveLen(3))
+		routes, err := client.ListTransitGatewayRoutes(defaultRouteTableID())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routes).To(HaveLen(3))
+
+		Expect(second.DetachTransitGateway(gatewayID)).To(Succeed())
+		Expect(routesTo(second, gatewayID)).To(BeEmpty())
+		routes, err = client.ListTransitGatewayRoutes(defaultRouteTableID())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routes).To(HaveLen(2))
+		Expect(client.DeleteTransitGateway(gatewayID, 1)).ToNot(Succeed())
+		Expect(first.DetachTransitGateway(gatewayID)).To(Succeed())
+		Expect(third.DetachTransitGateway(gatewayID)).To(Succeed())
+		Expect(client.DeleteTransitGateway(gatewayID, 1)).To(Succeed())
+	})
+
+	It("should send the egress of the spokes through the hub", func() {
+		hub := createVPC("hub-vpc", "10.0.0.0/16", fake.Zones()[0])
+		spoke := createVPC("spoke-vpc", "10.1.0.0/16")
+		_, err := spoke.CreatePrivateSubnet(fake.Zones()[0], false)
+		Expect(err).ToNot(HaveOccurred())
+
+		attachmentIDs, err := ConnectHubAndSpokes(gatewayID, hub, spoke)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(attachmentIDs).To(HaveLen(2))
+		Expect(routesTo(spoke, gatewayID)).To(Equal([]string{CON.RouteDestinationCidrBlock}))
+		Expect(routesTo(hub, gatewayID)).To(Equal([]string{"10.1.0.0/16", "10.1.0.0/16"}))
+
+		routes, err := client.ListTransitGatewayRoutes(defaultRouteTableID())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routes).To(HaveLen(3))
+		Expect(routes[0].Type).To(Equal(types.TransitGatewayRouteTypeStatic))
+		Expect(aws.ToString(routes[0].DestinationCidrBlock)).To(Equal(CON.RouteDestinationCidrBlock))
+		Expect(aws.ToString(routes[0].TransitGatewayAttachments[0].TransitGatewayAttachmentId)).To(Equal(attachmentIDs[0]))
+	})
+
+	It("should refuse spokes which already have a default route", func() {
+		hub := createVPC("hub-vpc", "10.0.0.0/16", fake.Zones()[0])
+		spoke := createVPC("spoke-vpc", "10.1.0.0/16", fake.Zones()[0])
+		_, err := ConnectHubAndSpokes(gatewayID, hub, spoke)
+		Expect(err).To(MatchError(ContainSubstring("0.0.0.0/0")))
+	})
+})
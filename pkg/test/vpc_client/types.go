@@ -9,27 +9,50 @@ import (
 
 // ************************* CIDR Pool *************************
 type SubnetCIDR struct {
-	Reserved bool
-	CIDR     string
-	IPNet    *net.IPNet
+	Reserved bool       `json:"reserved"`
+	CIDR     string     `json:"cidr"`
+	IPNet    *net.IPNet `json:"-"`
 }
 
 type VPCCIDRPool struct {
-	CIDR       string
-	Prefix     int
-	SubNetPool []*SubnetCIDR
+	CIDR       string        `json:"cidr"`
+	Prefix     int           `json:"prefix"`
+	SubNetPool []*SubnetCIDR `json:"subnetPool"`
+	// SecondaryCIDRs are the IPv4 CIDR blocks associated to the VPC besides CIDR
+	SecondaryCIDRs []string `json:"secondaryCidrs,omitempty"`
+	// Allocations are the blocks handed out by the pool or reserved, whatever their prefix
+	Allocations []string `json:"allocations,omitempty"`
+	// IPv6CIDR is the /56 IPv6 CIDR block of a dual-stack VPC, carved into IPv6SubNetPool
+	IPv6CIDR       string        `json:"ipv6Cidr,omitempty"`
+	IPv6SubNetPool []*SubnetCIDR `json:"ipv6SubnetPool,omitempty"`
+}
+
+// CIDRPoolUsage summarizes the IPv4 address space of a VPCCIDRPool.
+type CIDRPoolUsage struct {
+	TotalAddresses     uint64 `json:"totalAddresses"`
+	AllocatedAddresses uint64 `json:"allocatedAddresses"`
+	FreeAddresses      uint64 `json:"freeAddresses"`
+	// LargestFreeBlock is the biggest block AllocatePrefix can still hand out, empty once the pool
+	// is full.
+	LargestFreeBlock string `json:"largestFreeBlock,omitempty"`
+	// FreeBlocks is the number of aligned blocks the free space is split into.
+	FreeBlocks int `json:"freeBlocks"`
+	// Fragmentation is 0 when the free space is a single block and gets closer to 1 as it is
+	// scattered in small blocks.
+	Fragmentation float64 `json:"fragmentation"`
 }
 
 // ************************** Subnet ****************************
 type Subnet struct {
-	ID      string
-	Private bool
-	Zone    string
-	Cidr    string
-	Region  string
-	VpcID   string
-	Name    string
-	RTable  *types.RouteTable
+	ID       string
+	Private  bool
+	Zone     string
+	Cidr     string
+	IPv6Cidr string
+	Region   string
+	VpcID    string
+	Name     string
+	RTable   *types.RouteTable
 }
 
 func NewSubnet() *Subnet {
@@ -65,6 +88,11 @@ func (subnet *Subnet) SetCidr(cidr string) *Subnet {
 	return subnet
 }
 
+func (subnet *Subnet) SetIPv6Cidr(cidr string) *Subnet {
+	subnet.IPv6Cidr = cidr
+	return subnet
+}
+
 func (subnet *Subnet) SetName(name string) *Subnet {
 	subnet.Name = name
 	return subnet
@@ -79,6 +107,12 @@ type VPC struct {
 	CIDRPool   *VPCCIDRPool
 	SubnetList []*Subnet
 	Region     string
+	// IPv6Enabled makes CreateVPCChain create a dual-stack VPC
+	IPv6Enabled bool
+	// SourceManifest is the manifest the VPC was rebuilt from by VPCManifest.VPC. PlanTeardown
+	// deletes the key pairs, addresses and internet gateways it records even once they are no
+	// longer attached to the VPC.
+	SourceManifest *VPCManifest
 }
 
 func NewVPC() *VPC {
@@ -105,6 +139,13 @@ func (vpc *VPC) CIDR(cidr string) *VPC {
 	return vpc
 }
 
+// DualStack makes CreateVPCChain request an Amazon provided IPv6 CIDR block for the VPC and carve
+// a /64 for every subnet.
+func (vpc *VPC) DualStack(enabled bool) *VPC {
+	vpc.IPv6Enabled = enabled
+	return vpc
+}
+
 func (vpc *VPC) CIDRpool(cidrPool *VPCCIDRPool) *VPC {
 	vpc.CIDRPool = cidrPool
 	return vpc
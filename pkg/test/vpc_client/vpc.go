@@ -1,10 +1,13 @@
 package vpc_client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
 
 	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
@@ -31,16 +34,30 @@ func (vpc *VPC) GenerateVPCBySubnet(subnetID string) (*VPC, error) {
 //	vpcCidr is a string of the vpc's cidr, e.g. "10.190.0.0/16".
 //	region is a string of the AWS region. If this value is empty, the default region is "us-east-2".
 //	zone is a slice. If only one subnet should be created, the first zone should be selected. If this value is empty, the default zone is "a".
+//	With DualStack set, the vpc gets an Amazon provided IPv6 block and every subnet a /64 of it.
 //	If success, a VPC struct containing the ids of the created resources and nil.
 //	Otherwise, nil and an error from the call.
 func (vpc *VPC) CreateVPCChain(zones ...string) (*VPC, error) {
 	log.LogInfo("Going to create vpc and the follow resources on zones: %s", strings.Join(zones, ","))
-	respVpc, err := vpc.AWSClient.CreateVpc(vpc.CIDRValue, vpc.VPCName)
+	createVpc := vpc.AWSClient.CreateVpc
+	if vpc.IPv6Enabled {
+		createVpc = vpc.AWSClient.CreateDualStackVpc
+	}
+	respVpc, err := createVpc(vpc.CIDRValue, vpc.VPCName)
 	if err != nil {
 		log.LogError("Create vpc meets error: %s ", err.Error())
 		return nil, err
 	}
 	log.LogInfo("VPC created on AWS with id: %s", *respVpc.Vpc.VpcId)
+	if vpc.IPv6Enabled {
+		err = vpc.prepareIPv6SubnetPool(*respVpc.Vpc)
+		if err != nil {
+			if _, deleteErr := vpc.AWSClient.DeleteVpc(*respVpc.Vpc.VpcId); deleteErr != nil {
+				err = errors.Join(err, deleteErr)
+			}
+			return nil, err
+		}
+	}
 	_, err = vpc.AWSClient.ModifyVpcDnsAttribute(*respVpc.Vpc.VpcId, CON.VpcDnsHostnamesAttribute, true)
 	if err != nil {
 		log.LogError("Modify Vpc failed: %s ", err.Error())
@@ -63,6 +80,57 @@ func (vpc *VPC) CreateVPCChain(zones ...string) (*VPC, error) {
 	return vpc, err
 }
 
+// prepareIPv6SubnetPool waits for the IPv6 block of a new dual-stack vpc to be associated and carves
+// the /64 subnet pool out of it.
+func (vpc *VPC) prepareIPv6SubnetPool(created types.Vpc) error {
+	ipv6CIDR := aws_client.VpcIPv6CidrBlock(created)
+	if ipv6CIDR == "" {
+		var err error
+		ipv6CIDR, err = vpc.AWSClient.WaitForVpcIPv6CidrBlock(aws.ToString(created.VpcId))
+		if err != nil {
+			return err
+		}
+	}
+	if vpc.CIDRPool == nil {
+		vpc.CIDRPool = NewCIDRPool(vpc.CIDRValue)
+	}
+	return vpc.CIDRPool.GenerateIPv6SubnetPool(ipv6CIDR)
+}
+
+// CreateVPCChainWithContext is CreateVPCChain with every AWS call bound to ctx. Like CreateVPCChain it
+// fills in and returns the VPC, which keeps its own AWS client rather than one bound to ctx.
+func (vpc *VPC) CreateVPCChainWithContext(ctx context.Context, zones ...string) (*VPC, error) {
+	created, err := vpc.withContext(ctx).CreateVPCChain(zones...)
+	if created == nil {
+		return nil, err
+	}
+	vpc.adopt(created)
+	return vpc, err
+}
+
+// DeleteVPCChainWithContext is DeleteVPCChain with every AWS call bound to ctx, so a cancelled or
+// expired context stops the teardown.
+func (vpc *VPC) DeleteVPCChainWithContext(ctx context.Context, totalClean ...bool) error {
+	return vpc.withContext(ctx).DeleteVPCChain(totalClean...)
+}
+
+// withContext returns a copy of the VPC whose AWS client runs with ctx.
+func (vpc *VPC) withContext(ctx context.Context) *VPC {
+	copied := *vpc
+	copied.AWSClient = vpc.AWSClient.WithContext(ctx)
+	return &copied
+}
+
+// adopt copies the state of bound, a copy made by withContext, back into the VPC, keeping the AWS
+// client of the VPC.
+func (vpc *VPC) adopt(bound *VPC) {
+	client := vpc.AWSClient
+	*vpc = *bound
+	vpc.AWSClient = client
+}
+
+// DeleteVPCChain deletes the resources created by CreateVPCChain in a fixed order. Use Teardown for
+// a VPC holding other resources, such as peering connections or transit gateway attachments.
 func (vpc *VPC) DeleteVPCChain(totalClean ...bool) error {
 	vpcID := vpc.VpcID
 	if vpcID == "" {
@@ -137,6 +205,11 @@ func (vpc *VPC) DeleteVPCChain(totalClean ...bool) error {
 		errs = append(errs, fmt.Errorf("delete internet gateways: %w", err))
 	}
 
+	if err := vpc.DeleteVPCEgressOnlyInternetGateways(); err != nil {
+		log.LogError("Delete vpc egress-only internet gateways meets error: %s", err.Error())
+		errs = append(errs, fmt.Errorf("delete egress-only internet gateways: %w", err))
+	}
+
 	if err := vpc.DeleteVPCSubnets(); err != nil {
 		log.LogError("Delete vpc subnets meets error: %s", err.Error())
 		errs = append(errs, fmt.Errorf("delete subnets: %w", err))
@@ -252,17 +325,31 @@ func GenerateVPCByID(vpcID string, region string, awsSharedCredentialFile ...str
 	if err != nil {
 		return nil, err
 	}
-	reservedCIDRs := []string{}
-	for _, sub := range vpc.SubnetList {
-		reservedCIDRs = append(reservedCIDRs, sub.Cidr)
+	_, err = vpc.LoadCIDRPool()
+	if err != nil {
+		return nil, err
+	}
+	return vpc, nil
+}
+
+// LoadCIDRPool rebuilds the CIDR pool of the vpc from the CIDR blocks and subnets it has on AWS,
+// and sets it as the pool of the vpc. prefix works as for NewCIDRPool.
+func (vpc *VPC) LoadCIDRPool(prefix ...int) (*VPCCIDRPool, error) {
+	vpcResp, err := vpc.AWSClient.DescribeVPC(vpc.VpcID)
+	if err != nil {
+		return nil, err
 	}
-	cidrPool := NewCIDRPool(vpc.CIDRValue)
-	err = cidrPool.Reserve(reservedCIDRs...)
+	subnets, err := vpc.AWSClient.ListSubnetByVpcID(vpc.VpcID)
+	if err != nil {
+		return nil, err
+	}
+	cidrPool, err := NewCIDRPoolFromVPC(vpcResp, subnets, prefix...)
 	if err != nil {
 		return nil, err
 	}
 	vpc.CIDRPool = cidrPool
-	return vpc, nil
+	vpc.IPv6Enabled = cidrPool.IPv6CIDR != ""
+	return cidrPool, nil
 }
 
 // GenerateVPCBySubnet will return a VPC with CIDRpool and subnets based on one of the subnet ID
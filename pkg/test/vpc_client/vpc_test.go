@@ -0,0 +1,103 @@
+package vpc_client_test
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift-online/ocm-common/pkg/aws/aws_client"
+	CON "github.com/openshift-online/ocm-common/pkg/aws/consts"
+	"github.com/openshift-online/ocm-common/pkg/test/aws_fake"
+	. "github.com/openshift-online/ocm-common/pkg/test/vpc_client"
+)
+
+// ipv6StateEC2 reports the IPv6 blocks of VPCs in state for the next describes calls.
+type ipv6StateEC2 struct {
+	*aws_fake.EC2
+	mutex     sync.Mutex
+	state     types.VpcCidrBlockStateCode
+	describes int
+}
+
+func (f *ipv6StateEC2) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	output, err := f.EC2.DescribeVpcs(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.describes > 0 {
+		f.describes--
+		for _, vpc := range output.Vpcs {
+			for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+				association.Ipv6CidrBlockState.State = f.state
+			}
+		}
+	}
+	return output, nil
+}
+
+var _ = Describe("VPC chain", func() {
+	var (
+		fake   *aws_fake.EC2
+		client *aws_client.AWSClient
+	)
+
+	BeforeEach(func() {
+		fake = aws_fake.NewEC2(aws_fake.WithRegion("us-east-2"))
+		client = &aws_client.AWSClient{Ec2Client: fake}
+	})
+
+	It("should fill in the VPC created with a context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		vpc := NewVPC().
+			AWSclient(client).
+			Name("context-vpc").
+			CIDR(CON.DefaultVPCCIDR).
+			SetRegion(fake.Region()).
+			NewCIDRPool()
+		created, err := vpc.CreateVPCChainWithContext(ctx, fake.Zones()[0])
+		Expect(err).ToNot(HaveOccurred())
+		cancel()
+
+		Expect(created).To(BeIdenticalTo(vpc))
+		Expect(vpc.VpcID).ToNot(BeEmpty())
+		Expect(vpc.SubnetList).To(HaveLen(2))
+		Expect(vpc.AWSClient).To(BeIdenticalTo(client))
+		subnets, err := vpc.ListSubnets()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subnets).To(HaveLen(2))
+	})
+
+	createDualStack := func(ec2Client aws_client.EC2ClientAPI) (*VPC, error) {
+		return NewVPC().
+			AWSclient(&aws_client.AWSClient{Ec2Client: ec2Client}).
+			Name("dual-stack-vpc").
+			CIDR(CON.DefaultVPCCIDR).
+			SetRegion(fake.Region()).
+			NewCIDRPool().
+			DualStack(true).
+			CreateVPCChain(fake.Zones()[0])
+	}
+
+	It("should wait for the IPv6 block to be associated", func() {
+		vpc, err := createDualStack(&ipv6StateEC2{EC2: fake, state: types.VpcCidrBlockStateCodeAssociating, describes: 2})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpc.CIDRPool.IPv6SubNetPool).To(HaveLen(256))
+		for _, subnet := range vpc.SubnetList {
+			Expect(subnet.IPv6Cidr).To(HaveSuffix("::/64"))
+		}
+	})
+
+	It("should delete the VPC when the IPv6 block fails to associate", func() {
+		_, err := createDualStack(&ipv6StateEC2{EC2: fake, state: types.VpcCidrBlockStateCodeFailed, describes: 10})
+		Expect(err).To(MatchError(ContainSubstring("got no IPv6 CIDR block associated")))
+		vpcs, err := fake.DescribeVpcs(context.Background(), &ec2.DescribeVpcsInput{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpcs.Vpcs).To(BeEmpty())
+	})
+})